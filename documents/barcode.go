@@ -0,0 +1,56 @@
+package documents
+
+import (
+    "fmt"
+    "strings"
+
+    "github.com/jung-kurt/gofpdf"
+)
+
+// code39 adalah pola 9 elemen (batang, spasi, batang, ...) per karakter; '1' berarti elemen lebar
+var code39 = map[rune]string{
+    '0': "000110100", '1': "100100001", '2': "001100001", '3': "101100000", '4': "000110001",
+    '5': "100110000", '6': "001110000", '7': "000100101", '8': "100100100", '9': "001100100",
+    'A': "100001001", 'B': "001001001", 'C': "101001000", 'D': "000011001", 'E': "100011000",
+    'F': "001011000", 'G': "000001101", 'H': "100001100", 'I': "001001100", 'J': "000011100",
+    'K': "100000011", 'L': "001000011", 'M': "101000010", 'N': "000010011", 'O': "100010010",
+    'P': "001010010", 'Q': "000000111", 'R': "100000110", 'S': "001000110", 'T': "000010110",
+    'U': "110000001", 'V': "011000001", 'W': "111000000", 'X': "010010001", 'Y': "110010000",
+    'Z': "011010000", '-': "010000101", '.': "110000100", ' ': "011000100", '*': "010010100",
+}
+
+// drawCode39 menggambar barcode Code 39 selebar w di (x, y), termasuk quiet zone kosong di kiri-kanan.
+// Code 39 dipilih karena bisa dibaca semua scanner barcode biasa tanpa library tambahan.
+func drawCode39(pdf *gofpdf.Fpdf, x, y, w, h float64, text string) error {
+    const wide = 3.0
+    // quiet zone minimal 10x batang sempit, kurang dari itu scanner sering gagal membaca
+    const quiet = 10.0
+    encoded := "*" + strings.ToUpper(text) + "*"
+    for _, c := range encoded[1 : len(encoded)-1] {
+        if _, ok := code39[c]; !ok || c == '*' {
+            return fmt.Errorf("karakter '%c' tidak bisa dibuat barcode", c)
+        }
+    }
+
+    // setiap karakter: 6 elemen sempit + 3 lebar, ditambah 1 spasi sempit antar karakter
+    units := float64(len(encoded))*(6+3*wide) + float64(len(encoded)-1) + 2*quiet
+    narrow := w / units
+    x += quiet * narrow
+    pdf.SetFillColor(0, 0, 0)
+    for i, c := range encoded {
+        if i > 0 {
+            x += narrow
+        }
+        for j, e := range code39[c] {
+            width := narrow
+            if e == '1' {
+                width = narrow * wide
+            }
+            if j%2 == 0 {
+                pdf.Rect(x, y, width, h, "F")
+            }
+            x += width
+        }
+    }
+    return nil
+}
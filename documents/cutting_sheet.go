@@ -0,0 +1,67 @@
+package documents
+
+import (
+    "fmt"
+    "konveksi-app/models"
+    "time"
+)
+
+// CuttingSheet membuat PDF rekap ukuran untuk ruang potong.
+// filterInfo berisi keterangan filter (transaksi/customer/periode) yang dicetak di bawah judul.
+func CuttingSheet(recaps []models.SizeRecap, filterInfo []string) ([]byte, error) {
+    pdf := newPDF("P")
+    tr := pdf.UnicodeTranslatorFromDescriptor("")
+    pdf.AddPage()
+    writeHeader(pdf, "REKAP UKURAN / LEMBAR POTONG")
+
+    pdf.SetFont("Arial", "", 10)
+    pdf.CellFormat(0, 6, "Dicetak: "+time.Now().Format("02.01.2006 15:04"), "", 1, "L", false, 0, "")
+    for _, info := range filterInfo {
+        pdf.CellFormat(0, 6, tr(info), "", 1, "L", false, 0, "")
+    }
+    pdf.Ln(4)
+
+    if len(recaps) == 0 {
+        pdf.CellFormat(0, 8, "Tidak ada item untuk direkap.", "", 1, "L", false, 0, "")
+        return output(pdf)
+    }
+
+    widths := []float64{40, 30, 30, 40, 40}
+    headers := []string{"Ukuran", "Jumlah", "Transaksi", "Sudah Dipotong", "Paraf"}
+    grandTotal := 0
+    for _, recap := range recaps {
+        // Jaga supaya judul seragam tidak terpisah dari tabelnya
+        _, pageHeight := pdf.GetPageSize()
+        if pdf.GetY()+float64(len(recap.Sizes)+3)*7 > pageHeight-15 && len(recap.Sizes) < 30 {
+            pdf.AddPage()
+        }
+
+        pdf.SetFont("Arial", "B", 11)
+        pdf.CellFormat(0, 8, tr(recap.UniformName), "", 1, "L", false, 0, "")
+
+        pdf.SetFont("Arial", "B", 10)
+        pdf.SetFillColor(230, 230, 230)
+        for i, h := range headers {
+            pdf.CellFormat(widths[i], 7, h, "1", 0, "C", true, 0, "")
+        }
+        pdf.Ln(-1)
+
+        pdf.SetFont("Arial", "", 10)
+        for _, item := range recap.Sizes {
+            pdf.CellFormat(widths[0], 7, tr(item.Size), "1", 0, "C", false, 0, "")
+            pdf.CellFormat(widths[1], 7, fmt.Sprintf("%d", item.Quantity), "1", 0, "C", false, 0, "")
+            pdf.CellFormat(widths[2], 7, fmt.Sprintf("%d", item.TransactionCount), "1", 0, "C", false, 0, "")
+            pdf.CellFormat(widths[3], 7, "", "1", 0, "C", false, 0, "")
+            pdf.CellFormat(widths[4], 7, "", "1", 1, "C", false, 0, "")
+        }
+        pdf.SetFont("Arial", "B", 10)
+        pdf.CellFormat(widths[0], 7, "TOTAL", "1", 0, "C", false, 0, "")
+        pdf.CellFormat(widths[1], 7, fmt.Sprintf("%d", recap.Total), "1", 1, "C", false, 0, "")
+        pdf.Ln(4)
+        grandTotal += recap.Total
+    }
+
+    pdf.SetFont("Arial", "B", 11)
+    pdf.CellFormat(0, 8, fmt.Sprintf("Total semua seragam: %d pcs", grandTotal), "", 1, "L", false, 0, "")
+    return output(pdf)
+}
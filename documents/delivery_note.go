@@ -0,0 +1,83 @@
+package documents
+
+import (
+    "fmt"
+    "konveksi-app/models"
+)
+
+// DeliveryNoteLine adalah jumlah barang yang dikirim per seragam dan ukuran
+type DeliveryNoteLine struct {
+    UniformName string
+    Size        string
+    Quantity    int
+    Notes       string
+}
+
+// DeliveryNote membuat PDF surat jalan dengan kolom tanda tangan penerima
+func DeliveryNote(trx *models.Transaksi, customer *models.Customer, number, date string, lines []DeliveryNoteLine) ([]byte, error) {
+    pdf := newPDF("P")
+    tr := pdf.UnicodeTranslatorFromDescriptor("")
+    pdf.AddPage()
+    writeHeader(pdf, "SURAT JALAN "+number)
+
+    pdf.SetFont("Arial", "", 10)
+    info := [][2]string{
+        {"Tanggal", FormatDate(date)},
+        {"No. Pesanan", fmt.Sprintf("#%d", trx.ID)},
+        {"Kepada", trx.Customer_name},
+    }
+    if customer != nil {
+        info = append(info, [2]string{"Alamat", customer.Address}, [2]string{"No. Telp", customer.Contact})
+    }
+    for _, row := range info {
+        pdf.CellFormat(30, 6, row[0], "", 0, "L", false, 0, "")
+        pdf.CellFormat(0, 6, ": "+tr(row[1]), "", 1, "L", false, 0, "")
+    }
+    pdf.Ln(4)
+
+    widths := []float64{10, 80, 30, 20, 40}
+    headers := []string{"No", "Seragam", "Ukuran", "Jumlah", "Keterangan"}
+    pdf.SetFont("Arial", "B", 10)
+    pdf.SetFillColor(230, 230, 230)
+    for i, h := range headers {
+        pdf.CellFormat(widths[i], 7, h, "1", 0, "C", true, 0, "")
+    }
+    pdf.Ln(-1)
+
+    pdf.SetFont("Arial", "", 10)
+    total := 0
+    for i, l := range lines {
+        pdf.CellFormat(widths[0], 7, fmt.Sprintf("%d", i+1), "1", 0, "C", false, 0, "")
+        pdf.CellFormat(widths[1], 7, tr(l.UniformName), "1", 0, "L", false, 0, "")
+        pdf.CellFormat(widths[2], 7, tr(l.Size), "1", 0, "C", false, 0, "")
+        pdf.CellFormat(widths[3], 7, fmt.Sprintf("%d", l.Quantity), "1", 0, "C", false, 0, "")
+        pdf.CellFormat(widths[4], 7, tr(l.Notes), "1", 1, "L", false, 0, "")
+        total += l.Quantity
+    }
+    pdf.SetFont("Arial", "B", 10)
+    pdf.CellFormat(widths[0]+widths[1]+widths[2], 7, "TOTAL", "1", 0, "R", false, 0, "")
+    pdf.CellFormat(widths[3], 7, fmt.Sprintf("%d", total), "1", 0, "C", false, 0, "")
+    pdf.CellFormat(widths[4], 7, "pcs", "1", 1, "L", false, 0, "")
+    pdf.Ln(4)
+
+    pdf.SetFont("Arial", "", 9)
+    pdf.MultiCell(0, 5, "Barang telah diterima dalam keadaan baik dan jumlah sesuai. "+
+        "Mohon periksa kembali sebelum menandatangani surat jalan ini.", "", "L", false)
+
+    _, pageHeight := pdf.GetPageSize()
+    if pdf.GetY()+40 > pageHeight-15 {
+        pdf.AddPage()
+    }
+    pdf.Ln(8)
+    pdf.SetFont("Arial", "", 10)
+    for _, label := range []string{"Penerima,", "Pengirim,", "Hormat kami,"} {
+        pdf.CellFormat(60, 6, label, "", 0, "C", false, 0, "")
+    }
+    pdf.Ln(22)
+    pdf.CellFormat(60, 6, "(.............................)", "", 0, "C", false, 0, "")
+    pdf.CellFormat(60, 6, "(.............................)", "", 0, "C", false, 0, "")
+    pdf.CellFormat(60, 6, "( "+companyName+" )", "", 1, "C", false, 0, "")
+    pdf.SetFont("Arial", "", 8)
+    pdf.CellFormat(60, 5, "Nama jelas & tanggal terima", "", 1, "C", false, 0, "")
+    return output(pdf)
+}
@@ -0,0 +1,164 @@
+package documents
+
+import (
+    "bytes"
+    "fmt"
+    "konveksi-app/models"
+    "strings"
+    "time"
+
+    "github.com/jung-kurt/gofpdf"
+)
+
+// KuitansiLine adalah satu baris pada kuitansi (pesanan biasa maupun per siswa)
+type KuitansiLine struct {
+    StudentName string
+    UniformName string
+    Size        string
+    Quantity    int
+    Subtotal    float64
+}
+
+const companyName = "DiOlif Fashion"
+
+// FormatRupiah memformat angka menjadi "Rp 1.250.000"
+func FormatRupiah(amount float64) string {
+    negative := amount < 0
+    if negative {
+        amount = -amount
+    }
+    digits := fmt.Sprintf("%.0f", amount)
+    var out strings.Builder
+    for i, d := range digits {
+        if i > 0 && (len(digits)-i)%3 == 0 {
+            out.WriteByte('.')
+        }
+        out.WriteRune(d)
+    }
+    if negative {
+        return "-Rp " + out.String()
+    }
+    return "Rp " + out.String()
+}
+
+// FormatDate mengubah "2006-01-02" (atau datetime) menjadi "02.01.2006"
+func FormatDate(date string) string {
+    for _, layout := range []string{"2006-01-02", "2006-01-02 15:04:05"} {
+        if t, err := time.Parse(layout, date); err == nil {
+            return t.Format("02.01.2006")
+        }
+    }
+    return date
+}
+
+func statusText(status string) string {
+    switch status {
+    case "paid":
+        return "LUNAS"
+    case "cancelled":
+        return "DIBATALKAN"
+    default:
+        return "DP"
+    }
+}
+
+func newPDF(orientation string) *gofpdf.Fpdf {
+    pdf := gofpdf.New(orientation, "mm", "A4", "")
+    pdf.SetMargins(15, 15, 15)
+    pdf.SetAutoPageBreak(true, 15)
+    return pdf
+}
+
+// writeHeader menulis kop dokumen: nama usaha dan judul
+func writeHeader(pdf *gofpdf.Fpdf, title string) {
+    pdf.SetFont("Arial", "B", 16)
+    pdf.CellFormat(0, 8, companyName, "", 1, "L", false, 0, "")
+    pdf.SetFont("Arial", "B", 13)
+    pdf.CellFormat(0, 8, title, "B", 1, "L", false, 0, "")
+    pdf.Ln(4)
+}
+
+func output(pdf *gofpdf.Fpdf) ([]byte, error) {
+    var buf bytes.Buffer
+    if err := pdf.Output(&buf); err != nil {
+        return nil, err
+    }
+    return buf.Bytes(), nil
+}
+
+// Kuitansi membuat PDF kuitansi untuk satu transaksi
+func Kuitansi(trx *models.Transaksi, customer *models.Customer, lines []KuitansiLine) ([]byte, error) {
+    pdf := newPDF("P")
+    tr := pdf.UnicodeTranslatorFromDescriptor("")
+    pdf.AddPage()
+    writeHeader(pdf, fmt.Sprintf("KUITANSI #%d", trx.ID))
+
+    pdf.SetFont("Arial", "", 10)
+    info := [][2]string{
+        {"Tanggal", FormatDate(trx.Transaksidate)},
+        {"Kepada", trx.Customer_name},
+    }
+    if customer != nil {
+        info = append(info, [2]string{"Alamat", customer.Address}, [2]string{"No. Telp", customer.Contact})
+    }
+    for _, row := range info {
+        pdf.CellFormat(30, 6, row[0], "", 0, "L", false, 0, "")
+        pdf.CellFormat(0, 6, ": "+tr(row[1]), "", 1, "L", false, 0, "")
+    }
+    pdf.Ln(4)
+
+    hasStudent := false
+    for _, line := range lines {
+        if line.StudentName != "" {
+            hasStudent = true
+            break
+        }
+    }
+
+    headers := []string{"Seragam", "Ukuran", "Qty", "Subtotal"}
+    widths := []float64{90, 30, 20, 40}
+    if hasStudent {
+        headers = []string{"Nama Siswa", "Seragam", "Ukuran", "Qty", "Subtotal"}
+        widths = []float64{50, 60, 20, 15, 35}
+    }
+
+    pdf.SetFont("Arial", "B", 10)
+    pdf.SetFillColor(230, 230, 230)
+    for i, h := range headers {
+        pdf.CellFormat(widths[i], 7, h, "1", 0, "C", true, 0, "")
+    }
+    pdf.Ln(-1)
+
+    pdf.SetFont("Arial", "", 10)
+    for _, line := range lines {
+        cells := []string{tr(line.UniformName), line.Size, fmt.Sprintf("%d", line.Quantity), FormatRupiah(line.Subtotal)}
+        if hasStudent {
+            cells = append([]string{tr(line.StudentName)}, cells...)
+        }
+        for i, c := range cells {
+            align := "L"
+            if i >= len(cells)-2 {
+                align = "R"
+            }
+            pdf.CellFormat(widths[i], 6, c, "1", 0, align, false, 0, "")
+        }
+        pdf.Ln(-1)
+    }
+
+    var totalWidth float64
+    for _, w := range widths[:len(widths)-1] {
+        totalWidth += w
+    }
+    pdf.SetFont("Arial", "B", 10)
+    pdf.CellFormat(totalWidth, 7, "TOTAL", "1", 0, "R", false, 0, "")
+    pdf.CellFormat(widths[len(widths)-1], 7, FormatRupiah(trx.Total), "1", 1, "R", false, 0, "")
+    pdf.Ln(4)
+
+    pdf.SetFont("Arial", "", 10)
+    pdf.CellFormat(0, 6, "Status pembayaran: "+statusText(trx.Status), "", 1, "L", false, 0, "")
+    if trx.Paymentdate != "" {
+        pdf.CellFormat(0, 6, "Jatuh tempo: "+FormatDate(trx.Paymentdate), "", 1, "L", false, 0, "")
+    }
+
+    return output(pdf)
+}
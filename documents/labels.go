@@ -0,0 +1,110 @@
+package documents
+
+import (
+    "fmt"
+)
+
+// Label adalah isi satu stiker kantong seragam siswa
+type Label struct {
+    Customer    string
+    StudentName string
+    Grade       string
+    Items       []string // mis. "Batik - M (1)"
+}
+
+// LabelLayout adalah susunan stiker di kertas A4 (mm). Width/Height 0 berarti dihitung dari grid.
+type LabelLayout struct {
+    Columns    int
+    Rows       int
+    MarginTop  float64
+    MarginLeft float64
+    GapX       float64
+    GapY       float64
+    Width      float64
+    Height     float64
+    Skip       int  // jumlah stiker yang dilewati di halaman pertama (kertas sisa)
+    Border     bool // garis tepi untuk kertas polos yang digunting manual
+}
+
+// DefaultLabelLayout cocok untuk kertas stiker 3 x 8 per A4
+var DefaultLabelLayout = LabelLayout{Columns: 3, Rows: 8, MarginTop: 10, MarginLeft: 8, GapX: 3, GapY: 2}
+
+// Normalize melengkapi ukuran stiker dan menolak grid yang tidak muat di A4
+func (l *LabelLayout) Normalize() error {
+    const pageWidth, pageHeight = 210.0, 297.0
+    if l.Columns < 1 || l.Rows < 1 || l.Columns > 10 || l.Rows > 30 {
+        return fmt.Errorf("grid stiker harus 1-10 kolom dan 1-30 baris")
+    }
+    if l.MarginTop < 0 || l.MarginLeft < 0 || l.GapX < 0 || l.GapY < 0 || l.Skip < 0 {
+        return fmt.Errorf("margin, jarak dan skip tidak boleh negatif")
+    }
+    if l.Width <= 0 {
+        l.Width = (pageWidth - 2*l.MarginLeft - float64(l.Columns-1)*l.GapX) / float64(l.Columns)
+    }
+    if l.Height <= 0 {
+        l.Height = (pageHeight - 2*l.MarginTop - float64(l.Rows-1)*l.GapY) / float64(l.Rows)
+    }
+    if l.Width < 20 || l.Height < 12 {
+        return fmt.Errorf("stiker terlalu kecil (%.1f x %.1f mm)", l.Width, l.Height)
+    }
+    if l.MarginLeft+float64(l.Columns)*l.Width+float64(l.Columns-1)*l.GapX > pageWidth+0.01 ||
+        l.MarginTop+float64(l.Rows)*l.Height+float64(l.Rows-1)*l.GapY > pageHeight+0.01 {
+        return fmt.Errorf("grid stiker tidak muat di kertas A4")
+    }
+    return nil
+}
+
+// LabelSheet membuat PDF lembar stiker label kantong per siswa
+func LabelSheet(labels []Label, layout LabelLayout) ([]byte, error) {
+    if err := layout.Normalize(); err != nil {
+        return nil, err
+    }
+    pdf := newPDF("P")
+    pdf.SetAutoPageBreak(false, 0)
+    tr := pdf.UnicodeTranslatorFromDescriptor("")
+
+    perPage := layout.Columns * layout.Rows
+    const padding, lineHeight = 2.0, 3.8
+    for i, label := range labels {
+        slot := (i + layout.Skip) % perPage
+        if i == 0 || slot == 0 {
+            pdf.AddPage()
+        }
+        x := layout.MarginLeft + float64(slot%layout.Columns)*(layout.Width+layout.GapX)
+        y := layout.MarginTop + float64(slot/layout.Columns)*(layout.Height+layout.GapY)
+        if layout.Border {
+            pdf.SetDrawColor(180, 180, 180)
+            pdf.Rect(x, y, layout.Width, layout.Height, "D")
+        }
+        innerWidth := layout.Width - 2*padding
+        bottom := y + layout.Height - padding
+
+        pdf.SetXY(x+padding, y+padding)
+        pdf.SetFont("Arial", "", 6)
+        pdf.CellFormat(innerWidth, 3, tr(label.Customer), "", 2, "L", false, 0, "")
+        pdf.SetFont("Arial", "B", 10)
+        pdf.CellFormat(innerWidth, 5, tr(label.StudentName), "", 2, "L", false, 0, "")
+        pdf.SetFont("Arial", "", 8)
+        if label.Grade != "" {
+            pdf.CellFormat(innerWidth, lineHeight, tr("Kelas "+label.Grade), "", 2, "L", false, 0, "")
+        }
+        for j, item := range label.Items {
+            if pdf.GetY()+lineHeight > bottom {
+                break
+            }
+            // baris terakhir yang muat dipakai untuk penanda jika item masih ada
+            if pdf.GetY()+2*lineHeight > bottom && j < len(label.Items)-1 {
+                pdf.CellFormat(innerWidth, lineHeight, fmt.Sprintf("+%d item lagi", len(label.Items)-j), "", 2, "L", false, 0, "")
+                break
+            }
+            pdf.CellFormat(innerWidth, lineHeight, tr(item), "", 2, "L", false, 0, "")
+        }
+    }
+    if len(labels) == 0 {
+        pdf.AddPage()
+        pdf.SetFont("Arial", "", 10)
+        pdf.SetXY(layout.MarginLeft, layout.MarginTop)
+        pdf.CellFormat(0, 8, "Tidak ada item siswa untuk dibuat label.", "", 1, "L", false, 0, "")
+    }
+    return output(pdf)
+}
@@ -0,0 +1,83 @@
+package documents
+
+import (
+    "fmt"
+    "konveksi-app/models"
+)
+
+var operationLabels = map[string]string{
+    "potong":         "Potong",
+    "jahit":          "Jahit",
+    "lubang_kancing": "Lubang Kancing",
+    "setrika":        "Setrika",
+}
+
+func operationLabel(op string) string {
+    if label, ok := operationLabels[op]; ok {
+        return label
+    }
+    return op
+}
+
+// Payslips membuat PDF slip upah borongan, satu halaman per pekerja
+func Payslips(wages []models.WorkerWage, periodFrom, periodTo string) ([]byte, error) {
+    pdf := newPDF("P")
+    tr := pdf.UnicodeTranslatorFromDescriptor("")
+    period := FormatDate(periodFrom) + " s/d " + FormatDate(periodTo)
+
+    if len(wages) == 0 {
+        pdf.AddPage()
+        writeHeader(pdf, "SLIP UPAH BORONGAN")
+        pdf.SetFont("Arial", "", 10)
+        pdf.CellFormat(0, 8, "Tidak ada hasil kerja pada periode "+period+".", "", 1, "L", false, 0, "")
+        return output(pdf)
+    }
+
+    widths := []float64{20, 45, 20, 30, 18, 22, 25}
+    headers := []string{"Transaksi", "Seragam", "Ukuran", "Operasi", "Jumlah", "Tarif", "Upah"}
+    for _, wage := range wages {
+        pdf.AddPage()
+        writeHeader(pdf, "SLIP UPAH BORONGAN")
+
+        pdf.SetFont("Arial", "", 10)
+        pdf.CellFormat(30, 6, "Nama", "", 0, "L", false, 0, "")
+        pdf.CellFormat(0, 6, ": "+tr(wage.WorkerName), "", 1, "L", false, 0, "")
+        pdf.CellFormat(30, 6, "Periode", "", 0, "L", false, 0, "")
+        pdf.CellFormat(0, 6, ": "+period, "", 1, "L", false, 0, "")
+        pdf.Ln(4)
+
+        pdf.SetFont("Arial", "B", 9)
+        pdf.SetFillColor(230, 230, 230)
+        for i, h := range headers {
+            pdf.CellFormat(widths[i], 7, h, "1", 0, "C", true, 0, "")
+        }
+        pdf.Ln(-1)
+
+        pdf.SetFont("Arial", "", 9)
+        for _, l := range wage.Lines {
+            pdf.CellFormat(widths[0], 7, fmt.Sprintf("#%d", l.TransactionID), "1", 0, "C", false, 0, "")
+            pdf.CellFormat(widths[1], 7, tr(l.UniformName), "1", 0, "L", false, 0, "")
+            pdf.CellFormat(widths[2], 7, tr(l.Size), "1", 0, "C", false, 0, "")
+            pdf.CellFormat(widths[3], 7, operationLabel(l.Operation), "1", 0, "L", false, 0, "")
+            pdf.CellFormat(widths[4], 7, fmt.Sprintf("%d", l.Quantity), "1", 0, "C", false, 0, "")
+            pdf.CellFormat(widths[5], 7, FormatRupiah(l.Rate), "1", 0, "R", false, 0, "")
+            pdf.CellFormat(widths[6], 7, FormatRupiah(l.Amount), "1", 1, "R", false, 0, "")
+        }
+
+        pdf.SetFont("Arial", "B", 9)
+        labelWidth := widths[0] + widths[1] + widths[2] + widths[3]
+        pdf.CellFormat(labelWidth, 7, "TOTAL", "1", 0, "R", false, 0, "")
+        pdf.CellFormat(widths[4], 7, fmt.Sprintf("%d", wage.Pieces), "1", 0, "C", false, 0, "")
+        pdf.CellFormat(widths[5], 7, "", "1", 0, "C", false, 0, "")
+        pdf.CellFormat(widths[6], 7, FormatRupiah(wage.Total), "1", 1, "R", false, 0, "")
+
+        pdf.Ln(15)
+        pdf.SetFont("Arial", "", 10)
+        pdf.CellFormat(90, 6, "Diterima oleh,", "", 0, "C", false, 0, "")
+        pdf.CellFormat(90, 6, "Dibayar oleh,", "", 1, "C", false, 0, "")
+        pdf.Ln(18)
+        pdf.CellFormat(90, 6, "( "+tr(wage.WorkerName)+" )", "", 0, "C", false, 0, "")
+        pdf.CellFormat(90, 6, "( "+companyName+" )", "", 1, "C", false, 0, "")
+    }
+    return output(pdf)
+}
@@ -0,0 +1,115 @@
+package documents
+
+import (
+    "konveksi-app/models"
+    "konveksi-app/sizing"
+    "strings"
+    "time"
+)
+
+// PriceListItem adalah satu harga seragam/ukuran pada daftar harga customer
+type PriceListItem struct {
+    UniformName string
+    Size        string
+    Price       float64
+}
+
+// PriceList membuat PDF daftar harga satu customer: baris per seragam, kolom per ukuran
+func PriceList(customer *models.Customer, items []PriceListItem) ([]byte, error) {
+    var uniforms []string
+    sizeSet := map[string]bool{}
+    prices := map[string]map[string]float64{}
+    for _, item := range items {
+        key := strings.ToLower(item.UniformName)
+        if prices[key] == nil {
+            prices[key] = map[string]float64{}
+            uniforms = append(uniforms, item.UniformName)
+        }
+        prices[key][strings.ToLower(item.Size)] = item.Price
+        sizeSet[item.Size] = true
+    }
+    var sizes []string
+    seen := map[string]bool{}
+    for size := range sizeSet {
+        if !seen[strings.ToLower(size)] {
+            seen[strings.ToLower(size)] = true
+            sizes = append(sizes, size)
+        }
+    }
+    sizing.Sort(sizes)
+
+    orientation := "P"
+    if len(sizes) > 5 {
+        orientation = "L"
+    }
+    pdf := newPDF(orientation)
+    tr := pdf.UnicodeTranslatorFromDescriptor("")
+    pdf.AddPage()
+    writeHeader(pdf, "DAFTAR HARGA SERAGAM")
+
+    pdf.SetFont("Arial", "", 10)
+    info := [][2]string{
+        {"Customer", customer.Name},
+        {"Tipe", customer.Type},
+        {"Alamat", customer.Address},
+        {"Tanggal", time.Now().Format("02.01.2006")},
+    }
+    for _, row := range info {
+        pdf.CellFormat(30, 6, row[0], "", 0, "L", false, 0, "")
+        pdf.CellFormat(0, 6, ": "+tr(row[1]), "", 1, "L", false, 0, "")
+    }
+    pdf.Ln(4)
+
+    if len(uniforms) == 0 {
+        pdf.CellFormat(0, 8, "Belum ada daftar harga.", "", 1, "L", false, 0, "")
+        return output(pdf)
+    }
+
+    pageWidth, _ := pdf.GetPageSize()
+    left, _, right, _ := pdf.GetMargins()
+    available := pageWidth - left - right
+    nameWidth := 55.0
+    sizeWidth := (available - nameWidth) / float64(len(sizes))
+    if sizeWidth > 30 {
+        sizeWidth = 30
+    }
+    fontSize := 10.0
+    if sizeWidth < 22 {
+        fontSize = 8
+    }
+
+    header := func() {
+        pdf.SetFont("Arial", "B", fontSize)
+        pdf.SetFillColor(230, 230, 230)
+        pdf.CellFormat(nameWidth, 7, "Seragam", "1", 0, "C", true, 0, "")
+        for _, size := range sizes {
+            pdf.CellFormat(sizeWidth, 7, tr(size), "1", 0, "C", true, 0, "")
+        }
+        pdf.Ln(-1)
+    }
+    header()
+
+    _, pageHeight := pdf.GetPageSize()
+    pdf.SetFont("Arial", "", fontSize)
+    for _, name := range uniforms {
+        if pdf.GetY()+7 > pageHeight-15 {
+            pdf.AddPage()
+            header()
+            pdf.SetFont("Arial", "", fontSize)
+        }
+        pdf.CellFormat(nameWidth, 7, tr(name), "1", 0, "L", false, 0, "")
+        for _, size := range sizes {
+            text := "-"
+            if price, ok := prices[strings.ToLower(name)][strings.ToLower(size)]; ok {
+                text = strings.TrimPrefix(FormatRupiah(price), "Rp ")
+            }
+            pdf.CellFormat(sizeWidth, 7, text, "1", 0, "R", false, 0, "")
+        }
+        pdf.Ln(-1)
+    }
+
+    pdf.Ln(3)
+    pdf.SetFont("Arial", "I", 9)
+    pdf.CellFormat(0, 5, "Harga dalam Rupiah per potong. Tanda - berarti ukuran tidak tersedia.", "", 1, "L", false, 0, "")
+    return output(pdf)
+}
@@ -0,0 +1,94 @@
+package documents
+
+import (
+    "fmt"
+    "konveksi-app/models"
+    "strings"
+)
+
+// SizeGuideChart adalah satu tabel ukuran beserta seragam yang memakainya
+type SizeGuideChart struct {
+    Chart    models.SizeChart
+    Uniforms []string
+}
+
+func formatSizeRange(r *models.SizeRange) string {
+    switch {
+    case r == nil:
+        return "-"
+    case r.Min > 0 && r.Max > 0:
+        return fmt.Sprintf("%g - %g", r.Min, r.Max)
+    case r.Min > 0:
+        return fmt.Sprintf(">= %g", r.Min)
+    case r.Max > 0:
+        return fmt.Sprintf("<= %g", r.Max)
+    }
+    return "-"
+}
+
+// SizeGuide membuat PDF panduan ukuran untuk orang tua siswa; customer boleh kosong
+func SizeGuide(customerName string, charts []SizeGuideChart) ([]byte, error) {
+    pdf := newPDF("P")
+    tr := pdf.UnicodeTranslatorFromDescriptor("")
+    pdf.AddPage()
+    writeHeader(pdf, "PANDUAN UKURAN SERAGAM")
+
+    pdf.SetFont("Arial", "", 10)
+    if customerName != "" {
+        pdf.CellFormat(30, 6, "Sekolah", "", 0, "L", false, 0, "")
+        pdf.CellFormat(0, 6, ": "+tr(customerName), "", 1, "L", false, 0, "")
+    }
+    pdf.MultiCell(0, 5, tr("Ukur badan anak dengan pita ukur tanpa baju tebal. Lingkar dada diukur di bagian terlebar, "+
+        "lingkar pinggang di atas pusar, lingkar pinggul di bagian terlebar. Jika ukuran berada di antara dua ukuran, "+
+        "pilih ukuran yang lebih besar. Semua ukuran dalam cm."), "", "L", false)
+    pdf.Ln(3)
+
+    if len(charts) == 0 {
+        pdf.CellFormat(0, 8, "Belum ada tabel ukuran.", "", 1, "L", false, 0, "")
+        return output(pdf)
+    }
+
+    widths := []float64{20, 32, 32, 32, 32, 32}
+    headers := []string{"Ukuran", "Tinggi Badan", "Lingkar Dada", "Lingkar Pinggang", "Lingkar Pinggul", "Ket."}
+    _, pageHeight := pdf.GetPageSize()
+    for _, guide := range charts {
+        rowsHeight := float64(len(guide.Chart.Entries)+1)*7 + 20
+        if pdf.GetY()+rowsHeight > pageHeight-15 && rowsHeight < pageHeight-40 {
+            pdf.AddPage()
+        }
+
+        pdf.SetFont("Arial", "B", 11)
+        pdf.CellFormat(0, 7, tr(guide.Chart.Name), "", 1, "L", false, 0, "")
+        pdf.SetFont("Arial", "", 9)
+        if len(guide.Uniforms) > 0 {
+            pdf.MultiCell(0, 5, tr("Untuk: "+strings.Join(guide.Uniforms, ", ")), "", "L", false)
+        }
+        if guide.Chart.Notes != "" {
+            pdf.MultiCell(0, 5, tr(guide.Chart.Notes), "", "L", false)
+        }
+        pdf.Ln(1)
+
+        pdf.SetFont("Arial", "B", 9)
+        pdf.SetFillColor(230, 230, 230)
+        for i, h := range headers {
+            pdf.CellFormat(widths[i], 7, h, "1", 0, "C", true, 0, "")
+        }
+        pdf.Ln(-1)
+
+        pdf.SetFont("Arial", "", 9)
+        for _, e := range guide.Chart.Entries {
+            values := []string{e.Size, formatSizeRange(e.Height), formatSizeRange(e.Chest),
+                formatSizeRange(e.Waist), formatSizeRange(e.Hip), e.Notes}
+            for i, v := range values {
+                align := "C"
+                if i == len(values)-1 {
+                    align = "L"
+                }
+                pdf.CellFormat(widths[i], 7, tr(v), "1", 0, align, false, 0, "")
+            }
+            pdf.Ln(-1)
+        }
+        pdf.Ln(5)
+    }
+    return output(pdf)
+}
@@ -0,0 +1,211 @@
+package documents
+
+import (
+    "fmt"
+    "konveksi-app/models"
+    "strings"
+    "time"
+)
+
+// WorkOrderLine adalah satu baris SPK; StudentName kosong untuk pesanan biasa
+type WorkOrderLine struct {
+    StudentName string
+    Grade       string
+    UniformName string
+    Size        string
+    Quantity    int
+    Notes       string
+    Measurement *models.StudentMeasurement
+}
+
+// WorkOrderRecap adalah total per seragam dan ukuran untuk bagian potong
+type WorkOrderRecap struct {
+    UniformName string
+    Size        string
+    Quantity    int
+}
+
+var stageLabels = map[string]string{
+    "antri":      "Antri",
+    "potong":     "Potong",
+    "jahit":      "Jahit",
+    "finishing":  "Finishing",
+    "selesai":    "Selesai",
+    "diserahkan": "Diserahkan",
+}
+
+func formatMeasurement(m *models.StudentMeasurement) string {
+    fields := []struct {
+        label string
+        value *float64
+    }{
+        {"Dada", m.Chest}, {"Pinggang", m.Waist}, {"Pinggul", m.Hip}, {"Bahu", m.Shoulder},
+        {"Lengan", m.Sleeve}, {"Pj. Baju", m.ShirtLength}, {"Pj. Celana", m.TrouserLength},
+    }
+    var parts []string
+    for _, f := range fields {
+        if f.value != nil {
+            parts = append(parts, fmt.Sprintf("%s %g", f.label, *f.value))
+        }
+    }
+    if len(parts) == 0 {
+        return ""
+    }
+    text := strings.Join(parts, "  |  ") + " " + m.Unit
+    if m.Notes != "" {
+        text += " - " + m.Notes
+    }
+    return text
+}
+
+// WorkOrder membuat PDF surat perintah kerja (SPK) untuk bagian produksi: tanpa harga,
+// dengan barcode nomor transaksi untuk update progres lewat scanner
+func WorkOrder(trx *models.Transaksi, lines []WorkOrderLine, recap []WorkOrderRecap) ([]byte, error) {
+    pdf := newPDF("P")
+    tr := pdf.UnicodeTranslatorFromDescriptor("")
+    pdf.AddPage()
+    writeHeader(pdf, "SURAT PERINTAH KERJA (SPK)")
+
+    top := pdf.GetY()
+    if err := drawCode39(pdf, 125, top, 70, 14, fmt.Sprintf("%d", trx.ID)); err != nil {
+        return nil, err
+    }
+    pdf.SetXY(125, top+14)
+    pdf.SetFont("Arial", "", 8)
+    pdf.CellFormat(70, 4, fmt.Sprintf("%d", trx.ID), "", 0, "C", false, 0, "")
+    pdf.SetXY(15, top)
+
+    stage := stageLabels[trx.ProductionStage]
+    if stage == "" {
+        stage = trx.ProductionStage
+    }
+    pdf.SetFont("Arial", "", 10)
+    for _, row := range [][2]string{
+        {"No. SPK", fmt.Sprintf("#%d", trx.ID)},
+        {"Customer", trx.Customer_name},
+        {"Tgl Pesan", FormatDate(trx.CreatedAt)},
+        {"Target Selesai", FormatDate(trx.Transaksidate)},
+        {"Tahap", stage},
+    } {
+        pdf.CellFormat(30, 6, row[0], "", 0, "L", false, 0, "")
+        pdf.CellFormat(75, 6, ": "+tr(row[1]), "", 1, "L", false, 0, "")
+    }
+    if strings.TrimSpace(trx.Notes) != "" {
+        pdf.CellFormat(30, 6, "Catatan", "", 0, "L", false, 0, "")
+        pdf.MultiCell(150, 6, ": "+tr(trx.Notes), "", "L", false)
+    }
+    pdf.Ln(4)
+
+    students := false
+    for _, l := range lines {
+        if l.StudentName != "" {
+            students = true
+            break
+        }
+    }
+    var widths []float64
+    var headers []string
+    if students {
+        widths = []float64{8, 48, 14, 44, 17, 12, 37}
+        headers = []string{"No", "Nama Siswa", "Kelas", "Seragam", "Ukuran", "Jml", "Catatan"}
+    } else {
+        widths = []float64{8, 72, 25, 17, 58}
+        headers = []string{"No", "Seragam", "Ukuran", "Jml", "Catatan"}
+    }
+
+    // header tabel diulang di setiap halaman supaya kolom tetap terbaca di lantai produksi
+    header := func() {
+        pdf.SetFont("Arial", "B", 9)
+        pdf.SetFillColor(230, 230, 230)
+        for i, h := range headers {
+            pdf.CellFormat(widths[i], 7, h, "1", 0, "C", true, 0, "")
+        }
+        pdf.Ln(-1)
+    }
+    header()
+
+    _, pageHeight := pdf.GetPageSize()
+    total := 0
+    for i, l := range lines {
+        measurement := ""
+        if l.Measurement != nil {
+            measurement = formatMeasurement(l.Measurement)
+        }
+        // baris dan ukuran badannya dijaga di halaman yang sama
+        rowHeight := 7.0
+        if measurement != "" {
+            rowHeight += 6
+        }
+        if pdf.GetY()+rowHeight > pageHeight-15 {
+            pdf.AddPage()
+            header()
+        }
+        pdf.SetFont("Arial", "", 9)
+        var values []string
+        if students {
+            values = []string{fmt.Sprintf("%d", i+1), l.StudentName, l.Grade, l.UniformName, l.Size, fmt.Sprintf("%d", l.Quantity), l.Notes}
+        } else {
+            values = []string{fmt.Sprintf("%d", i+1), l.UniformName, l.Size, fmt.Sprintf("%d", l.Quantity), l.Notes}
+        }
+        for j, v := range values {
+            align := "L"
+            if j == 0 || headers[j] == "Ukuran" || headers[j] == "Jml" || headers[j] == "Kelas" {
+                align = "C"
+            }
+            pdf.CellFormat(widths[j], 7, tr(v), "1", 0, align, false, 0, "")
+        }
+        pdf.Ln(-1)
+        if measurement != "" {
+            pdf.SetFont("Arial", "I", 8)
+            pdf.CellFormat(widths[0], 6, "", "LB", 0, "C", false, 0, "")
+            pdf.CellFormat(180-widths[0], 6, tr("Ukuran badan: "+measurement), "RB", 1, "L", false, 0, "")
+        }
+        total += l.Quantity
+    }
+    pdf.SetFont("Arial", "B", 9)
+    pdf.CellFormat(0, 7, fmt.Sprintf("Total: %d pcs", total), "", 1, "R", false, 0, "")
+
+    if len(recap) > 0 {
+        if pdf.GetY()+float64(len(recap)+2)*7 > pageHeight-55 && len(recap) < 30 {
+            pdf.AddPage()
+        }
+        pdf.Ln(3)
+        pdf.SetFont("Arial", "B", 11)
+        pdf.CellFormat(0, 8, "Rekap Potong", "", 1, "L", false, 0, "")
+        recapHeader := func() {
+            pdf.SetFont("Arial", "B", 9)
+            pdf.CellFormat(80, 7, "Seragam", "1", 0, "C", true, 0, "")
+            pdf.CellFormat(30, 7, "Ukuran", "1", 0, "C", true, 0, "")
+            pdf.CellFormat(25, 7, "Jumlah", "1", 1, "C", true, 0, "")
+            pdf.SetFont("Arial", "", 9)
+        }
+        recapHeader()
+        for _, r := range recap {
+            if pdf.GetY()+7 > pageHeight-15 {
+                pdf.AddPage()
+                recapHeader()
+            }
+            pdf.CellFormat(80, 7, tr(r.UniformName), "1", 0, "L", false, 0, "")
+            pdf.CellFormat(30, 7, tr(r.Size), "1", 0, "C", false, 0, "")
+            pdf.CellFormat(25, 7, fmt.Sprintf("%d", r.Quantity), "1", 1, "C", false, 0, "")
+        }
+    }
+
+    // Paraf per tahap produksi
+    if pdf.GetY()+35 > pageHeight-15 {
+        pdf.AddPage()
+    }
+    pdf.Ln(6)
+    pdf.SetFont("Arial", "B", 9)
+    for _, stage := range []string{"Potong", "Jahit", "Finishing", "QC / Selesai"} {
+        pdf.CellFormat(45, 7, stage, "1", 0, "C", true, 0, "")
+    }
+    pdf.Ln(-1)
+    for i := 0; i < 4; i++ {
+        pdf.CellFormat(45, 20, "", "1", 0, "C", false, 0, "")
+    }
+    pdf.Ln(-1)
+    pdf.SetFont("Arial", "", 8)
+    pdf.CellFormat(0, 6, "Dicetak: "+time.Now().Format("02.01.2006 15:04"), "", 1, "L", false, 0, "")
+    return output(pdf)
+}
@@ -0,0 +1,202 @@
+package handlers
+
+import (
+    "crypto/subtle"
+    "encoding/json"
+    "fmt"
+    "konveksi-app/repositories"
+    "log"
+    "net/http"
+    "strings"
+    "time"
+)
+
+type CalendarHandler struct {
+    Repo *repositories.CalendarRepository
+    // Token untuk feed .ics (query ?token=), feed nonaktif jika kosong
+    FeedToken string
+}
+
+type calendarEventJSON struct {
+    ID            string                 `json:"id"`
+    Title         string                 `json:"title"`
+    Start         string                 `json:"start"`
+    AllDay        bool                   `json:"allDay"`
+    Color         string                 `json:"color"`
+    URL           string                 `json:"url"`
+    ExtendedProps map[string]interface{} `json:"extendedProps"`
+}
+
+func calendarEventTitle(e repositories.CalendarEvent) string {
+    if e.Kind == "payment" {
+        return fmt.Sprintf("Jatuh tempo: %s (#%d)", e.CustomerName, e.TransactionID)
+    }
+    return fmt.Sprintf("Target: %s (#%d, %d item)", e.CustomerName, e.TransactionID, e.ItemCount)
+}
+
+func calendarEventURL(e repositories.CalendarEvent) string {
+    if e.StudentOrder {
+        return fmt.Sprintf("/detailpesanan?id=%d", e.TransactionID)
+    }
+    return fmt.Sprintf("/detailpesananperitem?id=%d", e.TransactionID)
+}
+
+// parseCalendarRange membaca ?from=&to= (YYYY-MM-DD atau ISO datetime dari FullCalendar).
+// Default: 1 bulan ke belakang sampai 3 bulan ke depan.
+func parseCalendarRange(r *http.Request) (string, string, error) {
+    now := time.Now()
+    from := now.AddDate(0, -1, 0)
+    to := now.AddDate(0, 3, 0)
+
+    parse := func(value string) (time.Time, error) {
+        if len(value) >= 10 {
+            value = value[:10]
+        }
+        return time.Parse("2006-01-02", value)
+    }
+
+    if v := r.URL.Query().Get("from"); v != "" {
+        t, err := parse(v)
+        if err != nil {
+            return "", "", fmt.Errorf("parameter from tidak valid")
+        }
+        from = t
+    }
+    if v := r.URL.Query().Get("to"); v != "" {
+        t, err := parse(v)
+        if err != nil {
+            return "", "", fmt.Errorf("parameter to tidak valid")
+        }
+        to = t
+    }
+    if to.Before(from) {
+        return "", "", fmt.Errorf("parameter to harus setelah from")
+    }
+    return from.Format("2006-01-02"), to.Format("2006-01-02"), nil
+}
+
+// GetEvents - GET /api/calendar/events?from=&to= (format event FullCalendar)
+func (h *CalendarHandler) GetEvents(w http.ResponseWriter, r *http.Request) {
+    from, to, err := parseCalendarRange(r)
+    if err != nil {
+        http.Error(w, err.Error(), http.StatusBadRequest)
+        return
+    }
+
+    events, err := h.Repo.GetEvents(from, to)
+    if err != nil {
+        log.Printf("Error getting calendar events: %v", err)
+        http.Error(w, err.Error(), http.StatusInternalServerError)
+        return
+    }
+
+    response := []calendarEventJSON{}
+    for _, e := range events {
+        color := "#3b82f6"
+        if e.Kind == "payment" {
+            color = "#f59e0b"
+        }
+        if e.Date < time.Now().Format("2006-01-02") && e.Status == "pending" {
+            color = "#ef4444"
+        }
+
+        response = append(response, calendarEventJSON{
+            ID:     fmt.Sprintf("%s-%d", e.Kind, e.TransactionID),
+            Title:  calendarEventTitle(e),
+            Start:  e.Date,
+            AllDay: true,
+            Color:  color,
+            URL:    calendarEventURL(e),
+            ExtendedProps: map[string]interface{}{
+                "kind":           e.Kind,
+                "transaction_id": e.TransactionID,
+                "customer_id":    e.CustomerID,
+                "customer_name":  e.CustomerName,
+                "item_count":     e.ItemCount,
+                "status":         e.Status,
+                "total_price":    e.Total,
+            },
+        })
+    }
+
+    w.Header().Set("Content-Type", "application/json")
+    json.NewEncoder(w).Encode(response)
+}
+
+// ICSFeed - GET /calendar.ics?token=... untuk langganan kalender di HP
+func (h *CalendarHandler) ICSFeed(w http.ResponseWriter, r *http.Request) {
+    token := r.URL.Query().Get("token")
+    if h.FeedToken == "" || subtle.ConstantTimeCompare([]byte(token), []byte(h.FeedToken)) != 1 {
+        http.Error(w, "Not found", http.StatusNotFound)
+        return
+    }
+
+    // Feed mencakup 3 bulan ke belakang sampai 1 tahun ke depan
+    now := time.Now()
+    events, err := h.Repo.GetEvents(now.AddDate(0, -3, 0).Format("2006-01-02"), now.AddDate(1, 0, 0).Format("2006-01-02"))
+    if err != nil {
+        log.Printf("Error getting calendar feed: %v", err)
+        http.Error(w, "Failed to load calendar", http.StatusInternalServerError)
+        return
+    }
+
+    host := r.Host
+    if host == "" {
+        host = "diolif.local"
+    }
+    stamp := now.UTC().Format("20060102T150405Z")
+
+    var b strings.Builder
+    writeICSLine(&b, "BEGIN:VCALENDAR")
+    writeICSLine(&b, "VERSION:2.0")
+    writeICSLine(&b, "PRODID:-//DiOlif Fashion//Konveksi App//ID")
+    writeICSLine(&b, "CALSCALE:GREGORIAN")
+    writeICSLine(&b, "METHOD:PUBLISH")
+    writeICSLine(&b, "X-WR-CALNAME:DiOlif - Produksi & Pembayaran")
+    writeICSLine(&b, "X-WR-TIMEZONE:Asia/Jakarta")
+
+    for _, e := range events {
+        date, err := time.Parse("2006-01-02", e.Date)
+        if err != nil {
+            continue
+        }
+        description := fmt.Sprintf("Customer: %s\nJumlah item: %d\nStatus: %s\nTotal: %.0f",
+            e.CustomerName, e.ItemCount, e.Status, e.Total)
+
+        writeICSLine(&b, "BEGIN:VEVENT")
+        writeICSLine(&b, fmt.Sprintf("UID:%s-%d@%s", e.Kind, e.TransactionID, host))
+        writeICSLine(&b, "DTSTAMP:"+stamp)
+        writeICSLine(&b, "DTSTART;VALUE=DATE:"+date.Format("20060102"))
+        writeICSLine(&b, "DTEND;VALUE=DATE:"+date.AddDate(0, 0, 1).Format("20060102"))
+        writeICSLine(&b, "SUMMARY:"+escapeICSText(calendarEventTitle(e)))
+        writeICSLine(&b, "DESCRIPTION:"+escapeICSText(description))
+        writeICSLine(&b, "CATEGORIES:"+strings.ToUpper(e.Kind))
+        writeICSLine(&b, "URL:http://"+host+calendarEventURL(e))
+        writeICSLine(&b, "END:VEVENT")
+    }
+    writeICSLine(&b, "END:VCALENDAR")
+
+    w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+    w.Header().Set("Content-Disposition", "inline; filename=\"diolif.ics\"")
+    w.Write([]byte(b.String()))
+}
+
+var icsEscaper = strings.NewReplacer("\\", "\\\\", ";", "\\;", ",", "\\,", "\n", "\\n", "\r", "")
+
+func escapeICSText(s string) string {
+    return icsEscaper.Replace(s)
+}
+
+// writeICSLine menulis satu baris iCalendar dengan folding 75 oktet (RFC 5545)
+func writeICSLine(b *strings.Builder, line string) {
+    for len(line) > 75 {
+        cut := 75
+        // jangan memotong di tengah karakter UTF-8
+        for cut > 0 && line[cut]&0xC0 == 0x80 {
+            cut--
+        }
+        b.WriteString(line[:cut] + "\r\n ")
+        line = line[cut:]
+    }
+    b.WriteString(line + "\r\n")
+}
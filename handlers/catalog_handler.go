@@ -0,0 +1,189 @@
+package handlers
+
+import (
+    "database/sql"
+    "encoding/json"
+    "konveksi-app/models"
+    "konveksi-app/repositories"
+    "log"
+    "net/http"
+    "strconv"
+    "strings"
+
+    "github.com/gorilla/mux"
+)
+
+type CatalogHandler struct {
+    Repo *repositories.CatalogRepository
+}
+
+// GetCatalog - GET /api/catalog?all=true (all=true termasuk produk nonaktif)
+func (h *CatalogHandler) GetCatalog(w http.ResponseWriter, r *http.Request) {
+    items, err := h.Repo.GetAll(r.URL.Query().Get("all") == "true")
+    if err != nil {
+        log.Printf("Error getting catalog: %v", err)
+        writeJSONError(w, http.StatusInternalServerError, "Gagal mengambil katalog", err)
+        return
+    }
+    w.Header().Set("Content-Type", "application/json")
+    json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "data": items})
+}
+
+// GetCatalogItem - GET /api/catalog/{id}
+func (h *CatalogHandler) GetCatalogItem(w http.ResponseWriter, r *http.Request) {
+    id, err := strconv.Atoi(mux.Vars(r)["id"])
+    if err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Invalid ID", nil)
+        return
+    }
+    item, err := h.Repo.GetByID(id)
+    if err == sql.ErrNoRows {
+        writeJSONError(w, http.StatusNotFound, "Produk katalog tidak ditemukan", nil)
+        return
+    } else if err != nil {
+        writeJSONError(w, http.StatusInternalServerError, "Gagal mengambil produk katalog", err)
+        return
+    }
+    w.Header().Set("Content-Type", "application/json")
+    json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "data": item})
+}
+
+func decodeCatalogItem(r *http.Request) (*models.CatalogItem, error) {
+    item := models.CatalogItem{Active: true}
+    if err := json.NewDecoder(r.Body).Decode(&item); err != nil {
+        return nil, err
+    }
+    item.Name = strings.Join(strings.Fields(item.Name), " ")
+    return &item, nil
+}
+
+// CreateCatalogItem - POST /api/catalog
+func (h *CatalogHandler) CreateCatalogItem(w http.ResponseWriter, r *http.Request) {
+    item, err := decodeCatalogItem(r)
+    if err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Invalid JSON", err)
+        return
+    }
+    if item.Name == "" {
+        writeJSONError(w, http.StatusBadRequest, "Nama produk wajib diisi", nil)
+        return
+    }
+    if err := h.Repo.Create(item); err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Gagal menyimpan produk katalog", err)
+        return
+    }
+    w.Header().Set("Content-Type", "application/json")
+    w.WriteHeader(http.StatusCreated)
+    json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "data": item})
+}
+
+// UpdateCatalogItem - PUT /api/catalog/{id} (sizes menggantikan daftar ukuran lama)
+func (h *CatalogHandler) UpdateCatalogItem(w http.ResponseWriter, r *http.Request) {
+    id, err := strconv.Atoi(mux.Vars(r)["id"])
+    if err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Invalid ID", nil)
+        return
+    }
+    item, err := decodeCatalogItem(r)
+    if err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Invalid JSON", err)
+        return
+    }
+    if item.Name == "" {
+        writeJSONError(w, http.StatusBadRequest, "Nama produk wajib diisi", nil)
+        return
+    }
+    item.ID = id
+    if err := h.Repo.Update(item); err == sql.ErrNoRows {
+        writeJSONError(w, http.StatusNotFound, "Produk katalog tidak ditemukan", nil)
+        return
+    } else if err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Gagal menyimpan produk katalog", err)
+        return
+    }
+    w.Header().Set("Content-Type", "application/json")
+    json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "data": item})
+}
+
+// DeleteCatalogItem - DELETE /api/catalog/{id}
+func (h *CatalogHandler) DeleteCatalogItem(w http.ResponseWriter, r *http.Request) {
+    id, err := strconv.Atoi(mux.Vars(r)["id"])
+    if err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Invalid ID", nil)
+        return
+    }
+    if err := h.Repo.Delete(id); err == sql.ErrNoRows {
+        writeJSONError(w, http.StatusNotFound, "Produk katalog tidak ditemukan", nil)
+        return
+    } else if err != nil {
+        writeJSONError(w, http.StatusInternalServerError, "Gagal menghapus produk katalog", err)
+        return
+    }
+    w.WriteHeader(http.StatusNoContent)
+}
+
+// GetLinkSuggestions - GET /api/catalog/link-suggestions?min_score=0.6
+// Usulan pencocokan daftar harga lama (teks bebas) ke katalog untuk dikonfirmasi admin.
+func (h *CatalogHandler) GetLinkSuggestions(w http.ResponseWriter, r *http.Request) {
+    minScore := 0.6
+    if v := r.URL.Query().Get("min_score"); v != "" {
+        score, err := strconv.ParseFloat(v, 64)
+        if err != nil || score < 0 || score > 1 {
+            writeJSONError(w, http.StatusBadRequest, "min_score harus antara 0 dan 1", nil)
+            return
+        }
+        minScore = score
+    }
+
+    suggestions, err := h.Repo.GetLinkSuggestions(minScore)
+    if err != nil {
+        log.Printf("Error getting catalog link suggestions: %v", err)
+        writeJSONError(w, http.StatusInternalServerError, "Gagal membuat usulan pencocokan", err)
+        return
+    }
+
+    matched := 0
+    for _, s := range suggestions {
+        if s.CatalogID != 0 {
+            matched++
+        }
+    }
+    w.Header().Set("Content-Type", "application/json")
+    json.NewEncoder(w).Encode(map[string]interface{}{
+        "success":   true,
+        "data":      suggestions,
+        "unlinked":  len(suggestions),
+        "matched":   matched,
+        "min_score": minScore,
+    })
+}
+
+// ConfirmLinks - POST /api/catalog/link-suggestions/confirm
+// body: {"links": [{"customer_uniform_id": 1, "catalog_id": 2, "rename": true}]}
+func (h *CatalogHandler) ConfirmLinks(w http.ResponseWriter, r *http.Request) {
+    var req struct {
+        Links []repositories.CatalogLink `json:"links"`
+    }
+    if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Invalid JSON", err)
+        return
+    }
+    if len(req.Links) == 0 {
+        writeJSONError(w, http.StatusBadRequest, "Tidak ada baris yang dikonfirmasi", nil)
+        return
+    }
+
+    linked, renamed, err := h.Repo.ApplyLinks(req.Links)
+    if err != nil {
+        log.Printf("Error applying catalog links: %v", err)
+        writeJSONError(w, http.StatusBadRequest, "Gagal menghubungkan ke katalog", err)
+        return
+    }
+
+    w.Header().Set("Content-Type", "application/json")
+    json.NewEncoder(w).Encode(map[string]interface{}{
+        "success": true,
+        "linked":  linked,
+        "renamed": renamed,
+    })
+}
@@ -1,9 +1,11 @@
 package handlers
 
 import (
+	"database/sql"
 	"encoding/json"
 	"net/http"
 	"strconv"
+	"time"
 	"konveksi-app/models"
 	"konveksi-app/repositories"
 
@@ -19,6 +21,7 @@ func (h *CustomerHandler) CreateCustomer(w http.ResponseWriter, r *http.Request)
         Name     string                  `json:"name"`
         Type     string                  `json:"type"`
         Contact  string                  `json:"contact"`
+        Email    string                  `json:"email"`
         Address  string                  `json:"address"`
         Uniforms []models.CustomerUniform `json:"uniforms"`
     }
@@ -30,6 +33,7 @@ func (h *CustomerHandler) CreateCustomer(w http.ResponseWriter, r *http.Request)
         Name: req.Name,
         Type: req.Type,
         Contact: req.Contact,
+        Email: req.Email,
         Address: req.Address,
     }
     if err := h.Repo.CreateWithUniforms(&customer, req.Uniforms); err != nil {
@@ -99,12 +103,30 @@ func (h *CustomerHandler) UpdateCustomer(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	var customer models.Customer
-	if err := json.NewDecoder(r.Body).Decode(&customer); err != nil {
+	// Email pointer supaya field yang tidak dikirim tidak menghapus email yang tersimpan
+	var req struct {
+		models.Customer
+		Email *string `json:"email"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	customer := req.Customer
 	customer.ID = id
+	if req.Email != nil {
+		customer.Email = *req.Email
+	} else {
+		existing, err := h.Repo.GetByID(id)
+		if err == sql.ErrNoRows {
+			http.Error(w, "Customer not found", http.StatusNotFound)
+			return
+		} else if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		customer.Email = existing.Email
+	}
 
 	if err := h.Repo.Update(&customer); err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -142,6 +164,17 @@ func (h *CustomerHandler) GetUniformsByCustomerID(w http.ResponseWriter, r *http
         http.Error(w, err.Error(), http.StatusInternalServerError)
         return
     }
+    // ?date=YYYY-MM-DD: harga yang berlaku pada tanggal tersebut (untuk form pesanan)
+    if date := r.URL.Query().Get("date"); date != "" {
+        if _, err := time.Parse("2006-01-02", date); err != nil {
+            http.Error(w, "Invalid date", http.StatusBadRequest)
+            return
+        }
+        if err := h.Repo.ApplyPricesOnDate(uniforms, date); err != nil {
+            http.Error(w, err.Error(), http.StatusInternalServerError)
+            return
+        }
+    }
     w.Header().Set("Content-Type", "application/json")
     json.NewEncoder(w).Encode(uniforms)
 }
@@ -169,12 +202,13 @@ func (h *CustomerHandler) UpdateCustomerUniform(w http.ResponseWriter, r *http.R
         Size        string  `json:"size"`
         Price       float64 `json:"price"`
         Notes       string  `json:"notes"`
+        Reason      string  `json:"reason"`
     }
     if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
         http.Error(w, "Invalid request", http.StatusBadRequest)
         return
     }
-    err := h.Repo.UpdateCustomerUniformWithHistory(id, req.UniformName, req.Size, req.Price, req.Notes)
+    err := h.Repo.UpdateCustomerUniformWithHistory(id, req.UniformName, req.Size, req.Price, req.Notes, GetSessionUsername(r), req.Reason)
     if err != nil {
         http.Error(w, err.Error(), http.StatusInternalServerError)
         return
@@ -0,0 +1,68 @@
+package handlers
+
+import (
+    "konveksi-app/repositories"
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "testing"
+
+    "github.com/DATA-DOG/go-sqlmock"
+    "github.com/gorilla/mux"
+)
+
+func TestUpdateCustomerKeepsEmailWhenOmitted(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer db.Close()
+    h := &CustomerHandler{Repo: &repositories.CustomerRepository{DB: db}}
+
+    mock.ExpectQuery("FROM customers WHERE id").WithArgs(4).WillReturnRows(
+        sqlmock.NewRows([]string{"id", "name", "type", "contact", "email", "address", "created_at"}).
+            AddRow(4, "SD Ceria", "SD", "0812", "tu@sdceria.sch.id", "Jl. Melati", "2025-01-01"))
+    mock.ExpectExec("UPDATE customers").
+        WithArgs("SD Ceria Baru", "SD", "0812", "tu@sdceria.sch.id", "Jl. Melati", 4).
+        WillReturnResult(sqlmock.NewResult(0, 1))
+
+    body := `{"name": "SD Ceria Baru", "type": "SD", "contact": "0812", "address": "Jl. Melati"}`
+    req := mux.SetURLVars(httptest.NewRequest("PUT", "/api/customers/4", strings.NewReader(body)), map[string]string{"id": "4"})
+    rec := httptest.NewRecorder()
+    h.UpdateCustomer(rec, req)
+
+    if rec.Code != http.StatusOK {
+        t.Fatalf("status %d: %s", rec.Code, rec.Body.String())
+    }
+    if !strings.Contains(rec.Body.String(), `"email":"tu@sdceria.sch.id"`) {
+        t.Fatalf("email hilang dari respons: %s", rec.Body.String())
+    }
+    if err := mock.ExpectationsWereMet(); err != nil {
+        t.Fatal(err)
+    }
+}
+
+func TestUpdateCustomerClearsEmailWhenEmpty(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer db.Close()
+    h := &CustomerHandler{Repo: &repositories.CustomerRepository{DB: db}}
+
+    mock.ExpectExec("UPDATE customers").
+        WithArgs("SD Ceria", "SD", "0812", "", "Jl. Melati", 4).
+        WillReturnResult(sqlmock.NewResult(0, 1))
+
+    body := `{"name": "SD Ceria", "type": "SD", "contact": "0812", "email": "", "address": "Jl. Melati"}`
+    req := mux.SetURLVars(httptest.NewRequest("PUT", "/api/customers/4", strings.NewReader(body)), map[string]string{"id": "4"})
+    rec := httptest.NewRecorder()
+    h.UpdateCustomer(rec, req)
+
+    if rec.Code != http.StatusOK {
+        t.Fatalf("status %d: %s", rec.Code, rec.Body.String())
+    }
+    if err := mock.ExpectationsWereMet(); err != nil {
+        t.Fatal(err)
+    }
+}
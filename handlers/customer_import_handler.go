@@ -0,0 +1,417 @@
+package handlers
+
+import (
+    "encoding/json"
+    "fmt"
+    "konveksi-app/models"
+    "konveksi-app/repositories"
+    "konveksi-app/spreadsheet"
+    "log"
+    "net/http"
+    "regexp"
+    "strconv"
+    "strings"
+    "time"
+)
+
+// Alias header kolom file customer + daftar harga (satu baris per seragam/ukuran)
+var customerImportColumns = map[string][]string{
+    "customer_name": {"nama", "nama customer", "nama pelanggan", "pelanggan", "customer", "sekolah", "name"},
+    "type":          {"tipe", "jenis", "jenis customer", "kategori"},
+    "contact":       {"kontak", "telepon", "no hp", "hp", "phone", "telp"},
+    "email":         {"e-mail", "surel"},
+    "address":       {"alamat"},
+    "uniform_name":  {"seragam", "jenis seragam", "nama seragam", "uniform", "uniform name"},
+    "size":          {"ukuran", "uk"},
+    "price":         {"harga", "harga satuan", "unit price"},
+    "notes":         {"catatan", "keterangan", "ket"},
+}
+
+var customerImportRequired = []string{"customer_name"}
+
+var customerExportHeader = []string{"customer_name", "type", "contact", "email", "address", "uniform_name", "size", "price", "notes"}
+
+var customerTypes = []string{"TK", "SD", "SMP", "Kelompok Tadarus", "Lainnya"}
+
+// normalizeCustomerType mencocokkan tipe customer tanpa membedakan huruf besar/kecil
+func normalizeCustomerType(value string) (string, bool) {
+    for _, t := range customerTypes {
+        if strings.EqualFold(strings.TrimSpace(value), t) {
+            return t, true
+        }
+    }
+    return "", false
+}
+
+var rupiahThousands = regexp.MustCompile(`^\d{1,3}(\.\d{3})+(,\d+)?$`)
+
+// parsePrice menerima "125000", "125000.00", "125.000" atau "Rp 125.000,00"
+func parsePrice(value string) (float64, error) {
+    v := strings.TrimSpace(value)
+    v = strings.TrimPrefix(strings.TrimPrefix(v, "Rp"), "rp")
+    v = strings.ReplaceAll(strings.TrimSpace(v), " ", "")
+    if rupiahThousands.MatchString(v) {
+        v = strings.ReplaceAll(v, ".", "")
+    }
+    v = strings.Replace(v, ",", ".", 1)
+    price, err := strconv.ParseFloat(v, 64)
+    if err != nil || price < 0 {
+        return 0, fmt.Errorf("harga '%s' tidak valid", value)
+    }
+    return price, nil
+}
+
+func customerMatchKey(name, contact string) string {
+    return strings.ToLower(strings.Join(strings.Fields(name), " ")) + "|" + strings.TrimSpace(contact)
+}
+
+type customerImportUniform struct {
+    Row         int      `json:"row"`
+    Action      string   `json:"action"` // create, update, skip
+    UniformName string   `json:"uniform_name"`
+    Size        string   `json:"size"`
+    Price       float64  `json:"price"`
+    OldPrice    *float64 `json:"old_price,omitempty"`
+    Notes       string   `json:"notes"`
+    Reason      string   `json:"reason,omitempty"`
+}
+
+type customerImportResult struct {
+    Rows       []int                   `json:"rows"`
+    Action     string                  `json:"action"` // create, update, skip
+    CustomerID int                     `json:"customer_id,omitempty"`
+    Name       string                  `json:"name"`
+    Type       string                  `json:"type"`
+    Contact    string                  `json:"contact"`
+    Email      string                  `json:"email"`
+    Address    string                  `json:"address"`
+    Changes    []string                `json:"changes,omitempty"`
+    Uniforms   []customerImportUniform `json:"uniforms"`
+    Errors     []string                `json:"errors"`
+
+    existing *models.Customer
+    prices   map[string]models.CustomerUniform
+    seen     map[string]int
+}
+
+// ExportCustomers - GET /api/customers/export?format=csv|xlsx
+// Satu baris per seragam/ukuran, customer tanpa daftar harga tetap ditulis satu baris.
+func (h *ImportHandler) ExportCustomers(w http.ResponseWriter, r *http.Request) {
+    format := strings.ToLower(r.URL.Query().Get("format"))
+    if format == "" {
+        format = "xlsx"
+    }
+    if format != "csv" && format != "xlsx" {
+        http.Error(w, "Format harus csv atau xlsx", http.StatusBadRequest)
+        return
+    }
+
+    customers, err := h.Customers.GetAllWithUniforms()
+    if err != nil {
+        log.Printf("Error exporting customers: %v", err)
+        http.Error(w, err.Error(), http.StatusInternalServerError)
+        return
+    }
+
+    rows := [][]string{}
+    for _, c := range customers {
+        base := []string{c.Name, c.Type, c.Contact, c.Email, c.Address}
+        if len(c.Uniforms) == 0 {
+            rows = append(rows, append(base, "", "", "", ""))
+            continue
+        }
+        for _, u := range c.Uniforms {
+            row := append(append([]string{}, base...), u.UniformName, u.Size, strconv.FormatFloat(u.Price, 'f', -1, 64), u.Notes)
+            rows = append(rows, row)
+        }
+    }
+
+    filename := fmt.Sprintf("customers_%s.%s", time.Now().Format("20060102"), format)
+    w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", filename))
+    if format == "csv" {
+        w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+        err = spreadsheet.WriteCSV(w, customerExportHeader, rows)
+    } else {
+        w.Header().Set("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+        err = spreadsheet.WriteXLSX(w, "Customers", customerExportHeader, rows)
+    }
+    if err != nil {
+        log.Printf("Error writing customer export: %v", err)
+    }
+}
+
+// ImportCustomers - POST /api/customers/import
+// multipart: file (CSV/XLSX, format sama dengan export), mapping (JSON opsional),
+// dry_run ("false" untuk menyimpan, default hanya laporan rencana perubahan).
+// Customer dicocokkan berdasarkan nama + kontak (atau nama saja jika kontak kosong dan namanya unik).
+func (h *ImportHandler) ImportCustomers(w http.ResponseWriter, r *http.Request) {
+    if err := r.ParseMultipartForm(10 << 20); err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Upload file gagal", err)
+        return
+    }
+    file, header, err := r.FormFile("file")
+    if err != nil {
+        writeJSONError(w, http.StatusBadRequest, "File customer wajib diupload", err)
+        return
+    }
+    defer file.Close()
+
+    table, err := spreadsheet.Read(header.Filename, file)
+    if err != nil {
+        writeJSONError(w, http.StatusBadRequest, "File tidak dapat dibaca", err)
+        return
+    }
+
+    mapping := map[string]string{}
+    if m := r.FormValue("mapping"); m != "" {
+        if err := json.Unmarshal([]byte(m), &mapping); err != nil {
+            writeJSONError(w, http.StatusBadRequest, "Mapping kolom tidak valid", err)
+            return
+        }
+    }
+
+    columns, missing := table.MapColumns(customerImportColumns, customerImportRequired, mapping)
+    if len(missing) > 0 {
+        w.Header().Set("Content-Type", "application/json")
+        w.WriteHeader(http.StatusBadRequest)
+        json.NewEncoder(w).Encode(map[string]interface{}{
+            "success":         false,
+            "error":           "Kolom wajib tidak ditemukan: " + strings.Join(missing, ", "),
+            "missing_columns": missing,
+            "headers":         table.Header,
+        })
+        return
+    }
+
+    existing, err := h.Customers.GetAllWithUniforms()
+    if err != nil {
+        writeJSONError(w, http.StatusInternalServerError, "Gagal mengambil data customer", err)
+        return
+    }
+    byKey := make(map[string]*models.Customer)
+    byName := make(map[string][]*models.Customer)
+    for i := range existing {
+        c := &existing[i]
+        byKey[customerMatchKey(c.Name, c.Contact)] = c
+        nameKey := customerMatchKey(c.Name, "")
+        byName[nameKey] = append(byName[nameKey], c)
+    }
+
+    results := []*customerImportResult{}
+    resultByKey := make(map[string]*customerImportResult)
+    for i, record := range table.Rows {
+        rowNumber := table.Line(i)
+        name := strings.Join(strings.Fields(spreadsheet.Value(record, columns, "customer_name")), " ")
+        contact := spreadsheet.Value(record, columns, "contact")
+
+        key := customerMatchKey(name, contact)
+        result, ok := resultByKey[key]
+        if !ok {
+            result = &customerImportResult{
+                Name:     name,
+                Contact:  contact,
+                Uniforms: []customerImportUniform{},
+                Errors:   []string{},
+                prices:   make(map[string]models.CustomerUniform),
+                seen:     make(map[string]int),
+            }
+            if c, found := byKey[key]; found {
+                result.existing = c
+            } else if matches := byName[customerMatchKey(name, "")]; contact == "" && len(matches) == 1 {
+                result.existing = matches[0]
+            }
+            if result.existing != nil {
+                result.CustomerID = result.existing.ID
+                for _, u := range result.existing.Uniforms {
+                    result.prices[priceKey(u.UniformName, u.Size)] = u
+                }
+            }
+            if name == "" {
+                result.Errors = append(result.Errors, fmt.Sprintf("baris %d: nama customer kosong", rowNumber))
+            }
+            resultByKey[key] = result
+            results = append(results, result)
+        }
+        result.Rows = append(result.Rows, rowNumber)
+
+        // Data customer diambil dari baris pertama yang mengisi kolom tersebut
+        if v := spreadsheet.Value(record, columns, "type"); v != "" && result.Type == "" {
+            if t, valid := normalizeCustomerType(v); valid {
+                result.Type = t
+            } else {
+                result.Errors = append(result.Errors, fmt.Sprintf("baris %d: tipe '%s' tidak dikenal (%s)", rowNumber, v, strings.Join(customerTypes, ", ")))
+            }
+        }
+        if v := spreadsheet.Value(record, columns, "email"); v != "" && result.Email == "" {
+            result.Email = v
+        }
+        if v := spreadsheet.Value(record, columns, "address"); v != "" && result.Address == "" {
+            result.Address = v
+        }
+
+        uniform := customerImportUniform{
+            Row:         rowNumber,
+            UniformName: spreadsheet.Value(record, columns, "uniform_name"),
+            Size:        spreadsheet.Value(record, columns, "size"),
+            Notes:       spreadsheet.Value(record, columns, "notes"),
+        }
+        priceValue := spreadsheet.Value(record, columns, "price")
+        if uniform.UniformName == "" && uniform.Size == "" && priceValue == "" {
+            continue // baris data customer saja
+        }
+        if uniform.UniformName == "" || uniform.Size == "" {
+            result.Errors = append(result.Errors, fmt.Sprintf("baris %d: seragam dan ukuran wajib diisi", rowNumber))
+            continue
+        }
+        price, err := parsePrice(priceValue)
+        if err != nil {
+            result.Errors = append(result.Errors, fmt.Sprintf("baris %d: %v", rowNumber, err))
+            continue
+        }
+        uniform.Price = price
+
+        // Aturan unik uniform_name + size per customer, juga berlaku di dalam file
+        pk := priceKey(uniform.UniformName, uniform.Size)
+        if first, dup := result.seen[pk]; dup {
+            uniform.Action = "skip"
+            uniform.Reason = fmt.Sprintf("ukuran '%s' untuk seragam '%s' sudah ada di baris %d", uniform.Size, uniform.UniformName, first)
+            result.Uniforms = append(result.Uniforms, uniform)
+            continue
+        }
+        result.seen[pk] = rowNumber
+
+        if current, found := result.prices[pk]; found {
+            oldPrice := current.Price
+            uniform.OldPrice = &oldPrice
+            uniform.UniformName = current.UniformName
+            uniform.Size = current.Size
+            if current.Price == uniform.Price && current.Notes == uniform.Notes {
+                uniform.Action = "skip"
+                uniform.Reason = "tidak ada perubahan"
+            } else {
+                uniform.Action = "update"
+            }
+        } else {
+            uniform.Action = "create"
+        }
+        result.Uniforms = append(result.Uniforms, uniform)
+    }
+
+    summary := map[string]int{
+        "customers_created": 0, "customers_updated": 0, "customers_skipped": 0,
+        "prices_created": 0, "prices_updated": 0, "prices_skipped": 0,
+    }
+    errorCount := 0
+    imports := []repositories.CustomerImport{}
+    importResults := []*customerImportResult{}
+    for _, result := range results {
+        imp := repositories.CustomerImport{}
+        if c := result.existing; c != nil {
+            imp.Customer = *c
+            if result.Type != "" && result.Type != c.Type {
+                result.Changes = append(result.Changes, fmt.Sprintf("type: %s -> %s", c.Type, result.Type))
+                imp.Customer.Type = result.Type
+            }
+            if result.Email != "" && result.Email != c.Email {
+                result.Changes = append(result.Changes, fmt.Sprintf("email: %s -> %s", c.Email, result.Email))
+                imp.Customer.Email = result.Email
+            }
+            if result.Address != "" && result.Address != c.Address {
+                result.Changes = append(result.Changes, "address diperbarui")
+                imp.Customer.Address = result.Address
+            }
+            imp.UpdateCustomer = len(result.Changes) > 0
+            result.Name, result.Type, result.Contact = imp.Customer.Name, imp.Customer.Type, imp.Customer.Contact
+            result.Email, result.Address = imp.Customer.Email, imp.Customer.Address
+        } else {
+            if result.Type == "" {
+                result.Type = "Lainnya"
+            }
+            imp.Customer = models.Customer{
+                Name: result.Name, Type: result.Type, Contact: result.Contact,
+                Email: result.Email, Address: result.Address,
+            }
+        }
+
+        for _, u := range result.Uniforms {
+            cu := models.CustomerUniform{UniformName: u.UniformName, Size: u.Size, Price: u.Price, Notes: u.Notes}
+            switch u.Action {
+            case "create":
+                imp.NewUniforms = append(imp.NewUniforms, cu)
+                summary["prices_created"]++
+            case "update":
+                cu.ID = result.prices[priceKey(u.UniformName, u.Size)].ID
+                imp.UpdatedUniforms = append(imp.UpdatedUniforms, cu)
+                summary["prices_updated"]++
+            default:
+                summary["prices_skipped"]++
+            }
+        }
+
+        switch {
+        case result.existing == nil:
+            result.Action = "create"
+            summary["customers_created"]++
+        case imp.UpdateCustomer || len(imp.NewUniforms) > 0 || len(imp.UpdatedUniforms) > 0:
+            result.Action = "update"
+            summary["customers_updated"]++
+        default:
+            result.Action = "skip"
+            summary["customers_skipped"]++
+        }
+
+        errorCount += len(result.Errors)
+        if result.Action != "skip" {
+            imports = append(imports, imp)
+            importResults = append(importResults, result)
+        }
+    }
+
+    mappedColumns := map[string]string{}
+    for field, i := range columns {
+        mappedColumns[field] = table.Header[i]
+    }
+
+    dryRun := r.FormValue("dry_run") != "false" && r.FormValue("dry_run") != "0"
+    response := map[string]interface{}{
+        "dry_run":     dryRun,
+        "columns":     mappedColumns,
+        "customers":   results,
+        "summary":     summary,
+        "total_rows":  len(table.Rows),
+        "error_count": errorCount,
+        "applied":     false,
+    }
+
+    if dryRun {
+        w.Header().Set("Content-Type", "application/json")
+        json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "data": response})
+        return
+    }
+
+    if errorCount > 0 {
+        w.Header().Set("Content-Type", "application/json")
+        w.WriteHeader(http.StatusUnprocessableEntity)
+        json.NewEncoder(w).Encode(map[string]interface{}{
+            "success": false,
+            "error":   "Perbaiki baris yang error sebelum menyimpan import",
+            "data":    response,
+        })
+        return
+    }
+
+    if err := h.Customers.ApplyImport(imports, GetSessionUsername(r)); err != nil {
+        log.Printf("Error applying customer import: %v", err)
+        writeJSONError(w, http.StatusInternalServerError, "Gagal menyimpan import customer", err)
+        return
+    }
+
+    for i, result := range importResults {
+        result.CustomerID = imports[i].Customer.ID
+    }
+
+    log.Printf("Customer import applied: %v", summary)
+    response["applied"] = true
+    w.Header().Set("Content-Type", "application/json")
+    json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "data": response})
+}
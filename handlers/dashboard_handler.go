@@ -46,6 +46,7 @@ func (h *DashboardHandler) GetDashboardStats(w http.ResponseWriter, r *http.Requ
             "overdue_transactions": stats.OverdueTransactions,
             "reminder_payments":   stats.ReminderPayments,
             "reminder_transactions": stats.ReminderTransactions,
+            "low_stock_materials": stats.LowStockMaterials,
             "total_revenue":       0, // Will be calculated by frontend
         },
     }
@@ -131,6 +132,20 @@ func (h *DashboardHandler) GetNotifications(w http.ResponseWriter, r *http.Reque
         })
     }
 
+    // Low Stock Materials Notification
+    if stats.LowStockMaterials > 0 {
+        notifications = append(notifications, map[string]interface{}{
+            "type":        "low_stock_material",
+            "title":       "Stok Bahan Menipis",
+            "message":     fmt.Sprintf("%d bahan sudah di bawah batas pemesanan ulang", stats.LowStockMaterials),
+            "count":       stats.LowStockMaterials,
+            "icon":        "ph-package",
+            "color":       "warning",
+            "action_url":  "/bahan?filter=low_stock",
+            "created_at":  time.Now().Format("2006-01-02 15:04:05"),
+        })
+    }
+
     response := map[string]interface{}{
         "success": true,
         "data": map[string]interface{}{
@@ -0,0 +1,204 @@
+package handlers
+
+import (
+    "database/sql"
+    "encoding/json"
+    "fmt"
+    "konveksi-app/documents"
+    "konveksi-app/models"
+    "konveksi-app/repositories"
+    "konveksi-app/sizing"
+    "log"
+    "net/http"
+    "strconv"
+    "strings"
+
+    "github.com/gorilla/mux"
+)
+
+type DeliveryHandler struct {
+    Repo         *repositories.DeliveryRepository
+    Transactions *repositories.TransactionRepository
+}
+
+// deliveryNoteLines merekap item kirim per seragam dan ukuran untuk surat jalan, urut sesuai tabel ukuran
+func deliveryNoteLines(charts map[string]sizing.Chart, items []models.DeliveryItem) []documents.DeliveryNoteLine {
+    summary := make(map[string]map[string]int)
+    students := make(map[string]map[string]map[string]bool)
+    for _, item := range items {
+        if summary[item.UniformName] == nil {
+            summary[item.UniformName] = make(map[string]int)
+            students[item.UniformName] = make(map[string]map[string]bool)
+        }
+        if students[item.UniformName][item.Size] == nil {
+            students[item.UniformName][item.Size] = make(map[string]bool)
+        }
+        summary[item.UniformName][item.Size] += item.Quantity
+        if item.StudentName != "" {
+            students[item.UniformName][item.Size][item.StudentName+"|"+item.Grade] = true
+        }
+    }
+    var lines []documents.DeliveryNoteLine
+    for _, name := range sortedUniformNames(summary) {
+        for _, size := range charts[strings.ToLower(strings.TrimSpace(name))].SortedKeys(summary[name]) {
+            line := documents.DeliveryNoteLine{UniformName: name, Size: size, Quantity: summary[name][size]}
+            if n := len(students[name][size]); n > 0 {
+                line.Notes = fmt.Sprintf("%d siswa", n)
+            }
+            lines = append(lines, line)
+        }
+    }
+    return lines
+}
+
+// GetStatus - GET /api/transactions/{id}/deliveries
+// Sisa kirim per item pesanan dan riwayat pengiriman
+func (h *DeliveryHandler) GetStatus(w http.ResponseWriter, r *http.Request) {
+    id, err := strconv.Atoi(mux.Vars(r)["id"])
+    if err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Invalid ID", nil)
+        return
+    }
+    status, err := h.Repo.Status(id)
+    if err == sql.ErrNoRows {
+        writeJSONError(w, http.StatusNotFound, "Transaksi tidak ditemukan", nil)
+        return
+    } else if err != nil {
+        log.Printf("Error getting deliveries for transaction %d: %v", id, err)
+        writeJSONError(w, http.StatusInternalServerError, "Gagal mengambil data pengiriman", err)
+        return
+    }
+    w.Header().Set("Content-Type", "application/json")
+    json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "data": status})
+}
+
+// CreateDelivery - POST /api/transactions/{id}/deliveries
+// body: {"delivery_date": "2025-07-10", "receiver_name": "", "notes": "", "items": [{"item_type": "student", "item_id": 1, "quantity": 2}]}
+// atau {"all": true} untuk mengirim seluruh sisa
+func (h *DeliveryHandler) CreateDelivery(w http.ResponseWriter, r *http.Request) {
+    id, err := strconv.Atoi(mux.Vars(r)["id"])
+    if err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Invalid ID", nil)
+        return
+    }
+    var req struct {
+        DeliveryDate string `json:"delivery_date"`
+        ReceiverName string `json:"receiver_name"`
+        Notes        string `json:"notes"`
+        All          bool   `json:"all"`
+        Items        []struct {
+            ItemType string `json:"item_type"`
+            ItemID   int    `json:"item_id"`
+            Quantity int    `json:"quantity"`
+        } `json:"items"`
+    }
+    if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Invalid JSON", err)
+        return
+    }
+
+    d := models.Delivery{
+        TransactionID: id,
+        DeliveryDate:  req.DeliveryDate,
+        ReceiverName:  req.ReceiverName,
+        Notes:         req.Notes,
+        DeliveredBy:   GetSessionUsername(r),
+    }
+    for _, item := range req.Items {
+        d.Items = append(d.Items, models.DeliveryItem{ItemType: item.ItemType, ItemID: item.ItemID, Quantity: item.Quantity})
+    }
+    change, err := h.Repo.Create(&d, req.All)
+    if err == sql.ErrNoRows {
+        writeJSONError(w, http.StatusNotFound, "Transaksi tidak ditemukan", nil)
+        return
+    } else if err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Gagal mencatat pengiriman", err)
+        return
+    }
+
+    status, err := h.Repo.Status(id)
+    if err != nil {
+        writeJSONError(w, http.StatusInternalServerError, "Gagal mengambil data pengiriman", err)
+        return
+    }
+    w.Header().Set("Content-Type", "application/json")
+    w.WriteHeader(http.StatusCreated)
+    json.NewEncoder(w).Encode(map[string]interface{}{
+        "success": true,
+        "data": map[string]interface{}{
+            "delivery":     d,
+            "status":       status,
+            "stage_change": change,
+        },
+    })
+}
+
+// DeleteDelivery - DELETE /api/deliveries/{id}
+// Jika transaksi sudah diserahkan, tahap kembali ke selesai dan perubahannya dikembalikan di stage_change
+func (h *DeliveryHandler) DeleteDelivery(w http.ResponseWriter, r *http.Request) {
+    id, err := strconv.Atoi(mux.Vars(r)["id"])
+    if err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Invalid ID", nil)
+        return
+    }
+    change, err := h.Repo.Delete(id)
+    if err == sql.ErrNoRows {
+        writeJSONError(w, http.StatusNotFound, "Pengiriman tidak ditemukan", nil)
+        return
+    } else if err != nil {
+        writeJSONError(w, http.StatusConflict, "Gagal menghapus pengiriman", err)
+        return
+    }
+    if change == nil {
+        w.WriteHeader(http.StatusNoContent)
+        return
+    }
+    w.Header().Set("Content-Type", "application/json")
+    json.NewEncoder(w).Encode(map[string]interface{}{
+        "success": true,
+        "data":    map[string]interface{}{"stage_change": change},
+    })
+}
+
+// PrintDeliveryNote - GET /api/deliveries/{id}/delivery-note.pdf
+// Surat jalan untuk satu kali pengiriman, bernomor SJ-{transaksi}-{pengiriman ke}
+func (h *DeliveryHandler) PrintDeliveryNote(w http.ResponseWriter, r *http.Request) {
+    id, err := strconv.Atoi(mux.Vars(r)["id"])
+    if err != nil {
+        http.Error(w, "Invalid ID", http.StatusBadRequest)
+        return
+    }
+    d, err := h.Repo.GetByID(id)
+    if err == sql.ErrNoRows {
+        http.Error(w, "Delivery not found", http.StatusNotFound)
+        return
+    } else if err != nil {
+        http.Error(w, err.Error(), http.StatusInternalServerError)
+        return
+    }
+    trx, err := h.Transactions.GetByIDNormal(d.TransactionID)
+    if err != nil {
+        http.Error(w, err.Error(), http.StatusInternalServerError)
+        return
+    }
+    customer, err := h.Transactions.GetCustomerByID(trx.CustomerID)
+    if err != nil && err != sql.ErrNoRows {
+        http.Error(w, err.Error(), http.StatusInternalServerError)
+        return
+    }
+    charts, err := h.Transactions.SizeCharts(trx.CustomerID)
+    if err != nil {
+        log.Printf("Error getting size charts for customer %d: %v", trx.CustomerID, err)
+    }
+
+    number := fmt.Sprintf("SJ-%d-%d", trx.ID, d.Sequence)
+    pdf, err := documents.DeliveryNote(trx, customer, number, d.DeliveryDate, deliveryNoteLines(charts, d.Items))
+    if err != nil {
+        log.Printf("Error generating delivery note PDF: %v", err)
+        http.Error(w, "Failed to generate PDF", http.StatusInternalServerError)
+        return
+    }
+    w.Header().Set("Content-Type", "application/pdf")
+    w.Header().Set("Content-Disposition", fmt.Sprintf("inline; filename=\"surat_jalan_%d_%d.pdf\"", trx.ID, d.Sequence))
+    w.Write(pdf)
+}
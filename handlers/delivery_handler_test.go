@@ -0,0 +1,67 @@
+package handlers
+
+import (
+    "konveksi-app/repositories"
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "testing"
+
+    "github.com/DATA-DOG/go-sqlmock"
+    "github.com/gorilla/mux"
+)
+
+func TestDeleteDeliveryReturnsStageChange(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer db.Close()
+    h := &DeliveryHandler{Repo: &repositories.DeliveryRepository{DB: db}}
+
+    mock.ExpectBegin()
+    mock.ExpectQuery("FROM deliveries d").WithArgs(9).
+        WillReturnRows(sqlmock.NewRows([]string{"id", "production_stage"}).AddRow(5, "diserahkan"))
+    mock.ExpectExec("DELETE FROM deliveries").WithArgs(9).WillReturnResult(sqlmock.NewResult(0, 1))
+    mock.ExpectExec("UPDATE transactions").WithArgs(5).WillReturnResult(sqlmock.NewResult(0, 1))
+    mock.ExpectCommit()
+
+    req := mux.SetURLVars(httptest.NewRequest("DELETE", "/api/deliveries/9", nil), map[string]string{"id": "9"})
+    rec := httptest.NewRecorder()
+    h.DeleteDelivery(rec, req)
+
+    if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"to":"selesai"`) {
+        t.Fatalf("status = %d, body = %s; want stage change back to selesai", rec.Code, rec.Body.String())
+    }
+    if err := mock.ExpectationsWereMet(); err != nil {
+        t.Fatal(err)
+    }
+}
+
+func TestSetProductionStageLeavingDeliveredIsBadRequest(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer db.Close()
+    h := &ProductionHandler{Repo: &repositories.ProductionRepository{DB: db}}
+
+    mock.ExpectBegin()
+    mock.ExpectQuery("FOR UPDATE").WithArgs(5).
+        WillReturnRows(sqlmock.NewRows([]string{"status", "production_stage"}).AddRow("paid", "diserahkan"))
+    mock.ExpectQuery("FROM deliveries").WithArgs(5).
+        WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+    mock.ExpectRollback()
+
+    req := mux.SetURLVars(httptest.NewRequest("PUT", "/api/transactions/5/production-stage", strings.NewReader(`{"stage": "jahit", "deduct_materials": false}`)),
+        map[string]string{"id": "5"})
+    rec := httptest.NewRecorder()
+    h.SetProductionStage(rec, req)
+
+    if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), "hapus pengirimannya dulu") {
+        t.Fatalf("status = %d, body = %s; want 400", rec.Code, rec.Body.String())
+    }
+    if err := mock.ExpectationsWereMet(); err != nil {
+        t.Fatal(err)
+    }
+}
@@ -0,0 +1,156 @@
+package handlers
+
+import (
+    "database/sql"
+    "encoding/json"
+    "konveksi-app/mailer"
+    "log"
+    "net/http"
+    "strconv"
+
+    "github.com/gorilla/mux"
+)
+
+type EmailHandler struct {
+    Service *mailer.Service
+}
+
+// GetEmailLogs - GET /api/admin/emails?status=failed&limit=50
+func (h *EmailHandler) GetEmailLogs(w http.ResponseWriter, r *http.Request) {
+    limit := 50
+    if l, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && l > 0 && l <= 500 {
+        limit = l
+    }
+
+    logs, err := h.Service.Logs.GetAll(r.URL.Query().Get("status"), limit)
+    if err != nil {
+        log.Printf("Error getting email logs: %v", err)
+        writeJSONError(w, http.StatusInternalServerError, "Gagal mengambil log email", err)
+        return
+    }
+
+    w.Header().Set("Content-Type", "application/json")
+    json.NewEncoder(w).Encode(map[string]interface{}{
+        "success": true,
+        "data":    logs,
+    })
+}
+
+// RetryEmail - POST /api/admin/emails/{id}/retry
+func (h *EmailHandler) RetryEmail(w http.ResponseWriter, r *http.Request) {
+    id, err := strconv.Atoi(mux.Vars(r)["id"])
+    if err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Invalid ID", nil)
+        return
+    }
+
+    emailLog, err := h.Service.Logs.GetByID(id)
+    if err == sql.ErrNoRows {
+        writeJSONError(w, http.StatusNotFound, "Email tidak ditemukan", nil)
+        return
+    } else if err != nil {
+        writeJSONError(w, http.StatusInternalServerError, "Gagal mengambil email", err)
+        return
+    }
+    if emailLog.Status == "sent" {
+        writeJSONError(w, http.StatusBadRequest, "Email sudah terkirim", nil)
+        return
+    }
+
+    if err := h.Service.Logs.ResetAttempts(id); err == sql.ErrNoRows {
+        writeJSONError(w, http.StatusConflict, "Email sedang dikirim oleh proses lain", nil)
+        return
+    } else if err != nil {
+        writeJSONError(w, http.StatusInternalServerError, "Gagal mengirim ulang email", err)
+        return
+    }
+    if err := h.Service.Deliver(emailLog); err == mailer.ErrAlreadyClaimed {
+        writeJSONError(w, http.StatusConflict, "Email sedang dikirim oleh proses lain", nil)
+        return
+    } else if err != nil {
+        writeJSONError(w, http.StatusBadGateway, "Email gagal dikirim", err)
+        return
+    }
+
+    w.Header().Set("Content-Type", "application/json")
+    json.NewEncoder(w).Encode(map[string]interface{}{
+        "success": true,
+        "message": "Email berhasil dikirim",
+    })
+}
+
+// SendKuitansiEmail - POST /api/transactions/{id}/email-kuitansi, body opsional: {"recipient": "..."}
+func (h *EmailHandler) SendKuitansiEmail(w http.ResponseWriter, r *http.Request) {
+    id, err := strconv.Atoi(mux.Vars(r)["id"])
+    if err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Invalid ID", nil)
+        return
+    }
+
+    var req struct {
+        Recipient string `json:"recipient"`
+    }
+    if r.ContentLength != 0 {
+        if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+            writeJSONError(w, http.StatusBadRequest, "Invalid JSON", err)
+            return
+        }
+    }
+
+    emailLog, err := h.Service.QueueKuitansi(id, req.Recipient)
+    if err == sql.ErrNoRows {
+        writeJSONError(w, http.StatusNotFound, "Transaksi tidak ditemukan", nil)
+        return
+    } else if err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Gagal membuat email kuitansi", err)
+        return
+    }
+
+    // Jika gagal, email tetap tercatat dan akan dicoba ulang oleh job send_emails.
+    // Jika job sudah lebih dulu mengambilnya, email terkirim lewat job tersebut.
+    if err := h.Service.Deliver(emailLog); err == mailer.ErrAlreadyClaimed {
+        w.Header().Set("Content-Type", "application/json")
+        w.WriteHeader(http.StatusAccepted)
+        json.NewEncoder(w).Encode(map[string]interface{}{
+            "success": true,
+            "message": "Kuitansi sedang dikirim ke " + emailLog.Recipient,
+        })
+        return
+    } else if err != nil {
+        writeJSONError(w, http.StatusBadGateway, "Email gagal dikirim, akan dicoba ulang otomatis", err)
+        return
+    }
+
+    w.Header().Set("Content-Type", "application/json")
+    json.NewEncoder(w).Encode(map[string]interface{}{
+        "success": true,
+        "message": "Kuitansi terkirim ke " + emailLog.Recipient,
+    })
+}
+
+// SendTestEmail - POST /api/admin/emails/test, body: {"to": "..."}
+func (h *EmailHandler) SendTestEmail(w http.ResponseWriter, r *http.Request) {
+    var req struct {
+        To string `json:"to"`
+    }
+    if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.To == "" {
+        writeJSONError(w, http.StatusBadRequest, "Alamat tujuan (to) wajib diisi", nil)
+        return
+    }
+
+    err := h.Service.Mailer.Send(mailer.Message{
+        To:      []string{req.To},
+        Subject: "Tes Email DiOlif Fashion",
+        Body:    "Konfigurasi SMTP berhasil. Email ini dikirim dari sistem DiOlif Fashion.",
+    })
+    if err != nil {
+        writeJSONError(w, http.StatusBadGateway, "Email gagal dikirim", err)
+        return
+    }
+
+    w.Header().Set("Content-Type", "application/json")
+    json.NewEncoder(w).Encode(map[string]interface{}{
+        "success": true,
+        "message": "Email tes terkirim ke " + req.To,
+    })
+}
@@ -0,0 +1,40 @@
+package handlers
+
+import (
+    "konveksi-app/mailer"
+    "konveksi-app/repositories"
+    "net/http"
+    "net/http/httptest"
+    "testing"
+
+    "github.com/DATA-DOG/go-sqlmock"
+    "github.com/gorilla/mux"
+)
+
+func TestRetryEmailWhileSendingIsConflict(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer db.Close()
+    h := &EmailHandler{Service: &mailer.Service{Logs: &repositories.EmailLogRepository{DB: db}, DB: db}}
+
+    mock.ExpectQuery("FROM email_logs WHERE id = \\?").WithArgs(6).
+        WillReturnRows(sqlmock.NewRows([]string{"id", "template", "recipient", "subject", "body", "transaction_id", "customer_id",
+            "event_key", "attach_kuitansi", "status", "attempts", "last_error", "sent_at", "created_at"}).
+            AddRow(6, "payment_reminder", "sekolah@example.com", "Pengingat", "", 3, 2, "", false, "sending", 1, "", "", "2025-07-01 07:00:00"))
+    // Scheduler sedang mengirim email ini, jadi tidak ada baris yang direset
+    mock.ExpectExec("UPDATE email_logs SET status = 'pending', attempts = 0 WHERE id = \\? AND status IN \\('pending', 'failed'\\)").
+        WithArgs(6).WillReturnResult(sqlmock.NewResult(0, 0))
+
+    req := mux.SetURLVars(httptest.NewRequest("POST", "/api/admin/emails/6/retry", nil), map[string]string{"id": "6"})
+    rec := httptest.NewRecorder()
+    h.RetryEmail(rec, req)
+
+    if rec.Code != http.StatusConflict {
+        t.Fatalf("status = %d, body = %s; want 409", rec.Code, rec.Body.String())
+    }
+    if err := mock.ExpectationsWereMet(); err != nil {
+        t.Fatal(err)
+    }
+}
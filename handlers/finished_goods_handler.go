@@ -0,0 +1,190 @@
+package handlers
+
+import (
+    "database/sql"
+    "encoding/json"
+    "konveksi-app/repositories"
+    "log"
+    "net/http"
+    "strconv"
+
+    "github.com/gorilla/mux"
+)
+
+type FinishedGoodsHandler struct {
+    Repo *repositories.FinishedGoodsRepository
+}
+
+// GetStock - GET /api/finished-goods?catalog_id=&available=true
+func (h *FinishedGoodsHandler) GetStock(w http.ResponseWriter, r *http.Request) {
+    q := r.URL.Query()
+    catalogID, _ := strconv.Atoi(q.Get("catalog_id"))
+    stock, err := h.Repo.GetStock(catalogID, q.Get("available") == "true")
+    if err != nil {
+        log.Printf("Error getting finished goods stock: %v", err)
+        writeJSONError(w, http.StatusInternalServerError, "Gagal mengambil stok barang jadi", err)
+        return
+    }
+    w.Header().Set("Content-Type", "application/json")
+    json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "data": stock})
+}
+
+// GetAvailability - GET /api/finished-goods/availability?customer_id=&uniform_name=
+// Stok per ukuran untuk seragam customer, ditampilkan saat menambah baris pesanan
+func (h *FinishedGoodsHandler) GetAvailability(w http.ResponseWriter, r *http.Request) {
+    q := r.URL.Query()
+    customerID, _ := strconv.Atoi(q.Get("customer_id"))
+    uniformName := q.Get("uniform_name")
+    if customerID == 0 || uniformName == "" {
+        writeJSONError(w, http.StatusBadRequest, "customer_id dan uniform_name wajib diisi", nil)
+        return
+    }
+    catalogID, stock, err := h.Repo.Availability(customerID, uniformName)
+    if err != nil {
+        writeJSONError(w, http.StatusInternalServerError, "Gagal mengambil stok barang jadi", err)
+        return
+    }
+    w.Header().Set("Content-Type", "application/json")
+    json.NewEncoder(w).Encode(map[string]interface{}{
+        "success": true,
+        "data": map[string]interface{}{
+            "catalog_id": catalogID,
+            "stock":      stock,
+        },
+    })
+}
+
+// ReceiveStock - POST /api/finished-goods/receipts
+// body: {"catalog_id": 1, "size": "M", "quantity": 24, "transaction_id": 0, "reference": "SPK 12"}
+func (h *FinishedGoodsHandler) ReceiveStock(w http.ResponseWriter, r *http.Request) {
+    var req struct {
+        CatalogID     int    `json:"catalog_id"`
+        Size          string `json:"size"`
+        Quantity      int    `json:"quantity"`
+        TransactionID int    `json:"transaction_id"`
+        Reference     string `json:"reference"`
+    }
+    if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Invalid JSON", err)
+        return
+    }
+    movement, err := h.Repo.Receive(repositories.FinishedGoodsInput{
+        CatalogID:     req.CatalogID,
+        Size:          req.Size,
+        Quantity:      req.Quantity,
+        TransactionID: req.TransactionID,
+        Reference:     req.Reference,
+        PerformedBy:   GetSessionUsername(r),
+    })
+    if err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Gagal mencatat barang jadi masuk", err)
+        return
+    }
+    w.Header().Set("Content-Type", "application/json")
+    w.WriteHeader(http.StatusCreated)
+    json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "data": movement})
+}
+
+// CountStock - POST /api/finished-goods/counts
+// body: {"reason": "opname akhir bulan", "lines": [{"catalog_id": 1, "size": "M", "counted": 20}]}
+func (h *FinishedGoodsHandler) CountStock(w http.ResponseWriter, r *http.Request) {
+    var req struct {
+        Reason string                        `json:"reason"`
+        Lines  []repositories.StockCountLine `json:"lines"`
+    }
+    if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Invalid JSON", err)
+        return
+    }
+    movements, err := h.Repo.Count(req.Lines, req.Reason, GetSessionUsername(r))
+    if err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Gagal menyimpan hasil hitung stok", err)
+        return
+    }
+    w.Header().Set("Content-Type", "application/json")
+    json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "data": movements})
+}
+
+// GetMovements - GET /api/finished-goods/movements?catalog_id=&size=&transaction_id=&type=&from=&to=&limit=
+func (h *FinishedGoodsHandler) GetMovements(w http.ResponseWriter, r *http.Request) {
+    q := r.URL.Query()
+    var f repositories.FinishedGoodsMovementFilter
+    f.CatalogID, _ = strconv.Atoi(q.Get("catalog_id"))
+    f.TransactionID, _ = strconv.Atoi(q.Get("transaction_id"))
+    f.Limit, _ = strconv.Atoi(q.Get("limit"))
+    f.Size = q.Get("size")
+    f.Type = q.Get("type")
+    if err := parseDateRange(q, &f.From, &f.To); err != nil {
+        writeJSONError(w, http.StatusBadRequest, err.Error(), nil)
+        return
+    }
+    movements, err := h.Repo.GetMovements(f)
+    if err != nil {
+        log.Printf("Error getting finished goods movements: %v", err)
+        writeJSONError(w, http.StatusInternalServerError, "Gagal mengambil mutasi barang jadi", err)
+        return
+    }
+    w.Header().Set("Content-Type", "application/json")
+    json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "data": movements})
+}
+
+// GetReservations - GET /api/transactions/{id}/stock-reservations
+func (h *FinishedGoodsHandler) GetReservations(w http.ResponseWriter, r *http.Request) {
+    id, err := strconv.Atoi(mux.Vars(r)["id"])
+    if err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Invalid ID", nil)
+        return
+    }
+    reservations, err := h.Repo.GetReservations(id)
+    if err != nil {
+        writeJSONError(w, http.StatusInternalServerError, "Gagal mengambil stok yang disisihkan", err)
+        return
+    }
+    w.Header().Set("Content-Type", "application/json")
+    json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "data": reservations})
+}
+
+// ReserveStock - POST /api/transactions/{id}/stock-reservations
+// body: {"lines": [{"catalog_id": 1, "size": "M", "quantity": 10}]}; gagal semua jika stok tidak cukup
+func (h *FinishedGoodsHandler) ReserveStock(w http.ResponseWriter, r *http.Request) {
+    id, err := strconv.Atoi(mux.Vars(r)["id"])
+    if err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Invalid ID", nil)
+        return
+    }
+    var req struct {
+        Lines []repositories.ReservationLine `json:"lines"`
+    }
+    if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Invalid JSON", err)
+        return
+    }
+    if err := h.Repo.Reserve(id, req.Lines); err == sql.ErrNoRows {
+        writeJSONError(w, http.StatusNotFound, "Transaksi tidak ditemukan", nil)
+        return
+    } else if err != nil {
+        writeJSONError(w, http.StatusConflict, "Gagal menyisihkan stok", err)
+        return
+    }
+    reservations, err := h.Repo.GetReservations(id)
+    if err != nil {
+        writeJSONError(w, http.StatusInternalServerError, "Gagal mengambil stok yang disisihkan", err)
+        return
+    }
+    w.Header().Set("Content-Type", "application/json")
+    json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "data": reservations})
+}
+
+// ReleaseReservations - DELETE /api/transactions/{id}/stock-reservations
+func (h *FinishedGoodsHandler) ReleaseReservations(w http.ResponseWriter, r *http.Request) {
+    id, err := strconv.Atoi(mux.Vars(r)["id"])
+    if err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Invalid ID", nil)
+        return
+    }
+    if err := h.Repo.ReleaseReservations(id); err != nil {
+        writeJSONError(w, http.StatusInternalServerError, "Gagal melepas stok yang disisihkan", err)
+        return
+    }
+    w.WriteHeader(http.StatusNoContent)
+}
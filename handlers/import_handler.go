@@ -0,0 +1,268 @@
+package handlers
+
+import (
+    "encoding/json"
+    "fmt"
+    "konveksi-app/models"
+    "konveksi-app/repositories"
+    "konveksi-app/spreadsheet"
+    "log"
+    "net/http"
+    "strconv"
+    "strings"
+    "time"
+
+    "github.com/gorilla/mux"
+)
+
+type ImportHandler struct {
+    Customers    *repositories.CustomerRepository
+    Transactions *repositories.TransactionRepository
+}
+
+// Alias header kolom roster siswa yang umum dipakai sekolah
+var studentRosterColumns = map[string][]string{
+    "student_name": {"nama", "nama siswa", "nama murid", "nama lengkap", "student name", "name"},
+    "grade":        {"kelas", "kls", "grade", "class"},
+    "uniform_name": {"seragam", "jenis seragam", "nama seragam", "uniform", "uniform name", "item"},
+    "size":         {"ukuran", "size", "uk"},
+    "quantity":     {"jumlah", "qty", "quantity", "jml", "pcs"},
+    "notes":        {"catatan", "keterangan", "ket", "notes"},
+}
+
+var studentRosterRequired = []string{"student_name", "uniform_name", "size"}
+
+type studentImportRow struct {
+    Row         int      `json:"row"`
+    StudentName string   `json:"student_name"`
+    Grade       string   `json:"grade"`
+    UniformName string   `json:"uniform_name"`
+    Size        string   `json:"size"`
+    Quantity    int      `json:"quantity"`
+    UnitPrice   float64  `json:"unit_price"`
+    Subtotal    float64  `json:"subtotal"`
+    Notes       string   `json:"notes"`
+    Errors      []string `json:"errors"`
+}
+
+func priceKey(uniformName, size string) string {
+    return strings.ToLower(strings.TrimSpace(uniformName)) + "|" + strings.ToLower(strings.TrimSpace(size))
+}
+
+// parseQuantity menerima "2", "2.0" atau "2,0" (angka dari Excel)
+func parseQuantity(value string) (int, error) {
+    if value == "" {
+        return 1, nil
+    }
+    value = strings.Replace(value, ",", ".", 1)
+    f, err := strconv.ParseFloat(value, 64)
+    if err != nil || f != float64(int(f)) {
+        return 0, fmt.Errorf("jumlah '%s' tidak valid", value)
+    }
+    if f <= 0 {
+        return 0, fmt.Errorf("jumlah harus lebih dari 0")
+    }
+    return int(f), nil
+}
+
+// ImportStudentOrder - POST /api/customers/{id}/student-orders/import
+// multipart: file (CSV/XLSX), transaction_date (wajib, juga untuk preview), payment_date, status, notes,
+// mapping (JSON opsional {"student_name": "Nama Murid", ...}), confirm ("true" untuk menyimpan)
+func (h *ImportHandler) ImportStudentOrder(w http.ResponseWriter, r *http.Request) {
+    customerID, err := strconv.Atoi(mux.Vars(r)["id"])
+    if err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Invalid customer ID", nil)
+        return
+    }
+
+    customer, err := h.Customers.GetByID(customerID)
+    if err != nil {
+        writeJSONError(w, http.StatusNotFound, "Customer tidak ditemukan", nil)
+        return
+    }
+
+    if err := r.ParseMultipartForm(10 << 20); err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Upload file gagal", err)
+        return
+    }
+    status := r.FormValue("status")
+    if status == "" {
+        status = "pending"
+    }
+    if status != "pending" && status != "paid" && status != "cancelled" {
+        writeJSONError(w, http.StatusBadRequest, "Status tidak valid, gunakan pending, paid, atau cancelled", nil)
+        return
+    }
+    // Tanggal transaksi menentukan harga, jadi dibutuhkan sejak preview supaya total sama dengan yang disimpan
+    transactionDate := r.FormValue("transaction_date")
+    if transactionDate == "" {
+        writeJSONError(w, http.StatusBadRequest, "transaction_date wajib diisi", nil)
+        return
+    }
+    if _, err := time.Parse("2006-01-02", transactionDate); err != nil {
+        writeJSONError(w, http.StatusBadRequest, "transaction_date harus berformat YYYY-MM-DD", nil)
+        return
+    }
+    file, header, err := r.FormFile("file")
+    if err != nil {
+        writeJSONError(w, http.StatusBadRequest, "File roster wajib diupload", err)
+        return
+    }
+    defer file.Close()
+
+    table, err := spreadsheet.Read(header.Filename, file)
+    if err != nil {
+        writeJSONError(w, http.StatusBadRequest, "File tidak dapat dibaca", err)
+        return
+    }
+
+    mapping := map[string]string{}
+    if m := r.FormValue("mapping"); m != "" {
+        if err := json.Unmarshal([]byte(m), &mapping); err != nil {
+            writeJSONError(w, http.StatusBadRequest, "Mapping kolom tidak valid", err)
+            return
+        }
+    }
+
+    columns, missing := table.MapColumns(studentRosterColumns, studentRosterRequired, mapping)
+    if len(missing) > 0 {
+        w.Header().Set("Content-Type", "application/json")
+        w.WriteHeader(http.StatusBadRequest)
+        json.NewEncoder(w).Encode(map[string]interface{}{
+            "success":         false,
+            "error":           "Kolom wajib tidak ditemukan: " + strings.Join(missing, ", "),
+            "missing_columns": missing,
+            "headers":         table.Header,
+        })
+        return
+    }
+
+    // Daftar harga customer yang berlaku pada tanggal transaksi sebagai acuan validasi dan harga satuan
+    uniforms, err := h.Customers.GetUniformsByCustomerID(customerID)
+    if err != nil {
+        writeJSONError(w, http.StatusInternalServerError, "Gagal mengambil daftar harga", err)
+        return
+    }
+    if err := h.Customers.ApplyPricesOnDate(uniforms, transactionDate); err != nil {
+        writeJSONError(w, http.StatusInternalServerError, "Gagal mengambil daftar harga", err)
+        return
+    }
+    prices := make(map[string]models.CustomerUniform)
+    for _, u := range uniforms {
+        prices[priceKey(u.UniformName, u.Size)] = u
+    }
+
+    rows := make([]studentImportRow, 0, len(table.Rows))
+    errorRows := 0
+    var total float64
+    for i, record := range table.Rows {
+        row := studentImportRow{
+            Row:         table.Line(i),
+            StudentName: spreadsheet.Value(record, columns, "student_name"),
+            Grade:       spreadsheet.Value(record, columns, "grade"),
+            UniformName: spreadsheet.Value(record, columns, "uniform_name"),
+            Size:        spreadsheet.Value(record, columns, "size"),
+            Notes:       spreadsheet.Value(record, columns, "notes"),
+            Errors:      []string{},
+        }
+
+        if row.StudentName == "" {
+            row.Errors = append(row.Errors, "nama siswa kosong")
+        }
+        qty, err := parseQuantity(spreadsheet.Value(record, columns, "quantity"))
+        if err != nil {
+            row.Errors = append(row.Errors, err.Error())
+        }
+        row.Quantity = qty
+
+        if row.UniformName == "" || row.Size == "" {
+            row.Errors = append(row.Errors, "seragam dan ukuran wajib diisi")
+        } else if u, ok := prices[priceKey(row.UniformName, row.Size)]; ok {
+            // Pakai penulisan dari daftar harga supaya konsisten
+            row.UniformName = u.UniformName
+            row.Size = u.Size
+            row.UnitPrice = u.Price
+            row.Subtotal = u.Price * float64(row.Quantity)
+        } else {
+            row.Errors = append(row.Errors, fmt.Sprintf("seragam '%s' ukuran '%s' tidak ada di daftar harga %s", row.UniformName, row.Size, customer.Name))
+        }
+
+        if len(row.Errors) > 0 {
+            errorRows++
+        } else {
+            total += row.Subtotal
+        }
+        rows = append(rows, row)
+    }
+
+    mappedColumns := map[string]string{}
+    for field, i := range columns {
+        mappedColumns[field] = table.Header[i]
+    }
+
+    response := map[string]interface{}{
+        "customer_id":   customerID,
+        "customer_name": customer.Name,
+        "columns":       mappedColumns,
+        "rows":          rows,
+        "total_rows":    len(rows),
+        "valid_rows":    len(rows) - errorRows,
+        "error_rows":    errorRows,
+        "total":         total,
+        "created":       false,
+    }
+
+    confirm := r.FormValue("confirm")
+    if confirm != "true" && confirm != "1" {
+        w.Header().Set("Content-Type", "application/json")
+        json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "data": response})
+        return
+    }
+
+    if len(rows) == 0 || errorRows > 0 {
+        w.Header().Set("Content-Type", "application/json")
+        w.WriteHeader(http.StatusUnprocessableEntity)
+        json.NewEncoder(w).Encode(map[string]interface{}{
+            "success": false,
+            "error":   "Perbaiki baris yang error sebelum menyimpan pesanan",
+            "data":    response,
+        })
+        return
+    }
+
+    transaction := &models.Transaksi{
+        CustomerID:    customerID,
+        Transaksidate: transactionDate,
+        Paymentdate:   r.FormValue("payment_date"),
+        Status:        status,
+        Notes:         r.FormValue("notes"),
+    }
+    studentItems := make([]models.StudentOrderItem, 0, len(rows))
+    for _, row := range rows {
+        studentItems = append(studentItems, models.StudentOrderItem{
+            CustomerID:  customerID,
+            StudentName: row.StudentName,
+            Grade:       row.Grade,
+            UniformName: row.UniformName,
+            Size:        row.Size,
+            Quantity:    row.Quantity,
+            UnitPrice:   row.UnitPrice,
+            Notes:       row.Notes,
+        })
+    }
+
+    if err := h.Transactions.CreateStudentOrder(transaction, studentItems); err != nil {
+        log.Printf("Error creating imported student order: %v", err)
+        writeJSONError(w, http.StatusInternalServerError, "Gagal menyimpan pesanan", err)
+        return
+    }
+
+    log.Printf("Imported student order %d for customer %d with %d rows", transaction.ID, customerID, len(studentItems))
+    response["created"] = true
+    response["transaction_id"] = transaction.ID
+    response["total"] = transaction.Total
+
+    w.Header().Set("Content-Type", "application/json")
+    w.WriteHeader(http.StatusCreated)
+    json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "data": response})
+}
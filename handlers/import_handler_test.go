@@ -0,0 +1,126 @@
+package handlers
+
+import (
+    "bytes"
+    "konveksi-app/repositories"
+    "mime/multipart"
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "testing"
+
+    "github.com/DATA-DOG/go-sqlmock"
+    "github.com/gorilla/mux"
+)
+
+func TestImportStudentOrderRejectsInvalidStatus(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer db.Close()
+    h := &ImportHandler{
+        Customers:    &repositories.CustomerRepository{DB: db},
+        Transactions: &repositories.TransactionRepository{DB: db},
+    }
+
+    mock.ExpectQuery("FROM customers WHERE id").WithArgs(4).WillReturnRows(
+        sqlmock.NewRows([]string{"id", "name", "type", "contact", "email", "address", "created_at"}).
+            AddRow(4, "SD Ceria", "SD", "0812", "", "Jl. Melati", "2025-01-01"))
+
+    var body bytes.Buffer
+    form := multipart.NewWriter(&body)
+    form.WriteField("status", "lunas")
+    form.WriteField("transaction_date", "2025-07-01")
+    part, _ := form.CreateFormFile("file", "roster.csv")
+    part.Write([]byte("Nama Siswa,Seragam,Ukuran,Jumlah\nBudi,Kemeja,M,1\n"))
+    form.Close()
+
+    req := httptest.NewRequest("POST", "/api/customers/4/student-orders/import", &body)
+    req.Header.Set("Content-Type", form.FormDataContentType())
+    req = mux.SetURLVars(req, map[string]string{"id": "4"})
+    rec := httptest.NewRecorder()
+    h.ImportStudentOrder(rec, req)
+
+    if rec.Code != http.StatusBadRequest {
+        t.Fatalf("status = %d, want 400: %s", rec.Code, rec.Body.String())
+    }
+    if err := mock.ExpectationsWereMet(); err != nil {
+        t.Fatal(err)
+    }
+}
+
+func TestImportStudentOrderPreviewUsesPriceOnTransactionDate(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer db.Close()
+    h := &ImportHandler{
+        Customers:    &repositories.CustomerRepository{DB: db},
+        Transactions: &repositories.TransactionRepository{DB: db},
+    }
+
+    mock.ExpectQuery("FROM customers WHERE id").WithArgs(4).WillReturnRows(
+        sqlmock.NewRows([]string{"id", "name", "type", "contact", "email", "address", "created_at"}).
+            AddRow(4, "SD Ceria", "SD", "0812", "", "Jl. Melati", "2025-01-01"))
+    mock.ExpectQuery("FROM customer_uniforms WHERE customer_id").WithArgs(4).WillReturnRows(
+        sqlmock.NewRows([]string{"id", "customer_id", "catalog_id", "uniform_name", "size", "price", "notes", "created_at"}).
+            AddRow(21, 4, 0, "Kemeja", "M", 50000.0, "", "2025-01-01"))
+    // Harga baru berlaku 1 Juli, sesudah hari ini tapi sebelum tanggal transaksi
+    mock.ExpectQuery("FROM customer_uniform_prices").WithArgs(21, "2025-07-15").
+        WillReturnRows(sqlmock.NewRows([]string{"price"}).AddRow(55000.0))
+
+    var body bytes.Buffer
+    form := multipart.NewWriter(&body)
+    form.WriteField("transaction_date", "2025-07-15")
+    part, _ := form.CreateFormFile("file", "roster.csv")
+    part.Write([]byte("Nama Siswa,Seragam,Ukuran,Jumlah\nBudi,Kemeja,M,2\n"))
+    form.Close()
+
+    req := httptest.NewRequest("POST", "/api/customers/4/student-orders/import", &body)
+    req.Header.Set("Content-Type", form.FormDataContentType())
+    req = mux.SetURLVars(req, map[string]string{"id": "4"})
+    rec := httptest.NewRecorder()
+    h.ImportStudentOrder(rec, req)
+
+    if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"total":110000`) {
+        t.Fatalf("status = %d, body = %s; want preview priced on transaction date", rec.Code, rec.Body.String())
+    }
+    if err := mock.ExpectationsWereMet(); err != nil {
+        t.Fatal(err)
+    }
+}
+
+func TestImportStudentOrderPreviewRequiresTransactionDate(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer db.Close()
+    h := &ImportHandler{Customers: &repositories.CustomerRepository{DB: db}}
+
+    mock.ExpectQuery("FROM customers WHERE id").WithArgs(4).WillReturnRows(
+        sqlmock.NewRows([]string{"id", "name", "type", "contact", "email", "address", "created_at"}).
+            AddRow(4, "SD Ceria", "SD", "0812", "", "Jl. Melati", "2025-01-01"))
+
+    var body bytes.Buffer
+    form := multipart.NewWriter(&body)
+    form.WriteField("transaction_date", "15-07-2025")
+    part, _ := form.CreateFormFile("file", "roster.csv")
+    part.Write([]byte("Nama Siswa,Seragam,Ukuran,Jumlah\nBudi,Kemeja,M,2\n"))
+    form.Close()
+
+    req := httptest.NewRequest("POST", "/api/customers/4/student-orders/import", &body)
+    req.Header.Set("Content-Type", form.FormDataContentType())
+    req = mux.SetURLVars(req, map[string]string{"id": "4"})
+    rec := httptest.NewRecorder()
+    h.ImportStudentOrder(rec, req)
+
+    if rec.Code != http.StatusBadRequest {
+        t.Fatalf("status = %d, want 400: %s", rec.Code, rec.Body.String())
+    }
+    if err := mock.ExpectationsWereMet(); err != nil {
+        t.Fatal(err)
+    }
+}
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+    "encoding/json"
+    "konveksi-app/scheduler"
+    "log"
+    "net/http"
+    "strconv"
+
+    "github.com/gorilla/mux"
+)
+
+type JobHandler struct {
+    Scheduler *scheduler.Scheduler
+}
+
+// ListJobs - GET /api/admin/jobs
+func (h *JobHandler) ListJobs(w http.ResponseWriter, r *http.Request) {
+    w.Header().Set("Content-Type", "application/json")
+    json.NewEncoder(w).Encode(map[string]interface{}{
+        "success": true,
+        "data":    h.Scheduler.Status(),
+    })
+}
+
+// GetJobRuns - GET /api/admin/jobs/{name}/runs?limit=20
+func (h *JobHandler) GetJobRuns(w http.ResponseWriter, r *http.Request) {
+    name := mux.Vars(r)["name"]
+    if !h.Scheduler.HasJob(name) {
+        writeJSONError(w, http.StatusNotFound, "Job tidak ditemukan", nil)
+        return
+    }
+
+    limit := 20
+    if l, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && l > 0 && l <= 200 {
+        limit = l
+    }
+
+    runs, err := h.Scheduler.Runs.GetByJobName(name, limit)
+    if err != nil {
+        log.Printf("Error getting job runs for %s: %v", name, err)
+        writeJSONError(w, http.StatusInternalServerError, "Gagal mengambil riwayat job", err)
+        return
+    }
+
+    w.Header().Set("Content-Type", "application/json")
+    json.NewEncoder(w).Encode(map[string]interface{}{
+        "success": true,
+        "data":    runs,
+    })
+}
+
+// RunJob - POST /api/admin/jobs/{name}/run
+func (h *JobHandler) RunJob(w http.ResponseWriter, r *http.Request) {
+    name := mux.Vars(r)["name"]
+    if !h.Scheduler.HasJob(name) {
+        writeJSONError(w, http.StatusNotFound, "Job tidak ditemukan", nil)
+        return
+    }
+
+    if err := h.Scheduler.RunNow(name); err != nil {
+        writeJSONError(w, http.StatusConflict, "Job tidak dapat dijalankan", err)
+        return
+    }
+
+    log.Printf("Job %s triggered manually by %s", name, GetSessionUsername(r))
+    w.Header().Set("Content-Type", "application/json")
+    w.WriteHeader(http.StatusAccepted)
+    json.NewEncoder(w).Encode(map[string]interface{}{
+        "success": true,
+        "message": "Job " + name + " dijalankan",
+    })
+}
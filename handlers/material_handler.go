@@ -0,0 +1,255 @@
+package handlers
+
+import (
+    "database/sql"
+    "encoding/json"
+    "fmt"
+    "konveksi-app/models"
+    "konveksi-app/repositories"
+    "log"
+    "net/http"
+    "net/url"
+    "strconv"
+    "time"
+
+    "github.com/go-sql-driver/mysql"
+    "github.com/gorilla/mux"
+)
+
+type MaterialHandler struct {
+    Repo *repositories.MaterialRepository
+}
+
+// writeMaterialSaveError membedakan nama bahan dobel dan error validasi
+func writeMaterialSaveError(w http.ResponseWriter, err error) {
+    if mysqlErr, ok := err.(*mysql.MySQLError); ok {
+        if mysqlErr.Number == 1062 {
+            writeJSONError(w, http.StatusConflict, "Nama bahan sudah ada", nil)
+            return
+        }
+        log.Printf("Error saving material: %v", err)
+        writeJSONError(w, http.StatusInternalServerError, "Gagal menyimpan bahan", err)
+        return
+    }
+    writeJSONError(w, http.StatusBadRequest, "Gagal menyimpan bahan", err)
+}
+
+// GetMaterials - GET /api/materials?all=true&low_stock=true
+// all=true termasuk bahan nonaktif, low_stock=true hanya bahan yang stoknya di bawah batas pemesanan ulang
+func (h *MaterialHandler) GetMaterials(w http.ResponseWriter, r *http.Request) {
+    q := r.URL.Query()
+    materials, err := h.Repo.GetAll(q.Get("all") == "true", q.Get("low_stock") == "true")
+    if err != nil {
+        log.Printf("Error getting materials: %v", err)
+        writeJSONError(w, http.StatusInternalServerError, "Gagal mengambil data bahan", err)
+        return
+    }
+    w.Header().Set("Content-Type", "application/json")
+    json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "data": materials})
+}
+
+// GetMaterial - GET /api/materials/{id}
+func (h *MaterialHandler) GetMaterial(w http.ResponseWriter, r *http.Request) {
+    id, err := strconv.Atoi(mux.Vars(r)["id"])
+    if err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Invalid ID", nil)
+        return
+    }
+    material, err := h.Repo.GetByID(id)
+    if err == sql.ErrNoRows {
+        writeJSONError(w, http.StatusNotFound, "Bahan tidak ditemukan", nil)
+        return
+    } else if err != nil {
+        writeJSONError(w, http.StatusInternalServerError, "Gagal mengambil data bahan", err)
+        return
+    }
+    w.Header().Set("Content-Type", "application/json")
+    json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "data": material})
+}
+
+// CreateMaterial - POST /api/materials
+func (h *MaterialHandler) CreateMaterial(w http.ResponseWriter, r *http.Request) {
+    material := models.Material{Active: true}
+    if err := json.NewDecoder(r.Body).Decode(&material); err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Invalid JSON", err)
+        return
+    }
+    if err := h.Repo.Create(&material); err != nil {
+        writeMaterialSaveError(w, err)
+        return
+    }
+    w.Header().Set("Content-Type", "application/json")
+    w.WriteHeader(http.StatusCreated)
+    json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "data": material})
+}
+
+// UpdateMaterial - PUT /api/materials/{id}
+func (h *MaterialHandler) UpdateMaterial(w http.ResponseWriter, r *http.Request) {
+    id, err := strconv.Atoi(mux.Vars(r)["id"])
+    if err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Invalid ID", nil)
+        return
+    }
+    material := models.Material{Active: true}
+    if err := json.NewDecoder(r.Body).Decode(&material); err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Invalid JSON", err)
+        return
+    }
+    material.ID = id
+    if err := h.Repo.Update(&material); err == sql.ErrNoRows {
+        writeJSONError(w, http.StatusNotFound, "Bahan tidak ditemukan", nil)
+        return
+    } else if err != nil {
+        writeMaterialSaveError(w, err)
+        return
+    }
+    saved, err := h.Repo.GetByID(id)
+    if err != nil {
+        writeJSONError(w, http.StatusInternalServerError, "Gagal mengambil data bahan", err)
+        return
+    }
+    w.Header().Set("Content-Type", "application/json")
+    json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "data": saved})
+}
+
+// DeleteMaterial - DELETE /api/materials/{id}
+func (h *MaterialHandler) DeleteMaterial(w http.ResponseWriter, r *http.Request) {
+    id, err := strconv.Atoi(mux.Vars(r)["id"])
+    if err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Invalid ID", nil)
+        return
+    }
+    if err := h.Repo.Delete(id); err == sql.ErrNoRows {
+        writeJSONError(w, http.StatusNotFound, "Bahan tidak ditemukan", nil)
+        return
+    } else if err != nil {
+        writeJSONError(w, http.StatusConflict, "Gagal menghapus bahan", err)
+        return
+    }
+    w.WriteHeader(http.StatusNoContent)
+}
+
+// RecordMovement - POST /api/materials/movements
+// body: {"material_id": 1, "type": "in|out|adjustment", "quantity": 50, "reference": "", "reason": ""}
+// quantity adjustment bertanda: -2.5 untuk mengurangi stok
+func (h *MaterialHandler) RecordMovement(w http.ResponseWriter, r *http.Request) {
+    var req struct {
+        MaterialID int     `json:"material_id"`
+        Type       string  `json:"type"`
+        Quantity   float64 `json:"quantity"`
+        Reference  string  `json:"reference"`
+        Reason     string  `json:"reason"`
+    }
+    if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Invalid JSON", err)
+        return
+    }
+    movement, err := h.Repo.RecordMovement(repositories.StockMovementInput{
+        MaterialID:  req.MaterialID,
+        Type:        req.Type,
+        Quantity:    req.Quantity,
+        Reference:   req.Reference,
+        Reason:      req.Reason,
+        PerformedBy: GetSessionUsername(r),
+    })
+    if err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Gagal mencatat mutasi stok", err)
+        return
+    }
+    w.Header().Set("Content-Type", "application/json")
+    w.WriteHeader(http.StatusCreated)
+    json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "data": movement})
+}
+
+// parseDateRange membaca parameter from/to berformat YYYY-MM-DD untuk buku mutasi
+func parseDateRange(q url.Values, from, to *string) error {
+    for _, d := range []struct {
+        name  string
+        value *string
+    }{{"from", from}, {"to", to}} {
+        v := q.Get(d.name)
+        if v == "" {
+            continue
+        }
+        if _, err := time.Parse("2006-01-02", v); err != nil {
+            return fmt.Errorf("Parameter %s harus berformat YYYY-MM-DD", d.name)
+        }
+        *d.value = v
+    }
+    return nil
+}
+
+// GetMovements - GET /api/materials/movements?material_id=&transaction_id=&type=&from=&to=&limit=
+// juga GET /api/materials/{id}/movements
+func (h *MaterialHandler) GetMovements(w http.ResponseWriter, r *http.Request) {
+    q := r.URL.Query()
+    var f repositories.MovementFilter
+    f.MaterialID, _ = strconv.Atoi(q.Get("material_id"))
+    if id, ok := mux.Vars(r)["id"]; ok {
+        f.MaterialID, _ = strconv.Atoi(id)
+    }
+    f.TransactionID, _ = strconv.Atoi(q.Get("transaction_id"))
+    f.Limit, _ = strconv.Atoi(q.Get("limit"))
+    f.Type = q.Get("type")
+    if err := parseDateRange(q, &f.From, &f.To); err != nil {
+        writeJSONError(w, http.StatusBadRequest, err.Error(), nil)
+        return
+    }
+
+    movements, err := h.Repo.GetMovements(f)
+    if err != nil {
+        log.Printf("Error getting material movements: %v", err)
+        writeJSONError(w, http.StatusInternalServerError, "Gagal mengambil mutasi stok", err)
+        return
+    }
+    w.Header().Set("Content-Type", "application/json")
+    json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "data": movements})
+}
+
+// GetBOM - GET /api/catalog/{id}/bom
+func (h *MaterialHandler) GetBOM(w http.ResponseWriter, r *http.Request) {
+    id, err := strconv.Atoi(mux.Vars(r)["id"])
+    if err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Invalid ID", nil)
+        return
+    }
+    lines, err := h.Repo.GetBOM(id)
+    if err != nil {
+        writeJSONError(w, http.StatusInternalServerError, "Gagal mengambil BOM", err)
+        return
+    }
+    w.Header().Set("Content-Type", "application/json")
+    json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "data": lines})
+}
+
+// SaveBOM - PUT /api/catalog/{id}/bom
+// body: {"lines": [{"size": "", "material_id": 1, "quantity": 1.2}, {"size": "XL", "material_id": 1, "quantity": 1.5}]}
+// size kosong berlaku untuk semua ukuran; lines mengganti seluruh BOM
+func (h *MaterialHandler) SaveBOM(w http.ResponseWriter, r *http.Request) {
+    id, err := strconv.Atoi(mux.Vars(r)["id"])
+    if err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Invalid ID", nil)
+        return
+    }
+    var req struct {
+        Lines []models.BOMLine `json:"lines"`
+    }
+    if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Invalid JSON", err)
+        return
+    }
+    if err := h.Repo.SaveBOM(id, req.Lines); err == sql.ErrNoRows {
+        writeJSONError(w, http.StatusNotFound, "Produk katalog tidak ditemukan", nil)
+        return
+    } else if err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Gagal menyimpan BOM", err)
+        return
+    }
+    lines, err := h.Repo.GetBOM(id)
+    if err != nil {
+        writeJSONError(w, http.StatusInternalServerError, "Gagal mengambil BOM", err)
+        return
+    }
+    w.Header().Set("Content-Type", "application/json")
+    json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "data": lines})
+}
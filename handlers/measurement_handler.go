@@ -0,0 +1,158 @@
+package handlers
+
+import (
+    "database/sql"
+    "encoding/json"
+    "konveksi-app/models"
+    "konveksi-app/repositories"
+    "log"
+    "net/http"
+    "strconv"
+
+    "github.com/gorilla/mux"
+)
+
+type MeasurementHandler struct {
+    Repo *repositories.MeasurementRepository
+}
+
+// GetStudentMeasurements - GET /api/students/{id}/measurements (terbaru dulu)
+func (h *MeasurementHandler) GetStudentMeasurements(w http.ResponseWriter, r *http.Request) {
+    id, err := strconv.Atoi(mux.Vars(r)["id"])
+    if err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Invalid ID", nil)
+        return
+    }
+    measurements, err := h.Repo.GetByStudent(id)
+    if err != nil {
+        log.Printf("Error getting measurements for student %d: %v", id, err)
+        writeJSONError(w, http.StatusInternalServerError, "Gagal mengambil data ukuran", err)
+        return
+    }
+    w.Header().Set("Content-Type", "application/json")
+    json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "data": measurements})
+}
+
+// CreateStudentMeasurement - POST /api/students/{id}/measurements
+func (h *MeasurementHandler) CreateStudentMeasurement(w http.ResponseWriter, r *http.Request) {
+    id, err := strconv.Atoi(mux.Vars(r)["id"])
+    if err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Invalid ID", nil)
+        return
+    }
+    var m models.StudentMeasurement
+    if err := json.NewDecoder(r.Body).Decode(&m); err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Invalid JSON", err)
+        return
+    }
+    m.StudentID = id
+    if m.MeasuredBy == "" {
+        m.MeasuredBy = GetSessionUsername(r)
+    }
+
+    if err := h.Repo.Create(&m); err == sql.ErrNoRows {
+        writeJSONError(w, http.StatusNotFound, "Siswa tidak ditemukan", nil)
+        return
+    } else if err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Gagal menyimpan ukuran", err)
+        return
+    }
+    w.Header().Set("Content-Type", "application/json")
+    w.WriteHeader(http.StatusCreated)
+    json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "data": m})
+}
+
+// DeleteMeasurement - DELETE /api/student-measurements/{id}
+func (h *MeasurementHandler) DeleteMeasurement(w http.ResponseWriter, r *http.Request) {
+    id, err := strconv.Atoi(mux.Vars(r)["id"])
+    if err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Invalid ID", nil)
+        return
+    }
+    if err := h.Repo.Delete(id); err == sql.ErrNoRows {
+        writeJSONError(w, http.StatusNotFound, "Data ukuran tidak ditemukan", nil)
+        return
+    } else if err != nil {
+        writeJSONError(w, http.StatusInternalServerError, "Gagal menghapus data ukuran", err)
+        return
+    }
+    w.WriteHeader(http.StatusNoContent)
+}
+
+// SuggestSizes - GET /api/student-measurements/{id}/size-suggestions?catalog_id=
+// Tanpa catalog_id dihitung untuk semua seragam yang punya aturan ukuran.
+func (h *MeasurementHandler) SuggestSizes(w http.ResponseWriter, r *http.Request) {
+    id, err := strconv.Atoi(mux.Vars(r)["id"])
+    if err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Invalid ID", nil)
+        return
+    }
+    catalogID := 0
+    if v := r.URL.Query().Get("catalog_id"); v != "" {
+        if catalogID, err = strconv.Atoi(v); err != nil {
+            writeJSONError(w, http.StatusBadRequest, "catalog_id tidak valid", nil)
+            return
+        }
+    }
+
+    suggestions, err := h.Repo.SuggestSizes(id, catalogID)
+    if err == sql.ErrNoRows {
+        writeJSONError(w, http.StatusNotFound, "Data ukuran tidak ditemukan", nil)
+        return
+    } else if err != nil {
+        log.Printf("Error suggesting sizes for measurement %d: %v", id, err)
+        writeJSONError(w, http.StatusInternalServerError, "Gagal menghitung ukuran", err)
+        return
+    }
+    w.Header().Set("Content-Type", "application/json")
+    json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "data": suggestions})
+}
+
+// GetSizeRules - GET /api/catalog/{id}/size-rules
+func (h *MeasurementHandler) GetSizeRules(w http.ResponseWriter, r *http.Request) {
+    id, err := strconv.Atoi(mux.Vars(r)["id"])
+    if err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Invalid ID", nil)
+        return
+    }
+    rules, err := h.Repo.GetSizeRules(id)
+    if err != nil {
+        writeJSONError(w, http.StatusInternalServerError, "Gagal mengambil aturan ukuran", err)
+        return
+    }
+    w.Header().Set("Content-Type", "application/json")
+    json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "data": rules})
+}
+
+// SaveSizeRules - PUT /api/catalog/{id}/size-rules
+// body: {"rules": [{"size": "M", "chest": 84, "shirt_length": 62}, ...]} dalam cm, mengganti semua aturan
+func (h *MeasurementHandler) SaveSizeRules(w http.ResponseWriter, r *http.Request) {
+    id, err := strconv.Atoi(mux.Vars(r)["id"])
+    if err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Invalid ID", nil)
+        return
+    }
+    var req struct {
+        Rules []models.SizeRule `json:"rules"`
+    }
+    if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Invalid JSON", err)
+        return
+    }
+
+    if err := h.Repo.SaveSizeRules(id, req.Rules); err == sql.ErrNoRows {
+        writeJSONError(w, http.StatusNotFound, "Katalog tidak ditemukan", nil)
+        return
+    } else if err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Gagal menyimpan aturan ukuran", err)
+        return
+    }
+
+    rules, err := h.Repo.GetSizeRules(id)
+    if err != nil {
+        writeJSONError(w, http.StatusInternalServerError, "Gagal mengambil aturan ukuran", err)
+        return
+    }
+    w.Header().Set("Content-Type", "application/json")
+    json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "data": rules})
+}
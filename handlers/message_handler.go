@@ -0,0 +1,101 @@
+package handlers
+
+import (
+    "database/sql"
+    "encoding/json"
+    "errors"
+    "konveksi-app/messenger"
+    "log"
+    "net/http"
+    "strconv"
+
+    "github.com/gorilla/mux"
+)
+
+type MessageHandler struct {
+    Service *messenger.Service
+}
+
+// NotifyOrderReady - POST /api/transactions/{id}/notify-ready
+func (h *MessageHandler) NotifyOrderReady(w http.ResponseWriter, r *http.Request) {
+    id, err := strconv.Atoi(mux.Vars(r)["id"])
+    if err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Invalid ID", nil)
+        return
+    }
+
+    entry, err := h.Service.SendOrderReady(id)
+    if err == sql.ErrNoRows {
+        writeJSONError(w, http.StatusNotFound, "Transaksi tidak ditemukan", nil)
+        return
+    } else if errors.Is(err, messenger.ErrInvalidPhone) {
+        writeJSONError(w, http.StatusBadRequest, "Kontak customer tidak valid untuk WhatsApp/SMS", err)
+        return
+    } else if err != nil {
+        log.Printf("Error sending order ready message for transaction %d: %v", id, err)
+        writeJSONError(w, http.StatusBadGateway, "Pesan gagal dikirim", err)
+        return
+    }
+
+    w.Header().Set("Content-Type", "application/json")
+    json.NewEncoder(w).Encode(map[string]interface{}{
+        "success": true,
+        "message": "Pesan terkirim ke " + entry.Phone,
+        "data":    entry,
+    })
+}
+
+// GetCustomerMessages - GET /api/customers/{id}/messages
+func (h *MessageHandler) GetCustomerMessages(w http.ResponseWriter, r *http.Request) {
+    id, err := strconv.Atoi(mux.Vars(r)["id"])
+    if err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Invalid ID", nil)
+        return
+    }
+
+    logs, err := h.Service.Logs.GetByCustomerID(id)
+    if err != nil {
+        log.Printf("Error getting messages for customer %d: %v", id, err)
+        writeJSONError(w, http.StatusInternalServerError, "Gagal mengambil riwayat pesan", err)
+        return
+    }
+
+    w.Header().Set("Content-Type", "application/json")
+    json.NewEncoder(w).Encode(map[string]interface{}{
+        "success": true,
+        "data":    logs,
+    })
+}
+
+// SendTestMessage - POST /api/admin/messages/test, body: {"phone": "0812...", "message": "..."}
+func (h *MessageHandler) SendTestMessage(w http.ResponseWriter, r *http.Request) {
+    var req struct {
+        Phone   string `json:"phone"`
+        Message string `json:"message"`
+    }
+    if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Invalid JSON", err)
+        return
+    }
+
+    phone, err := messenger.NormalizePhone(req.Phone)
+    if err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Nomor telepon tidak valid", err)
+        return
+    }
+    if req.Message == "" {
+        req.Message = "Tes pesan dari sistem DiOlif Fashion."
+    }
+
+    if err := h.Service.Messenger.Send(phone, req.Message); err != nil {
+        writeJSONError(w, http.StatusBadGateway, "Pesan gagal dikirim", err)
+        return
+    }
+
+    w.Header().Set("Content-Type", "application/json")
+    json.NewEncoder(w).Encode(map[string]interface{}{
+        "success":  true,
+        "message":  "Pesan tes terkirim ke " + phone,
+        "provider": h.Service.Messenger.Name(),
+    })
+}
@@ -0,0 +1,168 @@
+package handlers
+
+import (
+    "database/sql"
+    "encoding/json"
+    "konveksi-app/repositories"
+    "log"
+    "net/http"
+    "strconv"
+    "time"
+
+    "github.com/gorilla/mux"
+)
+
+type NotificationHandler struct {
+    Repo  *repositories.NotificationRepository
+    Users *repositories.UserRepository
+}
+
+func writeJSONError(w http.ResponseWriter, status int, message string, err error) {
+    w.Header().Set("Content-Type", "application/json")
+    w.WriteHeader(status)
+    response := map[string]interface{}{
+        "success": false,
+        "error":   message,
+    }
+    if err != nil {
+        response["message"] = err.Error()
+    }
+    json.NewEncoder(w).Encode(response)
+}
+
+// currentUserID mengambil ID user yang sedang login dari session
+func (h *NotificationHandler) currentUserID(r *http.Request) (int, bool) {
+    username := GetSessionUsername(r)
+    if username == "" {
+        return 0, false
+    }
+    user, err := h.Users.GetByUsername(username)
+    if err != nil {
+        log.Printf("Error getting user %s: %v", username, err)
+        return 0, false
+    }
+    return user.ID, true
+}
+
+// ListNotifications - GET /api/notifications?unread=1
+// Notifikasi dibuat oleh job generate_notifications, endpoint ini hanya membaca
+func (h *NotificationHandler) ListNotifications(w http.ResponseWriter, r *http.Request) {
+    userID, ok := h.currentUserID(r)
+    if !ok {
+        writeJSONError(w, http.StatusUnauthorized, "User tidak dikenali", nil)
+        return
+    }
+
+    unreadOnly := r.URL.Query().Get("unread") == "1"
+    notifications, err := h.Repo.GetForUser(userID, unreadOnly)
+    if err != nil {
+        log.Printf("Error getting notifications: %v", err)
+        writeJSONError(w, http.StatusInternalServerError, "Gagal mengambil notifikasi", err)
+        return
+    }
+
+    unreadCount, err := h.Repo.CountUnread(userID)
+    if err != nil {
+        log.Printf("Error counting unread notifications: %v", err)
+        writeJSONError(w, http.StatusInternalServerError, "Gagal mengambil notifikasi", err)
+        return
+    }
+
+    w.Header().Set("Content-Type", "application/json")
+    json.NewEncoder(w).Encode(map[string]interface{}{
+        "success": true,
+        "data": map[string]interface{}{
+            "notifications":     notifications,
+            "total_count":       len(notifications),
+            "unread_count":      unreadCount,
+            "has_notifications": unreadCount > 0,
+        },
+    })
+}
+
+// notificationAction menjalankan aksi (read/dismiss/snooze) pada satu notifikasi
+func (h *NotificationHandler) notificationAction(w http.ResponseWriter, r *http.Request, action func(id, userID int) error) {
+    id, err := strconv.Atoi(mux.Vars(r)["id"])
+    if err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Invalid ID", nil)
+        return
+    }
+
+    userID, ok := h.currentUserID(r)
+    if !ok {
+        writeJSONError(w, http.StatusUnauthorized, "User tidak dikenali", nil)
+        return
+    }
+
+    if err := action(id, userID); err != nil {
+        if err == sql.ErrNoRows {
+            writeJSONError(w, http.StatusNotFound, "Notifikasi tidak ditemukan", nil)
+            return
+        }
+        log.Printf("Error updating notification %d: %v", id, err)
+        writeJSONError(w, http.StatusInternalServerError, "Gagal memperbarui notifikasi", err)
+        return
+    }
+
+    w.Header().Set("Content-Type", "application/json")
+    json.NewEncoder(w).Encode(map[string]interface{}{
+        "success": true,
+        "message": "Notifikasi diperbarui",
+    })
+}
+
+// MarkRead - PUT /api/notifications/{id}/read
+func (h *NotificationHandler) MarkRead(w http.ResponseWriter, r *http.Request) {
+    h.notificationAction(w, r, h.Repo.MarkRead)
+}
+
+// Dismiss - PUT /api/notifications/{id}/dismiss
+func (h *NotificationHandler) Dismiss(w http.ResponseWriter, r *http.Request) {
+    h.notificationAction(w, r, h.Repo.Dismiss)
+}
+
+// Snooze - PUT /api/notifications/{id}/snooze, body: {"until": "2006-01-02"}
+func (h *NotificationHandler) Snooze(w http.ResponseWriter, r *http.Request) {
+    var req struct {
+        Until string `json:"until"`
+    }
+    if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Invalid JSON", err)
+        return
+    }
+
+    until, err := time.Parse("2006-01-02", req.Until)
+    if err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Format tanggal harus YYYY-MM-DD", nil)
+        return
+    }
+    if !until.After(time.Now()) {
+        writeJSONError(w, http.StatusBadRequest, "Tanggal snooze harus setelah hari ini", nil)
+        return
+    }
+
+    h.notificationAction(w, r, func(id, userID int) error {
+        return h.Repo.Snooze(id, userID, req.Until)
+    })
+}
+
+// MarkAllRead - PUT /api/notifications/read-all
+func (h *NotificationHandler) MarkAllRead(w http.ResponseWriter, r *http.Request) {
+    userID, ok := h.currentUserID(r)
+    if !ok {
+        writeJSONError(w, http.StatusUnauthorized, "User tidak dikenali", nil)
+        return
+    }
+
+    if err := h.Repo.MarkAllRead(userID); err != nil {
+        log.Printf("Error marking all notifications read: %v", err)
+        writeJSONError(w, http.StatusInternalServerError, "Gagal memperbarui notifikasi", err)
+        return
+    }
+
+    w.Header().Set("Content-Type", "application/json")
+    json.NewEncoder(w).Encode(map[string]interface{}{
+        "success": true,
+        "message": "Semua notifikasi ditandai sudah dibaca",
+    })
+}
@@ -0,0 +1,240 @@
+package handlers
+
+import (
+    "database/sql"
+    "encoding/json"
+    "konveksi-app/models"
+    "konveksi-app/repositories"
+    "log"
+    "net/http"
+    "strconv"
+    "strings"
+    "time"
+
+    "github.com/gorilla/mux"
+)
+
+// PriceHandler menangani operasi massal pada daftar harga customer
+type PriceHandler struct {
+    Customers *repositories.CustomerRepository
+}
+
+// CopyPriceList - POST /api/customers/{id}/uniforms/copy
+// body: {"source_customer_id": 1, "adjust_percent": 10, "round_to": 500, "on_duplicate": "skip|overwrite", "confirm": false}
+// Tanpa confirm hanya mengembalikan preview.
+func (h *PriceHandler) CopyPriceList(w http.ResponseWriter, r *http.Request) {
+    targetID, err := strconv.Atoi(mux.Vars(r)["id"])
+    if err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Invalid customer ID", nil)
+        return
+    }
+
+    var req struct {
+        SourceCustomerID int     `json:"source_customer_id"`
+        AdjustPercent    float64 `json:"adjust_percent"`
+        RoundTo          float64 `json:"round_to"`
+        OnDuplicate      string  `json:"on_duplicate"`
+        Confirm          bool    `json:"confirm"`
+    }
+    if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Invalid JSON", err)
+        return
+    }
+    if req.SourceCustomerID == 0 {
+        writeJSONError(w, http.StatusBadRequest, "source_customer_id wajib diisi", nil)
+        return
+    }
+    if req.RoundTo != 0 && req.RoundTo != 500 && req.RoundTo != 1000 {
+        writeJSONError(w, http.StatusBadRequest, "round_to harus 0, 500 atau 1000", nil)
+        return
+    }
+    if req.AdjustPercent <= -100 {
+        writeJSONError(w, http.StatusBadRequest, "adjust_percent harus lebih dari -100", nil)
+        return
+    }
+    switch req.OnDuplicate {
+    case "":
+        req.OnDuplicate = "skip"
+    case "skip", "overwrite":
+    default:
+        writeJSONError(w, http.StatusBadRequest, "on_duplicate harus skip atau overwrite", nil)
+        return
+    }
+
+    opts := repositories.PriceCopyOptions{
+        AdjustPercent: req.AdjustPercent,
+        RoundTo:       req.RoundTo,
+        Overwrite:     req.OnDuplicate == "overwrite",
+        ChangedBy:     GetSessionUsername(r),
+    }
+    rows, err := h.Customers.CopyUniforms(req.SourceCustomerID, targetID, opts, req.Confirm)
+    if err == sql.ErrNoRows {
+        writeJSONError(w, http.StatusNotFound, "Customer tidak ditemukan", nil)
+        return
+    } else if err != nil {
+        log.Printf("Error copying price list %d -> %d: %v", req.SourceCustomerID, targetID, err)
+        writeJSONError(w, http.StatusBadRequest, "Gagal menyalin daftar harga", err)
+        return
+    }
+
+    summary := map[string]int{"create": 0, "overwrite": 0, "skip": 0}
+    for _, row := range rows {
+        summary[row.Action]++
+    }
+    if req.Confirm {
+        log.Printf("Copied price list from customer %d to %d: %v", req.SourceCustomerID, targetID, summary)
+    }
+
+    w.Header().Set("Content-Type", "application/json")
+    json.NewEncoder(w).Encode(map[string]interface{}{
+        "success": true,
+        "applied": req.Confirm,
+        "summary": summary,
+        "data":    rows,
+    })
+}
+
+// SchedulePrice - POST /api/customer-uniforms/{id}/scheduled-prices
+// body: {"price": 135000, "effective_from": "2026-07-01", "reason": "harga tahun ajaran baru"}
+// Tanggal hari ini atau sebelumnya langsung diterapkan.
+func (h *PriceHandler) SchedulePrice(w http.ResponseWriter, r *http.Request) {
+    id, err := strconv.Atoi(mux.Vars(r)["id"])
+    if err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Invalid ID", nil)
+        return
+    }
+
+    var p models.ScheduledPrice
+    if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Invalid JSON", err)
+        return
+    }
+    p.CustomerUniformID = id
+    p.ChangedBy = GetSessionUsername(r)
+
+    if err := h.Customers.SchedulePrice(&p); err == sql.ErrNoRows {
+        writeJSONError(w, http.StatusNotFound, "Daftar harga tidak ditemukan", nil)
+        return
+    } else if err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Gagal menjadwalkan harga", err)
+        return
+    }
+
+    w.Header().Set("Content-Type", "application/json")
+    w.WriteHeader(http.StatusCreated)
+    json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "data": p})
+}
+
+// GetScheduledPrices - GET /api/customer-uniforms/{id}/scheduled-prices
+func (h *PriceHandler) GetScheduledPrices(w http.ResponseWriter, r *http.Request) {
+    id, err := strconv.Atoi(mux.Vars(r)["id"])
+    if err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Invalid ID", nil)
+        return
+    }
+
+    prices, err := h.Customers.GetScheduledPrices(id)
+    if err != nil {
+        log.Printf("Error getting scheduled prices for uniform %d: %v", id, err)
+        writeJSONError(w, http.StatusInternalServerError, "Gagal mengambil jadwal harga", err)
+        return
+    }
+    current, err := h.Customers.GetPriceOnDate(id, time.Now().Format("2006-01-02"))
+    if err != nil && err != sql.ErrNoRows {
+        writeJSONError(w, http.StatusInternalServerError, "Gagal mengambil harga berlaku", err)
+        return
+    }
+
+    w.Header().Set("Content-Type", "application/json")
+    json.NewEncoder(w).Encode(map[string]interface{}{
+        "success":       true,
+        "current_price": current,
+        "data":          prices,
+    })
+}
+
+// DeleteScheduledPrice - DELETE /api/scheduled-prices/{id} (hanya jadwal yang belum berlaku)
+func (h *PriceHandler) DeleteScheduledPrice(w http.ResponseWriter, r *http.Request) {
+    id, err := strconv.Atoi(mux.Vars(r)["id"])
+    if err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Invalid ID", nil)
+        return
+    }
+    if err := h.Customers.DeleteScheduledPrice(id); err == sql.ErrNoRows {
+        writeJSONError(w, http.StatusNotFound, "Jadwal harga tidak ditemukan atau sudah berlaku", nil)
+        return
+    } else if err != nil {
+        writeJSONError(w, http.StatusInternalServerError, "Gagal menghapus jadwal harga", err)
+        return
+    }
+    w.WriteHeader(http.StatusNoContent)
+}
+
+// AdjustPrices - POST /api/admin/prices/adjust
+// body: {"customer_type": "SD", "uniform_pattern": "kemeja*", "size": "", "mode": "percent|fixed",
+// "amount": 10, "round_to": 500, "reason": "kenaikan harga kain", "confirm": false}
+// Tanpa confirm hanya mengembalikan preview.
+func (h *PriceHandler) AdjustPrices(w http.ResponseWriter, r *http.Request) {
+    var req struct {
+        CustomerType   string  `json:"customer_type"`
+        UniformPattern string  `json:"uniform_pattern"`
+        Size           string  `json:"size"`
+        Mode           string  `json:"mode"`
+        Amount         float64 `json:"amount"`
+        RoundTo        float64 `json:"round_to"`
+        Reason         string  `json:"reason"`
+        Confirm        bool    `json:"confirm"`
+    }
+    if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Invalid JSON", err)
+        return
+    }
+    if req.RoundTo != 0 && req.RoundTo != 500 && req.RoundTo != 1000 {
+        writeJSONError(w, http.StatusBadRequest, "round_to harus 0, 500 atau 1000", nil)
+        return
+    }
+    if req.Amount == 0 {
+        writeJSONError(w, http.StatusBadRequest, "amount tidak boleh 0", nil)
+        return
+    }
+    if req.Confirm && strings.TrimSpace(req.Reason) == "" {
+        writeJSONError(w, http.StatusBadRequest, "Alasan perubahan harga wajib diisi", nil)
+        return
+    }
+
+    adjustment := repositories.PriceAdjustment{
+        CustomerType:   req.CustomerType,
+        UniformPattern: req.UniformPattern,
+        Size:           req.Size,
+        Mode:           req.Mode,
+        Amount:         req.Amount,
+        RoundTo:        req.RoundTo,
+        ChangedBy:      GetSessionUsername(r),
+        Reason:         req.Reason,
+    }
+    rows, err := h.Customers.AdjustPrices(adjustment, req.Confirm)
+    if err != nil {
+        log.Printf("Error adjusting prices: %v", err)
+        writeJSONError(w, http.StatusBadRequest, "Gagal menyesuaikan harga", err)
+        return
+    }
+
+    changed := 0
+    for _, row := range rows {
+        if row.NewPrice != row.OldPrice {
+            changed++
+        }
+    }
+    if req.Confirm {
+        log.Printf("Price adjustment by %s applied to %d rows (%s %.2f)", adjustment.ChangedBy, changed, req.Mode, req.Amount)
+    }
+
+    w.Header().Set("Content-Type", "application/json")
+    json.NewEncoder(w).Encode(map[string]interface{}{
+        "success": true,
+        "applied": req.Confirm,
+        "matched": len(rows),
+        "changed": changed,
+        "data":    rows,
+    })
+}
@@ -0,0 +1,91 @@
+package handlers
+
+import (
+    "database/sql"
+    "encoding/json"
+    "fmt"
+    "konveksi-app/documents"
+    "konveksi-app/repositories"
+    "log"
+    "net/http"
+    "strconv"
+    "time"
+
+    "github.com/gorilla/mux"
+)
+
+type PriceListHandler struct {
+    Repo      *repositories.PriceRepository
+    Customers *repositories.CustomerRepository
+}
+
+// GetPrices - GET /api/prices?customer_id=&customer_type=&uniform=
+func (h *PriceListHandler) GetPrices(w http.ResponseWriter, r *http.Request) {
+    q := r.URL.Query()
+    filter := repositories.PriceFilter{
+        CustomerType: q.Get("customer_type"),
+        Uniform:      q.Get("uniform"),
+    }
+    if v := q.Get("customer_id"); v != "" {
+        id, err := strconv.Atoi(v)
+        if err != nil {
+            writeJSONError(w, http.StatusBadRequest, "customer_id tidak valid", nil)
+            return
+        }
+        filter.CustomerID = id
+    }
+
+    prices, err := h.Repo.GetPrices(filter)
+    if err != nil {
+        log.Printf("Error getting prices: %v", err)
+        writeJSONError(w, http.StatusInternalServerError, "Gagal mengambil daftar harga", err)
+        return
+    }
+
+    w.Header().Set("Content-Type", "application/json")
+    json.NewEncoder(w).Encode(map[string]interface{}{
+        "success": true,
+        "data":    prices,
+        "total":   len(prices),
+    })
+}
+
+// PrintPriceList - GET /api/customers/{id}/price-list.pdf
+func (h *PriceListHandler) PrintPriceList(w http.ResponseWriter, r *http.Request) {
+    id, err := strconv.Atoi(mux.Vars(r)["id"])
+    if err != nil {
+        http.Error(w, "Invalid ID", http.StatusBadRequest)
+        return
+    }
+
+    customer, err := h.Customers.GetByID(id)
+    if err == sql.ErrNoRows {
+        http.Error(w, "Customer not found", http.StatusNotFound)
+        return
+    } else if err != nil {
+        http.Error(w, err.Error(), http.StatusInternalServerError)
+        return
+    }
+
+    prices, err := h.Repo.GetPrices(repositories.PriceFilter{CustomerID: id})
+    if err != nil {
+        log.Printf("Error getting price list for customer %d: %v", id, err)
+        http.Error(w, err.Error(), http.StatusInternalServerError)
+        return
+    }
+    items := make([]documents.PriceListItem, 0, len(prices))
+    for _, p := range prices {
+        items = append(items, documents.PriceListItem{UniformName: p.UniformName, Size: p.Size, Price: p.Price})
+    }
+
+    pdf, err := documents.PriceList(customer, items)
+    if err != nil {
+        log.Printf("Error generating price list PDF for customer %d: %v", id, err)
+        http.Error(w, "Failed to generate PDF", http.StatusInternalServerError)
+        return
+    }
+
+    w.Header().Set("Content-Type", "application/pdf")
+    w.Header().Set("Content-Disposition", fmt.Sprintf("inline; filename=\"daftar_harga_%d_%s.pdf\"", id, time.Now().Format("20060102")))
+    w.Write(pdf)
+}
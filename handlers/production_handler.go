@@ -0,0 +1,231 @@
+package handlers
+
+import (
+    "database/sql"
+    "encoding/json"
+    "fmt"
+    "konveksi-app/documents"
+    "konveksi-app/repositories"
+    "konveksi-app/spreadsheet"
+    "log"
+    "math"
+    "net/http"
+    "strconv"
+    "strings"
+    "time"
+
+    "github.com/gorilla/mux"
+)
+
+type ProductionHandler struct {
+    Repo *repositories.ProductionRepository
+    // AutoDeductMaterials memotong stok bahan sesuai BOM saat transaksi masuk tahap potong
+    AutoDeductMaterials bool
+}
+
+// parseIDList membaca "1,2,3" menjadi []int
+func parseIDList(value string) ([]int, error) {
+    var ids []int
+    for _, part := range strings.Split(value, ",") {
+        part = strings.TrimSpace(part)
+        if part == "" {
+            continue
+        }
+        id, err := strconv.Atoi(part)
+        if err != nil {
+            return nil, fmt.Errorf("ID '%s' tidak valid", part)
+        }
+        ids = append(ids, id)
+    }
+    return ids, nil
+}
+
+func parseRecapFilter(r *http.Request) (repositories.RecapFilter, error) {
+    q := r.URL.Query()
+    var f repositories.RecapFilter
+    var err error
+
+    if f.TransactionIDs, err = parseIDList(q.Get("transaction_ids")); err != nil {
+        return f, err
+    }
+    if f.CustomerIDs, err = parseIDList(q.Get("customer_ids")); err != nil {
+        return f, err
+    }
+    for _, d := range []struct {
+        name  string
+        value *string
+    }{{"from", &f.From}, {"to", &f.To}} {
+        v := q.Get(d.name)
+        if v == "" {
+            continue
+        }
+        if _, err := time.Parse("2006-01-02", v); err != nil {
+            return f, fmt.Errorf("parameter %s harus berformat YYYY-MM-DD", d.name)
+        }
+        *d.value = v
+    }
+    f.Status = q.Get("status")
+    return f, nil
+}
+
+func recapFilterInfo(f repositories.RecapFilter) []string {
+    var info []string
+    if len(f.TransactionIDs) > 0 {
+        ids := make([]string, len(f.TransactionIDs))
+        for i, id := range f.TransactionIDs {
+            ids[i] = "#" + strconv.Itoa(id)
+        }
+        info = append(info, "Transaksi: "+strings.Join(ids, ", "))
+    }
+    if len(f.CustomerIDs) > 0 {
+        info = append(info, fmt.Sprintf("Customer ID: %v", f.CustomerIDs))
+    }
+    if f.From != "" || f.To != "" {
+        info = append(info, fmt.Sprintf("Periode: %s s/d %s", documents.FormatDate(f.From), documents.FormatDate(f.To)))
+    }
+    if f.Status != "" {
+        info = append(info, "Status: "+f.Status)
+    }
+    return info
+}
+
+// GetRecap - GET /api/production/recap?transaction_ids=1,2&customer_ids=3&from=&to=&status=&format=json|csv|pdf
+func (h *ProductionHandler) GetRecap(w http.ResponseWriter, r *http.Request) {
+    filter, err := parseRecapFilter(r)
+    if err != nil {
+        writeJSONError(w, http.StatusBadRequest, err.Error(), nil)
+        return
+    }
+
+    recaps, err := h.Repo.GetSizeRecap(filter)
+    if err != nil {
+        log.Printf("Error getting production recap: %v", err)
+        writeJSONError(w, http.StatusInternalServerError, "Gagal mengambil rekap ukuran", err)
+        return
+    }
+
+    filename := "rekap_ukuran_" + time.Now().Format("20060102")
+    switch r.URL.Query().Get("format") {
+    case "csv":
+        rows := [][]string{}
+        for _, recap := range recaps {
+            for _, item := range recap.Sizes {
+                rows = append(rows, []string{recap.UniformName, item.Size, strconv.Itoa(item.Quantity), strconv.Itoa(item.TransactionCount)})
+            }
+        }
+        w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+        w.Header().Set("Content-Disposition", "attachment; filename=\""+filename+".csv\"")
+        if err := spreadsheet.WriteCSV(w, []string{"uniform_name", "size", "quantity", "transaction_count"}, rows); err != nil {
+            log.Printf("Error writing recap CSV: %v", err)
+        }
+    case "pdf":
+        pdf, err := documents.CuttingSheet(recaps, recapFilterInfo(filter))
+        if err != nil {
+            log.Printf("Error generating cutting sheet: %v", err)
+            writeJSONError(w, http.StatusInternalServerError, "Gagal membuat lembar potong", err)
+            return
+        }
+        w.Header().Set("Content-Type", "application/pdf")
+        w.Header().Set("Content-Disposition", "inline; filename=\""+filename+".pdf\"")
+        w.Write(pdf)
+    default:
+        grandTotal := 0
+        for _, recap := range recaps {
+            grandTotal += recap.Total
+        }
+        w.Header().Set("Content-Type", "application/json")
+        json.NewEncoder(w).Encode(map[string]interface{}{
+            "success": true,
+            "data": map[string]interface{}{
+                "uniforms":    recaps,
+                "grand_total": grandTotal,
+            },
+        })
+    }
+}
+
+// GetMaterialRequirements - GET /api/production/materials?transaction_ids=&customer_ids=&from=&to=&status=&allowance=5&format=json|csv
+// Filter sama dengan rekap ukuran; allowance adalah cadangan (%) untuk bahan yang terbuang.
+func (h *ProductionHandler) GetMaterialRequirements(w http.ResponseWriter, r *http.Request) {
+    filter, err := parseRecapFilter(r)
+    if err != nil {
+        writeJSONError(w, http.StatusBadRequest, err.Error(), nil)
+        return
+    }
+    allowance := 0.0
+    if v := r.URL.Query().Get("allowance"); v != "" {
+        if allowance, err = strconv.ParseFloat(v, 64); err != nil || allowance < 0 || allowance > 100 {
+            writeJSONError(w, http.StatusBadRequest, "allowance harus 0-100", nil)
+            return
+        }
+    }
+
+    requirements, uncovered, err := h.Repo.MaterialRequirements(filter)
+    if err != nil {
+        log.Printf("Error calculating material requirements: %v", err)
+        writeJSONError(w, http.StatusInternalServerError, "Gagal menghitung kebutuhan bahan", err)
+        return
+    }
+    if allowance > 0 {
+        for i := range requirements {
+            requirements[i].Quantity = math.Round(requirements[i].Quantity*(1+allowance/100)*1000) / 1000
+        }
+    }
+
+    if r.URL.Query().Get("format") == "csv" {
+        rows := [][]string{}
+        for _, req := range requirements {
+            rows = append(rows, []string{req.Name, req.Category, strconv.FormatFloat(req.Quantity, 'f', -1, 64), req.Unit})
+        }
+        w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+        w.Header().Set("Content-Disposition", "attachment; filename=\"kebutuhan_bahan_"+time.Now().Format("20060102")+".csv\"")
+        if err := spreadsheet.WriteCSV(w, []string{"material", "category", "quantity", "unit"}, rows); err != nil {
+            log.Printf("Error writing material requirements CSV: %v", err)
+        }
+        return
+    }
+
+    w.Header().Set("Content-Type", "application/json")
+    json.NewEncoder(w).Encode(map[string]interface{}{
+        "success": true,
+        "data": map[string]interface{}{
+            "materials": requirements,
+            "uncovered": uncovered,
+            "allowance": allowance,
+        },
+    })
+}
+
+// SetProductionStage - PUT /api/transactions/{id}/production-stage
+// body: {"stage": "potong", "deduct_materials": true}; deduct_materials kosong mengikuti AUTO_DEDUCT_MATERIALS
+func (h *ProductionHandler) SetProductionStage(w http.ResponseWriter, r *http.Request) {
+    id, err := strconv.Atoi(mux.Vars(r)["id"])
+    if err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Invalid ID", nil)
+        return
+    }
+    var req struct {
+        Stage           string `json:"stage"`
+        DeductMaterials *bool  `json:"deduct_materials"`
+    }
+    if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Invalid JSON", err)
+        return
+    }
+    deduct := h.AutoDeductMaterials
+    if req.DeductMaterials != nil {
+        deduct = *req.DeductMaterials
+    }
+
+    change, err := h.Repo.SetStage(id, strings.ToLower(strings.TrimSpace(req.Stage)), deduct, GetSessionUsername(r))
+    if err == sql.ErrNoRows {
+        writeJSONError(w, http.StatusNotFound, "Transaksi tidak ditemukan", nil)
+        return
+    } else if err != nil {
+        log.Printf("Error setting production stage for transaction %d: %v", id, err)
+        writeJSONError(w, http.StatusBadRequest, "Gagal mengubah tahap produksi", err)
+        return
+    }
+    w.Header().Set("Content-Type", "application/json")
+    json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "data": change})
+}
@@ -0,0 +1,169 @@
+package handlers
+
+import (
+    "database/sql"
+    "encoding/json"
+    "konveksi-app/models"
+    "konveksi-app/repositories"
+    "log"
+    "net/http"
+    "strconv"
+
+    "github.com/gorilla/mux"
+)
+
+type PurchaseOrderHandler struct {
+    Repo *repositories.PurchaseOrderRepository
+}
+
+func (h *PurchaseOrderHandler) writeOrder(w http.ResponseWriter, id, status int) {
+    po, err := h.Repo.GetByID(id)
+    if err != nil {
+        writeJSONError(w, http.StatusInternalServerError, "Gagal mengambil PO", err)
+        return
+    }
+    w.Header().Set("Content-Type", "application/json")
+    w.WriteHeader(status)
+    json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "data": po})
+}
+
+// GetPurchaseOrders - GET /api/purchase-orders?supplier_id=&status=
+func (h *PurchaseOrderHandler) GetPurchaseOrders(w http.ResponseWriter, r *http.Request) {
+    q := r.URL.Query()
+    var f repositories.PurchaseOrderFilter
+    f.SupplierID, _ = strconv.Atoi(q.Get("supplier_id"))
+    f.Status = q.Get("status")
+    orders, err := h.Repo.GetAll(f)
+    if err != nil {
+        log.Printf("Error getting purchase orders: %v", err)
+        writeJSONError(w, http.StatusInternalServerError, "Gagal mengambil data PO", err)
+        return
+    }
+    w.Header().Set("Content-Type", "application/json")
+    json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "data": orders})
+}
+
+// GetPurchaseOrder - GET /api/purchase-orders/{id}
+func (h *PurchaseOrderHandler) GetPurchaseOrder(w http.ResponseWriter, r *http.Request) {
+    id, err := strconv.Atoi(mux.Vars(r)["id"])
+    if err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Invalid ID", nil)
+        return
+    }
+    po, err := h.Repo.GetByID(id)
+    if err == sql.ErrNoRows {
+        writeJSONError(w, http.StatusNotFound, "PO tidak ditemukan", nil)
+        return
+    } else if err != nil {
+        writeJSONError(w, http.StatusInternalServerError, "Gagal mengambil PO", err)
+        return
+    }
+    w.Header().Set("Content-Type", "application/json")
+    json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "data": po})
+}
+
+// CreatePurchaseOrder - POST /api/purchase-orders
+// body: {"supplier_id": 1, "order_date": "2025-07-01", "expected_date": "", "status": "draft|ordered",
+//        "lines": [{"material_id": 1, "quantity": 100, "unit_price": 25000}]}
+func (h *PurchaseOrderHandler) CreatePurchaseOrder(w http.ResponseWriter, r *http.Request) {
+    var po models.PurchaseOrder
+    if err := json.NewDecoder(r.Body).Decode(&po); err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Invalid JSON", err)
+        return
+    }
+    po.CreatedBy = GetSessionUsername(r)
+    if err := h.Repo.Create(&po); err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Gagal menyimpan PO", err)
+        return
+    }
+    h.writeOrder(w, po.ID, http.StatusCreated)
+}
+
+// UpdatePurchaseOrder - PUT /api/purchase-orders/{id}, hanya untuk PO draft; lines mengganti seluruh isi
+func (h *PurchaseOrderHandler) UpdatePurchaseOrder(w http.ResponseWriter, r *http.Request) {
+    id, err := strconv.Atoi(mux.Vars(r)["id"])
+    if err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Invalid ID", nil)
+        return
+    }
+    var po models.PurchaseOrder
+    if err := json.NewDecoder(r.Body).Decode(&po); err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Invalid JSON", err)
+        return
+    }
+    po.ID = id
+    if err := h.Repo.Update(&po); err == sql.ErrNoRows {
+        writeJSONError(w, http.StatusNotFound, "PO tidak ditemukan", nil)
+        return
+    } else if err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Gagal menyimpan PO", err)
+        return
+    }
+    h.writeOrder(w, id, http.StatusOK)
+}
+
+// DeletePurchaseOrder - DELETE /api/purchase-orders/{id}
+func (h *PurchaseOrderHandler) DeletePurchaseOrder(w http.ResponseWriter, r *http.Request) {
+    id, err := strconv.Atoi(mux.Vars(r)["id"])
+    if err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Invalid ID", nil)
+        return
+    }
+    if err := h.Repo.Delete(id); err == sql.ErrNoRows {
+        writeJSONError(w, http.StatusNotFound, "PO tidak ditemukan", nil)
+        return
+    } else if err != nil {
+        writeJSONError(w, http.StatusConflict, "Gagal menghapus PO", err)
+        return
+    }
+    w.WriteHeader(http.StatusNoContent)
+}
+
+// UpdatePurchaseOrderStatus - PUT /api/purchase-orders/{id}/status
+// body: {"status": "ordered|cancelled"}
+func (h *PurchaseOrderHandler) UpdatePurchaseOrderStatus(w http.ResponseWriter, r *http.Request) {
+    id, err := strconv.Atoi(mux.Vars(r)["id"])
+    if err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Invalid ID", nil)
+        return
+    }
+    var req struct {
+        Status string `json:"status"`
+    }
+    if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Invalid JSON", err)
+        return
+    }
+    if err := h.Repo.SetStatus(id, req.Status); err == sql.ErrNoRows {
+        writeJSONError(w, http.StatusNotFound, "PO tidak ditemukan", nil)
+        return
+    } else if err != nil {
+        writeJSONError(w, http.StatusConflict, "Gagal mengubah status PO", err)
+        return
+    }
+    h.writeOrder(w, id, http.StatusOK)
+}
+
+// ReceiveGoods - POST /api/purchase-orders/{id}/receipts
+// body: {"received_date": "2025-07-03", "reference": "SJ-001", "lines": [{"purchase_order_line_id": 5, "quantity": 60}]}
+func (h *PurchaseOrderHandler) ReceiveGoods(w http.ResponseWriter, r *http.Request) {
+    id, err := strconv.Atoi(mux.Vars(r)["id"])
+    if err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Invalid ID", nil)
+        return
+    }
+    var receipt models.GoodsReceipt
+    if err := json.NewDecoder(r.Body).Decode(&receipt); err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Invalid JSON", err)
+        return
+    }
+    receipt.ReceivedBy = GetSessionUsername(r)
+    if err := h.Repo.Receive(id, &receipt); err == sql.ErrNoRows {
+        writeJSONError(w, http.StatusNotFound, "PO tidak ditemukan", nil)
+        return
+    } else if err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Gagal mencatat penerimaan barang", err)
+        return
+    }
+    h.writeOrder(w, id, http.StatusCreated)
+}
@@ -0,0 +1,41 @@
+package handlers
+
+import (
+    "konveksi-app/repositories"
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "testing"
+
+    "github.com/DATA-DOG/go-sqlmock"
+    "github.com/gorilla/mux"
+)
+
+func TestReceiveGoodsDuplicateLineIsBadRequest(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer db.Close()
+    h := &PurchaseOrderHandler{Repo: &repositories.PurchaseOrderRepository{DB: db}}
+
+    mock.ExpectBegin()
+    mock.ExpectQuery("SELECT status FROM purchase_orders").WithArgs(4).
+        WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow("partially_received"))
+    mock.ExpectQuery("FROM purchase_order_lines l").WithArgs(4).
+        WillReturnRows(sqlmock.NewRows([]string{"id", "material_id", "name", "unit", "quantity", "received_quantity", "unit_price", "notes"}).
+            AddRow(11, 2, "Kancing kemeja", "pcs", 500.0, 200.0, 150.0, ""))
+    mock.ExpectRollback()
+
+    body := `{"received_date": "2025-07-01", "lines": [{"purchase_order_line_id": 11, "quantity": 200}, {"purchase_order_line_id": 11, "quantity": 200}]}`
+    req := mux.SetURLVars(httptest.NewRequest("POST", "/api/purchase-orders/4/receipts", strings.NewReader(body)), map[string]string{"id": "4"})
+    rec := httptest.NewRecorder()
+    h.ReceiveGoods(rec, req)
+
+    if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), "sudah diisi di baris 1") {
+        t.Fatalf("status = %d, body = %s; want 400 for duplicate line", rec.Code, rec.Body.String())
+    }
+    if err := mock.ExpectationsWereMet(); err != nil {
+        t.Fatal(err)
+    }
+}
@@ -0,0 +1,214 @@
+package handlers
+
+import (
+    "database/sql"
+    "encoding/json"
+    "fmt"
+    "konveksi-app/documents"
+    "konveksi-app/models"
+    "konveksi-app/repositories"
+    "log"
+    "net/http"
+    "strconv"
+    "strings"
+
+    "github.com/gorilla/mux"
+)
+
+type SizeChartHandler struct {
+    Repo      *repositories.SizeChartRepository
+    Customers *repositories.CustomerRepository
+}
+
+// GetSizeCharts - GET /api/size-charts?catalog_id=&customer_id=
+func (h *SizeChartHandler) GetSizeCharts(w http.ResponseWriter, r *http.Request) {
+    q := r.URL.Query()
+    catalogID, _ := strconv.Atoi(q.Get("catalog_id"))
+    customerID, _ := strconv.Atoi(q.Get("customer_id"))
+
+    charts, err := h.Repo.GetAll(catalogID, customerID)
+    if err != nil {
+        log.Printf("Error getting size charts: %v", err)
+        writeJSONError(w, http.StatusInternalServerError, "Gagal mengambil tabel ukuran", err)
+        return
+    }
+    w.Header().Set("Content-Type", "application/json")
+    json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "data": charts})
+}
+
+// GetSizeChart - GET /api/size-charts/{id}
+func (h *SizeChartHandler) GetSizeChart(w http.ResponseWriter, r *http.Request) {
+    id, err := strconv.Atoi(mux.Vars(r)["id"])
+    if err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Invalid ID", nil)
+        return
+    }
+    chart, err := h.Repo.GetByID(id)
+    if err == sql.ErrNoRows {
+        writeJSONError(w, http.StatusNotFound, "Tabel ukuran tidak ditemukan", nil)
+        return
+    } else if err != nil {
+        writeJSONError(w, http.StatusInternalServerError, "Gagal mengambil tabel ukuran", err)
+        return
+    }
+    w.Header().Set("Content-Type", "application/json")
+    json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "data": chart})
+}
+
+// CreateSizeChart - POST /api/size-charts
+// body: {"name", "catalog_id", "customer_id", "notes", "entries": [{"size": "S", "chest": {"min": 76, "max": 82}}, ...]}
+// entries diurutkan dari ukuran terkecil
+func (h *SizeChartHandler) CreateSizeChart(w http.ResponseWriter, r *http.Request) {
+    var chart models.SizeChart
+    if err := json.NewDecoder(r.Body).Decode(&chart); err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Invalid JSON", err)
+        return
+    }
+    if err := h.Repo.Create(&chart); err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Gagal menyimpan tabel ukuran", err)
+        return
+    }
+    saved, err := h.Repo.GetByID(chart.ID)
+    if err != nil {
+        writeJSONError(w, http.StatusInternalServerError, "Gagal mengambil tabel ukuran", err)
+        return
+    }
+    w.Header().Set("Content-Type", "application/json")
+    w.WriteHeader(http.StatusCreated)
+    json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "data": saved})
+}
+
+// UpdateSizeChart - PUT /api/size-charts/{id}, entries mengganti seluruh isi tabel
+func (h *SizeChartHandler) UpdateSizeChart(w http.ResponseWriter, r *http.Request) {
+    id, err := strconv.Atoi(mux.Vars(r)["id"])
+    if err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Invalid ID", nil)
+        return
+    }
+    var chart models.SizeChart
+    if err := json.NewDecoder(r.Body).Decode(&chart); err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Invalid JSON", err)
+        return
+    }
+    chart.ID = id
+
+    if err := h.Repo.Update(&chart); err == sql.ErrNoRows {
+        writeJSONError(w, http.StatusNotFound, "Tabel ukuran tidak ditemukan", nil)
+        return
+    } else if err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Gagal menyimpan tabel ukuran", err)
+        return
+    }
+    saved, err := h.Repo.GetByID(id)
+    if err != nil {
+        writeJSONError(w, http.StatusInternalServerError, "Gagal mengambil tabel ukuran", err)
+        return
+    }
+    w.Header().Set("Content-Type", "application/json")
+    json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "data": saved})
+}
+
+// DeleteSizeChart - DELETE /api/size-charts/{id}
+func (h *SizeChartHandler) DeleteSizeChart(w http.ResponseWriter, r *http.Request) {
+    id, err := strconv.Atoi(mux.Vars(r)["id"])
+    if err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Invalid ID", nil)
+        return
+    }
+    if err := h.Repo.Delete(id); err == sql.ErrNoRows {
+        writeJSONError(w, http.StatusNotFound, "Tabel ukuran tidak ditemukan", nil)
+        return
+    } else if err != nil {
+        writeJSONError(w, http.StatusInternalServerError, "Gagal menghapus tabel ukuran", err)
+        return
+    }
+    w.WriteHeader(http.StatusNoContent)
+}
+
+func writeSizeGuide(w http.ResponseWriter, filename, customerName string, charts []documents.SizeGuideChart) {
+    pdf, err := documents.SizeGuide(customerName, charts)
+    if err != nil {
+        log.Printf("Error generating size guide PDF: %v", err)
+        http.Error(w, "Failed to generate PDF", http.StatusInternalServerError)
+        return
+    }
+    w.Header().Set("Content-Type", "application/pdf")
+    w.Header().Set("Content-Disposition", fmt.Sprintf("inline; filename=\"%s\"", filename))
+    w.Write(pdf)
+}
+
+// PrintSizeChart - GET /api/size-charts/{id}/guide.pdf
+func (h *SizeChartHandler) PrintSizeChart(w http.ResponseWriter, r *http.Request) {
+    id, err := strconv.Atoi(mux.Vars(r)["id"])
+    if err != nil {
+        http.Error(w, "Invalid ID", http.StatusBadRequest)
+        return
+    }
+    chart, err := h.Repo.GetByID(id)
+    if err == sql.ErrNoRows {
+        http.Error(w, "Size chart not found", http.StatusNotFound)
+        return
+    } else if err != nil {
+        http.Error(w, err.Error(), http.StatusInternalServerError)
+        return
+    }
+    guide := documents.SizeGuideChart{Chart: *chart}
+    if chart.CatalogName != "" {
+        guide.Uniforms = []string{chart.CatalogName}
+    }
+    writeSizeGuide(w, fmt.Sprintf("panduan_ukuran_%d.pdf", id), chart.CustomerName, []documents.SizeGuideChart{guide})
+}
+
+// PrintCustomerSizeGuide - GET /api/customers/{id}/size-guide.pdf
+// Panduan ukuran semua seragam sekolah, satu tabel per tabel ukuran yang berlaku.
+func (h *SizeChartHandler) PrintCustomerSizeGuide(w http.ResponseWriter, r *http.Request) {
+    id, err := strconv.Atoi(mux.Vars(r)["id"])
+    if err != nil {
+        http.Error(w, "Invalid ID", http.StatusBadRequest)
+        return
+    }
+    customer, err := h.Customers.GetByID(id)
+    if err == sql.ErrNoRows {
+        http.Error(w, "Customer not found", http.StatusNotFound)
+        return
+    } else if err != nil {
+        http.Error(w, err.Error(), http.StatusInternalServerError)
+        return
+    }
+    uniforms, err := h.Customers.GetUniformsByCustomerID(id)
+    if err != nil {
+        http.Error(w, err.Error(), http.StatusInternalServerError)
+        return
+    }
+    chartIDs, err := h.Repo.CustomerChartIDs(id)
+    if err != nil {
+        log.Printf("Error getting size charts for customer %d: %v", id, err)
+        http.Error(w, err.Error(), http.StatusInternalServerError)
+        return
+    }
+
+    var guides []documents.SizeGuideChart
+    index := map[int]int{}
+    seen := map[string]bool{}
+    for _, u := range uniforms {
+        key := strings.ToLower(strings.TrimSpace(u.UniformName))
+        chartID, ok := chartIDs[key]
+        if !ok || seen[key] {
+            continue
+        }
+        seen[key] = true
+        i, ok := index[chartID]
+        if !ok {
+            chart, err := h.Repo.GetByID(chartID)
+            if err != nil {
+                http.Error(w, err.Error(), http.StatusInternalServerError)
+                return
+            }
+            i = len(guides)
+            index[chartID] = i
+            guides = append(guides, documents.SizeGuideChart{Chart: *chart})
+        }
+        guides[i].Uniforms = append(guides[i].Uniforms, u.UniformName)
+    }
+    writeSizeGuide(w, fmt.Sprintf("panduan_ukuran_customer_%d.pdf", id), customer.Name, guides)
+}
@@ -0,0 +1,245 @@
+package handlers
+
+import (
+    "database/sql"
+    "encoding/json"
+    "fmt"
+    "konveksi-app/models"
+    "konveksi-app/repositories"
+    "log"
+    "net/http"
+    "strconv"
+    "strings"
+    "time"
+
+    "github.com/go-sql-driver/mysql"
+    "github.com/gorilla/mux"
+)
+
+type StudentHandler struct {
+    Repo *repositories.StudentRepository
+}
+
+// writeStudentSaveError membedakan NIS dobel dari error lain
+func writeStudentSaveError(w http.ResponseWriter, err error) {
+    if mysqlErr, ok := err.(*mysql.MySQLError); ok && mysqlErr.Number == 1062 {
+        writeJSONError(w, http.StatusConflict, "NIS sudah dipakai siswa lain di sekolah ini", nil)
+        return
+    }
+    log.Printf("Error saving student: %v", err)
+    writeJSONError(w, http.StatusInternalServerError, "Gagal menyimpan data siswa", err)
+}
+
+// SearchStudents - GET /api/students?customer_id=&class=&q=&include_inactive=true&limit=
+// juga dipakai lewat GET /api/customers/{id}/students
+func (h *StudentHandler) SearchStudents(w http.ResponseWriter, r *http.Request) {
+    q := r.URL.Query()
+    filter := repositories.StudentFilter{
+        Class:           q.Get("class"),
+        Query:           q.Get("q"),
+        IncludeInactive: q.Get("include_inactive") == "true",
+    }
+
+    customerID := mux.Vars(r)["id"]
+    if customerID == "" {
+        customerID = q.Get("customer_id")
+    }
+    if customerID != "" {
+        id, err := strconv.Atoi(customerID)
+        if err != nil {
+            writeJSONError(w, http.StatusBadRequest, "customer_id tidak valid", nil)
+            return
+        }
+        filter.CustomerID = id
+    }
+    if v := q.Get("limit"); v != "" {
+        filter.Limit, _ = strconv.Atoi(v)
+    }
+
+    students, err := h.Repo.Search(filter)
+    if err != nil {
+        log.Printf("Error searching students: %v", err)
+        writeJSONError(w, http.StatusInternalServerError, "Gagal mengambil data siswa", err)
+        return
+    }
+    w.Header().Set("Content-Type", "application/json")
+    json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "data": students})
+}
+
+// GetStudent - GET /api/students/{id}
+func (h *StudentHandler) GetStudent(w http.ResponseWriter, r *http.Request) {
+    id, err := strconv.Atoi(mux.Vars(r)["id"])
+    if err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Invalid ID", nil)
+        return
+    }
+    student, err := h.Repo.GetByID(id)
+    if err == sql.ErrNoRows {
+        writeJSONError(w, http.StatusNotFound, "Siswa tidak ditemukan", nil)
+        return
+    } else if err != nil {
+        writeJSONError(w, http.StatusInternalServerError, "Gagal mengambil data siswa", err)
+        return
+    }
+    w.Header().Set("Content-Type", "application/json")
+    json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "data": student})
+}
+
+func decodeStudent(r *http.Request) (*models.Student, error) {
+    student := models.Student{Active: true}
+    if err := json.NewDecoder(r.Body).Decode(&student); err != nil {
+        return nil, err
+    }
+    student.Name = strings.Join(strings.Fields(student.Name), " ")
+    student.Class = strings.TrimSpace(student.Class)
+    student.NIS = strings.TrimSpace(student.NIS)
+    return &student, nil
+}
+
+// CreateStudent - POST /api/customers/{id}/students
+func (h *StudentHandler) CreateStudent(w http.ResponseWriter, r *http.Request) {
+    customerID, err := strconv.Atoi(mux.Vars(r)["id"])
+    if err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Invalid customer ID", nil)
+        return
+    }
+    student, err := decodeStudent(r)
+    if err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Invalid JSON", err)
+        return
+    }
+    if student.Name == "" {
+        writeJSONError(w, http.StatusBadRequest, "Nama siswa wajib diisi", nil)
+        return
+    }
+    student.CustomerID = customerID
+
+    if err := h.Repo.Create(student); err != nil {
+        writeStudentSaveError(w, err)
+        return
+    }
+    w.Header().Set("Content-Type", "application/json")
+    w.WriteHeader(http.StatusCreated)
+    json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "data": student})
+}
+
+// UpdateStudent - PUT /api/students/{id}
+func (h *StudentHandler) UpdateStudent(w http.ResponseWriter, r *http.Request) {
+    id, err := strconv.Atoi(mux.Vars(r)["id"])
+    if err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Invalid ID", nil)
+        return
+    }
+    student, err := decodeStudent(r)
+    if err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Invalid JSON", err)
+        return
+    }
+    if student.Name == "" {
+        writeJSONError(w, http.StatusBadRequest, "Nama siswa wajib diisi", nil)
+        return
+    }
+    student.ID = id
+
+    if err := h.Repo.Update(student); err == sql.ErrNoRows {
+        writeJSONError(w, http.StatusNotFound, "Siswa tidak ditemukan", nil)
+        return
+    } else if err != nil {
+        writeStudentSaveError(w, err)
+        return
+    }
+    w.Header().Set("Content-Type", "application/json")
+    json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "data": student})
+}
+
+// DeleteStudent - DELETE /api/students/{id}
+func (h *StudentHandler) DeleteStudent(w http.ResponseWriter, r *http.Request) {
+    id, err := strconv.Atoi(mux.Vars(r)["id"])
+    if err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Invalid ID", nil)
+        return
+    }
+    if err := h.Repo.Delete(id); err == sql.ErrNoRows {
+        writeJSONError(w, http.StatusNotFound, "Siswa tidak ditemukan", nil)
+        return
+    } else if err != nil {
+        writeJSONError(w, http.StatusInternalServerError, "Gagal menghapus siswa", err)
+        return
+    }
+    w.WriteHeader(http.StatusNoContent)
+}
+
+// GetStudentOrders - GET /api/students/{id}/orders
+func (h *StudentHandler) GetStudentOrders(w http.ResponseWriter, r *http.Request) {
+    id, err := strconv.Atoi(mux.Vars(r)["id"])
+    if err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Invalid ID", nil)
+        return
+    }
+    history, err := h.Repo.GetOrderHistory(id)
+    if err != nil {
+        log.Printf("Error getting order history for student %d: %v", id, err)
+        writeJSONError(w, http.StatusInternalServerError, "Gagal mengambil riwayat pesanan", err)
+        return
+    }
+    var total float64
+    for _, h := range history {
+        total += h.Subtotal
+    }
+    w.Header().Set("Content-Type", "application/json")
+    json.NewEncoder(w).Encode(map[string]interface{}{
+        "success": true,
+        "data":    history,
+        "total":   total,
+    })
+}
+
+// PromoteStudents - POST /api/customers/{id}/students/promote
+// body: {"academic_year": "2026/2027", "confirm": false}. Tanpa confirm hanya preview.
+func (h *StudentHandler) PromoteStudents(w http.ResponseWriter, r *http.Request) {
+    customerID, err := strconv.Atoi(mux.Vars(r)["id"])
+    if err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Invalid customer ID", nil)
+        return
+    }
+
+    var req struct {
+        AcademicYear string `json:"academic_year"`
+        Confirm      bool   `json:"confirm"`
+    }
+    if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Invalid JSON", err)
+        return
+    }
+    if req.AcademicYear == "" {
+        // Kenaikan kelas dilakukan menjelang tahun ajaran baru yang dimulai Juli tahun ini
+        year := time.Now().Year()
+        req.AcademicYear = fmt.Sprintf("%d/%d", year, year+1)
+    }
+
+    rows, err := h.Repo.PromoteStudents(customerID, req.AcademicYear, GetSessionUsername(r), req.Confirm)
+    if err == sql.ErrNoRows {
+        writeJSONError(w, http.StatusNotFound, "Customer tidak ditemukan", nil)
+        return
+    } else if err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Kenaikan kelas gagal", err)
+        return
+    }
+
+    summary := map[string]int{"promote": 0, "graduate": 0, "skip": 0}
+    for _, row := range rows {
+        summary[row.Action]++
+    }
+    if req.Confirm {
+        log.Printf("Promoted students of customer %d for %s: %v", customerID, req.AcademicYear, summary)
+    }
+
+    w.Header().Set("Content-Type", "application/json")
+    json.NewEncoder(w).Encode(map[string]interface{}{
+        "success":       true,
+        "applied":       req.Confirm,
+        "academic_year": req.AcademicYear,
+        "summary":       summary,
+        "data":          rows,
+    })
+}
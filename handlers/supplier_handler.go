@@ -0,0 +1,204 @@
+package handlers
+
+import (
+    "database/sql"
+    "encoding/json"
+    "konveksi-app/models"
+    "konveksi-app/repositories"
+    "log"
+    "net/http"
+    "strconv"
+
+    "github.com/go-sql-driver/mysql"
+    "github.com/gorilla/mux"
+)
+
+type SupplierHandler struct {
+    Repo *repositories.SupplierRepository
+}
+
+func writeSupplierSaveError(w http.ResponseWriter, err error) {
+    if mysqlErr, ok := err.(*mysql.MySQLError); ok {
+        if mysqlErr.Number == 1062 {
+            writeJSONError(w, http.StatusConflict, "Nama pemasok sudah ada", nil)
+            return
+        }
+        log.Printf("Error saving supplier: %v", err)
+        writeJSONError(w, http.StatusInternalServerError, "Gagal menyimpan pemasok", err)
+        return
+    }
+    writeJSONError(w, http.StatusBadRequest, "Gagal menyimpan pemasok", err)
+}
+
+// GetSuppliers - GET /api/suppliers?all=true
+func (h *SupplierHandler) GetSuppliers(w http.ResponseWriter, r *http.Request) {
+    suppliers, err := h.Repo.GetAll(r.URL.Query().Get("all") == "true")
+    if err != nil {
+        log.Printf("Error getting suppliers: %v", err)
+        writeJSONError(w, http.StatusInternalServerError, "Gagal mengambil data pemasok", err)
+        return
+    }
+    w.Header().Set("Content-Type", "application/json")
+    json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "data": suppliers})
+}
+
+// GetSupplier - GET /api/suppliers/{id}
+func (h *SupplierHandler) GetSupplier(w http.ResponseWriter, r *http.Request) {
+    id, err := strconv.Atoi(mux.Vars(r)["id"])
+    if err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Invalid ID", nil)
+        return
+    }
+    supplier, err := h.Repo.GetByID(id)
+    if err == sql.ErrNoRows {
+        writeJSONError(w, http.StatusNotFound, "Pemasok tidak ditemukan", nil)
+        return
+    } else if err != nil {
+        writeJSONError(w, http.StatusInternalServerError, "Gagal mengambil data pemasok", err)
+        return
+    }
+    w.Header().Set("Content-Type", "application/json")
+    json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "data": supplier})
+}
+
+// CreateSupplier - POST /api/suppliers
+func (h *SupplierHandler) CreateSupplier(w http.ResponseWriter, r *http.Request) {
+    supplier := models.Supplier{Active: true}
+    if err := json.NewDecoder(r.Body).Decode(&supplier); err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Invalid JSON", err)
+        return
+    }
+    if err := h.Repo.Create(&supplier); err != nil {
+        writeSupplierSaveError(w, err)
+        return
+    }
+    w.Header().Set("Content-Type", "application/json")
+    w.WriteHeader(http.StatusCreated)
+    json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "data": supplier})
+}
+
+// UpdateSupplier - PUT /api/suppliers/{id}
+func (h *SupplierHandler) UpdateSupplier(w http.ResponseWriter, r *http.Request) {
+    id, err := strconv.Atoi(mux.Vars(r)["id"])
+    if err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Invalid ID", nil)
+        return
+    }
+    supplier := models.Supplier{Active: true}
+    if err := json.NewDecoder(r.Body).Decode(&supplier); err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Invalid JSON", err)
+        return
+    }
+    supplier.ID = id
+    if err := h.Repo.Update(&supplier); err == sql.ErrNoRows {
+        writeJSONError(w, http.StatusNotFound, "Pemasok tidak ditemukan", nil)
+        return
+    } else if err != nil {
+        writeSupplierSaveError(w, err)
+        return
+    }
+    saved, err := h.Repo.GetByID(id)
+    if err != nil {
+        writeJSONError(w, http.StatusInternalServerError, "Gagal mengambil data pemasok", err)
+        return
+    }
+    w.Header().Set("Content-Type", "application/json")
+    json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "data": saved})
+}
+
+// DeleteSupplier - DELETE /api/suppliers/{id}
+func (h *SupplierHandler) DeleteSupplier(w http.ResponseWriter, r *http.Request) {
+    id, err := strconv.Atoi(mux.Vars(r)["id"])
+    if err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Invalid ID", nil)
+        return
+    }
+    if err := h.Repo.Delete(id); err == sql.ErrNoRows {
+        writeJSONError(w, http.StatusNotFound, "Pemasok tidak ditemukan", nil)
+        return
+    } else if err != nil {
+        writeJSONError(w, http.StatusConflict, "Gagal menghapus pemasok", err)
+        return
+    }
+    w.WriteHeader(http.StatusNoContent)
+}
+
+// GetPayables - GET /api/suppliers/payables?outstanding=true
+func (h *SupplierHandler) GetPayables(w http.ResponseWriter, r *http.Request) {
+    payables, err := h.Repo.Payables(0, r.URL.Query().Get("outstanding") == "true")
+    if err != nil {
+        log.Printf("Error getting supplier payables: %v", err)
+        writeJSONError(w, http.StatusInternalServerError, "Gagal menghitung hutang pemasok", err)
+        return
+    }
+    var total float64
+    for _, p := range payables {
+        total += p.Outstanding
+    }
+    w.Header().Set("Content-Type", "application/json")
+    json.NewEncoder(w).Encode(map[string]interface{}{
+        "success": true,
+        "data": map[string]interface{}{
+            "suppliers":         payables,
+            "total_outstanding": total,
+        },
+    })
+}
+
+// GetPayments - GET /api/suppliers/{id}/payments, beserta sisa hutang pemasok
+func (h *SupplierHandler) GetPayments(w http.ResponseWriter, r *http.Request) {
+    id, err := strconv.Atoi(mux.Vars(r)["id"])
+    if err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Invalid ID", nil)
+        return
+    }
+    payables, err := h.Repo.Payables(id, false)
+    if err != nil {
+        writeJSONError(w, http.StatusInternalServerError, "Gagal menghitung hutang pemasok", err)
+        return
+    }
+    if len(payables) == 0 {
+        writeJSONError(w, http.StatusNotFound, "Pemasok tidak ditemukan", nil)
+        return
+    }
+    payments, err := h.Repo.GetPayments(id)
+    if err != nil {
+        writeJSONError(w, http.StatusInternalServerError, "Gagal mengambil pembayaran pemasok", err)
+        return
+    }
+    w.Header().Set("Content-Type", "application/json")
+    json.NewEncoder(w).Encode(map[string]interface{}{
+        "success": true,
+        "data": map[string]interface{}{
+            "payable":  payables[0],
+            "payments": payments,
+        },
+    })
+}
+
+// CreatePayment - POST /api/suppliers/{id}/payments
+// body: {"payment_date": "2025-07-01", "amount": 1500000, "purchase_order_id": 3, "method": "transfer", "reference": ""}
+func (h *SupplierHandler) CreatePayment(w http.ResponseWriter, r *http.Request) {
+    id, err := strconv.Atoi(mux.Vars(r)["id"])
+    if err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Invalid ID", nil)
+        return
+    }
+    var payment models.SupplierPayment
+    if err := json.NewDecoder(r.Body).Decode(&payment); err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Invalid JSON", err)
+        return
+    }
+    payment.SupplierID = id
+    payment.CreatedBy = GetSessionUsername(r)
+    if err := h.Repo.CreatePayment(&payment); err == sql.ErrNoRows {
+        writeJSONError(w, http.StatusNotFound, "Pemasok tidak ditemukan", nil)
+        return
+    } else if err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Gagal mencatat pembayaran", err)
+        return
+    }
+    w.Header().Set("Content-Type", "application/json")
+    w.WriteHeader(http.StatusCreated)
+    json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "data": payment})
+}
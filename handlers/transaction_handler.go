@@ -1,14 +1,18 @@
 package handlers
 
 import (
+    "database/sql"
     "encoding/json"
     "fmt"
+    "konveksi-app/documents"
     "konveksi-app/models"
     "konveksi-app/repositories"
+    "konveksi-app/sizing"
     "log"
     "net/http"
     "strconv"
     "regexp"
+    "sort"
     "github.com/gorilla/mux"
     "os"
     "strings"
@@ -29,6 +33,7 @@ func (h *TransactionHandler) CreateTransaction(w http.ResponseWriter, r *http.Re
         PaymentDate     string `json:"payment_date"`
         Status          string `json:"status"`
         Notes           string `json:"notes"`
+        ReserveStock    bool   `json:"reserve_stock"`
         Items           []struct {
             UniformName string  `json:"uniform_name"`
             Size        string  `json:"size"`
@@ -73,6 +78,7 @@ func (h *TransactionHandler) CreateTransaction(w http.ResponseWriter, r *http.Re
         Status:        req.Status,
         Total:         total,
         Notes:         req.Notes,
+        ReserveStock:  req.ReserveStock,
         Items:         make([]models.OrderItem, len(req.Items)),
     }
 
@@ -106,6 +112,9 @@ func (h *TransactionHandler) CreateTransaction(w http.ResponseWriter, r *http.Re
         "message": "Transaction created successfully",
         "total":   transaction.Total,
     }
+    if transaction.ReserveStock {
+        response["stock_reservations"] = h.stockReservations(transaction.ID)
+    }
 
     json.NewEncoder(w).Encode(response)
 }
@@ -122,14 +131,17 @@ func (h *TransactionHandler) CreateStudentOrder(w http.ResponseWriter, r *http.R
         PaymentDate     string `json:"payment_date"`
         Status          string `json:"status"`
         Notes           string `json:"notes"`
+        ReserveStock    bool   `json:"reserve_stock"`
         Items           []struct {
-            StudentName string  `json:"student_name"`
-            Grade       string  `json:"grade"`
-            UniformName string  `json:"uniform_name"`
-            Size        string  `json:"size"`
-            Quantity    int     `json:"quantity"`
-            UnitPrice   float64 `json:"unit_price"`
-            Notes       string  `json:"notes"`
+            StudentID     int     `json:"student_id"`
+            MeasurementID int     `json:"measurement_id"`
+            StudentName   string  `json:"student_name"`
+            Grade         string  `json:"grade"`
+            UniformName   string  `json:"uniform_name"`
+            Size          string  `json:"size"`
+            Quantity      int     `json:"quantity"`
+            UnitPrice     float64 `json:"unit_price"`
+            Notes         string  `json:"notes"`
         } `json:"items"`
     }
 
@@ -166,21 +178,24 @@ func (h *TransactionHandler) CreateStudentOrder(w http.ResponseWriter, r *http.R
         Status:        req.Status,
         Total:         total,
         Notes:         req.Notes,
+        ReserveStock:  req.ReserveStock,
     }
 
     // Map student items
     var studentItems []models.StudentOrderItem
     for _, item := range req.Items {
         studentItems = append(studentItems, models.StudentOrderItem{
-            CustomerID:  req.CustomerID,
-            StudentName: item.StudentName,
-            Grade:       item.Grade,
-            UniformName: item.UniformName,
-            Size:        item.Size,
-            Quantity:    item.Quantity,
-            UnitPrice:   item.UnitPrice,
-            Subtotal:    item.UnitPrice * float64(item.Quantity),
-            Notes:       item.Notes,
+            CustomerID:    req.CustomerID,
+            StudentID:     item.StudentID,
+            MeasurementID: item.MeasurementID,
+            StudentName:   item.StudentName,
+            Grade:         item.Grade,
+            UniformName:   item.UniformName,
+            Size:          item.Size,
+            Quantity:      item.Quantity,
+            UnitPrice:     item.UnitPrice,
+            Subtotal:      item.UnitPrice * float64(item.Quantity),
+            Notes:         item.Notes,
         })
     }
 
@@ -201,6 +216,9 @@ func (h *TransactionHandler) CreateStudentOrder(w http.ResponseWriter, r *http.R
         "message": "Student order created successfully",
         "total":   transaction.Total,
     }
+    if transaction.ReserveStock {
+        response["stock_reservations"] = h.stockReservations(transaction.ID)
+    }
 
     json.NewEncoder(w).Encode(response)
 }
@@ -462,6 +480,10 @@ func (h *TransactionHandler) UpdateTransactionHeader(w http.ResponseWriter, r *h
 
     // Update transaction header
     err = h.Repo.UpdateTransaction(id, req.TransactionDate, req.PaymentDate, req.Notes)
+    if err == sql.ErrNoRows {
+        http.Error(w, "Transaction not found", http.StatusNotFound)
+        return
+    }
     if err != nil {
         http.Error(w, err.Error(), http.StatusInternalServerError)
         return
@@ -498,7 +520,10 @@ func (h *TransactionHandler) UpdateTransaction(w http.ResponseWriter, r *http.Re
         return
     }
     
-    if err := h.Repo.UpdateTransaction(id, req.TransactionDate, req.PaymentDate, req.Notes); err != nil {
+    if err := h.Repo.UpdateTransaction(id, req.TransactionDate, req.PaymentDate, req.Notes); err == sql.ErrNoRows {
+        http.Error(w, "Transaction not found", http.StatusNotFound)
+        return
+    } else if err != nil {
         http.Error(w, err.Error(), http.StatusInternalServerError)
         return
     }
@@ -560,14 +585,16 @@ func (h *TransactionHandler) UpdateOrderItemsStudent(w http.ResponseWriter, r *h
     
     var req struct {
         Items []struct {
-            CustomerID  int    `json:"customer_id"`
-            StudentName string `json:"student_name"`
-            Grade       string `json:"grade"`
-            UniformName string `json:"uniform_name"`
-            Size        string `json:"size"`
-            Quantity    int    `json:"quantity"`
-            UnitPrice   float64 `json:"unit_price"`
-            Notes       string `json:"notes"`
+            CustomerID    int     `json:"customer_id"`
+            StudentID     int     `json:"student_id"`
+            MeasurementID int     `json:"measurement_id"`
+            StudentName   string  `json:"student_name"`
+            Grade         string  `json:"grade"`
+            UniformName   string  `json:"uniform_name"`
+            Size          string  `json:"size"`
+            Quantity      int     `json:"quantity"`
+            UnitPrice     float64 `json:"unit_price"`
+            Notes         string  `json:"notes"`
         } `json:"items"`
     }
     
@@ -579,14 +606,16 @@ func (h *TransactionHandler) UpdateOrderItemsStudent(w http.ResponseWriter, r *h
     var studentItems []models.StudentOrderItem
     for _, item := range req.Items {
         studentItems = append(studentItems, models.StudentOrderItem{
-            CustomerID:  item.CustomerID,
-            StudentName: item.StudentName,
-            Grade:       item.Grade,
-            UniformName: item.UniformName,
-            Size:        item.Size,
-            Quantity:    item.Quantity,
-            UnitPrice:   item.UnitPrice,
-            Notes:       item.Notes,
+            CustomerID:    item.CustomerID,
+            StudentID:     item.StudentID,
+            MeasurementID: item.MeasurementID,
+            StudentName:   item.StudentName,
+            Grade:         item.Grade,
+            UniformName:   item.UniformName,
+            Size:          item.Size,
+            Quantity:      item.Quantity,
+            UnitPrice:     item.UnitPrice,
+            Notes:         item.Notes,
         })
     }
     
@@ -726,6 +755,35 @@ func (h *TransactionHandler) GetRemindPayments(w http.ResponseWriter, r *http.Re
     json.NewEncoder(w).Encode(transactions)
 }
 
+// sortedUniformNames mengurutkan nama seragam pada ringkasan kuitansi secara alfabetis
+func sortedUniformNames(summary map[string]map[string]int) []string {
+    names := make([]string, 0, len(summary))
+    for name := range summary {
+        names = append(names, name)
+    }
+    sort.Strings(names)
+    return names
+}
+
+// stockReservations mengambil stok yang disisihkan untuk respons; error cukup dicatat
+func (h *TransactionHandler) stockReservations(transactionID int) []models.StockReservation {
+    reservations, err := h.Repo.StockReservations(transactionID)
+    if err != nil {
+        log.Printf("Error getting stock reservations for transaction %d: %v", transactionID, err)
+        return []models.StockReservation{}
+    }
+    return reservations
+}
+
+// sizeCharts mengambil urutan ukuran customer; jika gagal kuitansi tetap dicetak dengan urutan bawaan
+func (h *TransactionHandler) sizeCharts(customerID int) map[string]sizing.Chart {
+    charts, err := h.Repo.SizeCharts(customerID)
+    if err != nil {
+        log.Printf("Error getting size charts for customer %d: %v", customerID, err)
+    }
+    return charts
+}
+
 func (h *TransactionHandler) PrintKuitansi(w http.ResponseWriter, r *http.Request) {
     params := mux.Vars(r)
     id, err := strconv.Atoi(params["id"])
@@ -827,10 +885,12 @@ func (h *TransactionHandler) PrintKuitansi(w http.ResponseWriter, r *http.Reques
         summary[item.UniformName][item.Size] += item.Quantity
     }
 
-    // Generate summary rows
+    // Generate summary rows, ukuran mengikuti tabel ukuran customer
+    charts := h.sizeCharts(trx.CustomerID)
     var summaryRows strings.Builder
-    for name, sizes := range summary {
-        for size, qty := range sizes {
+    for _, name := range sortedUniformNames(summary) {
+        for _, size := range charts[strings.ToLower(strings.TrimSpace(name))].SortedKeys(summary[name]) {
+            qty := summary[name][size]
             summaryRows.WriteString(fmt.Sprintf(`
                     <tr class="tm_table_baseline">
                       <td class="tm_width_4">%s</td>
@@ -942,10 +1002,12 @@ func (h *TransactionHandler) PrintKuitansibiasa(w http.ResponseWriter, r *http.R
             summary[item.UniformName][item.Size] += item.Quantity
         }
 
-        // Generate summary rows
+        // Generate summary rows, ukuran mengikuti tabel ukuran customer
+        charts := h.sizeCharts(trx.CustomerID)
         var allSummaryRows strings.Builder
-        for name, sizes := range summary {
-            for size, qty := range sizes {
+        for _, name := range sortedUniformNames(summary) {
+            for _, size := range charts[strings.ToLower(strings.TrimSpace(name))].SortedKeys(summary[name]) {
+                qty := summary[name][size]
                 allSummaryRows.WriteString(fmt.Sprintf(`
                     <tr class="tm_table_baseline">
                       <td class="tm_width_4">%s</td>
@@ -1021,3 +1083,253 @@ func getStatusText(status string) string {
         return "DP"
     }
 }
+
+// PrintWorkOrder - GET /api/transactions/{id}/work-order.pdf
+// SPK untuk bagian produksi: item, ukuran badan dan rekap potong tanpa harga
+func (h *TransactionHandler) PrintWorkOrder(w http.ResponseWriter, r *http.Request) {
+    id, err := strconv.Atoi(mux.Vars(r)["id"])
+    if err != nil {
+        http.Error(w, "Invalid ID", http.StatusBadRequest)
+        return
+    }
+
+    trx, studentItems, err := h.Repo.GetByIDStudentOrder(id)
+    if err == sql.ErrNoRows {
+        http.Error(w, "Transaction not found", http.StatusNotFound)
+        return
+    } else if err != nil {
+        http.Error(w, err.Error(), http.StatusInternalServerError)
+        return
+    }
+
+    var lines []documents.WorkOrderLine
+    if len(studentItems) > 0 {
+        for _, item := range studentItems {
+            lines = append(lines, documents.WorkOrderLine{
+                StudentName: item.StudentName,
+                Grade:       item.Grade,
+                UniformName: item.UniformName,
+                Size:        item.Size,
+                Quantity:    item.Quantity,
+                Notes:       item.Notes,
+                Measurement: item.Measurement,
+            })
+        }
+    } else {
+        normal, err := h.Repo.GetByIDNormal(id)
+        if err != nil {
+            http.Error(w, err.Error(), http.StatusInternalServerError)
+            return
+        }
+        for _, item := range normal.Items {
+            lines = append(lines, documents.WorkOrderLine{
+                UniformName: item.UniformName,
+                Size:        item.Size,
+                Quantity:    item.Quantity,
+                Notes:       item.Notes,
+            })
+        }
+    }
+
+    // Rekap potong, ukuran mengikuti tabel ukuran customer
+    summary := make(map[string]map[string]int)
+    for _, l := range lines {
+        if summary[l.UniformName] == nil {
+            summary[l.UniformName] = make(map[string]int)
+        }
+        summary[l.UniformName][l.Size] += l.Quantity
+    }
+    charts := h.sizeCharts(trx.CustomerID)
+    var recap []documents.WorkOrderRecap
+    for _, name := range sortedUniformNames(summary) {
+        for _, size := range charts[strings.ToLower(strings.TrimSpace(name))].SortedKeys(summary[name]) {
+            recap = append(recap, documents.WorkOrderRecap{UniformName: name, Size: size, Quantity: summary[name][size]})
+        }
+    }
+
+    pdf, err := documents.WorkOrder(trx, lines, recap)
+    if err != nil {
+        log.Printf("Error generating work order PDF: %v", err)
+        http.Error(w, "Failed to generate PDF", http.StatusInternalServerError)
+        return
+    }
+    w.Header().Set("Content-Type", "application/pdf")
+    w.Header().Set("Content-Disposition", fmt.Sprintf("inline; filename=\"spk_%d.pdf\"", id))
+    w.Write(pdf)
+}
+
+// PrintDeliveryNote - GET /api/transactions/{id}/delivery-note.pdf?date=YYYY-MM-DD
+// Surat jalan seluruh pesanan; pesanan siswa direkap per seragam dan ukuran
+func (h *TransactionHandler) PrintDeliveryNote(w http.ResponseWriter, r *http.Request) {
+    id, err := strconv.Atoi(mux.Vars(r)["id"])
+    if err != nil {
+        http.Error(w, "Invalid ID", http.StatusBadRequest)
+        return
+    }
+    date := r.URL.Query().Get("date")
+    if date == "" {
+        date = time.Now().Format("2006-01-02")
+    } else if _, err := time.Parse("2006-01-02", date); err != nil {
+        http.Error(w, "date harus berformat YYYY-MM-DD", http.StatusBadRequest)
+        return
+    }
+
+    trx, studentItems, err := h.Repo.GetByIDStudentOrder(id)
+    if err == sql.ErrNoRows {
+        http.Error(w, "Transaction not found", http.StatusNotFound)
+        return
+    } else if err != nil {
+        http.Error(w, err.Error(), http.StatusInternalServerError)
+        return
+    }
+    if trx.Status == "cancelled" {
+        http.Error(w, "Transaksi sudah dibatalkan", http.StatusBadRequest)
+        return
+    }
+
+    var lines []documents.DeliveryNoteLine
+    if len(studentItems) > 0 {
+        items := make([]models.DeliveryItem, len(studentItems))
+        for i, item := range studentItems {
+            items[i] = models.DeliveryItem{
+                StudentName: item.StudentName,
+                Grade:       item.Grade,
+                UniformName: item.UniformName,
+                Size:        item.Size,
+                Quantity:    item.Quantity,
+            }
+        }
+        lines = deliveryNoteLines(h.sizeCharts(trx.CustomerID), items)
+    } else {
+        normal, err := h.Repo.GetByIDNormal(id)
+        if err != nil {
+            http.Error(w, err.Error(), http.StatusInternalServerError)
+            return
+        }
+        for _, item := range normal.Items {
+            lines = append(lines, documents.DeliveryNoteLine{
+                UniformName: item.UniformName,
+                Size:        item.Size,
+                Quantity:    item.Quantity,
+                Notes:       item.Notes,
+            })
+        }
+    }
+
+    customer, err := h.Repo.GetCustomerByID(trx.CustomerID)
+    if err != nil && err != sql.ErrNoRows {
+        http.Error(w, err.Error(), http.StatusInternalServerError)
+        return
+    }
+
+    pdf, err := documents.DeliveryNote(trx, customer, fmt.Sprintf("SJ-%d", id), date, lines)
+    if err != nil {
+        log.Printf("Error generating delivery note PDF: %v", err)
+        http.Error(w, "Failed to generate PDF", http.StatusInternalServerError)
+        return
+    }
+    w.Header().Set("Content-Type", "application/pdf")
+    w.Header().Set("Content-Disposition", fmt.Sprintf("inline; filename=\"surat_jalan_%d.pdf\"", id))
+    w.Write(pdf)
+}
+
+// PrintLabels - GET /api/transactions/{id}/labels.pdf
+// Stiker label kantong per siswa (group=student) atau per item (group=item).
+// Grid kertas stiker diatur lewat cols, rows, margin_top, margin_left, gap_x, gap_y, width, height (mm),
+// skip untuk melewati stiker yang sudah terpakai dan border=true untuk kertas polos.
+func (h *TransactionHandler) PrintLabels(w http.ResponseWriter, r *http.Request) {
+    id, err := strconv.Atoi(mux.Vars(r)["id"])
+    if err != nil {
+        http.Error(w, "Invalid ID", http.StatusBadRequest)
+        return
+    }
+    q := r.URL.Query()
+    layout := documents.DefaultLabelLayout
+    layout.Border = q.Get("border") == "true"
+    for key, target := range map[string]*int{"cols": &layout.Columns, "rows": &layout.Rows, "skip": &layout.Skip} {
+        if v := q.Get(key); v != "" {
+            if *target, err = strconv.Atoi(v); err != nil {
+                http.Error(w, key+" harus berupa angka", http.StatusBadRequest)
+                return
+            }
+        }
+    }
+    for key, target := range map[string]*float64{
+        "margin_top": &layout.MarginTop, "margin_left": &layout.MarginLeft,
+        "gap_x": &layout.GapX, "gap_y": &layout.GapY,
+        "width": &layout.Width, "height": &layout.Height,
+    } {
+        if v := q.Get(key); v != "" {
+            if *target, err = strconv.ParseFloat(v, 64); err != nil {
+                http.Error(w, key+" harus berupa angka (mm)", http.StatusBadRequest)
+                return
+            }
+        }
+    }
+    group := q.Get("group")
+    if group == "" {
+        group = "student"
+    }
+    if group != "student" && group != "item" {
+        http.Error(w, "group harus student atau item", http.StatusBadRequest)
+        return
+    }
+    if err := layout.Normalize(); err != nil {
+        http.Error(w, err.Error(), http.StatusBadRequest)
+        return
+    }
+
+    trx, studentItems, err := h.Repo.GetByIDStudentOrder(id)
+    if err == sql.ErrNoRows {
+        http.Error(w, "Transaction not found", http.StatusNotFound)
+        return
+    } else if err != nil {
+        http.Error(w, err.Error(), http.StatusInternalServerError)
+        return
+    }
+
+    itemText := func(item models.StudentOrderItem) string {
+        return fmt.Sprintf("%s - %s (%d)", item.UniformName, item.Size, item.Quantity)
+    }
+    var labels []documents.Label
+    if group == "item" {
+        for _, item := range studentItems {
+            labels = append(labels, documents.Label{
+                Customer:    trx.Customer_name,
+                StudentName: item.StudentName,
+                Grade:       item.Grade,
+                Items:       []string{itemText(item)},
+            })
+        }
+    } else {
+        // Urutan label mengikuti urutan siswa pertama kali muncul di pesanan
+        index := make(map[string]int)
+        for _, item := range studentItems {
+            key := item.StudentName + "|" + item.Grade
+            if item.StudentID != 0 {
+                key = strconv.Itoa(item.StudentID)
+            }
+            i, ok := index[key]
+            if !ok {
+                i = len(labels)
+                index[key] = i
+                labels = append(labels, documents.Label{
+                    Customer:    trx.Customer_name,
+                    StudentName: item.StudentName,
+                    Grade:       item.Grade,
+                })
+            }
+            labels[i].Items = append(labels[i].Items, itemText(item))
+        }
+    }
+
+    pdf, err := documents.LabelSheet(labels, layout)
+    if err != nil {
+        log.Printf("Error generating label PDF: %v", err)
+        http.Error(w, "Failed to generate PDF", http.StatusInternalServerError)
+        return
+    }
+    w.Header().Set("Content-Type", "application/pdf")
+    w.Header().Set("Content-Disposition", fmt.Sprintf("inline; filename=\"label_%d.pdf\"", id))
+    w.Write(pdf)
+}
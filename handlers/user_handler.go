@@ -12,6 +12,7 @@ import (
     "database/sql"
     "strings"
     "log"
+    "sync"
 
 )
 
@@ -22,6 +23,10 @@ type UserHandler struct {
 
 // Simple in-memory session store (untuk production gunakan Redis/database)
 var sessions = make(map[string]string)
+var sessionExpiry = make(map[string]time.Time)
+var sessionMu sync.RWMutex
+
+const sessionDuration = 24 * time.Hour
 
 func generateSessionID() string {
     bytes := make([]byte, 16)
@@ -31,7 +36,13 @@ func generateSessionID() string {
 
 // Export functions untuk digunakan di main.go
 func ValidateSession(sessionID string) bool {
+    sessionMu.RLock()
     _, exists := sessions[sessionID]
+    expiry, hasExpiry := sessionExpiry[sessionID]
+    sessionMu.RUnlock()
+    if exists && hasExpiry && time.Now().After(expiry) {
+        exists = false
+    }
     log.Printf("Validating session %s: exists=%v", sessionID, exists)
     return exists
 }
@@ -40,6 +51,34 @@ func GetSessions() map[string]string {
     return sessions
 }
 
+// GetSessionUsername mengembalikan username dari cookie session request ("" jika tidak login)
+func GetSessionUsername(r *http.Request) string {
+    cookie, err := r.Cookie("session")
+    if err != nil {
+        return ""
+    }
+    sessionMu.RLock()
+    defer sessionMu.RUnlock()
+    return sessions[cookie.Value]
+}
+
+// ExpireSessions menghapus session yang sudah melewati masa berlaku, mengembalikan jumlah yang dihapus
+func ExpireSessions() int {
+    sessionMu.Lock()
+    defer sessionMu.Unlock()
+
+    now := time.Now()
+    expired := 0
+    for id, expiry := range sessionExpiry {
+        if now.After(expiry) {
+            delete(sessions, id)
+            delete(sessionExpiry, id)
+            expired++
+        }
+    }
+    return expired
+}
+
 func (h *UserHandler) LoginAPI(w http.ResponseWriter, r *http.Request) {
     log.Println("LoginAPI called")
     
@@ -118,7 +157,11 @@ func (h *UserHandler) LoginAPI(w http.ResponseWriter, r *http.Request) {
 
     // Generate session
     sessionID := generateSessionID()
+    expiresAt := time.Now().Add(sessionDuration)
+    sessionMu.Lock()
     sessions[sessionID] = username
+    sessionExpiry[sessionID] = expiresAt
+    sessionMu.Unlock()
     
     log.Printf("Session created - ID: %s, Username: %s", sessionID, username)
 
@@ -130,7 +173,7 @@ func (h *UserHandler) LoginAPI(w http.ResponseWriter, r *http.Request) {
         HttpOnly: true,
         Secure:   false, // Set ke true untuk HTTPS
         SameSite: http.SameSiteLaxMode,
-        Expires:  time.Now().Add(24 * time.Hour), // 24 jam
+        Expires:  expiresAt, // 24 jam
     }
     http.SetCookie(w, cookie)
 
@@ -158,7 +201,10 @@ func (h *UserHandler) LogoutAPI(w http.ResponseWriter, r *http.Request) {
     cookie, err := r.Cookie("session")
     if err == nil {
         // Remove session dari store
+        sessionMu.Lock()
         delete(sessions, cookie.Value)
+        delete(sessionExpiry, cookie.Value)
+        sessionMu.Unlock()
     }
 
     // Clear cookie
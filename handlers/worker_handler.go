@@ -0,0 +1,300 @@
+package handlers
+
+import (
+    "database/sql"
+    "encoding/json"
+    "fmt"
+    "konveksi-app/documents"
+    "konveksi-app/models"
+    "konveksi-app/repositories"
+    "log"
+    "net/http"
+    "strconv"
+
+    "github.com/gorilla/mux"
+)
+
+type WorkerHandler struct {
+    Repo *repositories.WorkerRepository
+}
+
+// GetWorkers - GET /api/workers?all=true
+func (h *WorkerHandler) GetWorkers(w http.ResponseWriter, r *http.Request) {
+    workers, err := h.Repo.GetAll(r.URL.Query().Get("all") == "true")
+    if err != nil {
+        log.Printf("Error getting workers: %v", err)
+        writeJSONError(w, http.StatusInternalServerError, "Gagal mengambil data pekerja", err)
+        return
+    }
+    w.Header().Set("Content-Type", "application/json")
+    json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "data": workers})
+}
+
+// GetWorker - GET /api/workers/{id}
+func (h *WorkerHandler) GetWorker(w http.ResponseWriter, r *http.Request) {
+    id, err := strconv.Atoi(mux.Vars(r)["id"])
+    if err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Invalid ID", nil)
+        return
+    }
+    worker, err := h.Repo.GetByID(id)
+    if err == sql.ErrNoRows {
+        writeJSONError(w, http.StatusNotFound, "Pekerja tidak ditemukan", nil)
+        return
+    } else if err != nil {
+        writeJSONError(w, http.StatusInternalServerError, "Gagal mengambil data pekerja", err)
+        return
+    }
+    w.Header().Set("Content-Type", "application/json")
+    json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "data": worker})
+}
+
+// CreateWorker - POST /api/workers
+func (h *WorkerHandler) CreateWorker(w http.ResponseWriter, r *http.Request) {
+    worker := models.Worker{Active: true}
+    if err := json.NewDecoder(r.Body).Decode(&worker); err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Invalid JSON", err)
+        return
+    }
+    if err := h.Repo.Create(&worker); err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Gagal menyimpan pekerja", err)
+        return
+    }
+    w.Header().Set("Content-Type", "application/json")
+    w.WriteHeader(http.StatusCreated)
+    json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "data": worker})
+}
+
+// UpdateWorker - PUT /api/workers/{id}
+func (h *WorkerHandler) UpdateWorker(w http.ResponseWriter, r *http.Request) {
+    id, err := strconv.Atoi(mux.Vars(r)["id"])
+    if err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Invalid ID", nil)
+        return
+    }
+    worker := models.Worker{Active: true}
+    if err := json.NewDecoder(r.Body).Decode(&worker); err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Invalid JSON", err)
+        return
+    }
+    worker.ID = id
+    if err := h.Repo.Update(&worker); err == sql.ErrNoRows {
+        writeJSONError(w, http.StatusNotFound, "Pekerja tidak ditemukan", nil)
+        return
+    } else if err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Gagal menyimpan pekerja", err)
+        return
+    }
+    saved, err := h.Repo.GetByID(id)
+    if err != nil {
+        writeJSONError(w, http.StatusInternalServerError, "Gagal mengambil data pekerja", err)
+        return
+    }
+    w.Header().Set("Content-Type", "application/json")
+    json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "data": saved})
+}
+
+// DeleteWorker - DELETE /api/workers/{id}
+func (h *WorkerHandler) DeleteWorker(w http.ResponseWriter, r *http.Request) {
+    id, err := strconv.Atoi(mux.Vars(r)["id"])
+    if err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Invalid ID", nil)
+        return
+    }
+    if err := h.Repo.Delete(id); err == sql.ErrNoRows {
+        writeJSONError(w, http.StatusNotFound, "Pekerja tidak ditemukan", nil)
+        return
+    } else if err != nil {
+        writeJSONError(w, http.StatusConflict, "Gagal menghapus pekerja", err)
+        return
+    }
+    w.WriteHeader(http.StatusNoContent)
+}
+
+// GetPieceRates - GET /api/catalog/{id}/piece-rates
+func (h *WorkerHandler) GetPieceRates(w http.ResponseWriter, r *http.Request) {
+    id, err := strconv.Atoi(mux.Vars(r)["id"])
+    if err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Invalid ID", nil)
+        return
+    }
+    rates, err := h.Repo.GetPieceRates(id)
+    if err != nil {
+        writeJSONError(w, http.StatusInternalServerError, "Gagal mengambil tarif borongan", err)
+        return
+    }
+    w.Header().Set("Content-Type", "application/json")
+    json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "data": rates})
+}
+
+// SavePieceRates - PUT /api/catalog/{id}/piece-rates
+// body: {"rates": [{"operation": "jahit", "rate": 7500}, {"operation": "setrika", "rate": 1000}]}
+func (h *WorkerHandler) SavePieceRates(w http.ResponseWriter, r *http.Request) {
+    id, err := strconv.Atoi(mux.Vars(r)["id"])
+    if err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Invalid ID", nil)
+        return
+    }
+    var req struct {
+        Rates []models.PieceRate `json:"rates"`
+    }
+    if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Invalid JSON", err)
+        return
+    }
+    if err := h.Repo.SavePieceRates(id, req.Rates); err == sql.ErrNoRows {
+        writeJSONError(w, http.StatusNotFound, "Produk katalog tidak ditemukan", nil)
+        return
+    } else if err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Gagal menyimpan tarif borongan", err)
+        return
+    }
+    rates, err := h.Repo.GetPieceRates(id)
+    if err != nil {
+        writeJSONError(w, http.StatusInternalServerError, "Gagal mengambil tarif borongan", err)
+        return
+    }
+    w.Header().Set("Content-Type", "application/json")
+    json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "data": rates})
+}
+
+// GetAssignments - GET /api/work-assignments?worker_id=&transaction_id=&open=true
+func (h *WorkerHandler) GetAssignments(w http.ResponseWriter, r *http.Request) {
+    q := r.URL.Query()
+    var f repositories.AssignmentFilter
+    f.WorkerID, _ = strconv.Atoi(q.Get("worker_id"))
+    f.TransactionID, _ = strconv.Atoi(q.Get("transaction_id"))
+    f.OpenOnly = q.Get("open") == "true"
+    assignments, err := h.Repo.GetAssignments(f)
+    if err != nil {
+        log.Printf("Error getting work assignments: %v", err)
+        writeJSONError(w, http.StatusInternalServerError, "Gagal mengambil pembagian kerja", err)
+        return
+    }
+    w.Header().Set("Content-Type", "application/json")
+    json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "data": assignments})
+}
+
+// CreateAssignment - POST /api/work-assignments
+// body: {"worker_id": 1, "item_type": "order|student", "item_id": 12, "operation": "jahit", "quantity": 10}
+func (h *WorkerHandler) CreateAssignment(w http.ResponseWriter, r *http.Request) {
+    var a models.WorkAssignment
+    if err := json.NewDecoder(r.Body).Decode(&a); err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Invalid JSON", err)
+        return
+    }
+    a.CreatedBy = GetSessionUsername(r)
+    if err := h.Repo.CreateAssignment(&a); err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Gagal menyimpan pembagian kerja", err)
+        return
+    }
+    w.Header().Set("Content-Type", "application/json")
+    w.WriteHeader(http.StatusCreated)
+    json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "data": a})
+}
+
+// DeleteAssignment - DELETE /api/work-assignments/{id}
+func (h *WorkerHandler) DeleteAssignment(w http.ResponseWriter, r *http.Request) {
+    id, err := strconv.Atoi(mux.Vars(r)["id"])
+    if err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Invalid ID", nil)
+        return
+    }
+    if err := h.Repo.DeleteAssignment(id); err == sql.ErrNoRows {
+        writeJSONError(w, http.StatusNotFound, "Pembagian kerja tidak ditemukan", nil)
+        return
+    } else if err != nil {
+        writeJSONError(w, http.StatusConflict, "Gagal menghapus pembagian kerja", err)
+        return
+    }
+    w.WriteHeader(http.StatusNoContent)
+}
+
+// RecordCompletion - POST /api/work-assignments/{id}/completions
+// body: {"quantity": 5, "completed_date": "2025-07-04"}
+func (h *WorkerHandler) RecordCompletion(w http.ResponseWriter, r *http.Request) {
+    id, err := strconv.Atoi(mux.Vars(r)["id"])
+    if err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Invalid ID", nil)
+        return
+    }
+    var c models.WorkCompletion
+    if err := json.NewDecoder(r.Body).Decode(&c); err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Invalid JSON", err)
+        return
+    }
+    c.AssignmentID = id
+    c.RecordedBy = GetSessionUsername(r)
+    if err := h.Repo.RecordCompletion(&c); err == sql.ErrNoRows {
+        writeJSONError(w, http.StatusNotFound, "Pembagian kerja tidak ditemukan", nil)
+        return
+    } else if err != nil {
+        writeJSONError(w, http.StatusBadRequest, "Gagal mencatat hasil kerja", err)
+        return
+    }
+    assignment, err := h.Repo.GetAssignment(id)
+    if err != nil {
+        writeJSONError(w, http.StatusInternalServerError, "Gagal mengambil pembagian kerja", err)
+        return
+    }
+    w.Header().Set("Content-Type", "application/json")
+    w.WriteHeader(http.StatusCreated)
+    json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "data": assignment})
+}
+
+// GetWeeklyWages - GET /api/wages/weekly?week=2025-07-02&worker_id=
+// week boleh tanggal mana saja dalam minggu itu (Senin-Minggu), kosong berarti minggu ini
+func (h *WorkerHandler) GetWeeklyWages(w http.ResponseWriter, r *http.Request) {
+    q := r.URL.Query()
+    workerID, _ := strconv.Atoi(q.Get("worker_id"))
+    from, to, err := repositories.WeekRange(q.Get("week"))
+    if err != nil {
+        writeJSONError(w, http.StatusBadRequest, err.Error(), nil)
+        return
+    }
+    wages, err := h.Repo.WeeklyWages(from, workerID)
+    if err != nil {
+        log.Printf("Error getting weekly wages: %v", err)
+        writeJSONError(w, http.StatusInternalServerError, "Gagal menghitung upah mingguan", err)
+        return
+    }
+    var total float64
+    for _, wage := range wages {
+        total += wage.Total
+    }
+    w.Header().Set("Content-Type", "application/json")
+    json.NewEncoder(w).Encode(map[string]interface{}{
+        "success": true,
+        "data": map[string]interface{}{
+            "period_from": from,
+            "period_to":   to,
+            "workers":     wages,
+            "total":       total,
+        },
+    })
+}
+
+// PrintPayslips - GET /api/wages/payslips.pdf?week=&worker_id=
+func (h *WorkerHandler) PrintPayslips(w http.ResponseWriter, r *http.Request) {
+    q := r.URL.Query()
+    workerID, _ := strconv.Atoi(q.Get("worker_id"))
+    from, to, err := repositories.WeekRange(q.Get("week"))
+    if err != nil {
+        http.Error(w, err.Error(), http.StatusBadRequest)
+        return
+    }
+    wages, err := h.Repo.WeeklyWages(from, workerID)
+    if err != nil {
+        http.Error(w, err.Error(), http.StatusInternalServerError)
+        return
+    }
+    pdf, err := documents.Payslips(wages, from, to)
+    if err != nil {
+        log.Printf("Error generating payslip PDF: %v", err)
+        http.Error(w, "Failed to generate PDF", http.StatusInternalServerError)
+        return
+    }
+    w.Header().Set("Content-Type", "application/pdf")
+    w.Header().Set("Content-Disposition", fmt.Sprintf("inline; filename=\"slip_upah_%s.pdf\"", from))
+    w.Write(pdf)
+}
@@ -0,0 +1,45 @@
+package handlers
+
+import (
+    "konveksi-app/repositories"
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "testing"
+
+    "github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestCreateAssignmentOverCapIsBadRequest(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer db.Close()
+    h := &WorkerHandler{Repo: &repositories.WorkerRepository{DB: db}}
+
+    mock.ExpectBegin()
+    mock.ExpectQuery("SELECT name, active FROM workers").WithArgs(3).
+        WillReturnRows(sqlmock.NewRows([]string{"name", "active"}).AddRow("Bu Sri", true))
+    mock.ExpectQuery("FROM order_items WHERE id = \\?").WithArgs(91).
+        WillReturnRows(sqlmock.NewRows([]string{"transaction_id", "uniform_name", "size", "student_id", "student_name", "grade"}).
+            AddRow(8, "Kemeja SD", "M", 0, "", ""))
+    mock.ExpectQuery("FOR UPDATE").WithArgs(8).
+        WillReturnRows(sqlmock.NewRows([]string{"customer_id", "status"}).AddRow(2, "pending"))
+    mock.ExpectQuery("FROM order_items WHERE transaction_id = \\?").WithArgs(8, "Kemeja SD", "M").
+        WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow(40))
+    mock.ExpectQuery("FROM work_assignments").
+        WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow(40))
+    mock.ExpectRollback()
+
+    body := `{"worker_id": 3, "item_type": "order", "item_id": 91, "operation": "jahit", "quantity": 5, "assigned_date": "2025-07-01"}`
+    rec := httptest.NewRecorder()
+    h.CreateAssignment(rec, httptest.NewRequest("POST", "/api/work-assignments", strings.NewReader(body)))
+
+    if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), "tinggal 0 pcs") {
+        t.Fatalf("status = %d, body = %s; want 400 over cap", rec.Code, rec.Body.String())
+    }
+    if err := mock.ExpectationsWereMet(); err != nil {
+        t.Fatal(err)
+    }
+}
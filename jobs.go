@@ -0,0 +1,218 @@
+package main
+
+import (
+    "compress/gzip"
+    "context"
+    "database/sql"
+    "fmt"
+    "konveksi-app/handlers"
+    "konveksi-app/mailer"
+    "konveksi-app/messenger"
+    "konveksi-app/repositories"
+    "konveksi-app/scheduler"
+    "log"
+    "os"
+    "path/filepath"
+    "sort"
+    "strconv"
+    "time"
+)
+
+func getEnv(key, fallback string) string {
+    if value := os.Getenv(key); value != "" {
+        return value
+    }
+    return fallback
+}
+
+// jobDeps berisi dependency yang dibutuhkan job-job terjadwal
+type jobDeps struct {
+    DB               *sql.DB
+    NotificationRepo *repositories.NotificationRepository
+    CustomerRepo     *repositories.CustomerRepository
+    EmailService     *mailer.Service
+    MessageService   *messenger.Service
+}
+
+// registerJobs mendaftarkan job harian ke scheduler
+func registerJobs(s *scheduler.Scheduler, deps jobDeps) {
+    db := deps.DB
+    notificationRepo := deps.NotificationRepo
+    customerRepo := deps.CustomerRepo
+    emailService := deps.EmailService
+    messageService := deps.MessageService
+
+    jobs := []struct {
+        Name  string
+        Spec  string
+        Run   scheduler.JobFunc
+        Local bool // dijalankan di setiap instance, lihat scheduler.RegisterLocal
+    }{
+        {
+            // Setiap pagi jam 06:00
+            Name: "generate_notifications",
+            Spec: getEnv("JOB_NOTIFICATIONS_SCHEDULE", "0 6 * * *"),
+            Run: func(ctx context.Context) (string, error) {
+                created, err := notificationRepo.Generate()
+                if err != nil {
+                    return "", err
+                }
+                return fmt.Sprintf("%d notifikasi baru", created), nil
+            },
+        },
+        {
+            // Session disimpan di memori masing-masing proses
+            Name:  "expire_sessions",
+            Local: true,
+            Spec:  getEnv("JOB_SESSIONS_SCHEDULE", "*/15 * * * *"),
+            Run: func(ctx context.Context) (string, error) {
+                return fmt.Sprintf("%d session kedaluwarsa dihapus", handlers.ExpireSessions()), nil
+            },
+        },
+        {
+            // Pengingat pembayaran ke customer setiap pagi jam 07:00
+            Name: "email_payment_reminders",
+            Spec: getEnv("JOB_EMAIL_REMINDERS_SCHEDULE", "0 7 * * *"),
+            Run: func(ctx context.Context) (string, error) {
+                if !emailService.Mailer.Config.Enabled() {
+                    return "SMTP belum dikonfigurasi, dilewati", nil
+                }
+                queued, err := emailService.QueuePaymentReminders()
+                if err != nil {
+                    return "", err
+                }
+                sent, failed, err := emailService.SendPending()
+                if err != nil {
+                    return "", err
+                }
+                return fmt.Sprintf("%d pengingat dibuat, %d terkirim, %d gagal", queued, sent, failed), nil
+            },
+        },
+        {
+            Name: "email_staff_digest",
+            Spec: getEnv("JOB_EMAIL_DIGEST_SCHEDULE", "30 7 * * *"),
+            Run: func(ctx context.Context) (string, error) {
+                if !emailService.Mailer.Config.Enabled() {
+                    return "SMTP belum dikonfigurasi, dilewati", nil
+                }
+                queued, err := emailService.QueueStaffDigest()
+                if err != nil {
+                    return "", err
+                }
+                sent, failed, err := emailService.SendPending()
+                if err != nil {
+                    return "", err
+                }
+                return fmt.Sprintf("%d ringkasan dibuat, %d terkirim, %d gagal", queued, sent, failed), nil
+            },
+        },
+        {
+            // Coba ulang email yang gagal terkirim
+            Name: "send_emails",
+            Spec: getEnv("JOB_SEND_EMAILS_SCHEDULE", "*/10 * * * *"),
+            Run: func(ctx context.Context) (string, error) {
+                if !emailService.Mailer.Config.Enabled() {
+                    return "SMTP belum dikonfigurasi, dilewati", nil
+                }
+                sent, failed, err := emailService.SendPending()
+                if err != nil {
+                    return "", err
+                }
+                return fmt.Sprintf("%d terkirim, %d gagal", sent, failed), nil
+            },
+        },
+        {
+            // Pengingat pembayaran lewat WhatsApp/SMS setiap pagi jam 08:00
+            Name: "message_payment_reminders",
+            Spec: getEnv("JOB_MESSAGE_REMINDERS_SCHEDULE", "0 8 * * *"),
+            Run: func(ctx context.Context) (string, error) {
+                sent, failed, err := messageService.SendPaymentReminders()
+                if err != nil {
+                    return "", err
+                }
+                return fmt.Sprintf("%d pesan terkirim, %d gagal (%s)", sent, failed, messageService.Messenger.Name()), nil
+            },
+        },
+        {
+            // Harga terjadwal berlaku mulai dini hari
+            Name: "apply_scheduled_prices",
+            Spec: getEnv("JOB_SCHEDULED_PRICES_SCHEDULE", "5 0 * * *"),
+            Run: func(ctx context.Context) (string, error) {
+                applied, err := customerRepo.ApplyDuePrices()
+                if err != nil {
+                    return "", err
+                }
+                return fmt.Sprintf("%d harga terjadwal diterapkan", applied), nil
+            },
+        },
+        {
+            // Setiap malam jam 02:00
+            Name: "nightly_backup",
+            Spec: getEnv("JOB_BACKUP_SCHEDULE", "0 2 * * *"),
+            Run: func(ctx context.Context) (string, error) {
+                return runBackup(db)
+            },
+        },
+    }
+
+    for _, job := range jobs {
+        register := s.Register
+        if job.Local {
+            register = s.RegisterLocal
+        }
+        if err := register(job.Name, job.Spec, job.Run); err != nil {
+            log.Fatalf("Failed to register job %s: %v", job.Name, err)
+        }
+    }
+}
+
+// runBackup menulis backup database ter-gzip ke BACKUP_DIR dan hanya menyimpan BACKUP_KEEP file terakhir
+func runBackup(db *sql.DB) (string, error) {
+    dir := getEnv("BACKUP_DIR", "backups")
+    keep, err := strconv.Atoi(getEnv("BACKUP_KEEP", "7"))
+    if err != nil || keep < 1 {
+        keep = 7
+    }
+
+    if err := os.MkdirAll(dir, 0755); err != nil {
+        return "", err
+    }
+
+    filename := filepath.Join(dir, fmt.Sprintf("konveksi_bude_%s.sql.gz", time.Now().Format("20060102_150405")))
+    file, err := os.Create(filename)
+    if err != nil {
+        return "", err
+    }
+
+    gz := gzip.NewWriter(file)
+    if err := repositories.DumpDatabase(db, gz); err != nil {
+        gz.Close()
+        file.Close()
+        os.Remove(filename)
+        return "", err
+    }
+    if err := gz.Close(); err != nil {
+        file.Close()
+        return "", err
+    }
+    if err := file.Close(); err != nil {
+        return "", err
+    }
+
+    // Hapus backup lama
+    matches, _ := filepath.Glob(filepath.Join(dir, "konveksi_bude_*.sql.gz"))
+    sort.Strings(matches)
+    removed := 0
+    for len(matches) > keep {
+        if err := os.Remove(matches[0]); err == nil {
+            removed++
+        }
+        matches = matches[1:]
+    }
+
+    message := "Backup tersimpan di " + filename
+    if removed > 0 {
+        message += fmt.Sprintf(" (%d backup lama dihapus)", removed)
+    }
+    return message, nil
+}
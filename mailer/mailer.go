@@ -0,0 +1,158 @@
+package mailer
+
+import (
+    "bytes"
+    "crypto/rand"
+    "encoding/base64"
+    "encoding/hex"
+    "fmt"
+    "mime"
+    "net"
+    "net/smtp"
+    "os"
+    "strconv"
+    "strings"
+    "time"
+)
+
+// Config SMTP, dibaca dari environment supaya bisa diarahkan ke SMTP lokal
+// (mis. MailHog di localhost:1025) saat testing
+type Config struct {
+    Host       string
+    Port       int
+    Username   string
+    Password   string
+    From       string
+    FromName   string
+    StaffEmail []string
+}
+
+func LoadConfigFromEnv() Config {
+    port, err := strconv.Atoi(os.Getenv("SMTP_PORT"))
+    if err != nil || port == 0 {
+        port = 587
+    }
+
+    cfg := Config{
+        Host:     os.Getenv("SMTP_HOST"),
+        Port:     port,
+        Username: os.Getenv("SMTP_USERNAME"),
+        Password: os.Getenv("SMTP_PASSWORD"),
+        From:     os.Getenv("SMTP_FROM"),
+        FromName: os.Getenv("SMTP_FROM_NAME"),
+    }
+    if cfg.FromName == "" {
+        cfg.FromName = "DiOlif Fashion"
+    }
+    for _, email := range strings.Split(os.Getenv("STAFF_EMAILS"), ",") {
+        if email = strings.TrimSpace(email); email != "" {
+            cfg.StaffEmail = append(cfg.StaffEmail, email)
+        }
+    }
+    return cfg
+}
+
+func (c Config) Enabled() bool {
+    return c.Host != "" && c.From != ""
+}
+
+type Attachment struct {
+    Filename    string
+    ContentType string
+    Data        []byte
+}
+
+type Message struct {
+    To          []string
+    Subject     string
+    Body        string
+    Attachments []Attachment
+}
+
+type Mailer struct {
+    Config Config
+}
+
+func (m *Mailer) Send(msg Message) error {
+    if !m.Config.Enabled() {
+        return fmt.Errorf("SMTP belum dikonfigurasi (SMTP_HOST/SMTP_FROM)")
+    }
+    if len(msg.To) == 0 {
+        return fmt.Errorf("penerima email kosong")
+    }
+
+    data, err := m.build(msg)
+    if err != nil {
+        return err
+    }
+
+    addr := net.JoinHostPort(m.Config.Host, strconv.Itoa(m.Config.Port))
+    var auth smtp.Auth
+    if m.Config.Username != "" {
+        auth = smtp.PlainAuth("", m.Config.Username, m.Config.Password, m.Config.Host)
+    }
+    return smtp.SendMail(addr, auth, m.Config.From, msg.To, data)
+}
+
+// build menyusun email MIME (text/plain, ditambah multipart/mixed jika ada lampiran)
+func (m *Mailer) build(msg Message) ([]byte, error) {
+    var buf bytes.Buffer
+
+    from := mime.QEncoding.Encode("utf-8", m.Config.FromName) + " <" + m.Config.From + ">"
+    fmt.Fprintf(&buf, "From: %s\r\n", from)
+    fmt.Fprintf(&buf, "To: %s\r\n", strings.Join(msg.To, ", "))
+    fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+    fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+    fmt.Fprintf(&buf, "MIME-Version: 1.0\r\n")
+
+    if len(msg.Attachments) == 0 {
+        fmt.Fprintf(&buf, "Content-Type: text/plain; charset=utf-8\r\n")
+        fmt.Fprintf(&buf, "Content-Transfer-Encoding: base64\r\n\r\n")
+        writeBase64(&buf, []byte(msg.Body))
+        return buf.Bytes(), nil
+    }
+
+    boundary, err := randomBoundary()
+    if err != nil {
+        return nil, err
+    }
+    fmt.Fprintf(&buf, "Content-Type: multipart/mixed; boundary=%s\r\n\r\n", boundary)
+
+    fmt.Fprintf(&buf, "--%s\r\n", boundary)
+    fmt.Fprintf(&buf, "Content-Type: text/plain; charset=utf-8\r\n")
+    fmt.Fprintf(&buf, "Content-Transfer-Encoding: base64\r\n\r\n")
+    writeBase64(&buf, []byte(msg.Body))
+
+    for _, a := range msg.Attachments {
+        contentType := a.ContentType
+        if contentType == "" {
+            contentType = "application/octet-stream"
+        }
+        fmt.Fprintf(&buf, "--%s\r\n", boundary)
+        fmt.Fprintf(&buf, "Content-Type: %s; name=\"%s\"\r\n", contentType, a.Filename)
+        fmt.Fprintf(&buf, "Content-Disposition: attachment; filename=\"%s\"\r\n", a.Filename)
+        fmt.Fprintf(&buf, "Content-Transfer-Encoding: base64\r\n\r\n")
+        writeBase64(&buf, a.Data)
+    }
+    fmt.Fprintf(&buf, "--%s--\r\n", boundary)
+
+    return buf.Bytes(), nil
+}
+
+// writeBase64 menulis data base64 dengan baris maksimal 76 karakter (RFC 2045)
+func writeBase64(buf *bytes.Buffer, data []byte) {
+    encoded := base64.StdEncoding.EncodeToString(data)
+    for len(encoded) > 76 {
+        buf.WriteString(encoded[:76] + "\r\n")
+        encoded = encoded[76:]
+    }
+    buf.WriteString(encoded + "\r\n")
+}
+
+func randomBoundary() (string, error) {
+    b := make([]byte, 12)
+    if _, err := rand.Read(b); err != nil {
+        return "", err
+    }
+    return "diolif-" + hex.EncodeToString(b), nil
+}
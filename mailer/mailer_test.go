@@ -0,0 +1,140 @@
+package mailer
+
+import (
+    "bufio"
+    "net"
+    "net/textproto"
+    "strconv"
+    "strings"
+    "testing"
+)
+
+// testSMTP adalah SMTP lokal minimal pengganti MailHog untuk test; setiap email yang diterima
+// dikirim ke channel messages
+type testSMTP struct {
+    Host     string
+    Port     int
+    messages chan string
+}
+
+func startTestSMTP(t *testing.T) *testSMTP {
+    t.Helper()
+    ln, err := net.Listen("tcp", "127.0.0.1:0")
+    if err != nil {
+        t.Fatal(err)
+    }
+    t.Cleanup(func() { ln.Close() })
+
+    host, port, _ := net.SplitHostPort(ln.Addr().String())
+    s := &testSMTP{Host: host, messages: make(chan string, 10)}
+    s.Port, _ = strconv.Atoi(port)
+
+    go func() {
+        for {
+            conn, err := ln.Accept()
+            if err != nil {
+                return
+            }
+            go s.serve(conn)
+        }
+    }()
+    return s
+}
+
+func (s *testSMTP) serve(conn net.Conn) {
+    defer conn.Close()
+    tp := textproto.NewConn(conn)
+    tp.PrintfLine("220 localhost test SMTP")
+    for {
+        line, err := tp.ReadLine()
+        if err != nil {
+            return
+        }
+        cmd := strings.ToUpper(strings.Fields(line + " ")[0])
+        switch cmd {
+        case "EHLO", "HELO":
+            tp.PrintfLine("250-localhost")
+            tp.PrintfLine("250 8BITMIME")
+        case "DATA":
+            tp.PrintfLine("354 end with <CRLF>.<CRLF>")
+            data, err := tp.ReadDotBytes()
+            if err != nil {
+                return
+            }
+            s.messages <- string(data)
+            tp.PrintfLine("250 OK")
+        case "QUIT":
+            tp.PrintfLine("221 bye")
+            return
+        default:
+            tp.PrintfLine("250 OK")
+        }
+    }
+}
+
+func (s *testSMTP) config() Config {
+    return Config{Host: s.Host, Port: s.Port, From: "toko@example.com", FromName: "DiOlif Fashion"}
+}
+
+func (s *testSMTP) next(t *testing.T) string {
+    t.Helper()
+    select {
+    case msg := <-s.messages:
+        return msg
+    default:
+        t.Fatal("tidak ada email yang diterima SMTP lokal")
+        return ""
+    }
+}
+
+func TestSendDeliversToLocalSMTP(t *testing.T) {
+    server := startTestSMTP(t)
+    m := &Mailer{Config: server.config()}
+
+    err := m.Send(Message{
+        To:      []string{"budi@example.com"},
+        Subject: "Kuitansi #12",
+        Body:    "Terima kasih",
+        Attachments: []Attachment{
+            {Filename: "kuitansi-12.pdf", ContentType: "application/pdf", Data: []byte("%PDF-1.3")},
+        },
+    })
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    msg := server.next(t)
+    for _, want := range []string{
+        "To: budi@example.com",
+        "Subject: Kuitansi #12",
+        "Content-Type: multipart/mixed",
+        `Content-Disposition: attachment; filename="kuitansi-12.pdf"`,
+    } {
+        if !strings.Contains(msg, want) {
+            t.Errorf("email tidak memuat %q:\n%s", want, msg)
+        }
+    }
+}
+
+func TestSendRequiresConfig(t *testing.T) {
+    m := &Mailer{}
+    if err := m.Send(Message{To: []string{"a@example.com"}}); err == nil {
+        t.Fatal("Send tanpa SMTP_HOST seharusnya gagal")
+    }
+}
+
+func TestWriteBase64WrapsLines(t *testing.T) {
+    var msg strings.Builder
+    m := &Mailer{Config: Config{From: "a@example.com"}}
+    data, err := m.build(Message{To: []string{"b@example.com"}, Body: strings.Repeat("x", 300)})
+    if err != nil {
+        t.Fatal(err)
+    }
+    msg.Write(data)
+    scanner := bufio.NewScanner(strings.NewReader(msg.String()))
+    for scanner.Scan() {
+        if len(scanner.Text()) > 76 {
+            t.Fatalf("baris lebih dari 76 karakter: %q", scanner.Text())
+        }
+    }
+}
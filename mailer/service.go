@@ -0,0 +1,276 @@
+package mailer
+
+import (
+    "database/sql"
+    "errors"
+    "fmt"
+    "konveksi-app/documents"
+    "konveksi-app/models"
+    "konveksi-app/repositories"
+    "log"
+    "time"
+)
+
+// ErrAlreadyClaimed berarti email sedang atau sudah dikirim oleh proses lain
+var ErrAlreadyClaimed = errors.New("email sedang dikirim oleh proses lain")
+
+// Service menyusun email dari data transaksi, mencatatnya di email_logs,
+// lalu mengirim (dan mencoba ulang) lewat SMTP
+type Service struct {
+    Mailer       *Mailer
+    Logs         *repositories.EmailLogRepository
+    Transactions *repositories.TransactionRepository
+    DB           *sql.DB
+    MaxAttempts  int
+}
+
+func (s *Service) maxAttempts() int {
+    if s.MaxAttempts <= 0 {
+        return 3
+    }
+    return s.MaxAttempts
+}
+
+func (s *Service) queue(templateName string, data interface{}, e *models.EmailLog) (bool, error) {
+    subject, body, err := Render(templateName, data)
+    if err != nil {
+        return false, err
+    }
+    e.Template = templateName
+    e.Subject = subject
+    e.Body = body
+    return s.Logs.Enqueue(e)
+}
+
+// QueuePaymentReminders membuat email pengingat untuk customer yang punya email.
+// Pengingat jatuh tempo dikirim sekali per tanggal jatuh tempo, pengingat
+// terlambat diulang setiap minggu sampai transaksi lunas.
+func (s *Service) QueuePaymentReminders() (int, error) {
+    reminders, err := s.Transactions.GetPaymentReminders()
+    if err != nil {
+        return 0, err
+    }
+
+    queued := 0
+    for _, p := range reminders {
+        if p.CustomerEmail == "" {
+            continue
+        }
+
+        templateName := "payment_due"
+        eventKey := fmt.Sprintf("trx-%d-%s", p.TransactionID, p.PaymentDate)
+        if p.Overdue {
+            templateName = "payment_overdue"
+            year, week := time.Now().ISOWeek()
+            eventKey = fmt.Sprintf("%s-%d-w%02d", eventKey, year, week)
+        }
+
+        created, err := s.queue(templateName, p, &models.EmailLog{
+            Recipient:      p.CustomerEmail,
+            TransactionID:  p.TransactionID,
+            CustomerID:     p.CustomerID,
+            EventKey:       eventKey,
+            AttachKuitansi: true,
+        })
+        if err != nil {
+            return queued, err
+        }
+        if created {
+            queued++
+        }
+    }
+    return queued, nil
+}
+
+// QueueStaffDigest membuat ringkasan harian untuk setiap alamat di STAFF_EMAILS
+func (s *Service) QueueStaffDigest() (int, error) {
+    if len(s.Mailer.Config.StaffEmail) == 0 {
+        return 0, nil
+    }
+
+    stats, err := repositories.GetDashboardStats(s.DB)
+    if err != nil {
+        return 0, err
+    }
+    reminders, err := s.Transactions.GetPaymentReminders()
+    if err != nil {
+        return 0, err
+    }
+
+    data := struct {
+        Date     string
+        Stats    repositories.DashboardStats
+        Overdue  []repositories.PaymentReminder
+        Upcoming []repositories.PaymentReminder
+    }{
+        Date:  time.Now().Format("2006-01-02"),
+        Stats: stats,
+    }
+    for _, p := range reminders {
+        if p.Overdue {
+            data.Overdue = append(data.Overdue, p)
+        } else {
+            data.Upcoming = append(data.Upcoming, p)
+        }
+    }
+
+    queued := 0
+    for _, recipient := range s.Mailer.Config.StaffEmail {
+        created, err := s.queue("staff_digest", data, &models.EmailLog{
+            Recipient: recipient,
+            EventKey:  "digest-" + data.Date,
+        })
+        if err != nil {
+            return queued, err
+        }
+        if created {
+            queued++
+        }
+    }
+    return queued, nil
+}
+
+// QueueKuitansi membuat email kuitansi untuk satu transaksi. Jika recipient
+// kosong, dikirim ke email customer.
+func (s *Service) QueueKuitansi(transactionID int, recipient string) (*models.EmailLog, error) {
+    trx, err := s.Transactions.GetByIDNormal(transactionID)
+    if err != nil {
+        return nil, err
+    }
+    customer, err := s.Transactions.GetCustomerByID(trx.CustomerID)
+    if err != nil {
+        return nil, err
+    }
+    if recipient == "" {
+        recipient = customer.Email
+    }
+    if recipient == "" {
+        return nil, fmt.Errorf("customer %s belum memiliki email", customer.Name)
+    }
+
+    data := struct {
+        TransactionID   int
+        CustomerName    string
+        TransactionDate string
+        Total           float64
+    }{trx.ID, trx.Customer_name, trx.Transaksidate, trx.Total}
+
+    e := &models.EmailLog{
+        Recipient:      recipient,
+        TransactionID:  trx.ID,
+        CustomerID:     trx.CustomerID,
+        AttachKuitansi: true,
+    }
+    if _, err := s.queue("kuitansi", data, e); err != nil {
+        return nil, err
+    }
+    return e, nil
+}
+
+// KuitansiPDF membuat PDF kuitansi untuk transaksi (pesanan biasa atau per siswa)
+func (s *Service) KuitansiPDF(transactionID int) ([]byte, error) {
+    trx, studentItems, err := s.Transactions.GetByIDStudentOrder(transactionID)
+    if err != nil {
+        return nil, err
+    }
+
+    var lines []documents.KuitansiLine
+    if len(studentItems) > 0 {
+        for _, item := range studentItems {
+            lines = append(lines, documents.KuitansiLine{
+                StudentName: item.StudentName,
+                UniformName: item.UniformName,
+                Size:        item.Size,
+                Quantity:    item.Quantity,
+                Subtotal:    item.UnitPrice * float64(item.Quantity),
+            })
+        }
+    } else {
+        normal, err := s.Transactions.GetByIDNormal(transactionID)
+        if err != nil {
+            return nil, err
+        }
+        for _, item := range normal.Items {
+            lines = append(lines, documents.KuitansiLine{
+                UniformName: item.UniformName,
+                Size:        item.Size,
+                Quantity:    item.Quantity,
+                Subtotal:    item.Subtotal,
+            })
+        }
+    }
+
+    customer, err := s.Transactions.GetCustomerByID(trx.CustomerID)
+    if err != nil {
+        log.Printf("Error getting customer for kuitansi %d: %v", transactionID, err)
+        customer = nil
+    }
+    return documents.Kuitansi(trx, customer, lines)
+}
+
+// Deliver mengklaim satu email dari log, mengirimnya dan memperbarui statusnya.
+// Mengembalikan ErrAlreadyClaimed jika email sudah diambil pengirim lain.
+func (s *Service) Deliver(e *models.EmailLog) error {
+    claimed, err := s.Logs.Claim(e.ID, s.maxAttempts())
+    if err != nil {
+        return err
+    }
+    if !claimed {
+        return ErrAlreadyClaimed
+    }
+
+    msg := Message{
+        To:      []string{e.Recipient},
+        Subject: e.Subject,
+        Body:    e.Body,
+    }
+
+    if e.AttachKuitansi && e.TransactionID > 0 {
+        var pdf []byte
+        pdf, err = s.KuitansiPDF(e.TransactionID)
+        if err == nil {
+            msg.Attachments = append(msg.Attachments, Attachment{
+                Filename:    fmt.Sprintf("kuitansi-%d.pdf", e.TransactionID),
+                ContentType: "application/pdf",
+                Data:        pdf,
+            })
+        }
+    }
+    if err == nil {
+        err = s.Mailer.Send(msg)
+    }
+
+    if err != nil {
+        log.Printf("Email %d to %s failed: %v", e.ID, e.Recipient, err)
+        if markErr := s.Logs.MarkFailed(e.ID, err.Error()); markErr != nil {
+            log.Printf("Error updating email log %d: %v", e.ID, markErr)
+        }
+        return err
+    }
+
+    log.Printf("Email %d sent to %s: %s", e.ID, e.Recipient, e.Subject)
+    return s.Logs.MarkSent(e.ID)
+}
+
+// SendPending mengirim semua email pending/failed yang belum melewati batas percobaan.
+// Email yang sudah diklaim job atau request lain dilewati.
+func (s *Service) SendPending() (int, int, error) {
+    pending, err := s.Logs.GetPending(s.maxAttempts())
+    if err != nil {
+        return 0, 0, err
+    }
+
+    sent, failed := 0, 0
+    for i := range pending {
+        err := s.Deliver(&pending[i])
+        switch {
+        case err == ErrAlreadyClaimed:
+            continue
+        case err != nil:
+            failed++
+        default:
+            sent++
+        }
+    }
+    return sent, failed, nil
+}
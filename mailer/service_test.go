@@ -0,0 +1,81 @@
+package mailer
+
+import (
+    "konveksi-app/models"
+    "konveksi-app/repositories"
+    "strings"
+    "testing"
+
+    "github.com/DATA-DOG/go-sqlmock"
+)
+
+func emailLogRows() *sqlmock.Rows {
+    return sqlmock.NewRows([]string{
+        "id", "template", "recipient", "subject", "body", "transaction_id", "customer_id", "event_key",
+        "attach_kuitansi", "status", "attempts", "last_error", "sent_at", "created_at",
+    })
+}
+
+func TestSendPendingSkipsEmailClaimedElsewhere(t *testing.T) {
+    server := startTestSMTP(t)
+    db, mock, err := sqlmock.New()
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer db.Close()
+    s := &Service{
+        Mailer: &Mailer{Config: server.config()},
+        Logs:   &repositories.EmailLogRepository{DB: db},
+        DB:     db,
+    }
+
+    mock.ExpectQuery("FROM email_logs WHERE").WithArgs(3).WillReturnRows(emailLogRows().
+        AddRow(1, "staff_digest", "staf@example.com", "Ringkasan", "isi 1", 0, 0, "digest-2025-07-01", false, "pending", 0, "", "", "2025-07-01").
+        AddRow(2, "staff_digest", "owner@example.com", "Ringkasan", "isi 2", 0, 0, "digest-2025-07-01", false, "pending", 0, "", "", "2025-07-01"))
+    // Email 1 sudah diambil job lain (mis. send_emails yang berjalan bersamaan)
+    mock.ExpectExec("UPDATE email_logs SET status = 'sending'").WithArgs(1, 3).WillReturnResult(sqlmock.NewResult(0, 0))
+    mock.ExpectExec("UPDATE email_logs SET status = 'sending'").WithArgs(2, 3).WillReturnResult(sqlmock.NewResult(0, 1))
+    mock.ExpectExec("UPDATE email_logs SET status = 'sent'").WithArgs(2).WillReturnResult(sqlmock.NewResult(0, 1))
+
+    sent, failed, err := s.SendPending()
+    if err != nil {
+        t.Fatal(err)
+    }
+    if sent != 1 || failed != 0 {
+        t.Fatalf("sent=%d failed=%d, seharusnya 1 dan 0", sent, failed)
+    }
+    if msg := server.next(t); !strings.Contains(msg, "To: owner@example.com") {
+        t.Fatalf("email terkirim ke penerima yang salah:\n%s", msg)
+    }
+    select {
+    case msg := <-server.messages:
+        t.Fatalf("email yang sudah diklaim ikut terkirim:\n%s", msg)
+    default:
+    }
+    if err := mock.ExpectationsWereMet(); err != nil {
+        t.Fatal(err)
+    }
+}
+
+func TestDeliverMarksFailedWhenSMTPDown(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer db.Close()
+    // Port 1 tidak menerima koneksi
+    s := &Service{
+        Mailer: &Mailer{Config: Config{Host: "127.0.0.1", Port: 1, From: "toko@example.com"}},
+        Logs:   &repositories.EmailLogRepository{DB: db},
+    }
+    mock.ExpectExec("UPDATE email_logs SET status = 'sending'").WithArgs(5, 3).WillReturnResult(sqlmock.NewResult(0, 1))
+    mock.ExpectExec("UPDATE email_logs SET status = 'failed'").WithArgs(sqlmock.AnyArg(), 5).WillReturnResult(sqlmock.NewResult(0, 1))
+
+    e := &models.EmailLog{ID: 5, Recipient: "a@example.com", Subject: "Tes"}
+    if err := s.Deliver(e); err == nil || err == ErrAlreadyClaimed {
+        t.Fatalf("Deliver seharusnya gagal karena SMTP mati, err = %v", err)
+    }
+    if err := mock.ExpectationsWereMet(); err != nil {
+        t.Fatal(err)
+    }
+}
@@ -0,0 +1,90 @@
+package mailer
+
+import (
+    "bytes"
+    "fmt"
+    "konveksi-app/documents"
+    "strings"
+    "text/template"
+)
+
+// Template email (bahasa Indonesia). Baris pertama adalah subject.
+var templateSources = map[string]string{
+    "payment_due": `Pengingat Pembayaran Pesanan #{{.TransactionID}}
+Yth. {{.CustomerName}},
+
+Kami mengingatkan bahwa pembayaran pesanan seragam #{{.TransactionID}} sebesar {{rupiah .Total}} akan jatuh tempo pada {{date .PaymentDate}}.
+
+Mohon pembayaran dapat dilakukan sebelum tanggal tersebut. Abaikan pesan ini apabila pembayaran sudah dilakukan.
+
+Terima kasih atas kepercayaan Anda.
+
+Hormat kami,
+DiOlif Fashion`,
+
+    "payment_overdue": `Pembayaran Pesanan #{{.TransactionID}} Telah Melewati Jatuh Tempo
+Yth. {{.CustomerName}},
+
+Pembayaran pesanan seragam #{{.TransactionID}} sebesar {{rupiah .Total}} telah melewati tanggal jatuh tempo {{date .PaymentDate}}.
+
+Mohon segera melakukan pembayaran atau menghubungi kami apabila ada kendala. Abaikan pesan ini apabila pembayaran sudah dilakukan.
+
+Terima kasih.
+
+Hormat kami,
+DiOlif Fashion`,
+
+    "kuitansi": `Kuitansi Pesanan #{{.TransactionID}}
+Yth. {{.CustomerName}},
+
+Terlampir kuitansi untuk pesanan seragam #{{.TransactionID}} tanggal {{date .TransactionDate}} dengan total {{rupiah .Total}}.
+
+Terima kasih atas kepercayaan Anda.
+
+Hormat kami,
+DiOlif Fashion`,
+
+    "staff_digest": `Ringkasan Harian {{date .Date}}
+Ringkasan transaksi per {{date .Date}}:
+
+- Pesanan pending       : {{.Stats.Allpaymentspending}}
+- Pembayaran terlambat  : {{.Stats.OverduePayments}}
+- Pesanan terlambat     : {{.Stats.OverdueTransactions}}
+- Pembayaran jatuh tempo 2 hari ke depan : {{.Stats.ReminderPayments}}
+- Pesanan target 2 hari ke depan         : {{.Stats.ReminderTransactions}}
+{{if .Overdue}}
+Pembayaran terlambat:
+{{range .Overdue}}  #{{.TransactionID}} {{.CustomerName}} - {{rupiah .Total}} (jatuh tempo {{date .PaymentDate}})
+{{end}}{{end}}{{if .Upcoming}}
+Pembayaran mendekati jatuh tempo:
+{{range .Upcoming}}  #{{.TransactionID}} {{.CustomerName}} - {{rupiah .Total}} (jatuh tempo {{date .PaymentDate}})
+{{end}}{{end}}
+Email ini dikirim otomatis oleh sistem DiOlif Fashion.`,
+}
+
+var templates = func() map[string]*template.Template {
+    funcs := template.FuncMap{
+        "rupiah": documents.FormatRupiah,
+        "date":   documents.FormatDate,
+    }
+    parsed := make(map[string]*template.Template)
+    for name, src := range templateSources {
+        parsed[name] = template.Must(template.New(name).Funcs(funcs).Parse(src))
+    }
+    return parsed
+}()
+
+// Render mengembalikan subject dan body untuk template tertentu
+func Render(name string, data interface{}) (string, string, error) {
+    tmpl, ok := templates[name]
+    if !ok {
+        return "", "", fmt.Errorf("template email '%s' tidak ditemukan", name)
+    }
+    var buf bytes.Buffer
+    if err := tmpl.Execute(&buf, data); err != nil {
+        return "", "", err
+    }
+    content := buf.String()
+    subject, body, _ := strings.Cut(content, "\n")
+    return subject, body, nil
+}
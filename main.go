@@ -3,9 +3,13 @@ package main
 import (
     "database/sql"
     "konveksi-app/handlers"
+    "konveksi-app/mailer"
+    "konveksi-app/messenger"
     "konveksi-app/repositories"
+    "konveksi-app/scheduler"
     "log"
     "net/http"
+    "os"
 
     _ "github.com/go-sql-driver/mysql"
     "github.com/gorilla/mux"
@@ -19,6 +23,7 @@ func authMiddleware(next http.Handler) http.Handler {
         // Skip authentication untuk login page dan static assets
         if r.URL.Path == "/" || r.URL.Path == "/login" || 
            r.URL.Path == "/api/auth/login" || r.URL.Path == "/api/auth/logout" ||
+           r.URL.Path == "/calendar.ics" ||
            (len(r.URL.Path) >= 8 && r.URL.Path[:8] == "/assets/") {
             next.ServeHTTP(w, r)
             return
@@ -61,12 +66,71 @@ func main() {
     customerRepo := &repositories.CustomerRepository{DB: db}
     transactionRepo := &repositories.TransactionRepository{DB: db}
     userRepo := &repositories.UserRepository{DB: db} // Tambah user repo
+    notificationRepo := &repositories.NotificationRepository{DB: db}
+    jobRunRepo := &repositories.JobRunRepository{DB: db}
+    emailLogRepo := &repositories.EmailLogRepository{DB: db}
+
+    // Initialize email service (SMTP dari environment)
+    emailService := &mailer.Service{
+        Mailer:       &mailer.Mailer{Config: mailer.LoadConfigFromEnv()},
+        Logs:         emailLogRepo,
+        Transactions: transactionRepo,
+        DB:           db,
+    }
+
+    // Initialize messenger WhatsApp/SMS (provider dari environment)
+    messengerProvider, err := messenger.NewFromEnv()
+    if err != nil {
+        log.Fatal("Failed to initialize messenger:", err)
+    }
+    messageService := &messenger.Service{
+        Messenger:    messengerProvider,
+        Logs:         &repositories.MessageLogRepository{DB: db},
+        Transactions: transactionRepo,
+    }
+
+    // Initialize scheduler (job harian)
+    jobScheduler := scheduler.New(db, jobRunRepo)
+    registerJobs(jobScheduler, jobDeps{
+        DB:               db,
+        NotificationRepo: notificationRepo,
+        CustomerRepo:     customerRepo,
+        EmailService:     emailService,
+        MessageService:   messageService,
+    })
+    go jobScheduler.Start()
+    defer jobScheduler.Stop()
 
     // Initialize handlers
     customerHandler := &handlers.CustomerHandler{Repo: customerRepo}
     transactionHandler := &handlers.TransactionHandler{Repo: transactionRepo}
     dashboardHandler := &handlers.DashboardHandler{DB: db}
     userHandler := &handlers.UserHandler{Repo: userRepo, DB: db} // Tambah user handler
+    notificationHandler := &handlers.NotificationHandler{Repo: notificationRepo, Users: userRepo}
+    jobHandler := &handlers.JobHandler{Scheduler: jobScheduler}
+    emailHandler := &handlers.EmailHandler{Service: emailService}
+    messageHandler := &handlers.MessageHandler{Service: messageService}
+    importHandler := &handlers.ImportHandler{Customers: customerRepo, Transactions: transactionRepo}
+    studentHandler := &handlers.StudentHandler{Repo: &repositories.StudentRepository{DB: db}}
+    measurementHandler := &handlers.MeasurementHandler{Repo: &repositories.MeasurementRepository{DB: db}}
+    priceListHandler := &handlers.PriceListHandler{Repo: &repositories.PriceRepository{DB: db}, Customers: customerRepo}
+    priceHandler := &handlers.PriceHandler{Customers: customerRepo}
+    catalogHandler := &handlers.CatalogHandler{Repo: &repositories.CatalogRepository{DB: db}}
+    sizeChartHandler := &handlers.SizeChartHandler{Repo: &repositories.SizeChartRepository{DB: db}, Customers: customerRepo}
+    productionHandler := &handlers.ProductionHandler{
+        Repo:                &repositories.ProductionRepository{DB: db},
+        AutoDeductMaterials: os.Getenv("AUTO_DEDUCT_MATERIALS") == "true",
+    }
+    materialHandler := &handlers.MaterialHandler{Repo: &repositories.MaterialRepository{DB: db}}
+    finishedGoodsHandler := &handlers.FinishedGoodsHandler{Repo: &repositories.FinishedGoodsRepository{DB: db}}
+    deliveryHandler := &handlers.DeliveryHandler{Repo: &repositories.DeliveryRepository{DB: db}, Transactions: transactionRepo}
+    supplierHandler := &handlers.SupplierHandler{Repo: &repositories.SupplierRepository{DB: db}}
+    purchaseOrderHandler := &handlers.PurchaseOrderHandler{Repo: &repositories.PurchaseOrderRepository{DB: db}}
+    workerHandler := &handlers.WorkerHandler{Repo: &repositories.WorkerRepository{DB: db}}
+    calendarHandler := &handlers.CalendarHandler{
+        Repo:      &repositories.CalendarRepository{DB: db},
+        FeedToken: os.Getenv("CALENDAR_TOKEN"),
+    }
 
     // Setup router
     r := mux.NewRouter()
@@ -92,6 +156,9 @@ func main() {
     r.HandleFunc("/api/auth/login", userHandler.LoginAPI).Methods("POST")
     r.HandleFunc("/api/auth/logout", userHandler.LogoutAPI).Methods("POST")
 
+    // Feed kalender (.ics) memakai token, bukan session, supaya bisa dilanggan dari HP
+    r.HandleFunc("/calendar.ics", calendarHandler.ICSFeed).Methods("GET")
+
     // ==============================================
     // PROTECTED ROUTES (dengan middleware)
     // ==============================================
@@ -106,6 +173,73 @@ func main() {
     // Dashboard API routes
     protected.HandleFunc("/api/dashboard/stats", dashboardHandler.GetDashboardStats).Methods("GET")
     protected.HandleFunc("/api/dashboard/notifications", dashboardHandler.GetNotifications).Methods("GET")
+    protected.HandleFunc("/api/calendar/events", calendarHandler.GetEvents).Methods("GET")
+    protected.HandleFunc("/api/production/recap", productionHandler.GetRecap).Methods("GET")
+    protected.HandleFunc("/api/production/materials", productionHandler.GetMaterialRequirements).Methods("GET")
+    protected.HandleFunc("/bahan", func(w http.ResponseWriter, r *http.Request) {
+        http.ServeFile(w, r, "bahan.html")
+    }).Methods("GET")
+    protected.HandleFunc("/api/materials", materialHandler.GetMaterials).Methods("GET")
+    protected.HandleFunc("/api/materials", materialHandler.CreateMaterial).Methods("POST")
+    protected.HandleFunc("/api/materials/movements", materialHandler.GetMovements).Methods("GET")
+    protected.HandleFunc("/api/materials/movements", materialHandler.RecordMovement).Methods("POST")
+    protected.HandleFunc("/api/materials/{id:[0-9]+}", materialHandler.GetMaterial).Methods("GET")
+    protected.HandleFunc("/api/materials/{id:[0-9]+}", materialHandler.UpdateMaterial).Methods("PUT")
+    protected.HandleFunc("/api/materials/{id:[0-9]+}", materialHandler.DeleteMaterial).Methods("DELETE")
+    protected.HandleFunc("/api/materials/{id:[0-9]+}/movements", materialHandler.GetMovements).Methods("GET")
+    protected.HandleFunc("/api/finished-goods", finishedGoodsHandler.GetStock).Methods("GET")
+    protected.HandleFunc("/api/finished-goods/availability", finishedGoodsHandler.GetAvailability).Methods("GET")
+    protected.HandleFunc("/api/finished-goods/receipts", finishedGoodsHandler.ReceiveStock).Methods("POST")
+    protected.HandleFunc("/api/finished-goods/counts", finishedGoodsHandler.CountStock).Methods("POST")
+    protected.HandleFunc("/api/finished-goods/movements", finishedGoodsHandler.GetMovements).Methods("GET")
+    protected.HandleFunc("/api/suppliers", supplierHandler.GetSuppliers).Methods("GET")
+    protected.HandleFunc("/api/suppliers", supplierHandler.CreateSupplier).Methods("POST")
+    protected.HandleFunc("/api/suppliers/payables", supplierHandler.GetPayables).Methods("GET")
+    protected.HandleFunc("/api/suppliers/{id:[0-9]+}", supplierHandler.GetSupplier).Methods("GET")
+    protected.HandleFunc("/api/suppliers/{id:[0-9]+}", supplierHandler.UpdateSupplier).Methods("PUT")
+    protected.HandleFunc("/api/suppliers/{id:[0-9]+}", supplierHandler.DeleteSupplier).Methods("DELETE")
+    protected.HandleFunc("/api/suppliers/{id:[0-9]+}/payments", supplierHandler.GetPayments).Methods("GET")
+    protected.HandleFunc("/api/suppliers/{id:[0-9]+}/payments", supplierHandler.CreatePayment).Methods("POST")
+    protected.HandleFunc("/api/purchase-orders", purchaseOrderHandler.GetPurchaseOrders).Methods("GET")
+    protected.HandleFunc("/api/purchase-orders", purchaseOrderHandler.CreatePurchaseOrder).Methods("POST")
+    protected.HandleFunc("/api/purchase-orders/{id:[0-9]+}", purchaseOrderHandler.GetPurchaseOrder).Methods("GET")
+    protected.HandleFunc("/api/purchase-orders/{id:[0-9]+}", purchaseOrderHandler.UpdatePurchaseOrder).Methods("PUT")
+    protected.HandleFunc("/api/purchase-orders/{id:[0-9]+}", purchaseOrderHandler.DeletePurchaseOrder).Methods("DELETE")
+    protected.HandleFunc("/api/purchase-orders/{id:[0-9]+}/status", purchaseOrderHandler.UpdatePurchaseOrderStatus).Methods("PUT")
+    protected.HandleFunc("/api/purchase-orders/{id:[0-9]+}/receipts", purchaseOrderHandler.ReceiveGoods).Methods("POST")
+    protected.HandleFunc("/api/workers", workerHandler.GetWorkers).Methods("GET")
+    protected.HandleFunc("/api/workers", workerHandler.CreateWorker).Methods("POST")
+    protected.HandleFunc("/api/workers/{id:[0-9]+}", workerHandler.GetWorker).Methods("GET")
+    protected.HandleFunc("/api/workers/{id:[0-9]+}", workerHandler.UpdateWorker).Methods("PUT")
+    protected.HandleFunc("/api/workers/{id:[0-9]+}", workerHandler.DeleteWorker).Methods("DELETE")
+    protected.HandleFunc("/api/work-assignments", workerHandler.GetAssignments).Methods("GET")
+    protected.HandleFunc("/api/work-assignments", workerHandler.CreateAssignment).Methods("POST")
+    protected.HandleFunc("/api/work-assignments/{id:[0-9]+}", workerHandler.DeleteAssignment).Methods("DELETE")
+    protected.HandleFunc("/api/work-assignments/{id:[0-9]+}/completions", workerHandler.RecordCompletion).Methods("POST")
+    protected.HandleFunc("/api/wages/weekly", workerHandler.GetWeeklyWages).Methods("GET")
+    protected.HandleFunc("/api/wages/payslips.pdf", workerHandler.PrintPayslips).Methods("GET")
+
+    // Notification routes (status baca/dismiss/snooze per user)
+    protected.HandleFunc("/api/notifications", notificationHandler.ListNotifications).Methods("GET")
+    protected.HandleFunc("/api/notifications/read-all", notificationHandler.MarkAllRead).Methods("PUT")
+    protected.HandleFunc("/api/notifications/{id}/read", notificationHandler.MarkRead).Methods("PUT")
+    protected.HandleFunc("/api/notifications/{id}/dismiss", notificationHandler.Dismiss).Methods("PUT")
+    protected.HandleFunc("/api/notifications/{id}/snooze", notificationHandler.Snooze).Methods("PUT")
+
+    // Admin job routes
+    protected.HandleFunc("/api/admin/jobs", jobHandler.ListJobs).Methods("GET")
+    protected.HandleFunc("/api/admin/jobs/{name}/runs", jobHandler.GetJobRuns).Methods("GET")
+    protected.HandleFunc("/api/admin/jobs/{name}/run", jobHandler.RunJob).Methods("POST")
+
+    // Admin email routes
+    protected.HandleFunc("/api/admin/emails", emailHandler.GetEmailLogs).Methods("GET")
+    protected.HandleFunc("/api/admin/emails/test", emailHandler.SendTestEmail).Methods("POST")
+    protected.HandleFunc("/api/admin/emails/{id}/retry", emailHandler.RetryEmail).Methods("POST")
+
+    // WhatsApp/SMS message routes
+    protected.HandleFunc("/api/admin/messages/test", messageHandler.SendTestMessage).Methods("POST")
+    protected.HandleFunc("/api/customers/{id}/messages", messageHandler.GetCustomerMessages).Methods("GET")
+    protected.HandleFunc("/api/transactions/{id}/notify-ready", messageHandler.NotifyOrderReady).Methods("POST")
 
     // Customer routes
     protected.HandleFunc("/kelolapelanggan", func(w http.ResponseWriter, r *http.Request) {
@@ -114,6 +248,8 @@ func main() {
 
     protected.HandleFunc("/api/customers", customerHandler.GetAllCustomers).Methods("GET")
     protected.HandleFunc("/api/customers", customerHandler.CreateCustomer).Methods("POST")
+    protected.HandleFunc("/api/customers/export", importHandler.ExportCustomers).Methods("GET")
+    protected.HandleFunc("/api/customers/import", importHandler.ImportCustomers).Methods("POST")
     protected.HandleFunc("/api/customers/{id}", customerHandler.GetCustomer).Methods("GET")
     protected.HandleFunc("/api/customers/{id}", customerHandler.UpdateCustomer).Methods("PUT")
     protected.HandleFunc("/api/customers/{id}", customerHandler.DeleteCustomer).Methods("DELETE")
@@ -133,10 +269,39 @@ func main() {
     // Customer uniform routes
     protected.HandleFunc("/api/customers/{id}/uniforms", customerHandler.GetUniformsByCustomerID).Methods("GET")
     protected.HandleFunc("/api/customers/{id}/uniforms", customerHandler.AddCustomerUniform).Methods("POST")
+    protected.HandleFunc("/api/customers/{id}/uniforms/copy", priceHandler.CopyPriceList).Methods("POST")
     protected.HandleFunc("/api/customer-uniforms/{id}", customerHandler.GetCustomerUniform).Methods("GET")
     protected.HandleFunc("/api/customer-uniforms/{id}", customerHandler.UpdateCustomerUniform).Methods("PUT")
     protected.HandleFunc("/api/customer-uniforms/{id}", customerHandler.DeleteCustomerUniform).Methods("DELETE")
     protected.HandleFunc("/api/customer-uniforms/{id}/price-history", customerHandler.GetUniformPriceHistory).Methods("GET")
+    protected.HandleFunc("/api/customer-uniforms/{id}/scheduled-prices", priceHandler.GetScheduledPrices).Methods("GET")
+    protected.HandleFunc("/api/customer-uniforms/{id}/scheduled-prices", priceHandler.SchedulePrice).Methods("POST")
+    protected.HandleFunc("/api/scheduled-prices/{id}", priceHandler.DeleteScheduledPrice).Methods("DELETE")
+    protected.HandleFunc("/api/admin/prices/adjust", priceHandler.AdjustPrices).Methods("POST")
+    protected.HandleFunc("/api/prices", priceListHandler.GetPrices).Methods("GET")
+    protected.HandleFunc("/api/customers/{id}/price-list.pdf", priceListHandler.PrintPriceList).Methods("GET")
+    protected.HandleFunc("/api/customers/{id}/size-guide.pdf", sizeChartHandler.PrintCustomerSizeGuide).Methods("GET")
+
+    // Katalog master seragam
+    protected.HandleFunc("/api/catalog", catalogHandler.GetCatalog).Methods("GET")
+    protected.HandleFunc("/api/catalog", catalogHandler.CreateCatalogItem).Methods("POST")
+    protected.HandleFunc("/api/catalog/link-suggestions", catalogHandler.GetLinkSuggestions).Methods("GET")
+    protected.HandleFunc("/api/catalog/link-suggestions/confirm", catalogHandler.ConfirmLinks).Methods("POST")
+    protected.HandleFunc("/api/catalog/{id:[0-9]+}", catalogHandler.GetCatalogItem).Methods("GET")
+    protected.HandleFunc("/api/catalog/{id:[0-9]+}", catalogHandler.UpdateCatalogItem).Methods("PUT")
+    protected.HandleFunc("/api/catalog/{id:[0-9]+}", catalogHandler.DeleteCatalogItem).Methods("DELETE")
+    protected.HandleFunc("/api/catalog/{id:[0-9]+}/size-rules", measurementHandler.GetSizeRules).Methods("GET")
+    protected.HandleFunc("/api/catalog/{id:[0-9]+}/size-rules", measurementHandler.SaveSizeRules).Methods("PUT")
+    protected.HandleFunc("/api/catalog/{id:[0-9]+}/bom", materialHandler.GetBOM).Methods("GET")
+    protected.HandleFunc("/api/catalog/{id:[0-9]+}/bom", materialHandler.SaveBOM).Methods("PUT")
+    protected.HandleFunc("/api/catalog/{id:[0-9]+}/piece-rates", workerHandler.GetPieceRates).Methods("GET")
+    protected.HandleFunc("/api/catalog/{id:[0-9]+}/piece-rates", workerHandler.SavePieceRates).Methods("PUT")
+    protected.HandleFunc("/api/size-charts", sizeChartHandler.GetSizeCharts).Methods("GET")
+    protected.HandleFunc("/api/size-charts", sizeChartHandler.CreateSizeChart).Methods("POST")
+    protected.HandleFunc("/api/size-charts/{id:[0-9]+}", sizeChartHandler.GetSizeChart).Methods("GET")
+    protected.HandleFunc("/api/size-charts/{id:[0-9]+}", sizeChartHandler.UpdateSizeChart).Methods("PUT")
+    protected.HandleFunc("/api/size-charts/{id:[0-9]+}", sizeChartHandler.DeleteSizeChart).Methods("DELETE")
+    protected.HandleFunc("/api/size-charts/{id:[0-9]+}/guide.pdf", sizeChartHandler.PrintSizeChart).Methods("GET")
 
     // Transaction routes
     protected.HandleFunc("/kelolatransaksi", func(w http.ResponseWriter, r *http.Request) {
@@ -174,11 +339,38 @@ func main() {
     protected.HandleFunc("/api/transactions/{id}/customer-uniforms", transactionHandler.GetCustomerUniformsByTransactionID).Methods("GET")
     protected.HandleFunc("/api/transactions", transactionHandler.CreateTransaction).Methods("POST")
     protected.HandleFunc("/api/transactions/student", transactionHandler.CreateStudentOrder).Methods("POST")
+    protected.HandleFunc("/api/customers/{id}/student-orders/import", importHandler.ImportStudentOrder).Methods("POST")
+
+    // Data siswa
+    protected.HandleFunc("/api/students", studentHandler.SearchStudents).Methods("GET")
+    protected.HandleFunc("/api/students/{id}", studentHandler.GetStudent).Methods("GET")
+    protected.HandleFunc("/api/students/{id}", studentHandler.UpdateStudent).Methods("PUT")
+    protected.HandleFunc("/api/students/{id}", studentHandler.DeleteStudent).Methods("DELETE")
+    protected.HandleFunc("/api/students/{id}/orders", studentHandler.GetStudentOrders).Methods("GET")
+    protected.HandleFunc("/api/students/{id}/measurements", measurementHandler.GetStudentMeasurements).Methods("GET")
+    protected.HandleFunc("/api/students/{id}/measurements", measurementHandler.CreateStudentMeasurement).Methods("POST")
+    protected.HandleFunc("/api/student-measurements/{id}", measurementHandler.DeleteMeasurement).Methods("DELETE")
+    protected.HandleFunc("/api/student-measurements/{id}/size-suggestions", measurementHandler.SuggestSizes).Methods("GET")
+    protected.HandleFunc("/api/customers/{id}/students", studentHandler.SearchStudents).Methods("GET")
+    protected.HandleFunc("/api/customers/{id}/students", studentHandler.CreateStudent).Methods("POST")
+    protected.HandleFunc("/api/customers/{id}/students/promote", studentHandler.PromoteStudents).Methods("POST")
     protected.HandleFunc("/api/transactions/{id}/status", transactionHandler.UpdateStatus).Methods("PUT")
+    protected.HandleFunc("/api/transactions/{id}/production-stage", productionHandler.SetProductionStage).Methods("PUT")
+    protected.HandleFunc("/api/transactions/{id}/stock-reservations", finishedGoodsHandler.GetReservations).Methods("GET")
+    protected.HandleFunc("/api/transactions/{id}/stock-reservations", finishedGoodsHandler.ReserveStock).Methods("POST")
+    protected.HandleFunc("/api/transactions/{id}/stock-reservations", finishedGoodsHandler.ReleaseReservations).Methods("DELETE")
     protected.HandleFunc("/api/customers/{customerID}/transactions", transactionHandler.GetCustomerTransactions).Methods("GET")
     protected.HandleFunc("/api/transactions/{transactionID}/status", transactionHandler.UpdateTransactionStatus).Methods("PUT")
     protected.HandleFunc("/api/transactions/{id}/print-kuitansi", transactionHandler.PrintKuitansi).Methods("GET")
     protected.HandleFunc("/api/transactions/{id}/print-kuitansi-biasa", transactionHandler.PrintKuitansibiasa).Methods("GET")
+    protected.HandleFunc("/api/transactions/{id}/work-order.pdf", transactionHandler.PrintWorkOrder).Methods("GET")
+    protected.HandleFunc("/api/transactions/{id}/delivery-note.pdf", transactionHandler.PrintDeliveryNote).Methods("GET")
+    protected.HandleFunc("/api/transactions/{id}/labels.pdf", transactionHandler.PrintLabels).Methods("GET")
+    protected.HandleFunc("/api/transactions/{id}/deliveries", deliveryHandler.GetStatus).Methods("GET")
+    protected.HandleFunc("/api/transactions/{id}/deliveries", deliveryHandler.CreateDelivery).Methods("POST")
+    protected.HandleFunc("/api/deliveries/{id}", deliveryHandler.DeleteDelivery).Methods("DELETE")
+    protected.HandleFunc("/api/deliveries/{id}/delivery-note.pdf", deliveryHandler.PrintDeliveryNote).Methods("GET")
+    protected.HandleFunc("/api/transactions/{id}/email-kuitansi", emailHandler.SendKuitansiEmail).Methods("POST")
     protected.HandleFunc("/api/customers/list", customerHandler.GetAllCustomers).Methods("GET")
     protected.HandleFunc("/api/student-order-items/{id}", transactionHandler.UpdateStudentOrderItem).Methods("PUT")
     protected.HandleFunc("/api/order-items/{id}", transactionHandler.UpdateNormalOrderItem).Methods("PUT")
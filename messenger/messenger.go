@@ -0,0 +1,145 @@
+package messenger
+
+import (
+    "bytes"
+    "encoding/json"
+    "fmt"
+    "io"
+    "net/http"
+    "os"
+    "path/filepath"
+    "strings"
+    "sync"
+    "text/template"
+    "time"
+)
+
+// Messenger mengirim pesan teks (WhatsApp/SMS) ke nomor E.164
+type Messenger interface {
+    Name() string
+    Send(phone, message string) error
+}
+
+// NewFromEnv memilih provider berdasarkan MESSENGER_PROVIDER (webhook / file).
+// Default "file" supaya pesan tidak terkirim sungguhan sebelum gateway dikonfigurasi.
+func NewFromEnv() (Messenger, error) {
+    switch os.Getenv("MESSENGER_PROVIDER") {
+    case "webhook":
+        return NewWebhookMessenger(
+            os.Getenv("MESSENGER_WEBHOOK_URL"),
+            os.Getenv("MESSENGER_WEBHOOK_PAYLOAD"),
+            os.Getenv("MESSENGER_WEBHOOK_TOKEN"),
+        )
+    case "", "file":
+        path := os.Getenv("MESSENGER_LOG_FILE")
+        if path == "" {
+            path = filepath.Join("logs", "messages.log")
+        }
+        return &FileMessenger{Path: path}, nil
+    default:
+        return nil, fmt.Errorf("MESSENGER_PROVIDER '%s' tidak dikenal", os.Getenv("MESSENGER_PROVIDER"))
+    }
+}
+
+// DefaultWebhookPayload dipakai jika MESSENGER_WEBHOOK_PAYLOAD kosong
+const DefaultWebhookPayload = `{"phone": {{json .Phone}}, "message": {{json .Message}}}`
+
+// WebhookMessenger mengirim pesan lewat HTTP POST ke gateway (mis. Fonnte, Wablas,
+// atau gateway sendiri). Body request dibentuk dari template dengan field .Phone dan .Message.
+type WebhookMessenger struct {
+    URL     string
+    Token   string
+    Payload *template.Template
+    Client  *http.Client
+}
+
+func NewWebhookMessenger(url, payload, token string) (*WebhookMessenger, error) {
+    if url == "" {
+        return nil, fmt.Errorf("MESSENGER_WEBHOOK_URL wajib diisi untuk provider webhook")
+    }
+    if payload == "" {
+        payload = DefaultWebhookPayload
+    }
+    tmpl, err := template.New("payload").Funcs(template.FuncMap{
+        "json": func(v interface{}) (string, error) {
+            b, err := json.Marshal(v)
+            return string(b), err
+        },
+        // nomor tanpa tanda "+", untuk gateway yang meminta format 628xx
+        "digits": func(phone string) string {
+            return strings.TrimPrefix(phone, "+")
+        },
+    }).Parse(payload)
+    if err != nil {
+        return nil, fmt.Errorf("template payload webhook tidak valid: %v", err)
+    }
+    return &WebhookMessenger{
+        URL:     url,
+        Token:   token,
+        Payload: tmpl,
+        Client:  &http.Client{Timeout: 15 * time.Second},
+    }, nil
+}
+
+func (m *WebhookMessenger) Name() string {
+    return "webhook"
+}
+
+func (m *WebhookMessenger) Send(phone, message string) error {
+    var body bytes.Buffer
+    err := m.Payload.Execute(&body, struct {
+        Phone   string
+        Message string
+    }{phone, message})
+    if err != nil {
+        return err
+    }
+
+    req, err := http.NewRequest("POST", m.URL, &body)
+    if err != nil {
+        return err
+    }
+    req.Header.Set("Content-Type", "application/json")
+    if m.Token != "" {
+        req.Header.Set("Authorization", "Bearer "+m.Token)
+    }
+
+    resp, err := m.Client.Do(req)
+    if err != nil {
+        return err
+    }
+    defer resp.Body.Close()
+
+    if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+        respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+        return fmt.Errorf("gateway membalas HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+    }
+    return nil
+}
+
+// FileMessenger hanya menulis pesan ke file, untuk testing tanpa gateway
+type FileMessenger struct {
+    Path string
+    mu   sync.Mutex
+}
+
+func (m *FileMessenger) Name() string {
+    return "file"
+}
+
+func (m *FileMessenger) Send(phone, message string) error {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    if err := os.MkdirAll(filepath.Dir(m.Path), 0755); err != nil {
+        return err
+    }
+    f, err := os.OpenFile(m.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+    if err != nil {
+        return err
+    }
+    defer f.Close()
+
+    _, err = fmt.Fprintf(f, "[%s] to %s\n%s\n---\n", time.Now().Format("2006-01-02 15:04:05"), phone, message)
+    return err
+}
@@ -0,0 +1,41 @@
+package messenger
+
+import (
+    "fmt"
+    "strings"
+)
+
+// NormalizePhone mengubah nomor telepon Indonesia ke format E.164 (+62...).
+// Menerima format umum seperti "0812-3456-7890", "62812...", "+62 812...", "812...".
+func NormalizePhone(raw string) (string, error) {
+    var digits strings.Builder
+    for _, r := range raw {
+        if r >= '0' && r <= '9' {
+            digits.WriteRune(r)
+        }
+    }
+    number := digits.String()
+
+    switch {
+    case strings.HasPrefix(number, "62"):
+        number = number[2:]
+    case strings.HasPrefix(number, "0"):
+        number = number[1:]
+    }
+    // Nomor yang ditulis "+62 (0)812..." masih menyisakan angka 0
+    number = strings.TrimPrefix(number, "0")
+
+    // Nomor seluler 8xx (9-12 digit), telepon rumah kode area 2x-9x (7-11 digit)
+    if len(number) < 7 || len(number) > 12 {
+        return "", fmt.Errorf("nomor telepon '%s' tidak valid", raw)
+    }
+    if number[0] < '2' {
+        return "", fmt.Errorf("nomor telepon '%s' tidak valid", raw)
+    }
+    return "+62" + number, nil
+}
+
+// IsMobile mengecek apakah nomor E.164 adalah nomor seluler (bisa WhatsApp/SMS)
+func IsMobile(e164 string) bool {
+    return strings.HasPrefix(e164, "+628")
+}
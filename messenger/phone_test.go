@@ -0,0 +1,25 @@
+package messenger
+
+import "testing"
+
+func TestNormalizePhone(t *testing.T) {
+    tests := []struct {
+        raw, want string
+        ok        bool
+    }{
+        {"0812-3456-7890", "+6281234567890", true},
+        {"+62 812 3456 7890", "+6281234567890", true},
+        {"6281234567890", "+6281234567890", true},
+        {"+62 (0)812 3456 789", "+628123456789", true},
+        {"(024) 7654321", "+62247654321", true},
+        {"12345", "", false},
+        {"0812 3456 7890 / 0813 1111 2222 (bapak)", "", false},
+        {"bukan nomor", "", false},
+    }
+    for _, tt := range tests {
+        got, err := NormalizePhone(tt.raw)
+        if (err == nil) != tt.ok || got != tt.want {
+            t.Errorf("NormalizePhone(%q) = %q, %v; want %q ok=%v", tt.raw, got, err, tt.want, tt.ok)
+        }
+    }
+}
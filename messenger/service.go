@@ -0,0 +1,151 @@
+package messenger
+
+import (
+    "bytes"
+    "errors"
+    "fmt"
+    "konveksi-app/documents"
+    "konveksi-app/models"
+    "konveksi-app/repositories"
+    "log"
+    "text/template"
+    "time"
+)
+
+var messageTemplates = template.Must(template.New("messages").Funcs(template.FuncMap{
+    "rupiah": documents.FormatRupiah,
+    "date":   documents.FormatDate,
+}).Parse(`
+{{define "pesanan_siap"}}Halo {{.CustomerName}},
+Pesanan seragam #{{.TransactionID}} sudah selesai dan *siap diambil* di DiOlif Fashion.
+{{if .Unpaid}}Sisa pembayaran: {{rupiah .Total}}.
+{{end}}Terima kasih.{{end}}
+
+{{define "payment_due"}}Halo {{.CustomerName}},
+Mengingatkan pembayaran pesanan seragam #{{.TransactionID}} sebesar {{rupiah .Total}} jatuh tempo pada {{date .PaymentDate}}.
+Abaikan pesan ini jika sudah membayar. Terima kasih - DiOlif Fashion{{end}}
+
+{{define "payment_overdue"}}Halo {{.CustomerName}},
+Pembayaran pesanan seragam #{{.TransactionID}} sebesar {{rupiah .Total}} sudah melewati jatuh tempo {{date .PaymentDate}}.
+Mohon segera melakukan pembayaran. Abaikan pesan ini jika sudah membayar. Terima kasih - DiOlif Fashion{{end}}
+`))
+
+func renderMessage(name string, data interface{}) (string, error) {
+    var buf bytes.Buffer
+    if err := messageTemplates.ExecuteTemplate(&buf, name, data); err != nil {
+        return "", err
+    }
+    return buf.String(), nil
+}
+
+// ErrInvalidPhone berarti kontak customer bukan nomor seluler yang valid. Pesan tidak dikirim
+// dan tidak dicatat, karena kontak mentah tidak muat di kolom phone message_logs.
+var ErrInvalidPhone = errors.New("kontak customer bukan nomor seluler yang valid")
+
+type Service struct {
+    Messenger    Messenger
+    Logs         *repositories.MessageLogRepository
+    Transactions *repositories.TransactionRepository
+}
+
+// send menormalisasi nomor, mengirim pesan dan mencatatnya di message_logs
+func (s *Service) send(customerID, transactionID int, contact, templateName, eventKey string, data interface{}) (*models.MessageLog, error) {
+    text, err := renderMessage(templateName, data)
+    if err != nil {
+        return nil, err
+    }
+
+    phone, err := NormalizePhone(contact)
+    if err != nil {
+        return nil, fmt.Errorf("%w: %v", ErrInvalidPhone, err)
+    }
+    if !IsMobile(phone) {
+        return nil, fmt.Errorf("%w: nomor %s bukan nomor seluler", ErrInvalidPhone, phone)
+    }
+
+    entry := &models.MessageLog{
+        CustomerID:    customerID,
+        TransactionID: transactionID,
+        Template:      templateName,
+        Provider:      s.Messenger.Name(),
+        Phone:         phone,
+        Message:       text,
+        EventKey:      eventKey,
+    }
+    err = s.Messenger.Send(phone, text)
+
+    entry.Status = "sent"
+    if err != nil {
+        entry.Status = "failed"
+        entry.Error = err.Error()
+        log.Printf("Message %s to customer %d failed: %v", templateName, customerID, err)
+    }
+
+    if logErr := s.Logs.Create(entry); logErr != nil {
+        log.Printf("Error saving message log: %v", logErr)
+    }
+    return entry, err
+}
+
+// SendOrderReady mengirim pesan "pesanan siap diambil" ke kontak customer
+func (s *Service) SendOrderReady(transactionID int) (*models.MessageLog, error) {
+    trx, err := s.Transactions.GetByIDNormal(transactionID)
+    if err != nil {
+        return nil, err
+    }
+    customer, err := s.Transactions.GetCustomerByID(trx.CustomerID)
+    if err != nil {
+        return nil, err
+    }
+
+    data := struct {
+        CustomerName  string
+        TransactionID int
+        Total         float64
+        Unpaid        bool
+    }{customer.Name, trx.ID, trx.Total, trx.Status != "paid"}
+
+    return s.send(customer.ID, trx.ID, customer.Contact, "pesanan_siap", fmt.Sprintf("trx-%d", trx.ID), data)
+}
+
+// SendPaymentReminders mengirim pengingat pembayaran. Sama seperti email,
+// pengingat jatuh tempo sekali per tanggal, pengingat terlambat sekali per minggu.
+func (s *Service) SendPaymentReminders() (int, int, error) {
+    reminders, err := s.Transactions.GetPaymentReminders()
+    if err != nil {
+        return 0, 0, err
+    }
+
+    sent, failed := 0, 0
+    for _, p := range reminders {
+        if p.CustomerContact == "" {
+            continue
+        }
+
+        templateName := "payment_due"
+        eventKey := fmt.Sprintf("trx-%d-%s", p.TransactionID, p.PaymentDate)
+        if p.Overdue {
+            templateName = "payment_overdue"
+            year, week := time.Now().ISOWeek()
+            eventKey = fmt.Sprintf("%s-%d-w%02d", eventKey, year, week)
+        }
+
+        already, err := s.Logs.WasSent(templateName, eventKey)
+        if err != nil {
+            return sent, failed, err
+        }
+        if already {
+            continue
+        }
+
+        if _, err := s.send(p.CustomerID, p.TransactionID, p.CustomerContact, templateName, eventKey, p); err != nil {
+            if errors.Is(err, ErrInvalidPhone) {
+                log.Printf("Payment reminder for transaction %d skipped: %v", p.TransactionID, err)
+            }
+            failed++
+        } else {
+            sent++
+        }
+    }
+    return sent, failed, nil
+}
@@ -0,0 +1,66 @@
+package messenger
+
+import (
+    "errors"
+    "konveksi-app/repositories"
+    "testing"
+
+    "github.com/DATA-DOG/go-sqlmock"
+)
+
+type recordingMessenger struct {
+    sent []string
+}
+
+func (m *recordingMessenger) Name() string { return "test" }
+
+func (m *recordingMessenger) Send(phone, message string) error {
+    m.sent = append(m.sent, phone)
+    return nil
+}
+
+func newTestService(t *testing.T) (*Service, *recordingMessenger, sqlmock.Sqlmock) {
+    t.Helper()
+    db, mock, err := sqlmock.New()
+    if err != nil {
+        t.Fatal(err)
+    }
+    t.Cleanup(func() { db.Close() })
+    m := &recordingMessenger{}
+    return &Service{Messenger: m, Logs: &repositories.MessageLogRepository{DB: db}}, m, mock
+}
+
+func TestSendRejectsInvalidContactWithoutLogging(t *testing.T) {
+    for _, contact := range []string{"0812 3456 7890 / 0813 1111 2222 (bapak)", "(024) 7654321"} {
+        s, m, mock := newTestService(t)
+        _, err := s.send(1, 2, contact, "pesanan_siap", "trx-2", map[string]interface{}{"CustomerName": "SD Ceria"})
+        if !errors.Is(err, ErrInvalidPhone) {
+            t.Fatalf("send(%q) err = %v, seharusnya ErrInvalidPhone", contact, err)
+        }
+        if len(m.sent) != 0 {
+            t.Fatalf("pesan tetap dikirim ke %v", m.sent)
+        }
+        // Tidak ada INSERT ke message_logs
+        if err := mock.ExpectationsWereMet(); err != nil {
+            t.Fatal(err)
+        }
+    }
+}
+
+func TestSendLogsNormalizedPhone(t *testing.T) {
+    s, m, mock := newTestService(t)
+    mock.ExpectExec("INSERT INTO message_logs").
+        WithArgs(1, 2, "pesanan_siap", "test", "+6281234567890", sqlmock.AnyArg(), "trx-2", "sent", nil).
+        WillReturnResult(sqlmock.NewResult(9, 1))
+
+    entry, err := s.send(1, 2, "0812-3456-7890", "pesanan_siap", "trx-2", map[string]interface{}{"CustomerName": "SD Ceria"})
+    if err != nil {
+        t.Fatal(err)
+    }
+    if entry.ID != 9 || len(m.sent) != 1 || m.sent[0] != "+6281234567890" {
+        t.Fatalf("entry=%+v sent=%v", entry, m.sent)
+    }
+    if err := mock.ExpectationsWereMet(); err != nil {
+        t.Fatal(err)
+    }
+}
@@ -0,0 +1,28 @@
+-- Notifikasi per transaksi & event, beserta status baca/dismiss/snooze per user
+-- Jalankan setelah import `konveksi_bude.sql`
+
+CREATE TABLE IF NOT EXISTS `notifications` (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `type` varchar(50) NOT NULL,
+  `transaction_id` int NOT NULL,
+  `customer_id` int NOT NULL,
+  `title` varchar(150) NOT NULL,
+  `message` text NOT NULL,
+  `event_date` date NOT NULL,
+  `resolved_at` timestamp NULL DEFAULT NULL,
+  `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uniq_notification_event` (`type`,`transaction_id`,`event_date`),
+  KEY `transaction_id` (`transaction_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+CREATE TABLE IF NOT EXISTS `notification_user_states` (
+  `notification_id` int NOT NULL,
+  `user_id` int NOT NULL,
+  `read_at` timestamp NULL DEFAULT NULL,
+  `dismissed_at` timestamp NULL DEFAULT NULL,
+  `snoozed_until` date DEFAULT NULL,
+  `updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`notification_id`,`user_id`),
+  KEY `user_id` (`user_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
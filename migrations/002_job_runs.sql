@@ -0,0 +1,16 @@
+-- Riwayat eksekusi job scheduler. scheduled_at adalah menit jadwal yang dijalankan (NULL untuk run manual
+-- dan job lokal); UNIQUE mencegah instance lain menjalankan slot jadwal yang sama.
+
+CREATE TABLE IF NOT EXISTS `job_runs` (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `job_name` varchar(100) NOT NULL,
+  `trigger_type` enum('schedule','manual') NOT NULL DEFAULT 'schedule',
+  `status` enum('running','success','failed') NOT NULL DEFAULT 'running',
+  `message` text,
+  `scheduled_at` datetime DEFAULT NULL,
+  `started_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+  `finished_at` timestamp NULL DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  KEY `job_name` (`job_name`,`started_at`),
+  UNIQUE KEY `job_slot` (`job_name`,`scheduled_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
@@ -0,0 +1,26 @@
+-- Email customer + log pengiriman email (outbox dengan retry)
+
+ALTER TABLE `customers`
+  ADD COLUMN `email` varchar(100) DEFAULT NULL AFTER `contact`;
+
+CREATE TABLE IF NOT EXISTS `email_logs` (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `template` varchar(50) NOT NULL,
+  `recipient` varchar(255) NOT NULL,
+  `subject` varchar(255) NOT NULL,
+  `body` text NOT NULL,
+  `transaction_id` int DEFAULT NULL,
+  `customer_id` int DEFAULT NULL,
+  `event_key` varchar(100) DEFAULT NULL,
+  `attach_kuitansi` tinyint(1) NOT NULL DEFAULT '0',
+  `status` enum('pending','sending','sent','failed') NOT NULL DEFAULT 'pending', -- sending: sedang diklaim satu pengirim
+  `attempts` int NOT NULL DEFAULT '0',
+  `last_error` text,
+  `sent_at` timestamp NULL DEFAULT NULL,
+  `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uniq_email_event` (`template`,`recipient`,`event_key`),
+  KEY `status` (`status`),
+  KEY `transaction_id` (`transaction_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
@@ -0,0 +1,18 @@
+-- Log pesan WhatsApp/SMS per customer
+
+CREATE TABLE IF NOT EXISTS `message_logs` (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `customer_id` int NOT NULL,
+  `transaction_id` int DEFAULT NULL,
+  `template` varchar(50) NOT NULL,
+  `provider` varchar(30) NOT NULL,
+  `phone` varchar(20) NOT NULL,
+  `message` text NOT NULL,
+  `event_key` varchar(100) DEFAULT NULL,
+  `status` enum('sent','failed') NOT NULL,
+  `error` text,
+  `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `customer_id` (`customer_id`,`created_at`),
+  KEY `event_key` (`template`,`event_key`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
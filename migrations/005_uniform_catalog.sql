@@ -0,0 +1,33 @@
+-- Katalog master seragam yang dipakai bersama oleh daftar harga customer
+
+CREATE TABLE IF NOT EXISTS `uniform_catalog` (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `name` varchar(100) NOT NULL,
+  `category` varchar(50) DEFAULT NULL,
+  `fabric` varchar(100) DEFAULT NULL,
+  `description` text,
+  `active` tinyint(1) NOT NULL DEFAULT '1',
+  `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `name` (`name`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+-- Ukuran default beserta harga dasar
+CREATE TABLE IF NOT EXISTS `uniform_catalog_sizes` (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `catalog_id` int NOT NULL,
+  `size` varchar(20) NOT NULL,
+  `base_price` decimal(10,2) NOT NULL DEFAULT '0.00',
+  `sort_order` int NOT NULL DEFAULT '0',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `catalog_size` (`catalog_id`,`size`),
+  CONSTRAINT `uniform_catalog_sizes_ibfk_1` FOREIGN KEY (`catalog_id`) REFERENCES `uniform_catalog` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+-- Daftar harga customer merujuk ke katalog; price di customer_uniforms adalah harga khusus customer.
+-- Baris lama (catalog_id NULL) dihubungkan lewat /api/catalog/link-suggestions setelah dikonfirmasi admin.
+ALTER TABLE `customer_uniforms`
+  ADD COLUMN `catalog_id` int DEFAULT NULL AFTER `customer_id`,
+  ADD KEY `catalog_id` (`catalog_id`),
+  ADD CONSTRAINT `customer_uniforms_catalog_fk` FOREIGN KEY (`catalog_id`) REFERENCES `uniform_catalog` (`id`) ON DELETE SET NULL;
@@ -0,0 +1,24 @@
+-- Harga dengan tanggal berlaku. Setiap perubahan harga (langsung maupun terjadwal) dicatat di sini;
+-- harga yang berlaku pada tanggal X adalah baris dengan effective_from <= X yang paling akhir.
+
+CREATE TABLE IF NOT EXISTS `customer_uniform_prices` (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `customer_uniform_id` int NOT NULL,
+  `price` decimal(10,2) NOT NULL,
+  `previous_price` decimal(10,2) DEFAULT NULL,
+  `effective_from` date NOT NULL,
+  `changed_by` varchar(100) DEFAULT NULL,
+  `reason` text,
+  `applied_at` timestamp NULL DEFAULT NULL,
+  `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `uniform_effective` (`customer_uniform_id`,`effective_from`),
+  KEY `pending` (`applied_at`,`effective_from`),
+  CONSTRAINT `customer_uniform_prices_ibfk_1` FOREIGN KEY (`customer_uniform_id`) REFERENCES `customer_uniforms` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+ALTER TABLE `customer_uniform_price_history`
+  ADD COLUMN `new_price` decimal(10,2) DEFAULT NULL AFTER `old_price`,
+  ADD COLUMN `changed_by` varchar(100) DEFAULT NULL AFTER `new_price`,
+  ADD COLUMN `reason` text AFTER `changed_by`,
+  ADD COLUMN `effective_from` date DEFAULT NULL AFTER `reason`;
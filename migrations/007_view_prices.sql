@@ -0,0 +1,13 @@
+-- view_prices ditambah customer_id, tipe customer dan catalog_id supaya /api/prices bisa difilter
+
+CREATE OR REPLACE VIEW `view_prices` AS
+SELECT `cu`.`id` AS `id`,
+       `c`.`id` AS `customer_id`,
+       `c`.`name` AS `customer_name`,
+       `c`.`type` AS `customer_type`,
+       `cu`.`catalog_id` AS `catalog_id`,
+       `cu`.`uniform_name` AS `uniform_name`,
+       `cu`.`size` AS `size`,
+       `cu`.`price` AS `price`,
+       `cu`.`notes` AS `notes`
+FROM (`customer_uniforms` `cu` JOIN `customers` `c` ON ((`cu`.`customer_id` = `c`.`id`)));
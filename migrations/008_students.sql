@@ -0,0 +1,37 @@
+-- Data siswa per sekolah (customer); pesanan per siswa merujuk ke students.id
+
+CREATE TABLE IF NOT EXISTS `students` (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `customer_id` int NOT NULL,
+  `name` varchar(100) NOT NULL,
+  `class` varchar(20) DEFAULT NULL,
+  `nis` varchar(30) DEFAULT NULL,
+  `parent_name` varchar(100) DEFAULT NULL,
+  `parent_contact` varchar(100) DEFAULT NULL,
+  `active` tinyint(1) NOT NULL DEFAULT '1',
+  `notes` text,
+  `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `customer_nis` (`customer_id`,`nis`),
+  KEY `customer_name` (`customer_id`,`name`),
+  CONSTRAINT `students_ibfk_1` FOREIGN KEY (`customer_id`) REFERENCES `customers` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+ALTER TABLE `student_order_items`
+  ADD COLUMN `student_id` int DEFAULT NULL AFTER `customer_id`,
+  ADD KEY `student_id` (`student_id`),
+  ADD CONSTRAINT `student_order_items_student_fk` FOREIGN KEY (`student_id`) REFERENCES `students` (`id`) ON DELETE SET NULL;
+
+-- Isi roster dari pesanan lama: satu siswa per kombinasi sekolah + nama + kelas
+INSERT INTO `students` (`customer_id`, `name`, `class`)
+SELECT DISTINCT `customer_id`, TRIM(`student_name`), NULLIF(TRIM(`grade`), '')
+FROM `student_order_items`
+WHERE TRIM(`student_name`) != '';
+
+UPDATE `student_order_items` soi
+JOIN `students` s ON s.`customer_id` = soi.`customer_id`
+  AND s.`name` = TRIM(soi.`student_name`)
+  AND COALESCE(s.`class`, '') = COALESCE(NULLIF(TRIM(soi.`grade`), ''), '')
+SET soi.`student_id` = s.`id`
+WHERE soi.`student_id` IS NULL;
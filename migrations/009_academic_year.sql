@@ -0,0 +1,25 @@
+-- Tahun ajaran (mis. 2025/2026, mulai Juli) pada setiap pesanan dan riwayat kenaikan kelas
+
+ALTER TABLE `transactions`
+  ADD COLUMN `academic_year` varchar(9) DEFAULT NULL AFTER `transaction_date`,
+  ADD KEY `academic_year` (`academic_year`);
+
+UPDATE `transactions`
+SET `academic_year` = IF(MONTH(`transaction_date`) >= 7,
+    CONCAT(YEAR(`transaction_date`), '/', YEAR(`transaction_date`) + 1),
+    CONCAT(YEAR(`transaction_date`) - 1, '/', YEAR(`transaction_date`)))
+WHERE `academic_year` IS NULL;
+
+CREATE TABLE IF NOT EXISTS `student_promotions` (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `customer_id` int NOT NULL,
+  `academic_year` varchar(9) NOT NULL,
+  `promoted` int NOT NULL DEFAULT '0',
+  `graduated` int NOT NULL DEFAULT '0',
+  `skipped` int NOT NULL DEFAULT '0',
+  `performed_by` varchar(100) DEFAULT NULL,
+  `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `customer_year` (`customer_id`,`academic_year`),
+  CONSTRAINT `student_promotions_ibfk_1` FOREIGN KEY (`customer_id`) REFERENCES `customers` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
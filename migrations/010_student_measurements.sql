@@ -0,0 +1,44 @@
+-- Ukuran badan per siswa untuk seragam jahit ukur; satu siswa bisa punya beberapa catatan (terbaru dipakai)
+
+CREATE TABLE IF NOT EXISTS `student_measurements` (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `student_id` int NOT NULL,
+  `chest` decimal(5,1) DEFAULT NULL,
+  `waist` decimal(5,1) DEFAULT NULL,
+  `hip` decimal(5,1) DEFAULT NULL,
+  `shoulder` decimal(5,1) DEFAULT NULL,
+  `sleeve` decimal(5,1) DEFAULT NULL,
+  `shirt_length` decimal(5,1) DEFAULT NULL,
+  `trouser_length` decimal(5,1) DEFAULT NULL,
+  `unit` enum('cm','inch') NOT NULL DEFAULT 'cm',
+  `measured_at` date NOT NULL,
+  `measured_by` varchar(50) DEFAULT NULL,
+  `notes` text,
+  `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `student_measured` (`student_id`,`measured_at`),
+  CONSTRAINT `student_measurements_ibfk_1` FOREIGN KEY (`student_id`) REFERENCES `students` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+-- Ukuran standar (dalam cm) tiap ukuran katalog, dipakai mencari ukuran terdekat dari ukuran badan
+CREATE TABLE IF NOT EXISTS `uniform_size_rules` (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `catalog_id` int NOT NULL,
+  `size` varchar(20) NOT NULL,
+  `chest` decimal(5,1) DEFAULT NULL,
+  `waist` decimal(5,1) DEFAULT NULL,
+  `hip` decimal(5,1) DEFAULT NULL,
+  `shoulder` decimal(5,1) DEFAULT NULL,
+  `sleeve` decimal(5,1) DEFAULT NULL,
+  `shirt_length` decimal(5,1) DEFAULT NULL,
+  `trouser_length` decimal(5,1) DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `catalog_size` (`catalog_id`,`size`),
+  CONSTRAINT `uniform_size_rules_ibfk_1` FOREIGN KEY (`catalog_id`) REFERENCES `uniform_catalog` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+-- Item pesanan jahit ukur merujuk ke catatan ukuran yang dipakai penjahit
+ALTER TABLE `student_order_items`
+  ADD COLUMN `measurement_id` int DEFAULT NULL AFTER `student_id`,
+  ADD KEY `measurement_id` (`measurement_id`),
+  ADD CONSTRAINT `student_order_items_measurement_fk` FOREIGN KEY (`measurement_id`) REFERENCES `student_measurements` (`id`) ON DELETE SET NULL;
@@ -0,0 +1,39 @@
+-- Tabel ukuran per seragam katalog dan/atau per customer: urutan kode ukuran beserta rentang ukuran badan.
+-- Urutan pemakaian: customer + katalog, lalu customer saja (semua seragam customer), lalu katalog saja.
+-- Satu cakupan customer/katalog hanya boleh punya satu tabel (NULL dihitung 0 supaya ikut unik).
+
+CREATE TABLE IF NOT EXISTS `size_charts` (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `catalog_id` int DEFAULT NULL,
+  `customer_id` int DEFAULT NULL,
+  `name` varchar(100) NOT NULL,
+  `notes` text,
+  `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `catalog_id` (`catalog_id`),
+  KEY `customer_id` (`customer_id`),
+  UNIQUE KEY `chart_scope` ((COALESCE(`customer_id`, 0)), (COALESCE(`catalog_id`, 0))),
+  CONSTRAINT `size_charts_ibfk_1` FOREIGN KEY (`catalog_id`) REFERENCES `uniform_catalog` (`id`) ON DELETE CASCADE,
+  CONSTRAINT `size_charts_ibfk_2` FOREIGN KEY (`customer_id`) REFERENCES `customers` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+-- Rentang ukuran badan dalam cm; NULL berarti tidak ditentukan
+CREATE TABLE IF NOT EXISTS `size_chart_entries` (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `chart_id` int NOT NULL,
+  `size` varchar(20) NOT NULL,
+  `sort_order` int NOT NULL DEFAULT '0',
+  `height_min` decimal(5,1) DEFAULT NULL,
+  `height_max` decimal(5,1) DEFAULT NULL,
+  `chest_min` decimal(5,1) DEFAULT NULL,
+  `chest_max` decimal(5,1) DEFAULT NULL,
+  `waist_min` decimal(5,1) DEFAULT NULL,
+  `waist_max` decimal(5,1) DEFAULT NULL,
+  `hip_min` decimal(5,1) DEFAULT NULL,
+  `hip_max` decimal(5,1) DEFAULT NULL,
+  `notes` varchar(255) DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `chart_size` (`chart_id`,`size`),
+  CONSTRAINT `size_chart_entries_ibfk_1` FOREIGN KEY (`chart_id`) REFERENCES `size_charts` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
@@ -0,0 +1,30 @@
+-- Master bahan baku (kain, kancing, resleting, label, benang) dan kebutuhan bahan per seragam katalog
+
+CREATE TABLE IF NOT EXISTS `materials` (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `name` varchar(100) NOT NULL,
+  `category` enum('fabric','button','zipper','label','thread','other') NOT NULL DEFAULT 'other',
+  `unit` varchar(20) NOT NULL,
+  `notes` text,
+  `active` tinyint(1) NOT NULL DEFAULT '1',
+  `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `name` (`name`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+-- Kebutuhan bahan per potong. size '' berlaku untuk semua ukuran, baris dengan ukuran tertentu
+-- menggantikan baris umum untuk bahan yang sama.
+CREATE TABLE IF NOT EXISTS `uniform_bom` (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `catalog_id` int NOT NULL,
+  `size` varchar(20) NOT NULL DEFAULT '',
+  `material_id` int NOT NULL,
+  `quantity` decimal(10,3) NOT NULL,
+  `notes` varchar(255) DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `catalog_size_material` (`catalog_id`,`size`,`material_id`),
+  KEY `material_id` (`material_id`),
+  CONSTRAINT `uniform_bom_ibfk_1` FOREIGN KEY (`catalog_id`) REFERENCES `uniform_catalog` (`id`) ON DELETE CASCADE,
+  CONSTRAINT `uniform_bom_ibfk_2` FOREIGN KEY (`material_id`) REFERENCES `materials` (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
@@ -0,0 +1,28 @@
+-- Stok bahan baku: saldo per bahan, batas pemesanan ulang dan buku mutasi
+
+ALTER TABLE `materials`
+  ADD COLUMN `reorder_level` decimal(12,3) NOT NULL DEFAULT '0.000' AFTER `unit`,
+  ADD COLUMN `stock` decimal(12,3) NOT NULL DEFAULT '0.000' AFTER `reorder_level`;
+
+-- quantity bertanda: positif untuk masuk, negatif untuk keluar; balance_after adalah saldo setelah mutasi
+CREATE TABLE IF NOT EXISTS `material_movements` (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `material_id` int NOT NULL,
+  `type` enum('in','out','adjustment') NOT NULL,
+  `quantity` decimal(12,3) NOT NULL,
+  `balance_after` decimal(12,3) NOT NULL,
+  `transaction_id` int DEFAULT NULL,
+  `reference` varchar(100) DEFAULT NULL,
+  `reason` varchar(255) DEFAULT NULL,
+  `performed_by` varchar(50) DEFAULT NULL,
+  `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `material_created` (`material_id`,`created_at`),
+  KEY `transaction_id` (`transaction_id`),
+  CONSTRAINT `material_movements_ibfk_1` FOREIGN KEY (`material_id`) REFERENCES `materials` (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+-- Tahap produksi transaksi, terpisah dari status pembayaran
+ALTER TABLE `transactions`
+  ADD COLUMN `production_stage` enum('antri','potong','jahit','finishing','selesai','diserahkan') NOT NULL DEFAULT 'antri' AFTER `status`,
+  ADD COLUMN `production_stage_at` timestamp NULL DEFAULT NULL AFTER `production_stage`;
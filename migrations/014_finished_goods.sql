@@ -0,0 +1,47 @@
+-- Stok barang jadi per seragam katalog dan ukuran
+-- Jumlah dipesan (reserved) dihitung dari finished_goods_reservations, tersedia = on_hand - reserved
+
+CREATE TABLE IF NOT EXISTS `finished_goods_stock` (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `catalog_id` int NOT NULL,
+  `size` varchar(20) NOT NULL,
+  `on_hand` int NOT NULL DEFAULT '0',
+  `updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `catalog_size` (`catalog_id`,`size`),
+  CONSTRAINT `finished_goods_stock_ibfk_1` FOREIGN KEY (`catalog_id`) REFERENCES `uniform_catalog` (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+-- Pesanan yang diambil dari stok; dilepas saat transaksi dibatalkan, dikeluarkan saat diserahkan
+CREATE TABLE IF NOT EXISTS `finished_goods_reservations` (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `transaction_id` int NOT NULL,
+  `catalog_id` int NOT NULL,
+  `size` varchar(20) NOT NULL,
+  `quantity` int NOT NULL,
+  `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `transaction_catalog_size` (`transaction_id`,`catalog_id`,`size`),
+  KEY `catalog_size` (`catalog_id`,`size`),
+  CONSTRAINT `finished_goods_reservations_ibfk_1` FOREIGN KEY (`transaction_id`) REFERENCES `transactions` (`id`) ON DELETE CASCADE,
+  CONSTRAINT `finished_goods_reservations_ibfk_2` FOREIGN KEY (`catalog_id`) REFERENCES `uniform_catalog` (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+-- quantity bertanda; receipt = hasil produksi masuk, issue = diserahkan ke customer, count = selisih stock opname
+CREATE TABLE IF NOT EXISTS `finished_goods_movements` (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `catalog_id` int NOT NULL,
+  `size` varchar(20) NOT NULL,
+  `type` enum('receipt','issue','count') NOT NULL,
+  `quantity` int NOT NULL,
+  `balance_after` int NOT NULL,
+  `transaction_id` int DEFAULT NULL,
+  `reference` varchar(100) DEFAULT NULL,
+  `reason` varchar(255) DEFAULT NULL,
+  `performed_by` varchar(50) DEFAULT NULL,
+  `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `catalog_size_created` (`catalog_id`,`size`,`created_at`),
+  KEY `transaction_id` (`transaction_id`),
+  CONSTRAINT `finished_goods_movements_ibfk_1` FOREIGN KEY (`catalog_id`) REFERENCES `uniform_catalog` (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
@@ -0,0 +1,90 @@
+-- Pemasok, pembelian bahan (PO), penerimaan barang dan pembayaran ke pemasok
+
+CREATE TABLE IF NOT EXISTS `suppliers` (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `name` varchar(100) NOT NULL,
+  `contact` varchar(100) DEFAULT NULL,
+  `email` varchar(100) DEFAULT NULL,
+  `address` text,
+  `notes` text,
+  `active` tinyint(1) NOT NULL DEFAULT '1',
+  `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `name` (`name`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+CREATE TABLE IF NOT EXISTS `purchase_orders` (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `supplier_id` int NOT NULL,
+  `order_date` date NOT NULL,
+  `expected_date` date DEFAULT NULL,
+  `status` enum('draft','ordered','partially_received','received','cancelled') NOT NULL DEFAULT 'draft',
+  `total` decimal(12,2) NOT NULL DEFAULT '0.00',
+  `notes` text,
+  `created_by` varchar(50) DEFAULT NULL,
+  `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `supplier_status` (`supplier_id`,`status`),
+  CONSTRAINT `purchase_orders_ibfk_1` FOREIGN KEY (`supplier_id`) REFERENCES `suppliers` (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+CREATE TABLE IF NOT EXISTS `purchase_order_lines` (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `purchase_order_id` int NOT NULL,
+  `material_id` int NOT NULL,
+  `quantity` decimal(12,3) NOT NULL,
+  `received_quantity` decimal(12,3) NOT NULL DEFAULT '0.000',
+  `unit_price` decimal(12,2) NOT NULL DEFAULT '0.00',
+  `notes` varchar(255) DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  KEY `purchase_order_id` (`purchase_order_id`),
+  CONSTRAINT `purchase_order_lines_ibfk_1` FOREIGN KEY (`purchase_order_id`) REFERENCES `purchase_orders` (`id`) ON DELETE CASCADE,
+  CONSTRAINT `purchase_order_lines_ibfk_2` FOREIGN KEY (`material_id`) REFERENCES `materials` (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+-- Setiap baris penerimaan menambah stok bahan lewat material_movements (type in)
+CREATE TABLE IF NOT EXISTS `goods_receipts` (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `purchase_order_id` int NOT NULL,
+  `received_date` date NOT NULL,
+  `reference` varchar(100) DEFAULT NULL,
+  `notes` text,
+  `received_by` varchar(50) DEFAULT NULL,
+  `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `purchase_order_id` (`purchase_order_id`),
+  CONSTRAINT `goods_receipts_ibfk_1` FOREIGN KEY (`purchase_order_id`) REFERENCES `purchase_orders` (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+CREATE TABLE IF NOT EXISTS `goods_receipt_lines` (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `goods_receipt_id` int NOT NULL,
+  `purchase_order_line_id` int NOT NULL,
+  `quantity` decimal(12,3) NOT NULL,
+  `material_movement_id` int DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  KEY `goods_receipt_id` (`goods_receipt_id`),
+  CONSTRAINT `goods_receipt_lines_ibfk_1` FOREIGN KEY (`goods_receipt_id`) REFERENCES `goods_receipts` (`id`) ON DELETE CASCADE,
+  CONSTRAINT `goods_receipt_lines_ibfk_2` FOREIGN KEY (`purchase_order_line_id`) REFERENCES `purchase_order_lines` (`id`),
+  CONSTRAINT `goods_receipt_lines_ibfk_3` FOREIGN KEY (`material_movement_id`) REFERENCES `material_movements` (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+-- Hutang pemasok = nilai barang yang sudah diterima - pembayaran
+CREATE TABLE IF NOT EXISTS `supplier_payments` (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `supplier_id` int NOT NULL,
+  `purchase_order_id` int DEFAULT NULL,
+  `payment_date` date NOT NULL,
+  `amount` decimal(12,2) NOT NULL,
+  `method` varchar(50) DEFAULT NULL,
+  `reference` varchar(100) DEFAULT NULL,
+  `notes` text,
+  `created_by` varchar(50) DEFAULT NULL,
+  `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `supplier_id` (`supplier_id`),
+  CONSTRAINT `supplier_payments_ibfk_1` FOREIGN KEY (`supplier_id`) REFERENCES `suppliers` (`id`),
+  CONSTRAINT `supplier_payments_ibfk_2` FOREIGN KEY (`purchase_order_id`) REFERENCES `purchase_orders` (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
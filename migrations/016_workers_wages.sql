@@ -0,0 +1,68 @@
+-- Penjahit/pekerja, tarif borongan per seragam dan operasi, pembagian kerja dan hasil kerja
+
+CREATE TABLE IF NOT EXISTS `workers` (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `name` varchar(100) NOT NULL,
+  `phone` varchar(30) DEFAULT NULL,
+  `address` text,
+  `notes` text,
+  `active` tinyint(1) NOT NULL DEFAULT '1',
+  `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+-- Upah borongan per potong untuk satu seragam katalog dan operasi
+CREATE TABLE IF NOT EXISTS `piece_rates` (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `catalog_id` int NOT NULL,
+  `operation` enum('potong','jahit','lubang_kancing','setrika') NOT NULL,
+  `rate` decimal(10,2) NOT NULL,
+  `updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `catalog_operation` (`catalog_id`,`operation`),
+  CONSTRAINT `piece_rates_ibfk_1` FOREIGN KEY (`catalog_id`) REFERENCES `uniform_catalog` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+-- Pembagian kerja ke pekerja untuk baris pesanan. Item pesanan bisa ditulis ulang saat transaksi diedit,
+-- jadi seragam, ukuran, dan siswa disalin dan batas jumlah dihitung dari salinan itu, bukan item_id;
+-- rate adalah tarif saat pekerjaan diberikan.
+CREATE TABLE IF NOT EXISTS `work_assignments` (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `worker_id` int NOT NULL,
+  `transaction_id` int NOT NULL,
+  `item_type` enum('order','student') NOT NULL DEFAULT 'order',
+  `item_id` int DEFAULT NULL,
+  `uniform_name` varchar(100) NOT NULL,
+  `size` varchar(20) NOT NULL,
+  `student_id` int DEFAULT NULL, -- salinan student_order_items.student_id, kunci siswa jika ada
+  `student_name` varchar(100) NOT NULL DEFAULT '',
+  `grade` varchar(10) NOT NULL DEFAULT '',
+  `operation` enum('potong','jahit','lubang_kancing','setrika') NOT NULL,
+  `quantity` int NOT NULL,
+  `rate` decimal(10,2) NOT NULL,
+  `assigned_date` date NOT NULL,
+  `notes` varchar(255) DEFAULT NULL,
+  `created_by` varchar(50) DEFAULT NULL,
+  `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `worker_id` (`worker_id`),
+  KEY `transaction_id` (`transaction_id`),
+  KEY `transaction_line` (`transaction_id`,`item_type`,`uniform_name`,`size`,`operation`),
+  CONSTRAINT `work_assignments_ibfk_1` FOREIGN KEY (`worker_id`) REFERENCES `workers` (`id`),
+  CONSTRAINT `work_assignments_ibfk_2` FOREIGN KEY (`transaction_id`) REFERENCES `transactions` (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+-- Hasil kerja yang disetor; upah mingguan dihitung dari completed_date
+CREATE TABLE IF NOT EXISTS `work_completions` (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `assignment_id` int NOT NULL,
+  `quantity` int NOT NULL,
+  `completed_date` date NOT NULL,
+  `recorded_by` varchar(50) DEFAULT NULL,
+  `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `assignment_id` (`assignment_id`),
+  KEY `completed_date` (`completed_date`),
+  CONSTRAINT `work_completions_ibfk_1` FOREIGN KEY (`assignment_id`) REFERENCES `work_assignments` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
@@ -0,0 +1,33 @@
+-- Pengiriman bertahap per transaksi. Item pesanan bisa ditulis ulang saat transaksi diedit,
+-- jadi nama siswa, kelas, seragam dan ukuran disalin untuk surat jalan; sisa kirim dicocokkan lewat
+-- line_key yang dibentuk sekali saat dicatat (jenis item + siswa + seragam + ukuran, ternormalisasi).
+
+CREATE TABLE IF NOT EXISTS `deliveries` (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `transaction_id` int NOT NULL,
+  `delivery_date` date NOT NULL,
+  `receiver_name` varchar(100) DEFAULT NULL,
+  `notes` varchar(255) DEFAULT NULL,
+  `delivered_by` varchar(50) DEFAULT NULL,
+  `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `transaction_id` (`transaction_id`),
+  CONSTRAINT `deliveries_ibfk_1` FOREIGN KEY (`transaction_id`) REFERENCES `transactions` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+CREATE TABLE IF NOT EXISTS `delivery_items` (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `delivery_id` int NOT NULL,
+  `item_type` enum('order','student') NOT NULL DEFAULT 'order',
+  `item_id` int DEFAULT NULL,
+  `line_key` varchar(255) NOT NULL,
+  `student_name` varchar(100) NOT NULL DEFAULT '',
+  `grade` varchar(20) NOT NULL DEFAULT '',
+  `uniform_name` varchar(100) NOT NULL,
+  `size` varchar(20) NOT NULL,
+  `quantity` int NOT NULL,
+  PRIMARY KEY (`id`),
+  KEY `delivery_id` (`delivery_id`),
+  KEY `line_key` (`line_key`),
+  CONSTRAINT `delivery_items_ibfk_1` FOREIGN KEY (`delivery_id`) REFERENCES `deliveries` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
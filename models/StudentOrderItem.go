@@ -4,14 +4,20 @@ package models
 type StudentOrderItem struct {
     ID            int       `json:"id"`
     CustomerID    int       `json:"customer_id"`
+    StudentID     int       `json:"student_id,omitempty"`
+    MeasurementID int       `json:"measurement_id,omitempty"`
     StudentName   string    `json:"student_name"`
     Grade         string    `json:"grade"`
     TransactionID int       `json:"transaction_id"`
     UniformName   string    `json:"uniform_name"`
     Size          string    `json:"size"`
     Quantity      int       `json:"quantity"`
+    // UnitPrice dari client hanya dipakai untuk seragam di luar daftar harga customer (lihat OrderItem)
     UnitPrice     float64   `json:"unit_price"`
     Subtotal      float64   `json:"subtotal"`
     Notes         string    `json:"notes"`
     CreatedAt     string `json:"created_at"`
+
+    // Ukuran badan untuk item jahit ukur, ditampilkan ke bagian potong
+    Measurement   *StudentMeasurement `json:"measurement,omitempty"`
 }
\ No newline at end of file
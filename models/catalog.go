@@ -0,0 +1,36 @@
+package models
+
+type CatalogItem struct {
+    ID          int           `json:"id"`
+    Name        string        `json:"name"`
+    Category    string        `json:"category"` // mis. Kemeja, Celana, Rok, Olahraga, Batik
+    Fabric      string        `json:"fabric"`
+    Description string        `json:"description"`
+    Active      bool          `json:"active"`
+    Sizes       []CatalogSize `json:"sizes"`
+    CreatedAt   string        `json:"created_at"`
+    UpdatedAt   string        `json:"updated_at"`
+}
+
+type CatalogSize struct {
+    ID        int     `json:"id"`
+    CatalogID int     `json:"catalog_id"`
+    Size      string  `json:"size"`
+    BasePrice float64 `json:"base_price"`
+    SortOrder int     `json:"sort_order"`
+}
+
+// CatalogLinkSuggestion adalah usulan menghubungkan baris customer_uniforms ke katalog
+type CatalogLinkSuggestion struct {
+    CustomerUniformID int     `json:"customer_uniform_id"`
+    CustomerID        int     `json:"customer_id"`
+    CustomerName      string  `json:"customer_name"`
+    UniformName       string  `json:"uniform_name"`
+    Size              string  `json:"size"`
+    Price             float64 `json:"price"`
+    CatalogID         int     `json:"catalog_id,omitempty"`
+    CatalogName       string  `json:"catalog_name,omitempty"`
+    Score             float64 `json:"score"`
+    SizeInCatalog     bool    `json:"size_in_catalog"`
+    BasePrice         float64 `json:"base_price,omitempty"`
+}
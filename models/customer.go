@@ -5,6 +5,7 @@ type Customer struct {
 	Name      string `json:"name"`
 	Type      string `json:"type"` // TK, SD, SMP, Kelompok Tadarus
 	Contact   string `json:"contact"`
+	Email     string `json:"email"`
 	Address   string `json:"address"`
 	CreatedAt string `json:"created_at"`
 	Uniforms []CustomerUniform `json:"uniforms"`
@@ -13,6 +14,7 @@ type Customer struct {
 type CustomerUniform struct {
     ID         int     `json:"id"`
     CustomerID int     `json:"customer_id"`
+    CatalogID  int     `json:"catalog_id,omitempty"` // 0 jika belum terhubung ke katalog
     UniformName string `json:"uniform_name"`
     Size       string  `json:"size"`
     Price      float64 `json:"price"`
@@ -0,0 +1,60 @@
+package models
+
+// Delivery adalah satu kali pengiriman (surat jalan) untuk sebagian atau seluruh pesanan
+type Delivery struct {
+    ID            int            `json:"id"`
+    TransactionID int            `json:"transaction_id"`
+    Sequence      int            `json:"sequence"` // pengiriman ke-n dalam transaksi
+    DeliveryDate  string         `json:"delivery_date"`
+    ReceiverName  string         `json:"receiver_name"`
+    Notes         string         `json:"notes"`
+    DeliveredBy   string         `json:"delivered_by"`
+    TotalQuantity int            `json:"total_quantity"`
+    CreatedAt     string         `json:"created_at"`
+    Items         []DeliveryItem `json:"items"`
+}
+
+// DeliveryItem adalah jumlah yang dikirim untuk satu baris pesanan
+type DeliveryItem struct {
+    ID          int    `json:"id"`
+    DeliveryID  int    `json:"delivery_id"`
+    ItemType    string `json:"item_type"` // order, student
+    ItemID      int    `json:"item_id"`
+    LineKey     string `json:"line_key"`
+    StudentName string `json:"student_name,omitempty"`
+    Grade       string `json:"grade,omitempty"`
+    UniformName string `json:"uniform_name"`
+    Size        string `json:"size"`
+    Quantity    int    `json:"quantity"`
+}
+
+// DeliveryRemaining adalah jumlah pesanan, terkirim dan sisa kirim satu baris pesanan.
+// OverDelivered adalah kiriman yang melebihi pesanan, mis. karena jumlah pesanan dikurangi setelah dikirim;
+// baris dengan ItemID 0 adalah kiriman untuk baris yang sudah tidak ada di pesanan.
+type DeliveryRemaining struct {
+    ItemType      string `json:"item_type"`
+    ItemID        int    `json:"item_id"`
+    LineKey       string `json:"line_key"`
+    StudentName   string `json:"student_name,omitempty"`
+    Grade         string `json:"grade,omitempty"`
+    UniformName   string `json:"uniform_name"`
+    Size          string `json:"size"`
+    Ordered       int    `json:"ordered"`
+    Delivered     int    `json:"delivered"`
+    Remaining     int    `json:"remaining"`
+    OverDelivered int    `json:"over_delivered"`
+}
+
+// DeliveryStatus adalah rekap pengiriman transaksi beserta riwayatnya
+type DeliveryStatus struct {
+    TransactionID   int                 `json:"transaction_id"`
+    ProductionStage string              `json:"production_stage"`
+    Ordered         int                 `json:"ordered"`
+    Delivered       int                 `json:"delivered"`
+    Remaining       int                 `json:"remaining"`
+    OverDelivered   int                 `json:"over_delivered"`
+    HasOverDelivery bool                `json:"has_over_delivery"` // ada kiriman melebihi pesanan yang perlu dicek
+    Complete        bool                `json:"complete"`
+    Items           []DeliveryRemaining `json:"items"`
+    Deliveries      []Delivery          `json:"deliveries"`
+}
@@ -0,0 +1,18 @@
+package models
+
+type EmailLog struct {
+    ID             int    `json:"id"`
+    Template       string `json:"template"`
+    Recipient      string `json:"recipient"`
+    Subject        string `json:"subject"`
+    Body           string `json:"body"`
+    TransactionID  int    `json:"transaction_id"`
+    CustomerID     int    `json:"customer_id"`
+    EventKey       string `json:"event_key"`
+    AttachKuitansi bool   `json:"attach_kuitansi"`
+    Status         string `json:"status"` // pending, sent, failed
+    Attempts       int    `json:"attempts"`
+    LastError      string `json:"last_error"`
+    SentAt         string `json:"sent_at"`
+    CreatedAt      string `json:"created_at"`
+}
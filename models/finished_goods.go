@@ -0,0 +1,39 @@
+package models
+
+// FinishedGoodsStock adalah stok barang jadi satu seragam katalog per ukuran
+type FinishedGoodsStock struct {
+    CatalogID   int    `json:"catalog_id"`
+    CatalogName string `json:"catalog_name"`
+    Size        string `json:"size"`
+    OnHand      int    `json:"on_hand"`
+    Reserved    int    `json:"reserved"`
+    Available   int    `json:"available"` // on_hand - reserved
+    UpdatedAt   string `json:"updated_at"`
+}
+
+// StockReservation adalah barang jadi yang disisihkan untuk satu transaksi
+type StockReservation struct {
+    ID            int    `json:"id"`
+    TransactionID int    `json:"transaction_id"`
+    CatalogID     int    `json:"catalog_id"`
+    CatalogName   string `json:"catalog_name"`
+    Size          string `json:"size"`
+    Quantity      int    `json:"quantity"`
+    CreatedAt     string `json:"created_at"`
+}
+
+// FinishedGoodsMovement adalah satu baris buku mutasi barang jadi; Quantity bertanda
+type FinishedGoodsMovement struct {
+    ID            int    `json:"id"`
+    CatalogID     int    `json:"catalog_id"`
+    CatalogName   string `json:"catalog_name"`
+    Size          string `json:"size"`
+    Type          string `json:"type"` // receipt, issue, count
+    Quantity      int    `json:"quantity"`
+    BalanceAfter  int    `json:"balance_after"`
+    TransactionID int    `json:"transaction_id,omitempty"`
+    Reference     string `json:"reference"`
+    Reason        string `json:"reason"`
+    PerformedBy   string `json:"performed_by"`
+    CreatedAt     string `json:"created_at"`
+}
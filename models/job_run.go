@@ -0,0 +1,12 @@
+package models
+
+type JobRun struct {
+    ID          int    `json:"id"`
+    JobName     string `json:"job_name"`
+    TriggerType string `json:"trigger_type"` // schedule, manual
+    Status      string `json:"status"`       // running, success, failed
+    Message     string `json:"message"`
+    ScheduledAt string `json:"scheduled_at"` // kosong untuk run manual
+    StartedAt   string `json:"started_at"`
+    FinishedAt  string `json:"finished_at"`
+}
@@ -0,0 +1,62 @@
+package models
+
+type Material struct {
+    ID           int     `json:"id"`
+    Name         string  `json:"name"`
+    Category     string  `json:"category"` // fabric, button, zipper, label, thread, other
+    Unit         string  `json:"unit"`     // mis. meter, pcs, cone
+    ReorderLevel float64 `json:"reorder_level"`
+    Stock        float64 `json:"stock"` // hanya berubah lewat mutasi stok
+    LowStock     bool    `json:"low_stock"`
+    Notes        string  `json:"notes"`
+    Active       bool    `json:"active"`
+    CreatedAt    string  `json:"created_at"`
+    UpdatedAt    string  `json:"updated_at"`
+}
+
+// MaterialMovement adalah satu baris buku mutasi stok bahan.
+// Quantity bertanda: positif masuk, negatif keluar.
+type MaterialMovement struct {
+    ID            int     `json:"id"`
+    MaterialID    int     `json:"material_id"`
+    MaterialName  string  `json:"material_name"`
+    Unit          string  `json:"unit"`
+    Type          string  `json:"type"` // in, out, adjustment
+    Quantity      float64 `json:"quantity"`
+    BalanceAfter  float64 `json:"balance_after"`
+    TransactionID int     `json:"transaction_id,omitempty"`
+    Reference     string  `json:"reference"`
+    Reason        string  `json:"reason"`
+    PerformedBy   string  `json:"performed_by"`
+    CreatedAt     string  `json:"created_at"`
+}
+
+// BOMLine adalah kebutuhan satu bahan per potong seragam; Size kosong berlaku untuk semua ukuran
+type BOMLine struct {
+    ID           int     `json:"id"`
+    CatalogID    int     `json:"catalog_id"`
+    Size         string  `json:"size"`
+    MaterialID   int     `json:"material_id"`
+    MaterialName string  `json:"material_name"`
+    Category     string  `json:"category"`
+    Unit         string  `json:"unit"`
+    Quantity     float64 `json:"quantity"`
+    Notes        string  `json:"notes"`
+}
+
+// MaterialRequirement adalah total kebutuhan satu bahan untuk sekumpulan transaksi
+type MaterialRequirement struct {
+    MaterialID int     `json:"material_id"`
+    Name       string  `json:"name"`
+    Category   string  `json:"category"`
+    Unit       string  `json:"unit"`
+    Quantity   float64 `json:"quantity"`
+}
+
+// UncoveredItem adalah item pesanan yang kebutuhan bahannya tidak bisa dihitung
+type UncoveredItem struct {
+    UniformName string `json:"uniform_name"`
+    Size        string `json:"size"`
+    Quantity    int    `json:"quantity"`
+    Reason      string `json:"reason"`
+}
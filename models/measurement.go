@@ -0,0 +1,41 @@
+package models
+
+// Measurements adalah ukuran badan; nilai nil berarti tidak diukur
+type Measurements struct {
+    Chest         *float64 `json:"chest"`
+    Waist         *float64 `json:"waist"`
+    Hip           *float64 `json:"hip"`
+    Shoulder      *float64 `json:"shoulder"`
+    Sleeve        *float64 `json:"sleeve"`
+    ShirtLength   *float64 `json:"shirt_length"`
+    TrouserLength *float64 `json:"trouser_length"`
+}
+
+type StudentMeasurement struct {
+    ID         int    `json:"id"`
+    StudentID  int    `json:"student_id"`
+    Measurements
+    Unit       string `json:"unit"` // cm atau inch
+    MeasuredAt string `json:"measured_at"`
+    MeasuredBy string `json:"measured_by"`
+    Notes      string `json:"notes"`
+    CreatedAt  string `json:"created_at"`
+}
+
+// SizeRule adalah ukuran standar (cm) untuk satu ukuran seragam katalog
+type SizeRule struct {
+    ID        int    `json:"id"`
+    CatalogID int    `json:"catalog_id"`
+    Size      string `json:"size"`
+    Measurements
+}
+
+// SizeSuggestion adalah ukuran standar terdekat untuk satu catatan ukuran badan
+type SizeSuggestion struct {
+    CatalogID      int     `json:"catalog_id"`
+    CatalogName    string  `json:"catalog_name"`
+    Size           string  `json:"size"`
+    Distance       float64 `json:"distance"` // akar rata-rata kuadrat selisih (cm) pada ukuran yang dibandingkan
+    FieldsCompared int     `json:"fields_compared"`
+    RunnerUp       string  `json:"runner_up,omitempty"`
+}
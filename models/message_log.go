@@ -0,0 +1,15 @@
+package models
+
+type MessageLog struct {
+    ID            int    `json:"id"`
+    CustomerID    int    `json:"customer_id"`
+    TransactionID int    `json:"transaction_id"`
+    Template      string `json:"template"`
+    Provider      string `json:"provider"`
+    Phone         string `json:"phone"`
+    Message       string `json:"message"`
+    EventKey      string `json:"event_key"`
+    Status        string `json:"status"` // sent, failed
+    Error         string `json:"error"`
+    CreatedAt     string `json:"created_at"`
+}
@@ -0,0 +1,19 @@
+package models
+
+type Notification struct {
+    ID            int    `json:"id"`
+    Type          string `json:"type"` // overdue_payment, overdue_transaction, reminder_payment, reminder_transaction
+    TransactionID int    `json:"transaction_id"`
+    CustomerID    int    `json:"customer_id"`
+    CustomerName  string `json:"customer_name"`
+    Title         string `json:"title"`
+    Message       string `json:"message"`
+    EventDate     string `json:"event_date"`
+    Icon          string `json:"icon"`
+    Color         string `json:"color"`
+    ActionURL     string `json:"action_url"`
+    IsRead        bool   `json:"is_read"`
+    ReadAt        string `json:"read_at"`
+    SnoozedUntil  string `json:"snoozed_until"`
+    CreatedAt     string `json:"created_at"`
+}
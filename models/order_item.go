@@ -8,6 +8,8 @@ type OrderItem struct {
 	UniformName   string  `json:"uniform_name"`
 	Size        string    `json:"size"`
 	Quantity    int       `json:"quantity"`
+	// UnitPrice dari client hanya dipakai untuk seragam di luar daftar harga customer;
+	// seragam yang ada di daftar harga selalu memakai harga pada tanggal transaksi
 	UnitPrice   float64   `json:"unit_price"`
 	Subtotal    float64   `json:"subtotal"`
 	Notes       string    `json:"notes"`
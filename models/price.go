@@ -0,0 +1,14 @@
+package models
+
+// ScheduledPrice adalah harga customer_uniforms dengan tanggal mulai berlaku
+type ScheduledPrice struct {
+    ID                int      `json:"id"`
+    CustomerUniformID int      `json:"customer_uniform_id"`
+    Price             float64  `json:"price"`
+    PreviousPrice     *float64 `json:"previous_price"`
+    EffectiveFrom     string   `json:"effective_from"`
+    ChangedBy         string   `json:"changed_by"`
+    Reason            string   `json:"reason"`
+    AppliedAt         string   `json:"applied_at"` // kosong jika belum berlaku
+    CreatedAt         string   `json:"created_at"`
+}
@@ -0,0 +1,15 @@
+package models
+
+// SizeRecapItem adalah total jumlah satu ukuran untuk satu jenis seragam
+type SizeRecapItem struct {
+    Size             string `json:"size"`
+    Quantity         int    `json:"quantity"`
+    TransactionCount int    `json:"transaction_count"`
+}
+
+// SizeRecap adalah rekap ukuran per seragam untuk kebutuhan potong
+type SizeRecap struct {
+    UniformName string          `json:"uniform_name"`
+    Sizes       []SizeRecapItem `json:"sizes"`
+    Total       int             `json:"total"`
+}
@@ -0,0 +1,86 @@
+package models
+
+type Supplier struct {
+    ID        int    `json:"id"`
+    Name      string `json:"name"`
+    Contact   string `json:"contact"`
+    Email     string `json:"email"`
+    Address   string `json:"address"`
+    Notes     string `json:"notes"`
+    Active    bool   `json:"active"`
+    CreatedAt string `json:"created_at"`
+    UpdatedAt string `json:"updated_at"`
+}
+
+// PurchaseOrder adalah pesanan pembelian bahan ke pemasok
+type PurchaseOrder struct {
+    ID            int                 `json:"id"`
+    SupplierID    int                 `json:"supplier_id"`
+    SupplierName  string              `json:"supplier_name"`
+    OrderDate     string              `json:"order_date"`
+    ExpectedDate  string              `json:"expected_date"`
+    Status        string              `json:"status"` // draft, ordered, partially_received, received, cancelled
+    Total         float64             `json:"total"`
+    ReceivedValue float64             `json:"received_value"`
+    Notes         string              `json:"notes"`
+    CreatedBy     string              `json:"created_by"`
+    CreatedAt     string              `json:"created_at"`
+    UpdatedAt     string              `json:"updated_at"`
+    Lines         []PurchaseOrderLine `json:"lines,omitempty"`
+    Receipts      []GoodsReceipt      `json:"receipts,omitempty"`
+}
+
+type PurchaseOrderLine struct {
+    ID               int     `json:"id"`
+    MaterialID       int     `json:"material_id"`
+    MaterialName     string  `json:"material_name"`
+    Unit             string  `json:"unit"`
+    Quantity         float64 `json:"quantity"`
+    ReceivedQuantity float64 `json:"received_quantity"`
+    UnitPrice        float64 `json:"unit_price"`
+    Subtotal         float64 `json:"subtotal"`
+    Notes            string  `json:"notes"`
+}
+
+// GoodsReceipt adalah satu kali penerimaan barang untuk PO
+type GoodsReceipt struct {
+    ID              int                `json:"id"`
+    PurchaseOrderID int                `json:"purchase_order_id"`
+    ReceivedDate    string             `json:"received_date"`
+    Reference       string             `json:"reference"` // nomor surat jalan pemasok
+    Notes           string             `json:"notes"`
+    ReceivedBy      string             `json:"received_by"`
+    CreatedAt       string             `json:"created_at"`
+    Lines           []GoodsReceiptLine `json:"lines"`
+}
+
+type GoodsReceiptLine struct {
+    PurchaseOrderLineID int     `json:"purchase_order_line_id"`
+    MaterialID          int     `json:"material_id"`
+    MaterialName        string  `json:"material_name"`
+    Quantity            float64 `json:"quantity"`
+    MovementID          int     `json:"material_movement_id,omitempty"`
+}
+
+type SupplierPayment struct {
+    ID              int     `json:"id"`
+    SupplierID      int     `json:"supplier_id"`
+    PurchaseOrderID int     `json:"purchase_order_id,omitempty"`
+    PaymentDate     string  `json:"payment_date"`
+    Amount          float64 `json:"amount"`
+    Method          string  `json:"method"`
+    Reference       string  `json:"reference"`
+    Notes           string  `json:"notes"`
+    CreatedBy       string  `json:"created_by"`
+    CreatedAt       string  `json:"created_at"`
+}
+
+// SupplierPayable adalah hutang yang belum dibayar ke satu pemasok
+type SupplierPayable struct {
+    SupplierID    int     `json:"supplier_id"`
+    SupplierName  string  `json:"supplier_name"`
+    ReceivedValue float64 `json:"received_value"` // nilai barang yang sudah diterima
+    Paid          float64 `json:"paid"`
+    Outstanding   float64 `json:"outstanding"`
+    OpenOrders    int     `json:"open_orders"` // PO ordered/partially_received
+}
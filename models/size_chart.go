@@ -0,0 +1,31 @@
+package models
+
+// SizeRange adalah rentang ukuran badan dalam cm
+type SizeRange struct {
+    Min float64 `json:"min"`
+    Max float64 `json:"max"`
+}
+
+type SizeChart struct {
+    ID           int              `json:"id"`
+    CatalogID    int              `json:"catalog_id,omitempty"`
+    CatalogName  string           `json:"catalog_name,omitempty"`
+    CustomerID   int              `json:"customer_id,omitempty"`
+    CustomerName string           `json:"customer_name,omitempty"`
+    Name         string           `json:"name"`
+    Notes        string           `json:"notes"`
+    Entries      []SizeChartEntry `json:"entries"` // urut dari ukuran terkecil
+    CreatedAt    string           `json:"created_at"`
+    UpdatedAt    string           `json:"updated_at"`
+}
+
+type SizeChartEntry struct {
+    ID        int        `json:"id"`
+    Size      string     `json:"size"`
+    SortOrder int        `json:"sort_order"`
+    Height    *SizeRange `json:"height,omitempty"`
+    Chest     *SizeRange `json:"chest,omitempty"`
+    Waist     *SizeRange `json:"waist,omitempty"`
+    Hip       *SizeRange `json:"hip,omitempty"`
+    Notes     string     `json:"notes"`
+}
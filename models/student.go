@@ -0,0 +1,32 @@
+package models
+
+type Student struct {
+    ID            int    `json:"id"`
+    CustomerID    int    `json:"customer_id"`
+    CustomerName  string `json:"customer_name,omitempty"`
+    Name          string `json:"name"`
+    Class         string `json:"class"`
+    NIS           string `json:"nis"`
+    ParentName    string `json:"parent_name"`
+    ParentContact string `json:"parent_contact"`
+    Active        bool   `json:"active"`
+    Notes         string `json:"notes"`
+    CreatedAt     string `json:"created_at"`
+    UpdatedAt     string `json:"updated_at"`
+}
+
+// StudentOrderHistory adalah satu item pesanan milik siswa
+type StudentOrderHistory struct {
+    ItemID          int     `json:"item_id"`
+    TransactionID   int     `json:"transaction_id"`
+    TransactionDate string  `json:"transaction_date"`
+    AcademicYear    string  `json:"academic_year"`
+    Status          string  `json:"status"`
+    Grade           string  `json:"grade"`
+    UniformName     string  `json:"uniform_name"`
+    Size            string  `json:"size"`
+    Quantity        int     `json:"quantity"`
+    UnitPrice       float64 `json:"unit_price"`
+    Subtotal        float64 `json:"subtotal"`
+    Notes           string  `json:"notes"`
+}
@@ -5,10 +5,13 @@ type Transaksi struct {
 	ID            int       `json:"id" gorm:"primaryKey;autoIncrement"`
 	CustomerID    int       `json:"customer_id"`
 	Transaksidate string `json:"transaction_date"`
+	AcademicYear  string `json:"academic_year"` // tahun ajaran, mis. 2025/2026
 	Paymentdate   string `json:"payment_date"`
 	Status        string    `json:"status"` // paid, unpaid
+	ProductionStage string  `json:"production_stage"` // antri, potong, jahit, finishing, selesai, diserahkan
 	Total         float64   `json:"total_price"`
 	Notes         string    `json:"notes"`
+	ReserveStock  bool      `json:"reserve_stock"` // ambil dari stok barang jadi sebanyak yang tersedia
 	CreatedAt     string `json:"created_at"`
 	UpdatedAt     string `json:"updated_at"`
 	Customer_name string      `json:"customer_name"`
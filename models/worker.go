@@ -0,0 +1,74 @@
+package models
+
+type Worker struct {
+    ID        int    `json:"id"`
+    Name      string `json:"name"`
+    Phone     string `json:"phone"`
+    Address   string `json:"address"`
+    Notes     string `json:"notes"`
+    Active    bool   `json:"active"`
+    CreatedAt string `json:"created_at"`
+    UpdatedAt string `json:"updated_at"`
+}
+
+// PieceRate adalah upah borongan per potong untuk satu seragam katalog dan operasi
+type PieceRate struct {
+    CatalogID int     `json:"catalog_id"`
+    Operation string  `json:"operation"` // potong, jahit, lubang_kancing, setrika
+    Rate      float64 `json:"rate"`
+}
+
+// WorkAssignment adalah pekerjaan yang diberikan ke pekerja untuk satu baris pesanan
+type WorkAssignment struct {
+    ID                int     `json:"id"`
+    WorkerID          int     `json:"worker_id"`
+    WorkerName        string  `json:"worker_name"`
+    TransactionID     int     `json:"transaction_id"`
+    ItemType          string  `json:"item_type"` // order, student
+    ItemID            int     `json:"item_id"`
+    UniformName       string  `json:"uniform_name"`
+    Size              string  `json:"size"`
+    StudentID         int     `json:"student_id,omitempty"`   // hanya untuk item siswa
+    StudentName       string  `json:"student_name,omitempty"`
+    Grade             string  `json:"grade,omitempty"`
+    Operation         string  `json:"operation"`
+    Quantity          int     `json:"quantity"`
+    CompletedQuantity int     `json:"completed_quantity"`
+    Rate              float64 `json:"rate"`
+    AssignedDate      string  `json:"assigned_date"`
+    Notes             string  `json:"notes"`
+    CreatedBy         string  `json:"created_by"`
+    CreatedAt         string  `json:"created_at"`
+}
+
+type WorkCompletion struct {
+    ID            int    `json:"id"`
+    AssignmentID  int    `json:"assignment_id"`
+    Quantity      int    `json:"quantity"`
+    CompletedDate string `json:"completed_date"`
+    RecordedBy    string `json:"recorded_by"`
+    CreatedAt     string `json:"created_at"`
+}
+
+// WageLine adalah hasil kerja satu pekerjaan dalam periode upah
+type WageLine struct {
+    AssignmentID  int     `json:"assignment_id"`
+    TransactionID int     `json:"transaction_id"`
+    UniformName   string  `json:"uniform_name"`
+    Size          string  `json:"size"`
+    Operation     string  `json:"operation"`
+    Quantity      int     `json:"quantity"`
+    Rate          float64 `json:"rate"`
+    Amount        float64 `json:"amount"`
+}
+
+// WorkerWage adalah rekap upah borongan satu pekerja dalam satu minggu
+type WorkerWage struct {
+    WorkerID   int        `json:"worker_id"`
+    WorkerName string     `json:"worker_name"`
+    PeriodFrom string     `json:"period_from"`
+    PeriodTo   string     `json:"period_to"`
+    Pieces     int        `json:"pieces"`
+    Total      float64    `json:"total"`
+    Lines      []WageLine `json:"lines"`
+}
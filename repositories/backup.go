@@ -0,0 +1,120 @@
+package repositories
+
+import (
+    "database/sql"
+    "fmt"
+    "io"
+    "strings"
+    "time"
+)
+
+// DumpDatabase menulis backup SQL (struktur + data) semua tabel ke w.
+// Kolom generated (mis. subtotal) tidak ikut di-insert karena dihitung ulang oleh MySQL.
+func DumpDatabase(db *sql.DB, w io.Writer) error {
+    rows, err := db.Query("SHOW FULL TABLES WHERE Table_type = 'BASE TABLE'")
+    if err != nil {
+        return err
+    }
+    var tables []string
+    for rows.Next() {
+        var name, tableType string
+        if err := rows.Scan(&name, &tableType); err != nil {
+            rows.Close()
+            return err
+        }
+        tables = append(tables, name)
+    }
+    rows.Close()
+
+    fmt.Fprintf(w, "-- Backup konveksi-app\n-- Generated: %s\n\n", time.Now().Format("2006-01-02 15:04:05"))
+    fmt.Fprintln(w, "SET FOREIGN_KEY_CHECKS = 0;")
+    fmt.Fprintln(w, "SET NAMES utf8mb4;")
+
+    for _, table := range tables {
+        if err := dumpTable(db, w, table); err != nil {
+            return fmt.Errorf("backup tabel %s: %v", table, err)
+        }
+    }
+
+    fmt.Fprintln(w, "\nSET FOREIGN_KEY_CHECKS = 1;")
+    return nil
+}
+
+func dumpTable(db *sql.DB, w io.Writer, table string) error {
+    var name, createStmt string
+    if err := db.QueryRow("SHOW CREATE TABLE `"+table+"`").Scan(&name, &createStmt); err != nil {
+        return err
+    }
+    fmt.Fprintf(w, "\n--\n-- Table structure for table `%s`\n--\n\n", table)
+    fmt.Fprintf(w, "DROP TABLE IF EXISTS `%s`;\n%s;\n", table, createStmt)
+
+    colRows, err := db.Query(`
+        SELECT COLUMN_NAME FROM information_schema.COLUMNS
+        WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ?
+        AND EXTRA NOT LIKE '%GENERATED%'
+        ORDER BY ORDINAL_POSITION`, table)
+    if err != nil {
+        return err
+    }
+    var columns []string
+    for colRows.Next() {
+        var col string
+        if err := colRows.Scan(&col); err != nil {
+            colRows.Close()
+            return err
+        }
+        columns = append(columns, "`"+col+"`")
+    }
+    colRows.Close()
+    if len(columns) == 0 {
+        return nil
+    }
+
+    columnList := strings.Join(columns, ", ")
+    rows, err := db.Query("SELECT " + columnList + " FROM `" + table + "`")
+    if err != nil {
+        return err
+    }
+    defer rows.Close()
+
+    values := make([]sql.RawBytes, len(columns))
+    dest := make([]interface{}, len(columns))
+    for i := range values {
+        dest[i] = &values[i]
+    }
+
+    first := true
+    for rows.Next() {
+        if err := rows.Scan(dest...); err != nil {
+            return err
+        }
+        if first {
+            fmt.Fprintf(w, "\n--\n-- Dumping data for table `%s`\n--\n\n", table)
+            first = false
+        }
+
+        literals := make([]string, len(values))
+        for i, v := range values {
+            if v == nil {
+                literals[i] = "NULL"
+            } else {
+                literals[i] = quoteSQLString(string(v))
+            }
+        }
+        fmt.Fprintf(w, "INSERT INTO `%s` (%s) VALUES (%s);\n", table, columnList, strings.Join(literals, ", "))
+    }
+    return rows.Err()
+}
+
+var sqlStringEscaper = strings.NewReplacer(
+    "\\", "\\\\",
+    "'", "\\'",
+    "\x00", "\\0",
+    "\n", "\\n",
+    "\r", "\\r",
+    "\x1a", "\\Z",
+)
+
+func quoteSQLString(s string) string {
+    return "'" + sqlStringEscaper.Replace(s) + "'"
+}
@@ -0,0 +1,70 @@
+package repositories
+
+import (
+    "database/sql"
+)
+
+type CalendarRepository struct {
+    DB *sql.DB
+}
+
+type CalendarEvent struct {
+    TransactionID int     `json:"transaction_id"`
+    CustomerID    int     `json:"customer_id"`
+    CustomerName  string  `json:"customer_name"`
+    Kind          string  `json:"kind"` // target (tanggal pengerjaan) atau payment (jatuh tempo)
+    Date          string  `json:"date"`
+    Status        string  `json:"status"`
+    Total         float64 `json:"total_price"`
+    ItemCount     int     `json:"item_count"`
+    StudentOrder  bool    `json:"student_order"`
+    UpdatedAt     string  `json:"updated_at"`
+}
+
+// GetEvents mengambil target pengerjaan dan jatuh tempo pembayaran dalam rentang tanggal (inklusif).
+// Transaksi yang dibatalkan tidak ditampilkan, jatuh tempo hanya untuk yang belum lunas.
+func (r *CalendarRepository) GetEvents(from, to string) ([]CalendarEvent, error) {
+    rows, err := r.DB.Query(`
+        SELECT e.id, e.customer_id, e.customer_name, e.kind, e.event_date, e.status, e.total_price, e.updated_at,
+               (SELECT COUNT(*) FROM student_order_items soi WHERE soi.transaction_id = e.id) AS student_count,
+               (SELECT COUNT(*) FROM order_items oi WHERE oi.transaction_id = e.id) AS order_count
+        FROM (
+            SELECT t.id, t.customer_id, c.name AS customer_name, 'target' AS kind,
+                   t.transaction_date AS event_date, t.status, COALESCE(t.total_price, 0) AS total_price,
+                   COALESCE(t.updated_at, t.created_at) AS updated_at
+            FROM transactions t
+            JOIN customers c ON t.customer_id = c.id
+            WHERE t.status != 'cancelled' AND t.transaction_date BETWEEN ? AND ?
+            UNION ALL
+            SELECT t.id, t.customer_id, c.name, 'payment',
+                   t.payment_date, t.status, COALESCE(t.total_price, 0),
+                   COALESCE(t.updated_at, t.created_at)
+            FROM transactions t
+            JOIN customers c ON t.customer_id = c.id
+            WHERE t.status = 'pending' AND t.payment_date IS NOT NULL AND t.payment_date BETWEEN ? AND ?
+        ) e
+        ORDER BY e.event_date ASC, e.id ASC`,
+        from, to, from, to,
+    )
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    events := []CalendarEvent{}
+    for rows.Next() {
+        var e CalendarEvent
+        var studentCount, orderCount int
+        err := rows.Scan(
+            &e.TransactionID, &e.CustomerID, &e.CustomerName, &e.Kind, &e.Date,
+            &e.Status, &e.Total, &e.UpdatedAt, &studentCount, &orderCount,
+        )
+        if err != nil {
+            return nil, err
+        }
+        e.ItemCount = studentCount + orderCount
+        e.StudentOrder = studentCount > 0
+        events = append(events, e)
+    }
+    return events, nil
+}
@@ -0,0 +1,401 @@
+package repositories
+
+import (
+    "database/sql"
+    "fmt"
+    "konveksi-app/models"
+    "konveksi-app/sizing"
+    "sort"
+    "strings"
+    "unicode"
+)
+
+type CatalogRepository struct {
+    DB *sql.DB
+}
+
+const catalogColumns = `id, name, COALESCE(category, ''), COALESCE(fabric, ''), COALESCE(description, ''),
+    active, created_at, COALESCE(updated_at, created_at)`
+
+func scanCatalogItem(scanner interface{ Scan(...interface{}) error }) (models.CatalogItem, error) {
+    var c models.CatalogItem
+    err := scanner.Scan(&c.ID, &c.Name, &c.Category, &c.Fabric, &c.Description, &c.Active, &c.CreatedAt, &c.UpdatedAt)
+    c.Sizes = []models.CatalogSize{}
+    return c, err
+}
+
+// GetAll mengambil katalog beserta ukurannya. includeInactive=false hanya produk aktif.
+func (r *CatalogRepository) GetAll(includeInactive bool) ([]models.CatalogItem, error) {
+    query := "SELECT " + catalogColumns + " FROM uniform_catalog"
+    if !includeInactive {
+        query += " WHERE active = 1"
+    }
+    rows, err := r.DB.Query(query + " ORDER BY category, name")
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    items := []models.CatalogItem{}
+    index := make(map[int]int)
+    for rows.Next() {
+        c, err := scanCatalogItem(rows)
+        if err != nil {
+            return nil, err
+        }
+        index[c.ID] = len(items)
+        items = append(items, c)
+    }
+    if err := rows.Err(); err != nil {
+        return nil, err
+    }
+
+    sizes, err := r.getSizes("")
+    if err != nil {
+        return nil, err
+    }
+    for _, s := range sizes {
+        if i, ok := index[s.CatalogID]; ok {
+            items[i].Sizes = append(items[i].Sizes, s)
+        }
+    }
+    return items, nil
+}
+
+func (r *CatalogRepository) GetByID(id int) (*models.CatalogItem, error) {
+    c, err := scanCatalogItem(r.DB.QueryRow("SELECT "+catalogColumns+" FROM uniform_catalog WHERE id = ?", id))
+    if err != nil {
+        return nil, err
+    }
+    sizes, err := r.getSizes("WHERE catalog_id = ?", id)
+    if err != nil {
+        return nil, err
+    }
+    c.Sizes = sizes
+    return &c, nil
+}
+
+func (r *CatalogRepository) getSizes(where string, args ...interface{}) ([]models.CatalogSize, error) {
+    rows, err := r.DB.Query(
+        "SELECT id, catalog_id, size, base_price, sort_order FROM uniform_catalog_sizes "+where+" ORDER BY catalog_id, sort_order, id",
+        args...,
+    )
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    sizes := []models.CatalogSize{}
+    for rows.Next() {
+        var s models.CatalogSize
+        if err := rows.Scan(&s.ID, &s.CatalogID, &s.Size, &s.BasePrice, &s.SortOrder); err != nil {
+            return nil, err
+        }
+        sizes = append(sizes, s)
+    }
+    return sizes, rows.Err()
+}
+
+// GetSize mengambil harga dasar satu ukuran katalog
+func (r *CatalogRepository) GetSize(catalogID int, size string) (*models.CatalogSize, error) {
+    var s models.CatalogSize
+    err := r.DB.QueryRow(
+        "SELECT id, catalog_id, size, base_price, sort_order FROM uniform_catalog_sizes WHERE catalog_id = ? AND size = ?",
+        catalogID, size,
+    ).Scan(&s.ID, &s.CatalogID, &s.Size, &s.BasePrice, &s.SortOrder)
+    if err != nil {
+        return nil, err
+    }
+    return &s, nil
+}
+
+// validateCatalogSizes mengecek ukuran kosong/dobel dan mengurutkannya (S, M, L, ... atau numerik)
+func validateCatalogSizes(sizes []models.CatalogSize) error {
+    unique := map[string]bool{}
+    for i := range sizes {
+        sizes[i].Size = strings.TrimSpace(sizes[i].Size)
+        key := strings.ToLower(sizes[i].Size)
+        if key == "" {
+            return fmt.Errorf("ukuran tidak boleh kosong")
+        }
+        if unique[key] {
+            return fmt.Errorf("ukuran '%s' dobel", sizes[i].Size)
+        }
+        if sizes[i].BasePrice < 0 {
+            return fmt.Errorf("harga dasar ukuran '%s' tidak boleh negatif", sizes[i].Size)
+        }
+        unique[key] = true
+    }
+    sort.SliceStable(sizes, func(i, j int) bool { return sizing.Less(sizes[i].Size, sizes[j].Size) })
+    for i := range sizes {
+        sizes[i].SortOrder = i + 1
+    }
+    return nil
+}
+
+func saveCatalogSizes(tx *sql.Tx, catalogID int, sizes []models.CatalogSize) error {
+    keep := []interface{}{catalogID}
+    for _, s := range sizes {
+        _, err := tx.Exec(
+            `INSERT INTO uniform_catalog_sizes (catalog_id, size, base_price, sort_order) VALUES (?, ?, ?, ?)
+             ON DUPLICATE KEY UPDATE base_price = VALUES(base_price), sort_order = VALUES(sort_order)`,
+            catalogID, s.Size, s.BasePrice, s.SortOrder,
+        )
+        if err != nil {
+            return err
+        }
+        keep = append(keep, s.Size)
+    }
+    query := "DELETE FROM uniform_catalog_sizes WHERE catalog_id = ?"
+    if len(sizes) > 0 {
+        query += " AND size NOT IN (" + placeholders(len(sizes)) + ")"
+    }
+    _, err := tx.Exec(query, keep...)
+    return err
+}
+
+func (r *CatalogRepository) Create(c *models.CatalogItem) error {
+    if err := validateCatalogSizes(c.Sizes); err != nil {
+        return err
+    }
+    tx, err := r.DB.Begin()
+    if err != nil {
+        return err
+    }
+    res, err := tx.Exec(
+        "INSERT INTO uniform_catalog (name, category, fabric, description, active) VALUES (?, ?, ?, ?, ?)",
+        c.Name, nullableString(c.Category), nullableString(c.Fabric), nullableString(c.Description), c.Active,
+    )
+    if err != nil {
+        tx.Rollback()
+        return err
+    }
+    id, _ := res.LastInsertId()
+    c.ID = int(id)
+
+    if err := saveCatalogSizes(tx, c.ID, c.Sizes); err != nil {
+        tx.Rollback()
+        return err
+    }
+    return tx.Commit()
+}
+
+// Update menyimpan data produk; daftar ukuran diganti sesuai c.Sizes
+func (r *CatalogRepository) Update(c *models.CatalogItem) error {
+    if err := validateCatalogSizes(c.Sizes); err != nil {
+        return err
+    }
+    tx, err := r.DB.Begin()
+    if err != nil {
+        return err
+    }
+    res, err := tx.Exec(
+        "UPDATE uniform_catalog SET name = ?, category = ?, fabric = ?, description = ?, active = ? WHERE id = ?",
+        c.Name, nullableString(c.Category), nullableString(c.Fabric), nullableString(c.Description), c.Active, c.ID,
+    )
+    if err != nil {
+        tx.Rollback()
+        return err
+    }
+    if n, _ := res.RowsAffected(); n == 0 {
+        var exists int
+        if err := tx.QueryRow("SELECT COUNT(*) FROM uniform_catalog WHERE id = ?", c.ID).Scan(&exists); err != nil || exists == 0 {
+            tx.Rollback()
+            return sql.ErrNoRows
+        }
+    }
+    if err := saveCatalogSizes(tx, c.ID, c.Sizes); err != nil {
+        tx.Rollback()
+        return err
+    }
+    return tx.Commit()
+}
+
+// Delete menghapus produk; customer_uniforms yang merujuk menjadi tidak terhubung (catalog_id NULL)
+func (r *CatalogRepository) Delete(id int) error {
+    res, err := r.DB.Exec("DELETE FROM uniform_catalog WHERE id = ?", id)
+    if err != nil {
+        return err
+    }
+    if n, _ := res.RowsAffected(); n == 0 {
+        return sql.ErrNoRows
+    }
+    return nil
+}
+
+// normalizeUniformName menyamakan penulisan: huruf kecil, tanpa tanda baca, spasi tunggal
+func normalizeUniformName(s string) string {
+    s = strings.Map(func(r rune) rune {
+        if unicode.IsLetter(r) || unicode.IsDigit(r) {
+            return unicode.ToLower(r)
+        }
+        return ' '
+    }, s)
+    return strings.Join(strings.Fields(s), " ")
+}
+
+func levenshtein(a, b []rune) int {
+    prev := make([]int, len(b)+1)
+    curr := make([]int, len(b)+1)
+    for j := range prev {
+        prev[j] = j
+    }
+    for i := 1; i <= len(a); i++ {
+        curr[0] = i
+        for j := 1; j <= len(b); j++ {
+            cost := 1
+            if a[i-1] == b[j-1] {
+                cost = 0
+            }
+            curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+        }
+        prev, curr = curr, prev
+    }
+    return prev[len(b)]
+}
+
+// nameSimilarity memberi skor 0..1: gabungan kemiripan token (urutan kata bebas) dan jarak edit
+func nameSimilarity(a, b string) float64 {
+    a, b = normalizeUniformName(a), normalizeUniformName(b)
+    if a == "" || b == "" {
+        return 0
+    }
+    if a == b {
+        return 1
+    }
+
+    tokensA := strings.Fields(a)
+    tokensB := map[string]bool{}
+    for _, t := range strings.Fields(b) {
+        tokensB[t] = true
+    }
+    common := 0
+    for _, t := range tokensA {
+        if tokensB[t] {
+            common++
+        }
+    }
+    // Rata-rata Jaccard dan containment supaya "olahraga" tetap mirip dengan "Kaos Olahraga"
+    union := len(tokensA) + len(tokensB) - common
+    tokenScore := (float64(common)/float64(union) + float64(common)/float64(min(len(tokensA), len(tokensB)))) / 2
+
+    ra, rb := []rune(a), []rune(b)
+    editScore := 1 - float64(levenshtein(ra, rb))/float64(max(len(ra), len(rb)))
+
+    return max(tokenScore, editScore)
+}
+
+// GetLinkSuggestions mencari produk katalog yang paling mirip untuk setiap customer_uniforms
+// yang belum terhubung. Baris dengan skor di bawah minScore tetap dikembalikan tanpa usulan.
+func (r *CatalogRepository) GetLinkSuggestions(minScore float64) ([]models.CatalogLinkSuggestion, error) {
+    catalog, err := r.GetAll(false)
+    if err != nil {
+        return nil, err
+    }
+
+    rows, err := r.DB.Query(`
+        SELECT cu.id, cu.customer_id, c.name, cu.uniform_name, cu.size, cu.price
+        FROM customer_uniforms cu
+        JOIN customers c ON c.id = cu.customer_id
+        WHERE cu.catalog_id IS NULL
+        ORDER BY c.name, cu.uniform_name, cu.id`)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    suggestions := []models.CatalogLinkSuggestion{}
+    for rows.Next() {
+        var s models.CatalogLinkSuggestion
+        if err := rows.Scan(&s.CustomerUniformID, &s.CustomerID, &s.CustomerName, &s.UniformName, &s.Size, &s.Price); err != nil {
+            return nil, err
+        }
+        var best *models.CatalogItem
+        for i := range catalog {
+            score := nameSimilarity(s.UniformName, catalog[i].Name)
+            if score > s.Score {
+                s.Score = score
+                best = &catalog[i]
+            }
+        }
+        if best != nil && s.Score >= minScore {
+            s.CatalogID = best.ID
+            s.CatalogName = best.Name
+            for _, size := range best.Sizes {
+                if strings.EqualFold(size.Size, strings.TrimSpace(s.Size)) {
+                    s.SizeInCatalog = true
+                    s.BasePrice = size.BasePrice
+                    break
+                }
+            }
+        }
+        s.Score = float64(int(s.Score*100)) / 100
+        suggestions = append(suggestions, s)
+    }
+    return suggestions, rows.Err()
+}
+
+// CatalogLink adalah konfirmasi admin untuk menghubungkan satu baris daftar harga
+type CatalogLink struct {
+    CustomerUniformID int  `json:"customer_uniform_id"`
+    CatalogID         int  `json:"catalog_id"`
+    Rename            bool `json:"rename"` // samakan uniform_name dengan nama katalog
+}
+
+// ApplyLinks menghubungkan customer_uniforms ke katalog dalam satu transaksi.
+// Rename dilewati jika membuat kombinasi uniform_name + size dobel untuk customer tersebut.
+func (r *CatalogRepository) ApplyLinks(links []CatalogLink) (linked int, renamed int, err error) {
+    tx, err := r.DB.Begin()
+    if err != nil {
+        return 0, 0, err
+    }
+
+    for _, link := range links {
+        var catalogName string
+        if err := tx.QueryRow("SELECT name FROM uniform_catalog WHERE id = ?", link.CatalogID).Scan(&catalogName); err != nil {
+            tx.Rollback()
+            return 0, 0, fmt.Errorf("katalog %d tidak ditemukan", link.CatalogID)
+        }
+        res, err := tx.Exec("UPDATE customer_uniforms SET catalog_id = ? WHERE id = ?", link.CatalogID, link.CustomerUniformID)
+        if err != nil {
+            tx.Rollback()
+            return 0, 0, err
+        }
+        if n, _ := res.RowsAffected(); n == 0 {
+            var exists int
+            tx.QueryRow("SELECT COUNT(*) FROM customer_uniforms WHERE id = ?", link.CustomerUniformID).Scan(&exists)
+            if exists == 0 {
+                tx.Rollback()
+                return 0, 0, fmt.Errorf("daftar harga %d tidak ditemukan", link.CustomerUniformID)
+            }
+        }
+        linked++
+
+        if !link.Rename {
+            continue
+        }
+        var duplicates int
+        err = tx.QueryRow(`
+            SELECT COUNT(*) FROM customer_uniforms d
+            JOIN customer_uniforms cu ON cu.id = ?
+            WHERE d.customer_id = cu.customer_id AND d.uniform_name = ? AND d.size = cu.size AND d.id != cu.id`,
+            link.CustomerUniformID, catalogName,
+        ).Scan(&duplicates)
+        if err != nil {
+            tx.Rollback()
+            return 0, 0, err
+        }
+        if duplicates > 0 {
+            continue
+        }
+        res, err = tx.Exec("UPDATE customer_uniforms SET uniform_name = ? WHERE id = ?", catalogName, link.CustomerUniformID)
+        if err != nil {
+            tx.Rollback()
+            return 0, 0, err
+        }
+        if n, _ := res.RowsAffected(); n > 0 {
+            renamed++
+        }
+    }
+    return linked, renamed, tx.Commit()
+}
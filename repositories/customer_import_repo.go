@@ -0,0 +1,122 @@
+package repositories
+
+import (
+    "konveksi-app/models"
+)
+
+// CustomerImport adalah rencana perubahan satu customer hasil import.
+// Customer.ID = 0 berarti customer baru.
+type CustomerImport struct {
+    Customer        models.Customer
+    UpdateCustomer  bool
+    NewUniforms     []models.CustomerUniform
+    UpdatedUniforms []models.CustomerUniform // ID wajib diisi
+}
+
+// GetAllWithUniforms mengambil semua customer beserta daftar harganya (untuk export)
+func (r *CustomerRepository) GetAllWithUniforms() ([]models.Customer, error) {
+    rows, err := r.DB.Query(`
+        SELECT c.id, c.name, c.type, COALESCE(c.contact, ''), COALESCE(c.email, ''), COALESCE(c.address, ''), c.created_at,
+               COALESCE(cu.id, 0), COALESCE(cu.uniform_name, ''), COALESCE(cu.size, ''),
+               COALESCE(cu.price, 0), COALESCE(cu.notes, '')
+        FROM customers c
+        LEFT JOIN customer_uniforms cu ON cu.customer_id = c.id
+        ORDER BY c.name, c.id, cu.uniform_name, cu.id`)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    customers := []models.Customer{}
+    for rows.Next() {
+        var c models.Customer
+        var u models.CustomerUniform
+        err := rows.Scan(
+            &c.ID, &c.Name, &c.Type, &c.Contact, &c.Email, &c.Address, &c.CreatedAt,
+            &u.ID, &u.UniformName, &u.Size, &u.Price, &u.Notes,
+        )
+        if err != nil {
+            return nil, err
+        }
+        if n := len(customers); n == 0 || customers[n-1].ID != c.ID {
+            c.Uniforms = []models.CustomerUniform{}
+            customers = append(customers, c)
+        }
+        if u.ID != 0 {
+            u.CustomerID = c.ID
+            last := &customers[len(customers)-1]
+            last.Uniforms = append(last.Uniforms, u)
+        }
+    }
+    return customers, nil
+}
+
+// ApplyImport menyimpan seluruh hasil import dalam satu transaksi database.
+// Perubahan harga dicatat ke customer_uniform_price_history seperti edit manual.
+func (r *CustomerRepository) ApplyImport(imports []CustomerImport, changedBy string) error {
+    tx, err := r.DB.Begin()
+    if err != nil {
+        return err
+    }
+
+    for i := range imports {
+        imp := &imports[i]
+        c := &imp.Customer
+
+        if c.ID == 0 {
+            if err := ValidateUniqueUniforms(imp.NewUniforms); err != nil {
+                tx.Rollback()
+                return err
+            }
+            res, err := tx.Exec(
+                "INSERT INTO customers (name, type, contact, email, address) VALUES (?, ?, ?, ?, ?)",
+                c.Name, c.Type, c.Contact, c.Email, c.Address,
+            )
+            if err != nil {
+                tx.Rollback()
+                return err
+            }
+            customerID, _ := res.LastInsertId()
+            c.ID = int(customerID)
+        } else if imp.UpdateCustomer {
+            _, err := tx.Exec(
+                "UPDATE customers SET name = ?, type = ?, contact = ?, email = ?, address = ? WHERE id = ?",
+                c.Name, c.Type, c.Contact, c.Email, c.Address, c.ID,
+            )
+            if err != nil {
+                tx.Rollback()
+                return err
+            }
+        }
+
+        for _, u := range imp.NewUniforms {
+            _, err := tx.Exec(
+                "INSERT INTO customer_uniforms (customer_id, uniform_name, size, price, notes) VALUES (?, ?, ?, ?, ?)",
+                c.ID, u.UniformName, u.Size, u.Price, u.Notes,
+            )
+            if err != nil {
+                tx.Rollback()
+                return err
+            }
+        }
+
+        for _, u := range imp.UpdatedUniforms {
+            var oldPrice float64
+            err := tx.QueryRow("SELECT price FROM customer_uniforms WHERE id = ? AND customer_id = ?", u.ID, c.ID).Scan(&oldPrice)
+            if err != nil {
+                tx.Rollback()
+                return err
+            }
+            if err := recordPriceChange(tx, u.ID, oldPrice, u.Price, changedBy, "import customer"); err != nil {
+                tx.Rollback()
+                return err
+            }
+            _, err = tx.Exec("UPDATE customer_uniforms SET price = ?, notes = ? WHERE id = ?", u.Price, u.Notes, u.ID)
+            if err != nil {
+                tx.Rollback()
+                return err
+            }
+        }
+    }
+    return tx.Commit()
+}
@@ -16,8 +16,8 @@ func (r *CustomerRepository) CreateWithUniforms(customer *models.Customer, unifo
         return err
     }
     res, err := tx.Exec(
-        "INSERT INTO customers (name, type, contact, address) VALUES (?, ?, ?, ?)",
-        customer.Name, customer.Type, customer.Contact, customer.Address,
+        "INSERT INTO customers (name, type, contact, email, address) VALUES (?, ?, ?, ?, ?)",
+        customer.Name, customer.Type, customer.Contact, customer.Email, customer.Address,
     )
     if err != nil {
         tx.Rollback()
@@ -27,17 +27,14 @@ func (r *CustomerRepository) CreateWithUniforms(customer *models.Customer, unifo
     customer.ID = int(customerID)
 
     // Validasi unik kombinasi uniform_name + size
-    unique := map[string]bool{}
+    if err := ValidateUniqueUniforms(uniforms); err != nil {
+        tx.Rollback()
+        return err
+    }
     for _, u := range uniforms {
-        key := u.UniformName + "|" + u.Size
-        if unique[key] {
-            tx.Rollback()
-            return fmt.Errorf("ukuran '%s' untuk seragam '%s' sudah ada", u.Size, u.UniformName)
-        }
-        unique[key] = true
         _, err := tx.Exec(
-            "INSERT INTO customer_uniforms (customer_id, uniform_name, size, price, notes) VALUES (?, ?, ?, ?, ?)",
-            customer.ID, u.UniformName, u.Size, u.Price, u.Notes,
+            "INSERT INTO customer_uniforms (customer_id, catalog_id, uniform_name, size, price, notes) VALUES (?, ?, ?, ?, ?, ?)",
+            customer.ID, nullableInt(u.CatalogID), u.UniformName, u.Size, u.Price, u.Notes,
         )
         if err != nil {
             tx.Rollback()
@@ -47,7 +44,40 @@ func (r *CustomerRepository) CreateWithUniforms(customer *models.Customer, unifo
     return tx.Commit()
 }
 
+// ValidateUniqueUniforms memastikan tidak ada kombinasi uniform_name + size yang dobel
+func ValidateUniqueUniforms(uniforms []models.CustomerUniform) error {
+    unique := map[string]bool{}
+    for _, u := range uniforms {
+        key := u.UniformName + "|" + u.Size
+        if unique[key] {
+            return fmt.Errorf("ukuran '%s' untuk seragam '%s' sudah ada", u.Size, u.UniformName)
+        }
+        unique[key] = true
+    }
+    return nil
+}
+
 func (r *CustomerRepository) AddCustomerUniform(u *models.CustomerUniform) error {
+    // Produk dari katalog: nama mengikuti katalog, harga 0 berarti pakai harga dasar ukuran tersebut
+    if u.CatalogID != 0 {
+        var catalogName string
+        if err := r.DB.QueryRow("SELECT name FROM uniform_catalog WHERE id = ?", u.CatalogID).Scan(&catalogName); err != nil {
+            return fmt.Errorf("katalog seragam %d tidak ditemukan", u.CatalogID)
+        }
+        if u.UniformName == "" {
+            u.UniformName = catalogName
+        }
+        if u.Price == 0 {
+            err := r.DB.QueryRow(
+                "SELECT base_price FROM uniform_catalog_sizes WHERE catalog_id = ? AND size = ?",
+                u.CatalogID, u.Size,
+            ).Scan(&u.Price)
+            if err != nil && err != sql.ErrNoRows {
+                return err
+            }
+        }
+    }
+
     // Validasi unik kombinasi uniform_name + size untuk customer ini
     var count int
     err := r.DB.QueryRow(
@@ -61,21 +91,21 @@ func (r *CustomerRepository) AddCustomerUniform(u *models.CustomerUniform) error
         return fmt.Errorf("ukuran '%s' untuk seragam '%s' sudah ada", u.Size, u.UniformName)
     }
     _, err = r.DB.Exec(
-        `INSERT INTO customer_uniforms (customer_id, uniform_name, size, price, notes) VALUES (?, ?, ?, ?, ?)`,
-        u.CustomerID, u.UniformName, u.Size, u.Price, u.Notes,
+        `INSERT INTO customer_uniforms (customer_id, catalog_id, uniform_name, size, price, notes) VALUES (?, ?, ?, ?, ?, ?)`,
+        u.CustomerID, nullableInt(u.CatalogID), u.UniformName, u.Size, u.Price, u.Notes,
     )
     return err
 }
 
 func (r *CustomerRepository) GetByID(id int) (*models.Customer, error) {
 	query := `
-		SELECT id, name, type, contact, address, created_at
+		SELECT id, name, type, contact, COALESCE(email, ''), address, created_at
 		FROM customers WHERE id = ?`
 
 	var customer models.Customer
 	err := r.DB.QueryRow(query, id).Scan(
 		&customer.ID, &customer.Name, &customer.Type,
-		&customer.Contact, &customer.Address,
+		&customer.Contact, &customer.Email, &customer.Address,
 		&customer.CreatedAt,
 	)
 
@@ -84,7 +114,7 @@ func (r *CustomerRepository) GetByID(id int) (*models.Customer, error) {
 
 func (r *CustomerRepository) GetUniformsByCustomerID(customerID int) ([]models.CustomerUniform, error) {
     rows, err := r.DB.Query(
-        "SELECT id, customer_id, uniform_name, size, price, notes, created_at FROM customer_uniforms WHERE customer_id = ?",
+        "SELECT id, customer_id, COALESCE(catalog_id, 0), uniform_name, size, price, COALESCE(notes, ''), created_at FROM customer_uniforms WHERE customer_id = ?",
         customerID,
     )
     if err != nil {
@@ -94,7 +124,7 @@ func (r *CustomerRepository) GetUniformsByCustomerID(customerID int) ([]models.C
     var uniforms []models.CustomerUniform
     for rows.Next() {
         var u models.CustomerUniform
-        if err := rows.Scan(&u.ID, &u.CustomerID, &u.UniformName, &u.Size, &u.Price, &u.Notes, &u.CreatedAt); err != nil {
+        if err := rows.Scan(&u.ID, &u.CustomerID, &u.CatalogID, &u.UniformName, &u.Size, &u.Price, &u.Notes, &u.CreatedAt); err != nil {
             return nil, err
         }
         uniforms = append(uniforms, u)
@@ -104,7 +134,7 @@ func (r *CustomerRepository) GetUniformsByCustomerID(customerID int) ([]models.C
 
 func (r *CustomerRepository) GetAll() ([]models.Customer, error) {
     query := `
-        SELECT id, name, type, contact, address, created_at
+        SELECT id, name, type, contact, COALESCE(email, ''), address, created_at
         FROM customers ORDER BY name`  // Koma setelah created_at dihapus
 
     rows, err := r.DB.Query(query)
@@ -118,7 +148,7 @@ func (r *CustomerRepository) GetAll() ([]models.Customer, error) {
         var c models.Customer
         err := rows.Scan(
             &c.ID, &c.Name, &c.Type,
-            &c.Contact, &c.Address,
+            &c.Contact, &c.Email, &c.Address,
             &c.CreatedAt,
         )
         if err != nil {
@@ -133,12 +163,12 @@ func (r *CustomerRepository) GetAll() ([]models.Customer, error) {
 func (r *CustomerRepository) Update(customer *models.Customer) error {
     query := `
         UPDATE customers 
-        SET name = ?, type = ?, contact = ?, address = ? 
+        SET name = ?, type = ?, contact = ?, email = ?, address = ? 
         WHERE id = ?`  // Koma setelah address dihapus
 
     _, err := r.DB.Exec(query,
         customer.Name, customer.Type,
-        customer.Contact, customer.Address,
+        customer.Contact, customer.Email, customer.Address,
         customer.ID,
     )
 
@@ -148,16 +178,16 @@ func (r *CustomerRepository) Update(customer *models.Customer) error {
 func (r *CustomerRepository) GetCustomerUniformByID(id int) (*models.CustomerUniform, error) {
     var u models.CustomerUniform
     err := r.DB.QueryRow(
-        "SELECT id, customer_id, uniform_name, size, price, notes, created_at FROM customer_uniforms WHERE id = ?",
+        "SELECT id, customer_id, COALESCE(catalog_id, 0), uniform_name, size, price, COALESCE(notes, ''), created_at FROM customer_uniforms WHERE id = ?",
         id,
-    ).Scan(&u.ID, &u.CustomerID, &u.UniformName, &u.Size, &u.Price, &u.Notes, &u.CreatedAt)
+    ).Scan(&u.ID, &u.CustomerID, &u.CatalogID, &u.UniformName, &u.Size, &u.Price, &u.Notes, &u.CreatedAt)
     if err != nil {
         return nil, err
     }
     return &u, nil
 }
 
-func (r *CustomerRepository) UpdateCustomerUniformWithHistory(id int, uniformName, size string, price float64, notes string) error {
+func (r *CustomerRepository) UpdateCustomerUniformWithHistory(id int, uniformName, size string, price float64, notes, changedBy, reason string) error {
     tx, err := r.DB.Begin()
     if err != nil {
         return err
@@ -172,12 +202,9 @@ func (r *CustomerRepository) UpdateCustomerUniformWithHistory(id int, uniformNam
     }
 
     // Jika harga berubah, catat ke history
-    if oldPrice != price {
-        _, err = tx.Exec("INSERT INTO customer_uniform_price_history (customer_uniform_id, old_price) VALUES (?, ?)", id, oldPrice)
-        if err != nil {
-            tx.Rollback()
-            return err
-        }
+    if err := recordPriceChange(tx, id, oldPrice, price, changedBy, reason); err != nil {
+        tx.Rollback()
+        return err
     }
 
     // Update uniform
@@ -190,28 +217,35 @@ func (r *CustomerRepository) UpdateCustomerUniformWithHistory(id int, uniformNam
     return tx.Commit()
 }
 
+// PriceHistoryEntry adalah satu perubahan harga customer_uniforms
+type PriceHistoryEntry struct {
+    OldPrice      float64
+    NewPrice      *float64
+    ChangedBy     string
+    Reason        string
+    EffectiveFrom string
+    ChangedAt     string
+}
+
 // Ambil riwayat harga
-func (r *CustomerRepository) GetUniformPriceHistory(uniformID int) ([]struct {
-    OldPrice  float64
-    ChangedAt string
-}, error) {
-    rows, err := r.DB.Query("SELECT old_price, changed_at FROM customer_uniform_price_history WHERE customer_uniform_id = ? ORDER BY changed_at ASC", uniformID)
+func (r *CustomerRepository) GetUniformPriceHistory(uniformID int) ([]PriceHistoryEntry, error) {
+    rows, err := r.DB.Query(`
+        SELECT old_price, new_price, COALESCE(changed_by, ''), COALESCE(reason, ''), COALESCE(effective_from, ''), changed_at
+        FROM customer_uniform_price_history WHERE customer_uniform_id = ? ORDER BY changed_at ASC, id ASC`, uniformID)
     if err != nil {
         return nil, err
     }
     defer rows.Close()
-    var history []struct {
-        OldPrice  float64
-        ChangedAt string
-    }
+    var history []PriceHistoryEntry
     for rows.Next() {
-        var h struct {
-            OldPrice  float64
-            ChangedAt string
-        }
-        if err := rows.Scan(&h.OldPrice, &h.ChangedAt); err != nil {
+        var h PriceHistoryEntry
+        var newPrice sql.NullFloat64
+        if err := rows.Scan(&h.OldPrice, &newPrice, &h.ChangedBy, &h.Reason, &h.EffectiveFrom, &h.ChangedAt); err != nil {
             return nil, err
         }
+        if newPrice.Valid {
+            h.NewPrice = &newPrice.Float64
+        }
         history = append(history, h)
     }
     return history, nil
@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"database/sql"
+	"log"
 )
 
 type DashboardStats struct {
@@ -12,6 +13,7 @@ type DashboardStats struct {
 	Allpaymentscancelled int
 	ReminderPayments     int
 	ReminderTransactions int
+	LowStockMaterials    int
 }
 
 func GetDashboardStats(db *sql.DB) (DashboardStats, error) {
@@ -87,6 +89,13 @@ func GetDashboardStats(db *sql.DB) (DashboardStats, error) {
 		return stats, err
 	}
 
+	// Tabel materials baru ada setelah migrasi 013; jika gagal, dashboard tetap tampil tanpa hitungan stok
+	if lowStock, err := CountLowStock(db); err != nil {
+		log.Printf("Error counting low stock materials: %v", err)
+	} else {
+		stats.LowStockMaterials = lowStock
+	}
+
 	return stats, nil
 }
 
@@ -0,0 +1,32 @@
+package repositories
+
+import (
+    "errors"
+    "testing"
+
+    "github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestGetDashboardStatsWithoutMaterialsTable(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer db.Close()
+
+    for i := 1; i <= 7; i++ {
+        mock.ExpectQuery("FROM transactions").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(i))
+    }
+    mock.ExpectQuery("FROM materials").WillReturnError(errors.New("Table 'konveksi.materials' doesn't exist"))
+
+    stats, err := GetDashboardStats(db)
+    if err != nil {
+        t.Fatalf("err = %v, want dashboard stats without low stock count", err)
+    }
+    if stats.OverduePayments != 1 || stats.ReminderTransactions != 7 || stats.LowStockMaterials != 0 {
+        t.Fatalf("stats = %+v", stats)
+    }
+    if err := mock.ExpectationsWereMet(); err != nil {
+        t.Fatal(err)
+    }
+}
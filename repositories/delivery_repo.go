@@ -0,0 +1,451 @@
+package repositories
+
+import (
+    "database/sql"
+    "fmt"
+    "konveksi-app/models"
+    "strings"
+    "time"
+)
+
+type DeliveryRepository struct {
+    DB *sql.DB
+}
+
+// deliveryKey membentuk line_key baris pesanan: jenis item, siswa (student_id jika ada, selain itu
+// nama dan kelas), seragam dan ukuran. Kunci disimpan di delivery_items saat dicatat, jadi tidak
+// bergantung pada id item yang berubah setiap kali item transaksi ditulis ulang.
+func deliveryKey(itemType string, studentID int, studentName, grade, uniformName, size string) string {
+    parts := []string{itemType}
+    if itemType == "student" {
+        if studentID != 0 {
+            parts = append(parts, fmt.Sprintf("#%d", studentID))
+        } else {
+            parts = append(parts, studentName, grade)
+        }
+    }
+    parts = append(parts, uniformName, size)
+    for i, p := range parts {
+        parts[i] = strings.ToLower(strings.TrimSpace(p))
+    }
+    return strings.Join(parts, "|")
+}
+
+// deliveryLines menghitung jumlah terkirim dan sisa kirim per item pesanan transaksi.
+// Jumlah terkirim dibagi ke item dengan line_key yang sama sesuai urutan item; kelebihannya dicatat
+// sebagai OverDelivered di item terakhir, atau di baris tersendiri jika item sudah tidak ada di pesanan.
+func deliveryLines(q rowsQueryer, transactionID int) ([]models.DeliveryRemaining, error) {
+    lines := []models.DeliveryRemaining{}
+    byKey := map[string][]int{}
+    for _, query := range []string{
+        `SELECT 'student', id, COALESCE(student_id, 0), student_name, COALESCE(grade, ''), uniform_name, size, quantity
+         FROM student_order_items WHERE transaction_id = ? ORDER BY id`,
+        `SELECT 'order', id, 0, '', '', uniform_name, size, quantity
+         FROM order_items WHERE transaction_id = ? ORDER BY id`,
+    } {
+        rows, err := q.Query(query, transactionID)
+        if err != nil {
+            return nil, err
+        }
+        for rows.Next() {
+            var l models.DeliveryRemaining
+            var studentID int
+            if err := rows.Scan(&l.ItemType, &l.ItemID, &studentID, &l.StudentName, &l.Grade, &l.UniformName, &l.Size, &l.Ordered); err != nil {
+                rows.Close()
+                return nil, err
+            }
+            l.LineKey = deliveryKey(l.ItemType, studentID, l.StudentName, l.Grade, l.UniformName, l.Size)
+            byKey[l.LineKey] = append(byKey[l.LineKey], len(lines))
+            lines = append(lines, l)
+        }
+        rows.Close()
+        if err := rows.Err(); err != nil {
+            return nil, err
+        }
+    }
+
+    rows, err := q.Query(`
+        SELECT di.line_key, MIN(di.item_type), MIN(di.student_name), MIN(di.grade), MIN(di.uniform_name), MIN(di.size),
+               SUM(di.quantity)
+        FROM delivery_items di
+        JOIN deliveries d ON d.id = di.delivery_id
+        WHERE d.transaction_id = ?
+        GROUP BY di.line_key
+        ORDER BY MIN(di.id)`,
+        transactionID,
+    )
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+    var delivered []models.DeliveryRemaining
+    for rows.Next() {
+        var d models.DeliveryRemaining
+        if err := rows.Scan(&d.LineKey, &d.ItemType, &d.StudentName, &d.Grade, &d.UniformName, &d.Size, &d.Delivered); err != nil {
+            return nil, err
+        }
+        delivered = append(delivered, d)
+    }
+    if err := rows.Err(); err != nil {
+        return nil, err
+    }
+
+    for i := range lines {
+        lines[i].Remaining = lines[i].Ordered
+    }
+    for _, d := range delivered {
+        indexes := byKey[d.LineKey]
+        if len(indexes) == 0 {
+            d.OverDelivered = d.Delivered
+            lines = append(lines, d)
+            continue
+        }
+        left := d.Delivered
+        for _, i := range indexes {
+            l := &lines[i]
+            l.Delivered = l.Ordered
+            if left < l.Delivered {
+                l.Delivered = left
+            }
+            left -= l.Delivered
+            l.Remaining = l.Ordered - l.Delivered
+        }
+        if left > 0 {
+            last := &lines[indexes[len(indexes)-1]]
+            last.Delivered += left
+            last.OverDelivered = left
+        }
+    }
+    return lines, nil
+}
+
+// insertDelivery menyimpan pengiriman beserta itemnya; line_key dan salinan seragam dan ukuran harus sudah terisi
+func insertDelivery(tx *sql.Tx, d *models.Delivery) error {
+    res, err := tx.Exec(
+        "INSERT INTO deliveries (transaction_id, delivery_date, receiver_name, notes, delivered_by) VALUES (?, ?, ?, ?, ?)",
+        d.TransactionID, d.DeliveryDate, nullableString(d.ReceiverName), nullableString(d.Notes), nullableString(d.DeliveredBy),
+    )
+    if err != nil {
+        return err
+    }
+    id, _ := res.LastInsertId()
+    d.ID = int(id)
+    d.TotalQuantity = 0
+    for i := range d.Items {
+        item := &d.Items[i]
+        item.DeliveryID = d.ID
+        res, err := tx.Exec(
+            `INSERT INTO delivery_items (delivery_id, item_type, item_id, line_key, student_name, grade, uniform_name, size, quantity)
+             VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+            d.ID, item.ItemType, nullableInt(item.ItemID), item.LineKey, item.StudentName, item.Grade, item.UniformName, item.Size, item.Quantity,
+        )
+        if err != nil {
+            return err
+        }
+        itemID, _ := res.LastInsertId()
+        item.ID = int(itemID)
+        d.TotalQuantity += item.Quantity
+    }
+    return tx.QueryRow("SELECT COUNT(*) FROM deliveries WHERE transaction_id = ? AND id <= ?", d.TransactionID, d.ID).Scan(&d.Sequence)
+}
+
+// completeDeliveries dipanggil saat tahap diubah manual ke diserahkan. Transaksi tanpa riwayat pengiriman
+// dianggap diserahkan sekaligus dan dicatat satu pengiriman untuk seluruh pesanan; jika sudah ada
+// pengiriman bertahap, sisa kirim harus dicatat dulu.
+func completeDeliveries(tx *sql.Tx, transactionID int, performedBy string) (*models.Delivery, error) {
+    lines, err := deliveryLines(tx, transactionID)
+    if err != nil {
+        return nil, err
+    }
+    remaining := 0
+    for _, l := range lines {
+        remaining += l.Remaining
+    }
+    if remaining == 0 {
+        return nil, nil
+    }
+    var deliveries int
+    if err := tx.QueryRow("SELECT COUNT(*) FROM deliveries WHERE transaction_id = ?", transactionID).Scan(&deliveries); err != nil {
+        return nil, err
+    }
+    if deliveries > 0 {
+        return nil, fmt.Errorf("masih ada %d pcs yang belum dikirim, catat pengiriman sisanya dulu", remaining)
+    }
+
+    d := models.Delivery{
+        TransactionID: transactionID,
+        DeliveryDate:  time.Now().Format("2006-01-02"),
+        Notes:         "Diserahkan sekaligus",
+        DeliveredBy:   performedBy,
+    }
+    for _, l := range lines {
+        d.Items = append(d.Items, models.DeliveryItem{
+            ItemType: l.ItemType, ItemID: l.ItemID, LineKey: l.LineKey, StudentName: l.StudentName, Grade: l.Grade,
+            UniformName: l.UniformName, Size: l.Size, Quantity: l.Remaining,
+        })
+    }
+    if err := insertDelivery(tx, &d); err != nil {
+        return nil, err
+    }
+    return &d, nil
+}
+
+// Create mencatat pengiriman sebagian pesanan; all berarti kirim seluruh sisa.
+// Jika setelah ini tidak ada sisa kirim, tahap produksi pindah ke diserahkan dan stok yang
+// disisihkan ikut dikeluarkan dalam transaksi DB yang sama.
+func (r *DeliveryRepository) Create(d *models.Delivery, all bool) (*StageChange, error) {
+    d.ReceiverName = strings.TrimSpace(d.ReceiverName)
+    if d.DeliveryDate == "" {
+        d.DeliveryDate = time.Now().Format("2006-01-02")
+    }
+    if err := validateDate(d.DeliveryDate, "tanggal kirim"); err != nil {
+        return nil, err
+    }
+
+    tx, err := r.DB.Begin()
+    if err != nil {
+        return nil, err
+    }
+    defer tx.Rollback()
+
+    var status, stage string
+    err = tx.QueryRow("SELECT status, production_stage FROM transactions WHERE id = ? FOR UPDATE", d.TransactionID).
+        Scan(&status, &stage)
+    if err != nil {
+        return nil, err
+    }
+    if status == "cancelled" {
+        return nil, fmt.Errorf("transaksi sudah dibatalkan")
+    }
+    if stage == "diserahkan" {
+        return nil, fmt.Errorf("transaksi sudah diserahkan seluruhnya")
+    }
+
+    lines, err := deliveryLines(tx, d.TransactionID)
+    if err != nil {
+        return nil, err
+    }
+    remaining := 0
+    byItem := map[string]*models.DeliveryRemaining{}
+    for i := range lines {
+        remaining += lines[i].Remaining
+        if lines[i].ItemID != 0 {
+            byItem[fmt.Sprintf("%s:%d", lines[i].ItemType, lines[i].ItemID)] = &lines[i]
+        }
+    }
+
+    var items []models.DeliveryItem
+    if all {
+        for _, l := range lines {
+            if l.Remaining > 0 {
+                items = append(items, models.DeliveryItem{ItemType: l.ItemType, ItemID: l.ItemID, Quantity: l.Remaining})
+            }
+        }
+    } else {
+        requested := map[string]int{}
+        for i, item := range d.Items {
+            if item.ItemType == "" {
+                item.ItemType = "order"
+            }
+            key := fmt.Sprintf("%s:%d", item.ItemType, item.ItemID)
+            l, ok := byItem[key]
+            if !ok {
+                return nil, fmt.Errorf("baris %d: item %s %d bukan bagian dari transaksi ini", i+1, item.ItemType, item.ItemID)
+            }
+            if item.Quantity <= 0 {
+                return nil, fmt.Errorf("baris %d: jumlah harus lebih dari 0", i+1)
+            }
+            requested[key] += item.Quantity
+            if requested[key] > l.Remaining {
+                return nil, fmt.Errorf("baris %d: %s ukuran %s tinggal %d pcs yang belum dikirim", i+1, l.UniformName, l.Size, l.Remaining)
+            }
+            items = append(items, item)
+        }
+    }
+    if len(items) == 0 {
+        return nil, fmt.Errorf("tidak ada item yang dikirim")
+    }
+    for i := range items {
+        l := byItem[fmt.Sprintf("%s:%d", items[i].ItemType, items[i].ItemID)]
+        items[i].LineKey = l.LineKey
+        items[i].StudentName, items[i].Grade = l.StudentName, l.Grade
+        items[i].UniformName, items[i].Size = l.UniformName, l.Size
+    }
+    d.Items = items
+
+    if err := insertDelivery(tx, d); err != nil {
+        return nil, err
+    }
+
+    var change *StageChange
+    if d.TotalQuantity == remaining {
+        change = &StageChange{TransactionID: d.TransactionID, From: stage, To: "diserahkan"}
+        _, err = tx.Exec(
+            "UPDATE transactions SET production_stage = 'diserahkan', production_stage_at = NOW() WHERE id = ?",
+            d.TransactionID,
+        )
+        if err != nil {
+            return nil, err
+        }
+        if change.Issued, err = issueReservations(tx, d.TransactionID, d.DeliveredBy); err != nil {
+            return nil, err
+        }
+    }
+    if err := tx.Commit(); err != nil {
+        return nil, err
+    }
+    return change, nil
+}
+
+const deliveryColumns = `d.id, d.transaction_id, d.delivery_date, COALESCE(d.receiver_name, ''), COALESCE(d.notes, ''),
+    COALESCE(d.delivered_by, ''), d.created_at`
+
+// deliveryItems mengambil item pengiriman per delivery_id sesuai filter where
+func (r *DeliveryRepository) deliveryItems(where string, arg int) (map[int][]models.DeliveryItem, error) {
+    rows, err := r.DB.Query(`
+        SELECT di.id, di.delivery_id, di.item_type, COALESCE(di.item_id, 0), di.line_key, di.student_name, di.grade,
+               di.uniform_name, di.size, di.quantity
+        FROM delivery_items di
+        JOIN deliveries d ON d.id = di.delivery_id
+        WHERE `+where+`
+        ORDER BY di.id`,
+        arg,
+    )
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    items := map[int][]models.DeliveryItem{}
+    for rows.Next() {
+        var i models.DeliveryItem
+        err := rows.Scan(&i.ID, &i.DeliveryID, &i.ItemType, &i.ItemID, &i.LineKey, &i.StudentName, &i.Grade, &i.UniformName, &i.Size, &i.Quantity)
+        if err != nil {
+            return nil, err
+        }
+        items[i.DeliveryID] = append(items[i.DeliveryID], i)
+    }
+    return items, rows.Err()
+}
+
+// GetByTransaction mengambil riwayat pengiriman transaksi, urut dari yang pertama
+func (r *DeliveryRepository) GetByTransaction(transactionID int) ([]models.Delivery, error) {
+    rows, err := r.DB.Query("SELECT "+deliveryColumns+" FROM deliveries d WHERE d.transaction_id = ? ORDER BY d.id", transactionID)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    deliveries := []models.Delivery{}
+    for rows.Next() {
+        var d models.Delivery
+        err := rows.Scan(&d.ID, &d.TransactionID, &d.DeliveryDate, &d.ReceiverName, &d.Notes, &d.DeliveredBy, &d.CreatedAt)
+        if err != nil {
+            return nil, err
+        }
+        d.Sequence = len(deliveries) + 1
+        deliveries = append(deliveries, d)
+    }
+    if err := rows.Err(); err != nil {
+        return nil, err
+    }
+
+    items, err := r.deliveryItems("d.transaction_id = ?", transactionID)
+    if err != nil {
+        return nil, err
+    }
+    for i := range deliveries {
+        deliveries[i].Items = items[deliveries[i].ID]
+        for _, item := range deliveries[i].Items {
+            deliveries[i].TotalQuantity += item.Quantity
+        }
+    }
+    return deliveries, nil
+}
+
+func (r *DeliveryRepository) GetByID(id int) (*models.Delivery, error) {
+    var d models.Delivery
+    err := r.DB.QueryRow(`
+        SELECT `+deliveryColumns+`,
+               (SELECT COUNT(*) FROM deliveries p WHERE p.transaction_id = d.transaction_id AND p.id <= d.id)
+        FROM deliveries d WHERE d.id = ?`,
+        id,
+    ).Scan(&d.ID, &d.TransactionID, &d.DeliveryDate, &d.ReceiverName, &d.Notes, &d.DeliveredBy, &d.CreatedAt, &d.Sequence)
+    if err != nil {
+        return nil, err
+    }
+    items, err := r.deliveryItems("d.id = ?", id)
+    if err != nil {
+        return nil, err
+    }
+    d.Items = items[id]
+    for _, item := range d.Items {
+        d.TotalQuantity += item.Quantity
+    }
+    return &d, nil
+}
+
+// Status merekap jumlah pesanan, terkirim dan sisa kirim transaksi beserta riwayat pengirimannya
+func (r *DeliveryRepository) Status(transactionID int) (*models.DeliveryStatus, error) {
+    status := models.DeliveryStatus{TransactionID: transactionID}
+    err := r.DB.QueryRow("SELECT production_stage FROM transactions WHERE id = ?", transactionID).Scan(&status.ProductionStage)
+    if err != nil {
+        return nil, err
+    }
+    if status.Items, err = deliveryLines(r.DB, transactionID); err != nil {
+        return nil, err
+    }
+    for _, l := range status.Items {
+        status.Ordered += l.Ordered
+        status.Delivered += l.Delivered
+        status.Remaining += l.Remaining
+        status.OverDelivered += l.OverDelivered
+    }
+    status.HasOverDelivery = status.OverDelivered > 0
+    status.Complete = status.Ordered > 0 && status.Remaining == 0
+    if status.Deliveries, err = r.GetByTransaction(transactionID); err != nil {
+        return nil, err
+    }
+    return &status, nil
+}
+
+// Delete menghapus pengiriman yang salah catat. Jika transaksi sudah diserahkan, tahap produksi kembali
+// ke "selesai" karena pesanan tidak lagi terkirim seluruhnya; stok barang jadi yang sudah dikeluarkan
+// saat diserahkan tidak dikembalikan.
+func (r *DeliveryRepository) Delete(id int) (*StageChange, error) {
+    tx, err := r.DB.Begin()
+    if err != nil {
+        return nil, err
+    }
+    defer tx.Rollback()
+
+    var transactionID int
+    var stage string
+    err = tx.QueryRow(`
+        SELECT t.id, t.production_stage FROM deliveries d
+        JOIN transactions t ON t.id = d.transaction_id
+        WHERE d.id = ? FOR UPDATE`,
+        id,
+    ).Scan(&transactionID, &stage)
+    if err != nil {
+        return nil, err
+    }
+    if _, err := tx.Exec("DELETE FROM deliveries WHERE id = ?", id); err != nil {
+        return nil, err
+    }
+    var change *StageChange
+    if stage == "diserahkan" {
+        change = &StageChange{TransactionID: transactionID, From: stage, To: "selesai"}
+        _, err = tx.Exec(
+            "UPDATE transactions SET production_stage = 'selesai', production_stage_at = NOW() WHERE id = ?",
+            transactionID,
+        )
+        if err != nil {
+            return nil, err
+        }
+    }
+    if err := tx.Commit(); err != nil {
+        return nil, err
+    }
+    return change, nil
+}
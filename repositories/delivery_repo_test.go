@@ -0,0 +1,150 @@
+package repositories
+
+import (
+    "strings"
+    "testing"
+
+    "github.com/DATA-DOG/go-sqlmock"
+)
+
+var (
+    studentLineColumns   = []string{"item_type", "id", "student_id", "student_name", "grade", "uniform_name", "size", "quantity"}
+    deliveredLineColumns = []string{"line_key", "item_type", "student_name", "grade", "uniform_name", "size", "delivered"}
+)
+
+func TestDeliveryKey(t *testing.T) {
+    tests := []struct {
+        itemType    string
+        studentID   int
+        name, grade string
+        uniform     string
+        size        string
+        want        string
+    }{
+        {"order", 0, "", "", " Kemeja SD ", "m", "order|kemeja sd|m"},
+        {"student", 12, "Budi", "3A", "Kemeja SD", "M", "student|#12|kemeja sd|m"},
+        {"student", 0, " Budi ", "3a", "Kemeja SD", "M", "student|budi|3a|kemeja sd|m"},
+    }
+    for _, tt := range tests {
+        if got := deliveryKey(tt.itemType, tt.studentID, tt.name, tt.grade, tt.uniform, tt.size); got != tt.want {
+            t.Errorf("deliveryKey(%q, %d, %q, %q, %q, %q) = %q, want %q", tt.itemType, tt.studentID, tt.name, tt.grade, tt.uniform, tt.size, got, tt.want)
+        }
+    }
+}
+
+func TestDeliveryLinesMatchRewrittenItemsAndReportOverDelivery(t *testing.T) {
+    db, mock, tx := beginMock(t)
+    defer db.Close()
+
+    // Item ditulis ulang dengan id baru dan nama siswa diedit; Budi dikurangi dari 3 ke 1 pcs
+    // setelah 2 pcs terkirim, dan Celana SD sudah dihapus dari pesanan
+    mock.ExpectQuery("FROM student_order_items").WithArgs(5).
+        WillReturnRows(sqlmock.NewRows(studentLineColumns).
+            AddRow("student", 301, 12, "Budi S.", "3A", "Kemeja SD", "M", 1).
+            AddRow("student", 302, 13, "Sari", "3A", "Kemeja SD", "S", 2))
+    mock.ExpectQuery("FROM order_items").WithArgs(5).
+        WillReturnRows(sqlmock.NewRows(studentLineColumns))
+    mock.ExpectQuery("GROUP BY di.line_key").WithArgs(5).
+        WillReturnRows(sqlmock.NewRows(deliveredLineColumns).
+            AddRow("student|#12|kemeja sd|m", "student", "Budi", "3A", "Kemeja SD", "M", 2).
+            AddRow("student|#13|kemeja sd|s", "student", "Sari", "3A", "Kemeja SD", "S", 1).
+            AddRow("student|#13|celana sd|s", "student", "Sari", "3A", "Celana SD", "S", 1))
+
+    lines, err := deliveryLines(tx, 5)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if len(lines) != 3 {
+        t.Fatalf("lines = %+v, want 3 lines", lines)
+    }
+    budi, sari, removed := lines[0], lines[1], lines[2]
+    if budi.Delivered != 2 || budi.Remaining != 0 || budi.OverDelivered != 1 {
+        t.Errorf("budi = %+v, want delivered 2, remaining 0, over 1", budi)
+    }
+    if sari.Delivered != 1 || sari.Remaining != 1 || sari.OverDelivered != 0 {
+        t.Errorf("sari = %+v, want delivered 1, remaining 1", sari)
+    }
+    if removed.ItemID != 0 || removed.Ordered != 0 || removed.OverDelivered != 1 || removed.UniformName != "Celana SD" {
+        t.Errorf("removed = %+v, want over-delivered line outside the order", removed)
+    }
+    if err := mock.ExpectationsWereMet(); err != nil {
+        t.Fatal(err)
+    }
+}
+
+func TestDeliveryLinesSplitAcrossSameKey(t *testing.T) {
+    db, mock, tx := beginMock(t)
+    defer db.Close()
+
+    mock.ExpectQuery("FROM student_order_items").WithArgs(5).
+        WillReturnRows(sqlmock.NewRows(studentLineColumns))
+    mock.ExpectQuery("FROM order_items").WithArgs(5).
+        WillReturnRows(sqlmock.NewRows(studentLineColumns).
+            AddRow("order", 40, 0, "", "", "Kemeja SD", "M", 10).
+            AddRow("order", 41, 0, "", "", "kemeja sd ", "M", 5))
+    mock.ExpectQuery("GROUP BY di.line_key").WithArgs(5).
+        WillReturnRows(sqlmock.NewRows(deliveredLineColumns).
+            AddRow("order|kemeja sd|m", "order", "", "", "Kemeja SD", "M", 12))
+
+    lines, err := deliveryLines(tx, 5)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if lines[0].Delivered != 10 || lines[1].Delivered != 2 || lines[1].Remaining != 3 || lines[1].OverDelivered != 0 {
+        t.Fatalf("lines = %+v, want 10 + 2 delivered without over-delivery", lines)
+    }
+    if err := mock.ExpectationsWereMet(); err != nil {
+        t.Fatal(err)
+    }
+}
+
+func TestSetStageLeavingDeliveredRequiresDeletingDeliveries(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer db.Close()
+    repo := &ProductionRepository{DB: db}
+
+    mock.ExpectBegin()
+    mock.ExpectQuery("SELECT status, production_stage FROM transactions WHERE id = \\? FOR UPDATE").WithArgs(5).
+        WillReturnRows(sqlmock.NewRows([]string{"status", "production_stage"}).AddRow("paid", "diserahkan"))
+    mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM deliveries").WithArgs(5).
+        WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+    mock.ExpectRollback()
+
+    _, err = repo.SetStage(5, "finishing", false, "admin")
+    if err == nil || !strings.Contains(err.Error(), "hapus pengirimannya dulu") {
+        t.Fatalf("err = %v, want stage change blocked", err)
+    }
+    if err := mock.ExpectationsWereMet(); err != nil {
+        t.Fatal(err)
+    }
+}
+
+func TestDeleteDeliveryReopensDeliveredTransaction(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer db.Close()
+    repo := &DeliveryRepository{DB: db}
+
+    mock.ExpectBegin()
+    mock.ExpectQuery("FROM deliveries d").WithArgs(9).
+        WillReturnRows(sqlmock.NewRows([]string{"id", "production_stage"}).AddRow(5, "diserahkan"))
+    mock.ExpectExec("DELETE FROM deliveries WHERE id = \\?").WithArgs(9).WillReturnResult(sqlmock.NewResult(0, 1))
+    mock.ExpectExec("UPDATE transactions SET production_stage = 'selesai'").WithArgs(5).WillReturnResult(sqlmock.NewResult(0, 1))
+    mock.ExpectCommit()
+
+    change, err := repo.Delete(9)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if change == nil || change.From != "diserahkan" || change.To != "selesai" {
+        t.Fatalf("change = %+v, want diserahkan -> selesai", change)
+    }
+    if err := mock.ExpectationsWereMet(); err != nil {
+        t.Fatal(err)
+    }
+}
@@ -0,0 +1,163 @@
+package repositories
+
+import (
+    "database/sql"
+    "konveksi-app/models"
+)
+
+type EmailLogRepository struct {
+    DB *sql.DB
+}
+
+const emailLogColumns = `
+    id, template, recipient, subject, body,
+    COALESCE(transaction_id, 0), COALESCE(customer_id, 0), COALESCE(event_key, ''),
+    attach_kuitansi, status, attempts, COALESCE(last_error, ''),
+    COALESCE(sent_at, ''), created_at`
+
+func scanEmailLog(row interface{ Scan(...interface{}) error }) (models.EmailLog, error) {
+    var e models.EmailLog
+    err := row.Scan(
+        &e.ID, &e.Template, &e.Recipient, &e.Subject, &e.Body,
+        &e.TransactionID, &e.CustomerID, &e.EventKey,
+        &e.AttachKuitansi, &e.Status, &e.Attempts, &e.LastError,
+        &e.SentAt, &e.CreatedAt,
+    )
+    return e, err
+}
+
+func nullableInt(v int) interface{} {
+    if v == 0 {
+        return nil
+    }
+    return v
+}
+
+func nullableString(v string) interface{} {
+    if v == "" {
+        return nil
+    }
+    return v
+}
+
+// Enqueue menyimpan email dengan status pending. Jika event_key sudah pernah
+// dicatat untuk template + penerima yang sama, email tidak dibuat ulang (created = false).
+func (r *EmailLogRepository) Enqueue(e *models.EmailLog) (bool, error) {
+    res, err := r.DB.Exec(`
+        INSERT IGNORE INTO email_logs
+        (template, recipient, subject, body, transaction_id, customer_id, event_key, attach_kuitansi, status)
+        VALUES (?, ?, ?, ?, ?, ?, ?, ?, 'pending')`,
+        e.Template, e.Recipient, e.Subject, e.Body,
+        nullableInt(e.TransactionID), nullableInt(e.CustomerID), nullableString(e.EventKey),
+        e.AttachKuitansi,
+    )
+    if err != nil {
+        return false, err
+    }
+    affected, err := res.RowsAffected()
+    if err != nil || affected == 0 {
+        return false, err
+    }
+    id, err := res.LastInsertId()
+    if err != nil {
+        return false, err
+    }
+    e.ID = int(id)
+    e.Status = "pending"
+    return true, nil
+}
+
+func (r *EmailLogRepository) GetByID(id int) (*models.EmailLog, error) {
+    e, err := scanEmailLog(r.DB.QueryRow("SELECT "+emailLogColumns+" FROM email_logs WHERE id = ?", id))
+    if err != nil {
+        return nil, err
+    }
+    return &e, nil
+}
+
+// claimableEmail adalah email yang boleh diambil pengirim: pending/failed, atau "sending" yang
+// tertinggal lebih dari 15 menit (proses pengirimnya mati sebelum sempat menandai hasil)
+const claimableEmail = `(status IN ('pending', 'failed')
+    OR (status = 'sending' AND updated_at < NOW() - INTERVAL 15 MINUTE))`
+
+// GetPending mengambil email yang belum terkirim dan masih boleh dicoba ulang
+func (r *EmailLogRepository) GetPending(maxAttempts int) ([]models.EmailLog, error) {
+    return r.query(
+        "SELECT "+emailLogColumns+" FROM email_logs WHERE "+claimableEmail+" AND attempts < ? ORDER BY id ASC",
+        maxAttempts,
+    )
+}
+
+// Claim menandai email sedang dikirim. Hanya satu pengirim (job atau request) yang mendapat
+// claimed = true, sehingga email yang sama tidak terkirim dua kali.
+func (r *EmailLogRepository) Claim(id, maxAttempts int) (bool, error) {
+    res, err := r.DB.Exec(
+        "UPDATE email_logs SET status = 'sending' WHERE id = ? AND "+claimableEmail+" AND attempts < ?",
+        id, maxAttempts,
+    )
+    if err != nil {
+        return false, err
+    }
+    affected, err := res.RowsAffected()
+    return affected == 1, err
+}
+
+func (r *EmailLogRepository) GetAll(status string, limit int) ([]models.EmailLog, error) {
+    query := "SELECT " + emailLogColumns + " FROM email_logs"
+    args := []interface{}{}
+    if status != "" {
+        query += " WHERE status = ?"
+        args = append(args, status)
+    }
+    query += " ORDER BY id DESC LIMIT ?"
+    args = append(args, limit)
+    return r.query(query, args...)
+}
+
+func (r *EmailLogRepository) query(query string, args ...interface{}) ([]models.EmailLog, error) {
+    rows, err := r.DB.Query(query, args...)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    logs := []models.EmailLog{}
+    for rows.Next() {
+        e, err := scanEmailLog(rows)
+        if err != nil {
+            return nil, err
+        }
+        logs = append(logs, e)
+    }
+    return logs, nil
+}
+
+func (r *EmailLogRepository) MarkSent(id int) error {
+    _, err := r.DB.Exec(
+        "UPDATE email_logs SET status = 'sent', attempts = attempts + 1, last_error = NULL, sent_at = NOW() WHERE id = ?",
+        id,
+    )
+    return err
+}
+
+func (r *EmailLogRepository) MarkFailed(id int, errMessage string) error {
+    _, err := r.DB.Exec(
+        "UPDATE email_logs SET status = 'failed', attempts = attempts + 1, last_error = ? WHERE id = ?",
+        errMessage, id,
+    )
+    return err
+}
+
+// ResetAttempts dipakai untuk retry manual setelah batas percobaan habis. Hanya email pending/failed
+// yang direset; email yang sedang dikirim (sending) tidak dilepas supaya tidak terkirim dua kali.
+// sql.ErrNoRows berarti tidak ada yang direset.
+func (r *EmailLogRepository) ResetAttempts(id int) error {
+    res, err := r.DB.Exec("UPDATE email_logs SET status = 'pending', attempts = 0 WHERE id = ? AND status IN ('pending', 'failed')", id)
+    if err != nil {
+        return err
+    }
+    if n, _ := res.RowsAffected(); n == 0 {
+        return sql.ErrNoRows
+    }
+    return nil
+}
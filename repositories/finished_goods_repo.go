@@ -0,0 +1,692 @@
+package repositories
+
+import (
+    "database/sql"
+    "fmt"
+    "konveksi-app/models"
+    "konveksi-app/sizing"
+    "sort"
+    "strings"
+
+    "github.com/go-sql-driver/mysql"
+)
+
+type FinishedGoodsRepository struct {
+    DB *sql.DB
+}
+
+// FinishedGoodsInput adalah barang jadi yang masuk dari produksi
+type FinishedGoodsInput struct {
+    CatalogID     int
+    Size          string
+    Quantity      int
+    TransactionID int // transaksi/SPK asal produksi, boleh 0
+    Reference     string
+    PerformedBy   string
+}
+
+// StockCountLine adalah hasil hitung fisik satu seragam dan ukuran
+type StockCountLine struct {
+    CatalogID int    `json:"catalog_id"`
+    Size      string `json:"size"`
+    Counted   int    `json:"counted"`
+}
+
+// ReservationLine adalah permintaan menyisihkan stok untuk transaksi
+type ReservationLine struct {
+    CatalogID int    `json:"catalog_id"`
+    Size      string `json:"size"`
+    Quantity  int    `json:"quantity"`
+}
+
+// orderLine adalah jumlah pesanan per seragam dan ukuran, dipakai saat pesanan mengambil dari stok
+type orderLine struct {
+    uniformSize
+    Quantity int
+}
+
+const finishedGoodsColumns = `s.catalog_id, c.name, s.size, s.on_hand,
+    COALESCE((SELECT SUM(fr.quantity) FROM finished_goods_reservations fr
+              WHERE fr.catalog_id = s.catalog_id AND fr.size = s.size), 0),
+    COALESCE(s.updated_at, '')`
+
+func scanFinishedGoods(scanner interface{ Scan(...interface{}) error }) (models.FinishedGoodsStock, error) {
+    var s models.FinishedGoodsStock
+    err := scanner.Scan(&s.CatalogID, &s.CatalogName, &s.Size, &s.OnHand, &s.Reserved, &s.UpdatedAt)
+    s.Available = s.OnHand - s.Reserved
+    return s, err
+}
+
+func sortFinishedGoods(stock []models.FinishedGoodsStock) {
+    sort.SliceStable(stock, func(i, j int) bool {
+        if stock[i].CatalogName != stock[j].CatalogName {
+            return stock[i].CatalogName < stock[j].CatalogName
+        }
+        return sizing.Less(stock[i].Size, stock[j].Size)
+    })
+}
+
+// GetStock mengambil stok barang jadi; catalogID 0 berarti semua seragam
+func (r *FinishedGoodsRepository) GetStock(catalogID int, availableOnly bool) ([]models.FinishedGoodsStock, error) {
+    query := "SELECT " + finishedGoodsColumns + " FROM finished_goods_stock s JOIN uniform_catalog c ON c.id = s.catalog_id"
+    args := []interface{}{}
+    if catalogID != 0 {
+        query += " WHERE s.catalog_id = ?"
+        args = append(args, catalogID)
+    }
+    rows, err := r.DB.Query(query, args...)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    stock := []models.FinishedGoodsStock{}
+    for rows.Next() {
+        s, err := scanFinishedGoods(rows)
+        if err != nil {
+            return nil, err
+        }
+        if availableOnly && s.Available <= 0 {
+            continue
+        }
+        stock = append(stock, s)
+    }
+    if err := rows.Err(); err != nil {
+        return nil, err
+    }
+    sortFinishedGoods(stock)
+    return stock, nil
+}
+
+// catalogForUniform mencari seragam katalog dari daftar harga customer, 0 jika belum dihubungkan
+func catalogForUniform(q queryRower, customerID int, uniformName string) (int, error) {
+    var catalogID int
+    err := q.QueryRow(
+        "SELECT COALESCE(MAX(catalog_id), 0) FROM customer_uniforms WHERE customer_id = ? AND uniform_name = ?",
+        customerID, strings.TrimSpace(uniformName),
+    ).Scan(&catalogID)
+    return catalogID, err
+}
+
+// Availability mengambil stok tersedia untuk seragam customer, ditampilkan saat menambah baris pesanan.
+// catalogID 0 berarti seragam belum dihubungkan ke katalog sehingga tidak punya stok.
+func (r *FinishedGoodsRepository) Availability(customerID int, uniformName string) (int, []models.FinishedGoodsStock, error) {
+    catalogID, err := catalogForUniform(r.DB, customerID, uniformName)
+    if err != nil || catalogID == 0 {
+        return 0, []models.FinishedGoodsStock{}, err
+    }
+    stock, err := r.GetStock(catalogID, false)
+    return catalogID, stock, err
+}
+
+// lockFinishedGoods mengunci baris stok; create membuat baris kosong bila belum ada
+func lockFinishedGoods(tx *sql.Tx, catalogID int, size string, create bool) (onHand int, catalogName string, err error) {
+    if create {
+        _, err = tx.Exec(
+            "INSERT INTO finished_goods_stock (catalog_id, size, on_hand) VALUES (?, ?, 0) ON DUPLICATE KEY UPDATE id = id",
+            catalogID, size,
+        )
+        if mysqlErr, ok := err.(*mysql.MySQLError); ok && mysqlErr.Number == 1452 {
+            return 0, "", fmt.Errorf("seragam katalog %d tidak ditemukan", catalogID)
+        } else if err != nil {
+            return 0, "", err
+        }
+    }
+    err = tx.QueryRow(`
+        SELECT s.on_hand, c.name
+        FROM finished_goods_stock s
+        JOIN uniform_catalog c ON c.id = s.catalog_id
+        WHERE s.catalog_id = ? AND s.size = ?
+        FOR UPDATE`,
+        catalogID, size,
+    ).Scan(&onHand, &catalogName)
+    if err == sql.ErrNoRows && !create {
+        return 0, "", nil
+    }
+    return onHand, catalogName, err
+}
+
+// recordFinishedGoodsMovement menyimpan saldo baru (BalanceAfter) dan mencatat mutasinya
+func recordFinishedGoodsMovement(tx *sql.Tx, mv *models.FinishedGoodsMovement) error {
+    _, err := tx.Exec(
+        "UPDATE finished_goods_stock SET on_hand = ? WHERE catalog_id = ? AND size = ?",
+        mv.BalanceAfter, mv.CatalogID, mv.Size,
+    )
+    if err != nil {
+        return err
+    }
+    res, err := tx.Exec(
+        `INSERT INTO finished_goods_movements (catalog_id, size, type, quantity, balance_after, transaction_id, reference, reason, performed_by)
+         VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+        mv.CatalogID, mv.Size, mv.Type, mv.Quantity, mv.BalanceAfter, nullableInt(mv.TransactionID),
+        nullableString(mv.Reference), nullableString(mv.Reason), nullableString(mv.PerformedBy),
+    )
+    if err != nil {
+        return err
+    }
+    id, _ := res.LastInsertId()
+    mv.ID = int(id)
+    return nil
+}
+
+// Receive mencatat barang jadi hasil produksi yang masuk gudang
+func (r *FinishedGoodsRepository) Receive(in FinishedGoodsInput) (*models.FinishedGoodsMovement, error) {
+    in.Size = strings.TrimSpace(in.Size)
+    if in.CatalogID == 0 || in.Size == "" {
+        return nil, fmt.Errorf("seragam dan ukuran wajib diisi")
+    }
+    if in.Quantity <= 0 {
+        return nil, fmt.Errorf("jumlah harus lebih dari 0")
+    }
+
+    tx, err := r.DB.Begin()
+    if err != nil {
+        return nil, err
+    }
+    defer tx.Rollback()
+
+    onHand, name, err := lockFinishedGoods(tx, in.CatalogID, in.Size, true)
+    if err != nil {
+        return nil, err
+    }
+    mv := models.FinishedGoodsMovement{
+        CatalogID:     in.CatalogID,
+        CatalogName:   name,
+        Size:          in.Size,
+        Type:          "receipt",
+        Quantity:      in.Quantity,
+        BalanceAfter:  onHand + in.Quantity,
+        TransactionID: in.TransactionID,
+        Reference:     strings.TrimSpace(in.Reference),
+        PerformedBy:   in.PerformedBy,
+    }
+    if err := recordFinishedGoodsMovement(tx, &mv); err != nil {
+        return nil, err
+    }
+    if err := tx.Commit(); err != nil {
+        return nil, err
+    }
+    return &mv, nil
+}
+
+// Count menyimpan hasil stock opname; selisih dengan saldo sistem dicatat sebagai mutasi count
+func (r *FinishedGoodsRepository) Count(lines []StockCountLine, reason, performedBy string) ([]models.FinishedGoodsMovement, error) {
+    if len(lines) == 0 {
+        return nil, fmt.Errorf("hasil hitung stok kosong")
+    }
+    reason = strings.TrimSpace(reason)
+    if reason == "" {
+        reason = "stock opname"
+    }
+    unique := map[string]bool{}
+    for i := range lines {
+        lines[i].Size = strings.TrimSpace(lines[i].Size)
+        if lines[i].CatalogID == 0 || lines[i].Size == "" {
+            return nil, fmt.Errorf("baris %d: seragam dan ukuran wajib diisi", i+1)
+        }
+        if lines[i].Counted < 0 {
+            return nil, fmt.Errorf("baris %d: hasil hitung tidak boleh negatif", i+1)
+        }
+        key := fmt.Sprintf("%d|%s", lines[i].CatalogID, strings.ToLower(lines[i].Size))
+        if unique[key] {
+            return nil, fmt.Errorf("baris %d: seragam dan ukuran yang sama dobel", i+1)
+        }
+        unique[key] = true
+    }
+
+    tx, err := r.DB.Begin()
+    if err != nil {
+        return nil, err
+    }
+    defer tx.Rollback()
+
+    movements := []models.FinishedGoodsMovement{}
+    for i, l := range lines {
+        onHand, name, err := lockFinishedGoods(tx, l.CatalogID, l.Size, true)
+        if err != nil {
+            return nil, fmt.Errorf("baris %d: %v", i+1, err)
+        }
+        if l.Counted == onHand {
+            continue
+        }
+        mv := models.FinishedGoodsMovement{
+            CatalogID:    l.CatalogID,
+            CatalogName:  name,
+            Size:         l.Size,
+            Type:         "count",
+            Quantity:     l.Counted - onHand,
+            BalanceAfter: l.Counted,
+            Reason:       reason,
+            PerformedBy:  performedBy,
+        }
+        if err := recordFinishedGoodsMovement(tx, &mv); err != nil {
+            return nil, err
+        }
+        movements = append(movements, mv)
+    }
+    if err := tx.Commit(); err != nil {
+        return nil, err
+    }
+    return movements, nil
+}
+
+// reserveStock menyisihkan stok untuk transaksi. partial mengambil sebanyak yang tersedia
+// (dipakai saat pesanan dibuat); tanpa partial kekurangan stok menjadi error.
+func reserveStock(tx *sql.Tx, transactionID, catalogID int, size string, quantity int, partial bool) (int, error) {
+    onHand, name, err := lockFinishedGoods(tx, catalogID, size, false)
+    if err != nil {
+        return 0, err
+    }
+    var reserved int
+    err = tx.QueryRow(
+        "SELECT COALESCE(SUM(quantity), 0) FROM finished_goods_reservations WHERE catalog_id = ? AND size = ?",
+        catalogID, size,
+    ).Scan(&reserved)
+    if err != nil {
+        return 0, err
+    }
+    available := onHand - reserved
+    if quantity > available {
+        if !partial {
+            if available < 0 {
+                available = 0
+            }
+            return 0, fmt.Errorf("stok %s ukuran %s hanya tersedia %d", name, size, available)
+        }
+        quantity = available
+    }
+    if quantity <= 0 {
+        return 0, nil
+    }
+    _, err = tx.Exec(
+        `INSERT INTO finished_goods_reservations (transaction_id, catalog_id, size, quantity) VALUES (?, ?, ?, ?)
+         ON DUPLICATE KEY UPDATE quantity = quantity + VALUES(quantity)`,
+        transactionID, catalogID, size, quantity,
+    )
+    return quantity, err
+}
+
+// reserveOrderLines mengambil dari stok sebanyak yang tersedia untuk setiap baris pesanan;
+// sisanya tetap dibuat (make-to-order)
+func reserveOrderLines(tx *sql.Tx, transactionID, customerID int, lines []orderLine) error {
+    totals := map[uniformSize]int{}
+    var keys []uniformSize
+    for _, l := range lines {
+        key := uniformSize{strings.TrimSpace(l.Uniform), strings.TrimSpace(l.Size)}
+        if _, ok := totals[key]; !ok {
+            keys = append(keys, key)
+        }
+        totals[key] += l.Quantity
+    }
+
+    catalogs := map[string]int{}
+    for _, key := range keys {
+        catalogID, ok := catalogs[key.Uniform]
+        if !ok {
+            var err error
+            if catalogID, err = catalogForUniform(tx, customerID, key.Uniform); err != nil {
+                return err
+            }
+            catalogs[key.Uniform] = catalogID
+        }
+        if catalogID == 0 || key.Size == "" {
+            continue
+        }
+        if _, err := reserveStock(tx, transactionID, catalogID, key.Size, totals[key], true); err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+func checkReservableTransaction(tx *sql.Tx, transactionID int) (customerID int, err error) {
+    var status, stage string
+    err = tx.QueryRow("SELECT customer_id, status, production_stage FROM transactions WHERE id = ? FOR UPDATE", transactionID).
+        Scan(&customerID, &status, &stage)
+    if err != nil {
+        return 0, err
+    }
+    if status == "cancelled" {
+        return 0, fmt.Errorf("transaksi sudah dibatalkan")
+    }
+    if stage == "diserahkan" {
+        return 0, fmt.Errorf("transaksi sudah diserahkan")
+    }
+    return customerID, nil
+}
+
+// catalogSize adalah kunci stok barang jadi; ukuran disimpan huruf kecil karena kolasi case-insensitive
+type catalogSize struct {
+    CatalogID int
+    Size      string
+}
+
+func newCatalogSize(catalogID int, size string) catalogSize {
+    return catalogSize{catalogID, strings.ToLower(strings.TrimSpace(size))}
+}
+
+// orderedByCatalog menjumlahkan item transaksi (biasa dan siswa) per seragam katalog dan ukuran.
+// Seragam yang belum dihubungkan ke katalog tidak ikut karena tidak bisa diambil dari stok.
+func orderedByCatalog(tx *sql.Tx, transactionID, customerID int) (map[catalogSize]int, error) {
+    rows, err := tx.Query(`
+        SELECT uniform_name, size, SUM(quantity) FROM (
+            SELECT TRIM(uniform_name) AS uniform_name, TRIM(size) AS size, quantity FROM order_items WHERE transaction_id = ?
+            UNION ALL
+            SELECT TRIM(uniform_name), TRIM(size), quantity FROM student_order_items WHERE transaction_id = ?
+        ) i
+        GROUP BY uniform_name, size`,
+        transactionID, transactionID,
+    )
+    if err != nil {
+        return nil, err
+    }
+    var lines []orderLine
+    for rows.Next() {
+        var l orderLine
+        if err := rows.Scan(&l.Uniform, &l.Size, &l.Quantity); err != nil {
+            rows.Close()
+            return nil, err
+        }
+        lines = append(lines, l)
+    }
+    rows.Close()
+    if err := rows.Err(); err != nil {
+        return nil, err
+    }
+
+    ordered := map[catalogSize]int{}
+    catalogs := map[string]int{}
+    for _, l := range lines {
+        catalogID, ok := catalogs[l.Uniform]
+        if !ok {
+            if catalogID, err = catalogForUniform(tx, customerID, l.Uniform); err != nil {
+                return nil, err
+            }
+            catalogs[l.Uniform] = catalogID
+        }
+        if catalogID != 0 {
+            ordered[newCatalogSize(catalogID, l.Size)] += l.Quantity
+        }
+    }
+    return ordered, nil
+}
+
+// reservedByTransaction mengambil stok yang sudah disisihkan untuk transaksi
+func reservedByTransaction(tx *sql.Tx, transactionID int) (map[catalogSize]ReservationLine, error) {
+    rows, err := tx.Query(
+        "SELECT catalog_id, size, quantity FROM finished_goods_reservations WHERE transaction_id = ?",
+        transactionID,
+    )
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    reserved := map[catalogSize]ReservationLine{}
+    for rows.Next() {
+        var l ReservationLine
+        if err := rows.Scan(&l.CatalogID, &l.Size, &l.Quantity); err != nil {
+            return nil, err
+        }
+        reserved[newCatalogSize(l.CatalogID, l.Size)] = l
+    }
+    return reserved, rows.Err()
+}
+
+// syncReservations menyesuaikan stok yang disisihkan setelah item transaksi diubah: sisihan yang
+// melebihi jumlah pesanan dikurangi, dan dihapus jika seragam/ukurannya tidak lagi dipesan.
+// Sisihan tidak ditambah otomatis; tambahan diambil lewat Reserve.
+func syncReservations(tx *sql.Tx, transactionID, customerID int) error {
+    reserved, err := reservedByTransaction(tx, transactionID)
+    if err != nil || len(reserved) == 0 {
+        return err
+    }
+    ordered, err := orderedByCatalog(tx, transactionID, customerID)
+    if err != nil {
+        return err
+    }
+    for key, l := range reserved {
+        want := ordered[key]
+        switch {
+        case want <= 0:
+            _, err = tx.Exec(
+                "DELETE FROM finished_goods_reservations WHERE transaction_id = ? AND catalog_id = ? AND size = ?",
+                transactionID, l.CatalogID, l.Size,
+            )
+        case want < l.Quantity:
+            _, err = tx.Exec(
+                "UPDATE finished_goods_reservations SET quantity = ? WHERE transaction_id = ? AND catalog_id = ? AND size = ?",
+                want, transactionID, l.CatalogID, l.Size,
+            )
+        }
+        if err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+// Reserve menyisihkan stok untuk transaksi yang sudah ada. Setiap baris harus seragam/ukuran yang
+// dipesan, dan total sisihan tidak boleh melebihi jumlah pesanan.
+func (r *FinishedGoodsRepository) Reserve(transactionID int, lines []ReservationLine) error {
+    for i := range lines {
+        lines[i].Size = strings.TrimSpace(lines[i].Size)
+        if lines[i].CatalogID == 0 || lines[i].Size == "" {
+            return fmt.Errorf("baris %d: seragam dan ukuran wajib diisi", i+1)
+        }
+        if lines[i].Quantity <= 0 {
+            return fmt.Errorf("baris %d: jumlah harus lebih dari 0", i+1)
+        }
+    }
+
+    tx, err := r.DB.Begin()
+    if err != nil {
+        return err
+    }
+    defer tx.Rollback()
+
+    customerID, err := checkReservableTransaction(tx, transactionID)
+    if err != nil {
+        return err
+    }
+    ordered, err := orderedByCatalog(tx, transactionID, customerID)
+    if err != nil {
+        return err
+    }
+    reserved, err := reservedByTransaction(tx, transactionID)
+    if err != nil {
+        return err
+    }
+    requested := map[catalogSize]int{}
+    for i, l := range lines {
+        key := newCatalogSize(l.CatalogID, l.Size)
+        want, ok := ordered[key]
+        if !ok {
+            return fmt.Errorf("baris %d: seragam/ukuran %s tidak ada di pesanan ini", i+1, l.Size)
+        }
+        requested[key] += l.Quantity
+        if already := reserved[key].Quantity; already+requested[key] > want {
+            return fmt.Errorf("baris %d: ukuran %s dipesan %d, sudah disisihkan %d, tidak bisa menyisihkan %d lagi",
+                i+1, l.Size, want, already, requested[key])
+        }
+    }
+    for i, l := range lines {
+        if _, err := reserveStock(tx, transactionID, l.CatalogID, l.Size, l.Quantity, false); err != nil {
+            return fmt.Errorf("baris %d: %v", i+1, err)
+        }
+    }
+    return tx.Commit()
+}
+
+// GetReservations mengambil stok yang disisihkan untuk transaksi
+func (r *FinishedGoodsRepository) GetReservations(transactionID int) ([]models.StockReservation, error) {
+    rows, err := r.DB.Query(`
+        SELECT fr.id, fr.transaction_id, fr.catalog_id, c.name, fr.size, fr.quantity, fr.created_at
+        FROM finished_goods_reservations fr
+        JOIN uniform_catalog c ON c.id = fr.catalog_id
+        WHERE fr.transaction_id = ?`,
+        transactionID,
+    )
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    reservations := []models.StockReservation{}
+    for rows.Next() {
+        var res models.StockReservation
+        err := rows.Scan(&res.ID, &res.TransactionID, &res.CatalogID, &res.CatalogName, &res.Size, &res.Quantity, &res.CreatedAt)
+        if err != nil {
+            return nil, err
+        }
+        reservations = append(reservations, res)
+    }
+    if err := rows.Err(); err != nil {
+        return nil, err
+    }
+    sort.SliceStable(reservations, func(i, j int) bool {
+        if reservations[i].CatalogName != reservations[j].CatalogName {
+            return reservations[i].CatalogName < reservations[j].CatalogName
+        }
+        return sizing.Less(reservations[i].Size, reservations[j].Size)
+    })
+    return reservations, nil
+}
+
+// releaseReservations melepas seluruh stok yang disisihkan untuk transaksi
+func releaseReservations(q interface {
+    Exec(query string, args ...interface{}) (sql.Result, error)
+}, transactionID int) error {
+    _, err := q.Exec("DELETE FROM finished_goods_reservations WHERE transaction_id = ?", transactionID)
+    return err
+}
+
+// ReleaseReservations - stok yang disisihkan kembali tersedia untuk pesanan lain
+func (r *FinishedGoodsRepository) ReleaseReservations(transactionID int) error {
+    return releaseReservations(r.DB, transactionID)
+}
+
+// issueReservations mengeluarkan stok yang disisihkan saat pesanan diserahkan ke customer
+func issueReservations(tx *sql.Tx, transactionID int, performedBy string) ([]models.FinishedGoodsMovement, error) {
+    rows, err := tx.Query(
+        "SELECT catalog_id, size, quantity FROM finished_goods_reservations WHERE transaction_id = ?",
+        transactionID,
+    )
+    if err != nil {
+        return nil, err
+    }
+    var reservations []ReservationLine
+    for rows.Next() {
+        var l ReservationLine
+        if err := rows.Scan(&l.CatalogID, &l.Size, &l.Quantity); err != nil {
+            rows.Close()
+            return nil, err
+        }
+        reservations = append(reservations, l)
+    }
+    rows.Close()
+    if err := rows.Err(); err != nil {
+        return nil, err
+    }
+
+    movements := []models.FinishedGoodsMovement{}
+    for _, l := range reservations {
+        onHand, name, err := lockFinishedGoods(tx, l.CatalogID, l.Size, true)
+        if err != nil {
+            return nil, err
+        }
+        if onHand < l.Quantity {
+            return nil, fmt.Errorf("stok %s ukuran %s tinggal %d, tidak cukup untuk menyerahkan %d", name, l.Size, onHand, l.Quantity)
+        }
+        mv := models.FinishedGoodsMovement{
+            CatalogID:     l.CatalogID,
+            CatalogName:   name,
+            Size:          l.Size,
+            Type:          "issue",
+            Quantity:      -l.Quantity,
+            BalanceAfter:  onHand - l.Quantity,
+            TransactionID: transactionID,
+            Reference:     fmt.Sprintf("Transaksi #%d", transactionID),
+            PerformedBy:   performedBy,
+        }
+        if err := recordFinishedGoodsMovement(tx, &mv); err != nil {
+            return nil, err
+        }
+        movements = append(movements, mv)
+    }
+    return movements, releaseReservations(tx, transactionID)
+}
+
+// FinishedGoodsMovementFilter untuk buku mutasi barang jadi; nilai kosong berarti semua
+type FinishedGoodsMovementFilter struct {
+    CatalogID     int
+    Size          string
+    TransactionID int
+    Type          string
+    From          string
+    To            string
+    Limit         int
+}
+
+// GetMovements mengambil buku mutasi barang jadi, terbaru dulu
+func (r *FinishedGoodsRepository) GetMovements(f FinishedGoodsMovementFilter) ([]models.FinishedGoodsMovement, error) {
+    where := []string{"1 = 1"}
+    args := []interface{}{}
+    if f.CatalogID != 0 {
+        where = append(where, "fm.catalog_id = ?")
+        args = append(args, f.CatalogID)
+    }
+    if f.Size != "" {
+        where = append(where, "fm.size = ?")
+        args = append(args, f.Size)
+    }
+    if f.TransactionID != 0 {
+        where = append(where, "fm.transaction_id = ?")
+        args = append(args, f.TransactionID)
+    }
+    if f.Type != "" {
+        where = append(where, "fm.type = ?")
+        args = append(args, f.Type)
+    }
+    if f.From != "" {
+        where = append(where, "fm.created_at >= ?")
+        args = append(args, f.From)
+    }
+    if f.To != "" {
+        where = append(where, "fm.created_at < DATE_ADD(?, INTERVAL 1 DAY)")
+        args = append(args, f.To)
+    }
+    limit := f.Limit
+    if limit <= 0 || limit > 1000 {
+        limit = 200
+    }
+    args = append(args, limit)
+
+    rows, err := r.DB.Query(`
+        SELECT fm.id, fm.catalog_id, c.name, fm.size, fm.type, fm.quantity, fm.balance_after,
+               COALESCE(fm.transaction_id, 0), COALESCE(fm.reference, ''), COALESCE(fm.reason, ''),
+               COALESCE(fm.performed_by, ''), fm.created_at
+        FROM finished_goods_movements fm
+        JOIN uniform_catalog c ON c.id = fm.catalog_id
+        WHERE `+strings.Join(where, " AND ")+`
+        ORDER BY fm.created_at DESC, fm.id DESC
+        LIMIT ?`,
+        args...,
+    )
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    movements := []models.FinishedGoodsMovement{}
+    for rows.Next() {
+        var mv models.FinishedGoodsMovement
+        err := rows.Scan(&mv.ID, &mv.CatalogID, &mv.CatalogName, &mv.Size, &mv.Type, &mv.Quantity, &mv.BalanceAfter,
+            &mv.TransactionID, &mv.Reference, &mv.Reason, &mv.PerformedBy, &mv.CreatedAt)
+        if err != nil {
+            return nil, err
+        }
+        movements = append(movements, mv)
+    }
+    return movements, rows.Err()
+}
@@ -0,0 +1,116 @@
+package repositories
+
+import (
+    "strings"
+    "testing"
+
+    "github.com/DATA-DOG/go-sqlmock"
+)
+
+// expectOrdered menyiapkan orderedByCatalog: satu seragam "Kemeja" (katalog 2) dengan jumlah per ukuran
+func expectOrdered(mock sqlmock.Sqlmock, sizes map[string]int) {
+    rows := sqlmock.NewRows([]string{"uniform_name", "size", "quantity"})
+    for size, qty := range sizes {
+        rows.AddRow("Kemeja", size, qty)
+    }
+    mock.ExpectQuery("FROM order_items WHERE transaction_id = \\?\\s+UNION ALL").WithArgs(7, 7).WillReturnRows(rows)
+    mock.ExpectQuery("SELECT COALESCE\\(MAX\\(catalog_id\\), 0\\) FROM customer_uniforms").WithArgs(3, "Kemeja").
+        WillReturnRows(sqlmock.NewRows([]string{"catalog_id"}).AddRow(2))
+}
+
+func expectReservable(mock sqlmock.Sqlmock) {
+    mock.ExpectBegin()
+    mock.ExpectQuery("SELECT customer_id, status, production_stage FROM transactions WHERE id = \\? FOR UPDATE").WithArgs(7).
+        WillReturnRows(sqlmock.NewRows([]string{"customer_id", "status", "production_stage"}).AddRow(3, "pending", "antri"))
+}
+
+func TestReserveRejectsLinesOutsideOrder(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer db.Close()
+    repo := &FinishedGoodsRepository{DB: db}
+
+    expectReservable(mock)
+    expectOrdered(mock, map[string]int{"M": 5})
+    mock.ExpectQuery("FROM finished_goods_reservations WHERE transaction_id").WithArgs(7).
+        WillReturnRows(sqlmock.NewRows([]string{"catalog_id", "size", "quantity"}))
+    mock.ExpectRollback()
+
+    err = repo.Reserve(7, []ReservationLine{{CatalogID: 2, Size: "XL", Quantity: 1}})
+    if err == nil || !strings.Contains(err.Error(), "tidak ada di pesanan") {
+        t.Fatalf("err = %v, want size outside order rejected", err)
+    }
+    if err := mock.ExpectationsWereMet(); err != nil {
+        t.Fatal(err)
+    }
+}
+
+func TestReserveRejectsMoreThanOrdered(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer db.Close()
+    repo := &FinishedGoodsRepository{DB: db}
+
+    expectReservable(mock)
+    expectOrdered(mock, map[string]int{"M": 5})
+    mock.ExpectQuery("FROM finished_goods_reservations WHERE transaction_id").WithArgs(7).
+        WillReturnRows(sqlmock.NewRows([]string{"catalog_id", "size", "quantity"}).AddRow(2, "M", 3))
+    mock.ExpectRollback()
+
+    // Dua baris yang sama dijumlahkan: 3 sudah disisihkan + 1 + 2 > 5
+    err = repo.Reserve(7, []ReservationLine{{CatalogID: 2, Size: "m", Quantity: 1}, {CatalogID: 2, Size: "M", Quantity: 2}})
+    if err == nil || !strings.Contains(err.Error(), "baris 2") {
+        t.Fatalf("err = %v, want second line rejected", err)
+    }
+    if err := mock.ExpectationsWereMet(); err != nil {
+        t.Fatal(err)
+    }
+}
+
+func TestSyncReservationsShrinksToOrder(t *testing.T) {
+    db, mock, tx := beginMock(t)
+    defer db.Close()
+
+    mock.ExpectQuery("FROM finished_goods_reservations WHERE transaction_id").WithArgs(7).
+        WillReturnRows(sqlmock.NewRows([]string{"catalog_id", "size", "quantity"}).
+            AddRow(2, "M", 4).AddRow(2, "L", 2).AddRow(2, "S", 1))
+    // M turun ke 3, L tidak dipesan lagi, S tetap
+    expectOrdered(mock, map[string]int{"M": 3, "S": 6})
+    mock.ExpectExec("UPDATE finished_goods_reservations SET quantity").WithArgs(3, 7, 2, "M").WillReturnResult(sqlmock.NewResult(0, 1))
+    mock.ExpectExec("DELETE FROM finished_goods_reservations WHERE transaction_id = \\? AND catalog_id").WithArgs(7, 2, "L").
+        WillReturnResult(sqlmock.NewResult(0, 1))
+    mock.MatchExpectationsInOrder(false)
+
+    if err := syncReservations(tx, 7, 3); err != nil {
+        t.Fatal(err)
+    }
+    if err := mock.ExpectationsWereMet(); err != nil {
+        t.Fatal(err)
+    }
+}
+
+func TestUpdateStatusCancelReleasesInSameTransaction(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer db.Close()
+    repo := &TransactionRepository{DB: db}
+
+    mock.ExpectBegin()
+    mock.ExpectExec("UPDATE transactions SET status").WithArgs("cancelled", 7).WillReturnResult(sqlmock.NewResult(0, 1))
+    mock.ExpectExec("DELETE FROM finished_goods_reservations WHERE transaction_id").WithArgs(7).
+        WillReturnError(sqlmock.ErrCancelled)
+    mock.ExpectRollback()
+
+    if err := repo.UpdateStatus(7, "cancelled"); err == nil {
+        t.Fatal("want error when releasing reservations fails")
+    }
+    if err := mock.ExpectationsWereMet(); err != nil {
+        t.Fatal(err)
+    }
+}
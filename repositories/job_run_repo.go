@@ -0,0 +1,84 @@
+package repositories
+
+import (
+    "database/sql"
+    "konveksi-app/models"
+    "time"
+
+    "github.com/go-sql-driver/mysql"
+)
+
+type JobRunRepository struct {
+    DB *sql.DB
+}
+
+func (r *JobRunRepository) Start(jobName, triggerType string) (int, error) {
+    res, err := r.DB.Exec(
+        "INSERT INTO job_runs (job_name, trigger_type, status, started_at) VALUES (?, ?, 'running', NOW())",
+        jobName, triggerType,
+    )
+    if err != nil {
+        return 0, err
+    }
+    id, err := res.LastInsertId()
+    return int(id), err
+}
+
+// Claim mencatat run untuk satu slot jadwal. claimed false berarti slot itu sudah
+// dijalankan (atau sedang dijalankan) instance lain.
+func (r *JobRunRepository) Claim(jobName string, scheduledAt time.Time) (id int, claimed bool, err error) {
+    res, err := r.DB.Exec(
+        "INSERT INTO job_runs (job_name, trigger_type, status, scheduled_at, started_at) VALUES (?, 'schedule', 'running', ?, NOW())",
+        jobName, scheduledAt.Format("2006-01-02 15:04:05"),
+    )
+    if mysqlErr, ok := err.(*mysql.MySQLError); ok && mysqlErr.Number == 1062 {
+        return 0, false, nil
+    } else if err != nil {
+        return 0, false, err
+    }
+    lastID, err := res.LastInsertId()
+    return int(lastID), true, err
+}
+
+func (r *JobRunRepository) Finish(id int, status, message string) error {
+    _, err := r.DB.Exec(
+        "UPDATE job_runs SET status = ?, message = ?, finished_at = NOW() WHERE id = ?",
+        status, message, id,
+    )
+    return err
+}
+
+func (r *JobRunRepository) GetLastRun(jobName string) (*models.JobRun, error) {
+    var run models.JobRun
+    err := r.DB.QueryRow(`
+        SELECT id, job_name, trigger_type, status, COALESCE(message, ''), COALESCE(scheduled_at, ''), started_at, COALESCE(finished_at, '')
+        FROM job_runs WHERE job_name = ?
+        ORDER BY started_at DESC, id DESC LIMIT 1`, jobName,
+    ).Scan(&run.ID, &run.JobName, &run.TriggerType, &run.Status, &run.Message, &run.ScheduledAt, &run.StartedAt, &run.FinishedAt)
+    if err != nil {
+        return nil, err
+    }
+    return &run, nil
+}
+
+func (r *JobRunRepository) GetByJobName(jobName string, limit int) ([]models.JobRun, error) {
+    rows, err := r.DB.Query(`
+        SELECT id, job_name, trigger_type, status, COALESCE(message, ''), COALESCE(scheduled_at, ''), started_at, COALESCE(finished_at, '')
+        FROM job_runs WHERE job_name = ?
+        ORDER BY started_at DESC, id DESC LIMIT ?`, jobName, limit,
+    )
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    runs := []models.JobRun{}
+    for rows.Next() {
+        var run models.JobRun
+        if err := rows.Scan(&run.ID, &run.JobName, &run.TriggerType, &run.Status, &run.Message, &run.ScheduledAt, &run.StartedAt, &run.FinishedAt); err != nil {
+            return nil, err
+        }
+        runs = append(runs, run)
+    }
+    return runs, nil
+}
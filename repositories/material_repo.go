@@ -0,0 +1,239 @@
+package repositories
+
+import (
+    "database/sql"
+    "fmt"
+    "konveksi-app/models"
+    "konveksi-app/sizing"
+    "sort"
+    "strings"
+
+    "github.com/go-sql-driver/mysql"
+)
+
+type MaterialRepository struct {
+    DB *sql.DB
+}
+
+var materialCategories = map[string]bool{
+    "fabric": true, "button": true, "zipper": true, "label": true, "thread": true, "other": true,
+}
+
+const materialColumns = `id, name, category, unit, reorder_level, stock, COALESCE(notes, ''), active,
+    created_at, COALESCE(updated_at, created_at)`
+
+func scanMaterial(scanner interface{ Scan(...interface{}) error }) (models.Material, error) {
+    var m models.Material
+    err := scanner.Scan(&m.ID, &m.Name, &m.Category, &m.Unit, &m.ReorderLevel, &m.Stock, &m.Notes, &m.Active,
+        &m.CreatedAt, &m.UpdatedAt)
+    m.LowStock = m.Active && m.ReorderLevel > 0 && m.Stock <= m.ReorderLevel
+    return m, err
+}
+
+// GetAll mengambil daftar bahan; lowStockOnly hanya bahan aktif yang stoknya di bawah batas pemesanan ulang
+func (r *MaterialRepository) GetAll(includeInactive, lowStockOnly bool) ([]models.Material, error) {
+    where := []string{"1 = 1"}
+    if !includeInactive || lowStockOnly {
+        where = append(where, "active = 1")
+    }
+    if lowStockOnly {
+        where = append(where, "reorder_level > 0 AND stock <= reorder_level")
+    }
+    rows, err := r.DB.Query("SELECT " + materialColumns + " FROM materials WHERE " + strings.Join(where, " AND ") + " ORDER BY category, name")
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    materials := []models.Material{}
+    for rows.Next() {
+        m, err := scanMaterial(rows)
+        if err != nil {
+            return nil, err
+        }
+        materials = append(materials, m)
+    }
+    return materials, rows.Err()
+}
+
+func (r *MaterialRepository) GetByID(id int) (*models.Material, error) {
+    m, err := scanMaterial(r.DB.QueryRow("SELECT "+materialColumns+" FROM materials WHERE id = ?", id))
+    if err != nil {
+        return nil, err
+    }
+    return &m, nil
+}
+
+func validateMaterial(m *models.Material) error {
+    m.Name = strings.Join(strings.Fields(m.Name), " ")
+    m.Unit = strings.TrimSpace(m.Unit)
+    m.Category = strings.ToLower(strings.TrimSpace(m.Category))
+    if m.Category == "" {
+        m.Category = "other"
+    }
+    if m.Name == "" {
+        return fmt.Errorf("nama bahan wajib diisi")
+    }
+    if m.Unit == "" {
+        return fmt.Errorf("satuan bahan wajib diisi")
+    }
+    if m.ReorderLevel < 0 {
+        return fmt.Errorf("batas pemesanan ulang tidak boleh negatif")
+    }
+    if !materialCategories[m.Category] {
+        return fmt.Errorf("kategori bahan '%s' tidak dikenal", m.Category)
+    }
+    return nil
+}
+
+func (r *MaterialRepository) Create(m *models.Material) error {
+    if err := validateMaterial(m); err != nil {
+        return err
+    }
+    res, err := r.DB.Exec(
+        "INSERT INTO materials (name, category, unit, reorder_level, notes, active) VALUES (?, ?, ?, ?, ?, ?)",
+        m.Name, m.Category, m.Unit, m.ReorderLevel, nullableString(m.Notes), m.Active,
+    )
+    if err != nil {
+        return err
+    }
+    id, _ := res.LastInsertId()
+    m.ID = int(id)
+    return nil
+}
+
+func (r *MaterialRepository) Update(m *models.Material) error {
+    if err := validateMaterial(m); err != nil {
+        return err
+    }
+    res, err := r.DB.Exec(
+        "UPDATE materials SET name = ?, category = ?, unit = ?, reorder_level = ?, notes = ?, active = ? WHERE id = ?",
+        m.Name, m.Category, m.Unit, m.ReorderLevel, nullableString(m.Notes), m.Active, m.ID,
+    )
+    if err != nil {
+        return err
+    }
+    if n, _ := res.RowsAffected(); n == 0 {
+        var exists int
+        if err := r.DB.QueryRow("SELECT COUNT(*) FROM materials WHERE id = ?", m.ID).Scan(&exists); err != nil || exists == 0 {
+            return sql.ErrNoRows
+        }
+    }
+    return nil
+}
+
+// Delete menghapus bahan yang belum dipakai di BOM maupun mutasi stok; selebihnya cukup dinonaktifkan
+func (r *MaterialRepository) Delete(id int) error {
+    var used, moved int
+    err := r.DB.QueryRow(
+        "SELECT (SELECT COUNT(*) FROM uniform_bom WHERE material_id = ?), (SELECT COUNT(*) FROM material_movements WHERE material_id = ?)",
+        id, id,
+    ).Scan(&used, &moved)
+    if err != nil {
+        return err
+    }
+    if used > 0 {
+        return fmt.Errorf("bahan masih dipakai di %d baris BOM, nonaktifkan saja", used)
+    }
+    if moved > 0 {
+        return fmt.Errorf("bahan sudah punya mutasi stok, nonaktifkan saja")
+    }
+    res, err := r.DB.Exec("DELETE FROM materials WHERE id = ?", id)
+    if err != nil {
+        return err
+    }
+    if n, _ := res.RowsAffected(); n == 0 {
+        return sql.ErrNoRows
+    }
+    return nil
+}
+
+const bomColumns = `b.id, b.catalog_id, b.size, b.material_id, m.name, m.category, m.unit, b.quantity, COALESCE(b.notes, '')`
+
+// GetBOM mengambil kebutuhan bahan satu seragam katalog: baris umum dulu, lalu per ukuran
+func (r *MaterialRepository) GetBOM(catalogID int) ([]models.BOMLine, error) {
+    rows, err := r.DB.Query(`
+        SELECT `+bomColumns+`
+        FROM uniform_bom b
+        JOIN materials m ON m.id = b.material_id
+        WHERE b.catalog_id = ?`,
+        catalogID,
+    )
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    lines := []models.BOMLine{}
+    for rows.Next() {
+        var l models.BOMLine
+        err := rows.Scan(&l.ID, &l.CatalogID, &l.Size, &l.MaterialID, &l.MaterialName, &l.Category, &l.Unit, &l.Quantity, &l.Notes)
+        if err != nil {
+            return nil, err
+        }
+        lines = append(lines, l)
+    }
+    if err := rows.Err(); err != nil {
+        return nil, err
+    }
+    sort.SliceStable(lines, func(i, j int) bool {
+        if lines[i].Size != lines[j].Size {
+            if lines[i].Size == "" || lines[j].Size == "" {
+                return lines[i].Size == ""
+            }
+            return sizing.Less(lines[i].Size, lines[j].Size)
+        }
+        return lines[i].MaterialName < lines[j].MaterialName
+    })
+    return lines, nil
+}
+
+// SaveBOM mengganti seluruh BOM satu seragam katalog
+func (r *MaterialRepository) SaveBOM(catalogID int, lines []models.BOMLine) error {
+    unique := map[string]bool{}
+    for i := range lines {
+        lines[i].Size = strings.TrimSpace(lines[i].Size)
+        if lines[i].MaterialID == 0 {
+            return fmt.Errorf("baris %d: bahan wajib dipilih", i+1)
+        }
+        if lines[i].Quantity <= 0 {
+            return fmt.Errorf("baris %d: jumlah bahan harus lebih dari 0", i+1)
+        }
+        key := fmt.Sprintf("%s|%d", strings.ToLower(lines[i].Size), lines[i].MaterialID)
+        if unique[key] {
+            return fmt.Errorf("baris %d: bahan yang sama dobel untuk ukuran '%s'", i+1, lines[i].Size)
+        }
+        unique[key] = true
+    }
+
+    var exists int
+    if err := r.DB.QueryRow("SELECT COUNT(*) FROM uniform_catalog WHERE id = ?", catalogID).Scan(&exists); err != nil {
+        return err
+    }
+    if exists == 0 {
+        return sql.ErrNoRows
+    }
+
+    tx, err := r.DB.Begin()
+    if err != nil {
+        return err
+    }
+    defer tx.Rollback()
+
+    if _, err := tx.Exec("DELETE FROM uniform_bom WHERE catalog_id = ?", catalogID); err != nil {
+        return err
+    }
+    for i, l := range lines {
+        _, err := tx.Exec(
+            "INSERT INTO uniform_bom (catalog_id, size, material_id, quantity, notes) VALUES (?, ?, ?, ?, ?)",
+            catalogID, l.Size, l.MaterialID, l.Quantity, nullableString(l.Notes),
+        )
+        if err != nil {
+            if mysqlErr, ok := err.(*mysql.MySQLError); ok && mysqlErr.Number == 1452 {
+                return fmt.Errorf("baris %d: bahan %d tidak ditemukan", i+1, l.MaterialID)
+            }
+            return err
+        }
+    }
+    return tx.Commit()
+}
@@ -0,0 +1,213 @@
+package repositories
+
+import (
+    "database/sql"
+    "fmt"
+    "konveksi-app/models"
+    "math"
+    "strings"
+)
+
+// StockMovementInput adalah mutasi stok bahan yang dicatat manual.
+// Untuk type in/out Quantity selalu positif; untuk adjustment Quantity bertanda (koreksi +/-).
+type StockMovementInput struct {
+    MaterialID  int
+    Type        string
+    Quantity    float64
+    Reference   string // mis. nomor nota pembelian
+    Reason      string
+    PerformedBy string
+}
+
+// applyMovement mengubah saldo bahan dan mencatat mutasinya. delta bertanda.
+// allowNegative dipakai untuk pemakaian produksi: kekurangan stok tetap dicatat supaya terlihat.
+func applyMovement(tx *sql.Tx, mv *models.MaterialMovement, allowNegative bool) error {
+    var stock float64
+    err := tx.QueryRow("SELECT name, unit, stock FROM materials WHERE id = ? FOR UPDATE", mv.MaterialID).
+        Scan(&mv.MaterialName, &mv.Unit, &stock)
+    if err == sql.ErrNoRows {
+        return fmt.Errorf("bahan %d tidak ditemukan", mv.MaterialID)
+    } else if err != nil {
+        return err
+    }
+    mv.Quantity = math.Round(mv.Quantity*1000) / 1000
+    mv.BalanceAfter = math.Round((stock+mv.Quantity)*1000) / 1000
+    if mv.BalanceAfter < 0 && !allowNegative {
+        return fmt.Errorf("stok %s tidak cukup: tersedia %g %s", mv.MaterialName, stock, mv.Unit)
+    }
+
+    if _, err := tx.Exec("UPDATE materials SET stock = ? WHERE id = ?", mv.BalanceAfter, mv.MaterialID); err != nil {
+        return err
+    }
+    res, err := tx.Exec(
+        `INSERT INTO material_movements (material_id, type, quantity, balance_after, transaction_id, reference, reason, performed_by)
+         VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+        mv.MaterialID, mv.Type, mv.Quantity, mv.BalanceAfter, nullableInt(mv.TransactionID),
+        nullableString(mv.Reference), nullableString(mv.Reason), nullableString(mv.PerformedBy),
+    )
+    if err != nil {
+        return err
+    }
+    id, _ := res.LastInsertId()
+    mv.ID = int(id)
+    return nil
+}
+
+// RecordMovement mencatat stok masuk, stok keluar atau penyesuaian (wajib alasan)
+func (r *MaterialRepository) RecordMovement(in StockMovementInput) (*models.MaterialMovement, error) {
+    mv := models.MaterialMovement{
+        MaterialID:  in.MaterialID,
+        Type:        strings.ToLower(strings.TrimSpace(in.Type)),
+        Reference:   strings.TrimSpace(in.Reference),
+        Reason:      strings.TrimSpace(in.Reason),
+        PerformedBy: in.PerformedBy,
+    }
+    switch mv.Type {
+    case "in", "out":
+        if in.Quantity <= 0 {
+            return nil, fmt.Errorf("jumlah harus lebih dari 0")
+        }
+        mv.Quantity = in.Quantity
+        if mv.Type == "out" {
+            mv.Quantity = -in.Quantity
+        }
+    case "adjustment":
+        if in.Quantity == 0 {
+            return nil, fmt.Errorf("jumlah penyesuaian tidak boleh 0")
+        }
+        if mv.Reason == "" {
+            return nil, fmt.Errorf("alasan penyesuaian wajib diisi")
+        }
+        mv.Quantity = in.Quantity
+    default:
+        return nil, fmt.Errorf("jenis mutasi harus in, out atau adjustment")
+    }
+
+    tx, err := r.DB.Begin()
+    if err != nil {
+        return nil, err
+    }
+    defer tx.Rollback()
+
+    if err := applyMovement(tx, &mv, false); err != nil {
+        return nil, err
+    }
+    if err := tx.Commit(); err != nil {
+        return nil, err
+    }
+    return &mv, nil
+}
+
+// MovementFilter untuk buku mutasi; nilai kosong berarti semua
+type MovementFilter struct {
+    MaterialID    int
+    TransactionID int
+    Type          string
+    From          string // tanggal mutasi >= From (YYYY-MM-DD)
+    To            string
+    Limit         int
+}
+
+// GetMovements mengambil buku mutasi, terbaru dulu
+func (r *MaterialRepository) GetMovements(f MovementFilter) ([]models.MaterialMovement, error) {
+    where := []string{"1 = 1"}
+    args := []interface{}{}
+    if f.MaterialID != 0 {
+        where = append(where, "mm.material_id = ?")
+        args = append(args, f.MaterialID)
+    }
+    if f.TransactionID != 0 {
+        where = append(where, "mm.transaction_id = ?")
+        args = append(args, f.TransactionID)
+    }
+    if f.Type != "" {
+        where = append(where, "mm.type = ?")
+        args = append(args, f.Type)
+    }
+    if f.From != "" {
+        where = append(where, "mm.created_at >= ?")
+        args = append(args, f.From)
+    }
+    if f.To != "" {
+        where = append(where, "mm.created_at < DATE_ADD(?, INTERVAL 1 DAY)")
+        args = append(args, f.To)
+    }
+    limit := f.Limit
+    if limit <= 0 || limit > 1000 {
+        limit = 200
+    }
+    args = append(args, limit)
+
+    rows, err := r.DB.Query(`
+        SELECT mm.id, mm.material_id, m.name, m.unit, mm.type, mm.quantity, mm.balance_after,
+               COALESCE(mm.transaction_id, 0), COALESCE(mm.reference, ''), COALESCE(mm.reason, ''),
+               COALESCE(mm.performed_by, ''), mm.created_at
+        FROM material_movements mm
+        JOIN materials m ON m.id = mm.material_id
+        WHERE `+strings.Join(where, " AND ")+`
+        ORDER BY mm.created_at DESC, mm.id DESC
+        LIMIT ?`,
+        args...,
+    )
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    movements := []models.MaterialMovement{}
+    for rows.Next() {
+        var mv models.MaterialMovement
+        err := rows.Scan(&mv.ID, &mv.MaterialID, &mv.MaterialName, &mv.Unit, &mv.Type, &mv.Quantity, &mv.BalanceAfter,
+            &mv.TransactionID, &mv.Reference, &mv.Reason, &mv.PerformedBy, &mv.CreatedAt)
+        if err != nil {
+            return nil, err
+        }
+        movements = append(movements, mv)
+    }
+    return movements, rows.Err()
+}
+
+// deductMaterialsForTransaction mencatat pemakaian bahan sesuai BOM untuk satu transaksi.
+// Transaksi yang sudah pernah dipotong stoknya dilewati supaya tidak terpotong dua kali.
+func deductMaterialsForTransaction(tx *sql.Tx, transactionID int, performedBy string) ([]models.MaterialMovement, []models.UncoveredItem, error) {
+    var deducted int
+    err := tx.QueryRow(
+        "SELECT COUNT(*) FROM material_movements WHERE transaction_id = ? AND type = 'out'",
+        transactionID,
+    ).Scan(&deducted)
+    if err != nil {
+        return nil, nil, err
+    }
+    if deducted > 0 {
+        return []models.MaterialMovement{}, []models.UncoveredItem{}, nil
+    }
+
+    requirements, uncovered, err := materialRequirements(tx, RecapFilter{TransactionIDs: []int{transactionID}, Status: "all"})
+    if err != nil {
+        return nil, nil, err
+    }
+    movements := []models.MaterialMovement{}
+    for _, req := range requirements {
+        mv := models.MaterialMovement{
+            MaterialID:    req.MaterialID,
+            Type:          "out",
+            Quantity:      -req.Quantity,
+            TransactionID: transactionID,
+            Reference:     fmt.Sprintf("Transaksi #%d", transactionID),
+            Reason:        "pemakaian produksi (BOM)",
+            PerformedBy:   performedBy,
+        }
+        if err := applyMovement(tx, &mv, true); err != nil {
+            return nil, nil, err
+        }
+        movements = append(movements, mv)
+    }
+    return movements, uncovered, nil
+}
+
+// CountLowStock menghitung bahan aktif yang stoknya sudah di bawah batas pemesanan ulang
+func CountLowStock(db *sql.DB) (int, error) {
+    var n int
+    err := db.QueryRow("SELECT COUNT(*) FROM materials WHERE active = 1 AND reorder_level > 0 AND stock <= reorder_level").Scan(&n)
+    return n, err
+}
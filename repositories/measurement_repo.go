@@ -0,0 +1,371 @@
+package repositories
+
+import (
+    "database/sql"
+    "fmt"
+    "konveksi-app/models"
+    "konveksi-app/sizing"
+    "math"
+    "sort"
+    "strings"
+    "time"
+)
+
+type MeasurementRepository struct {
+    DB *sql.DB
+}
+
+const measurementColumns = `m.id, m.student_id, m.chest, m.waist, m.hip, m.shoulder, m.sleeve, m.shirt_length,
+    m.trouser_length, m.unit, m.measured_at, COALESCE(m.measured_by, ''), COALESCE(m.notes, ''), m.created_at`
+
+// measurementFields mengembalikan pointer ke tiap ukuran, urutannya sama dengan kolom di tabel
+func measurementFields(m *models.Measurements) []interface{} {
+    return []interface{}{&m.Chest, &m.Waist, &m.Hip, &m.Shoulder, &m.Sleeve, &m.ShirtLength, &m.TrouserLength}
+}
+
+var measurementNames = []string{"lingkar dada", "lingkar pinggang", "lingkar pinggul", "lebar bahu",
+    "panjang lengan", "panjang baju", "panjang celana"}
+
+func scanMeasurement(scanner interface{ Scan(...interface{}) error }) (models.StudentMeasurement, error) {
+    var m models.StudentMeasurement
+    dest := []interface{}{&m.ID, &m.StudentID}
+    dest = append(dest, measurementFields(&m.Measurements)...)
+    dest = append(dest, &m.Unit, &m.MeasuredAt, &m.MeasuredBy, &m.Notes, &m.CreatedAt)
+    err := scanner.Scan(dest...)
+    return m, err
+}
+
+// measurementValues mengambil nilai ukuran sebagai slice; nil untuk ukuran yang kosong
+func measurementValues(m models.Measurements) []*float64 {
+    return []*float64{m.Chest, m.Waist, m.Hip, m.Shoulder, m.Sleeve, m.ShirtLength, m.TrouserLength}
+}
+
+// toCentimeters mengonversi ukuran dalam inch ke cm supaya bisa dibandingkan dengan aturan ukuran
+func toCentimeters(m models.Measurements, unit string) models.Measurements {
+    if unit != "inch" {
+        return m
+    }
+    var converted models.Measurements
+    fields := measurementFields(&converted)
+    for i, v := range measurementValues(m) {
+        if v != nil {
+            cm := math.Round(*v*2.54*10) / 10
+            *(fields[i].(**float64)) = &cm
+        }
+    }
+    return converted
+}
+
+// validateMeasurements mengecek ukuran tidak negatif, masuk akal, dan minimal satu terisi
+func validateMeasurements(m models.Measurements, unit string) error {
+    limit := 250.0
+    if unit == "inch" {
+        limit = 100
+    }
+    filled := 0
+    for i, v := range measurementValues(m) {
+        if v == nil {
+            continue
+        }
+        if *v <= 0 || *v > limit {
+            return fmt.Errorf("%s %.1f %s tidak masuk akal", measurementNames[i], *v, unit)
+        }
+        filled++
+    }
+    if filled == 0 {
+        return fmt.Errorf("minimal satu ukuran harus diisi")
+    }
+    return nil
+}
+
+func (r *MeasurementRepository) GetByStudent(studentID int) ([]models.StudentMeasurement, error) {
+    rows, err := r.DB.Query(`
+        SELECT `+measurementColumns+`
+        FROM student_measurements m
+        WHERE m.student_id = ?
+        ORDER BY m.measured_at DESC, m.id DESC`,
+        studentID,
+    )
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    measurements := []models.StudentMeasurement{}
+    for rows.Next() {
+        m, err := scanMeasurement(rows)
+        if err != nil {
+            return nil, err
+        }
+        measurements = append(measurements, m)
+    }
+    return measurements, rows.Err()
+}
+
+func (r *MeasurementRepository) GetByID(id int) (*models.StudentMeasurement, error) {
+    m, err := scanMeasurement(r.DB.QueryRow("SELECT "+measurementColumns+" FROM student_measurements m WHERE m.id = ?", id))
+    if err != nil {
+        return nil, err
+    }
+    return &m, nil
+}
+
+// Create menyimpan catatan ukuran baru. Tanggal ukur default hari ini, satuan default cm.
+func (r *MeasurementRepository) Create(m *models.StudentMeasurement) error {
+    m.Unit = strings.ToLower(strings.TrimSpace(m.Unit))
+    if m.Unit == "" {
+        m.Unit = "cm"
+    }
+    if m.Unit != "cm" && m.Unit != "inch" {
+        return fmt.Errorf("satuan harus cm atau inch")
+    }
+    if err := validateMeasurements(m.Measurements, m.Unit); err != nil {
+        return err
+    }
+    if m.MeasuredAt == "" {
+        m.MeasuredAt = time.Now().Format("2006-01-02")
+    } else if _, err := time.Parse("2006-01-02", m.MeasuredAt); err != nil {
+        return fmt.Errorf("tanggal ukur harus berformat YYYY-MM-DD")
+    }
+
+    var exists int
+    if err := r.DB.QueryRow("SELECT COUNT(*) FROM students WHERE id = ?", m.StudentID).Scan(&exists); err != nil {
+        return err
+    }
+    if exists == 0 {
+        return sql.ErrNoRows
+    }
+
+    args := []interface{}{m.StudentID}
+    for _, v := range measurementValues(m.Measurements) {
+        args = append(args, v)
+    }
+    args = append(args, m.Unit, m.MeasuredAt, nullableString(m.MeasuredBy), nullableString(m.Notes))
+    res, err := r.DB.Exec(
+        `INSERT INTO student_measurements
+         (student_id, chest, waist, hip, shoulder, sleeve, shirt_length, trouser_length, unit, measured_at, measured_by, notes)
+         VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+        args...,
+    )
+    if err != nil {
+        return err
+    }
+    id, _ := res.LastInsertId()
+    m.ID = int(id)
+    return nil
+}
+
+// Delete menghapus catatan ukuran; item pesanan yang memakainya tetap ada dengan measurement_id NULL
+func (r *MeasurementRepository) Delete(id int) error {
+    res, err := r.DB.Exec("DELETE FROM student_measurements WHERE id = ?", id)
+    if err != nil {
+        return err
+    }
+    if n, _ := res.RowsAffected(); n == 0 {
+        return sql.ErrNoRows
+    }
+    return nil
+}
+
+func (r *MeasurementRepository) getSizeRules(where string, args ...interface{}) ([]models.SizeRule, error) {
+    rows, err := r.DB.Query(`
+        SELECT id, catalog_id, size, chest, waist, hip, shoulder, sleeve, shirt_length, trouser_length
+        FROM uniform_size_rules `+where,
+        args...,
+    )
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    rules := []models.SizeRule{}
+    for rows.Next() {
+        var rule models.SizeRule
+        dest := append([]interface{}{&rule.ID, &rule.CatalogID, &rule.Size}, measurementFields(&rule.Measurements)...)
+        if err := rows.Scan(dest...); err != nil {
+            return nil, err
+        }
+        rules = append(rules, rule)
+    }
+    if err := rows.Err(); err != nil {
+        return nil, err
+    }
+    sort.SliceStable(rules, func(i, j int) bool {
+        if rules[i].CatalogID != rules[j].CatalogID {
+            return rules[i].CatalogID < rules[j].CatalogID
+        }
+        return sizing.Less(rules[i].Size, rules[j].Size)
+    })
+    return rules, nil
+}
+
+// GetSizeRules mengambil ukuran standar satu seragam katalog, urut ukuran
+func (r *MeasurementRepository) GetSizeRules(catalogID int) ([]models.SizeRule, error) {
+    return r.getSizeRules("WHERE catalog_id = ?", catalogID)
+}
+
+// SaveSizeRules mengganti seluruh aturan ukuran satu seragam katalog
+func (r *MeasurementRepository) SaveSizeRules(catalogID int, rules []models.SizeRule) error {
+    unique := map[string]bool{}
+    for i := range rules {
+        rules[i].Size = strings.TrimSpace(rules[i].Size)
+        key := strings.ToLower(rules[i].Size)
+        if key == "" {
+            return fmt.Errorf("ukuran tidak boleh kosong")
+        }
+        if unique[key] {
+            return fmt.Errorf("ukuran '%s' dobel", rules[i].Size)
+        }
+        unique[key] = true
+        if err := validateMeasurements(rules[i].Measurements, "cm"); err != nil {
+            return fmt.Errorf("ukuran '%s': %v", rules[i].Size, err)
+        }
+    }
+
+    var exists int
+    if err := r.DB.QueryRow("SELECT COUNT(*) FROM uniform_catalog WHERE id = ?", catalogID).Scan(&exists); err != nil {
+        return err
+    }
+    if exists == 0 {
+        return sql.ErrNoRows
+    }
+
+    tx, err := r.DB.Begin()
+    if err != nil {
+        return err
+    }
+    defer tx.Rollback()
+
+    if _, err := tx.Exec("DELETE FROM uniform_size_rules WHERE catalog_id = ?", catalogID); err != nil {
+        return err
+    }
+    for _, rule := range rules {
+        args := []interface{}{catalogID, rule.Size}
+        for _, v := range measurementValues(rule.Measurements) {
+            args = append(args, v)
+        }
+        _, err := tx.Exec(
+            `INSERT INTO uniform_size_rules
+             (catalog_id, size, chest, waist, hip, shoulder, sleeve, shirt_length, trouser_length)
+             VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+            args...,
+        )
+        if err != nil {
+            return err
+        }
+    }
+    return tx.Commit()
+}
+
+// sizeDistance menghitung akar rata-rata kuadrat selisih pada ukuran yang terisi di keduanya
+func sizeDistance(body, standard models.Measurements) (float64, int) {
+    standardValues := measurementValues(standard)
+    var sum float64
+    compared := 0
+    for i, v := range measurementValues(body) {
+        if v == nil || standardValues[i] == nil {
+            continue
+        }
+        d := *v - *standardValues[i]
+        sum += d * d
+        compared++
+    }
+    if compared == 0 {
+        return 0, 0
+    }
+    return math.Sqrt(sum / float64(compared)), compared
+}
+
+// nearestSize memilih ukuran standar terdekat; ok=false jika tidak ada ukuran yang bisa dibandingkan
+func nearestSize(body models.Measurements, rules []models.SizeRule) (best models.SizeSuggestion, ok bool) {
+    bestDistance, secondDistance := math.MaxFloat64, math.MaxFloat64
+    for _, rule := range rules {
+        d, compared := sizeDistance(body, rule.Measurements)
+        if compared == 0 {
+            continue
+        }
+        if d < bestDistance {
+            if ok {
+                best.RunnerUp, secondDistance = best.Size, bestDistance
+            }
+            bestDistance = d
+            best.Size, best.Distance, best.FieldsCompared = rule.Size, math.Round(d*10)/10, compared
+            ok = true
+        } else if d < secondDistance {
+            best.RunnerUp, secondDistance = rule.Size, d
+        }
+    }
+    return best, ok
+}
+
+// SuggestSizes mencari ukuran standar terdekat untuk satu catatan ukuran, per seragam katalog.
+// catalogID 0 berarti semua seragam yang punya aturan ukuran.
+func (r *MeasurementRepository) SuggestSizes(measurementID, catalogID int) ([]models.SizeSuggestion, error) {
+    m, err := r.GetByID(measurementID)
+    if err != nil {
+        return nil, err
+    }
+    body := toCentimeters(m.Measurements, m.Unit)
+
+    var rules []models.SizeRule
+    if catalogID != 0 {
+        rules, err = r.GetSizeRules(catalogID)
+    } else {
+        rules, err = r.getSizeRules("")
+    }
+    if err != nil {
+        return nil, err
+    }
+
+    names := map[int]string{}
+    rows, err := r.DB.Query("SELECT id, name FROM uniform_catalog")
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+    for rows.Next() {
+        var id int
+        var name string
+        if err := rows.Scan(&id, &name); err != nil {
+            return nil, err
+        }
+        names[id] = name
+    }
+    if err := rows.Err(); err != nil {
+        return nil, err
+    }
+
+    suggestions := []models.SizeSuggestion{}
+    for start := 0; start < len(rules); {
+        end := start
+        for end < len(rules) && rules[end].CatalogID == rules[start].CatalogID {
+            end++
+        }
+        if s, ok := nearestSize(body, rules[start:end]); ok {
+            s.CatalogID = rules[start].CatalogID
+            s.CatalogName = names[s.CatalogID]
+            suggestions = append(suggestions, s)
+        }
+        start = end
+    }
+    sort.SliceStable(suggestions, func(i, j int) bool { return suggestions[i].CatalogName < suggestions[j].CatalogName })
+    return suggestions, nil
+}
+
+// checkMeasurement memastikan catatan ukuran pada item pesanan milik siswa item tersebut
+func checkMeasurement(tx *sql.Tx, item *models.StudentOrderItem) error {
+    if item.MeasurementID == 0 {
+        return nil
+    }
+    var studentID int
+    err := tx.QueryRow("SELECT student_id FROM student_measurements WHERE id = ?", item.MeasurementID).Scan(&studentID)
+    if err == sql.ErrNoRows {
+        return fmt.Errorf("catatan ukuran %d tidak ditemukan", item.MeasurementID)
+    } else if err != nil {
+        return err
+    }
+    if studentID != item.StudentID {
+        return fmt.Errorf("catatan ukuran %d bukan milik siswa %s", item.MeasurementID, item.StudentName)
+    }
+    return nil
+}
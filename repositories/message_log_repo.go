@@ -0,0 +1,65 @@
+package repositories
+
+import (
+    "database/sql"
+    "konveksi-app/models"
+)
+
+type MessageLogRepository struct {
+    DB *sql.DB
+}
+
+func (r *MessageLogRepository) Create(m *models.MessageLog) error {
+    res, err := r.DB.Exec(`
+        INSERT INTO message_logs
+        (customer_id, transaction_id, template, provider, phone, message, event_key, status, error)
+        VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+        m.CustomerID, nullableInt(m.TransactionID), m.Template, m.Provider, m.Phone,
+        m.Message, nullableString(m.EventKey), m.Status, nullableString(m.Error),
+    )
+    if err != nil {
+        return err
+    }
+    id, err := res.LastInsertId()
+    if err != nil {
+        return err
+    }
+    m.ID = int(id)
+    return nil
+}
+
+// WasSent mengecek apakah pesan untuk event tertentu sudah pernah terkirim
+func (r *MessageLogRepository) WasSent(template, eventKey string) (bool, error) {
+    var count int
+    err := r.DB.QueryRow(
+        "SELECT COUNT(*) FROM message_logs WHERE template = ? AND event_key = ? AND status = 'sent'",
+        template, eventKey,
+    ).Scan(&count)
+    return count > 0, err
+}
+
+func (r *MessageLogRepository) GetByCustomerID(customerID int) ([]models.MessageLog, error) {
+    rows, err := r.DB.Query(`
+        SELECT id, customer_id, COALESCE(transaction_id, 0), template, provider, phone, message,
+               COALESCE(event_key, ''), status, COALESCE(error, ''), created_at
+        FROM message_logs WHERE customer_id = ?
+        ORDER BY created_at DESC, id DESC`, customerID)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    logs := []models.MessageLog{}
+    for rows.Next() {
+        var m models.MessageLog
+        err := rows.Scan(
+            &m.ID, &m.CustomerID, &m.TransactionID, &m.Template, &m.Provider, &m.Phone, &m.Message,
+            &m.EventKey, &m.Status, &m.Error, &m.CreatedAt,
+        )
+        if err != nil {
+            return nil, err
+        }
+        logs = append(logs, m)
+    }
+    return logs, nil
+}
@@ -0,0 +1,257 @@
+package repositories
+
+import (
+    "database/sql"
+    "fmt"
+    "konveksi-app/models"
+    "log"
+)
+
+type NotificationRepository struct {
+    DB *sql.DB
+}
+
+// notificationRule mendefinisikan satu jenis notifikasi per transaksi.
+// Kondisi sama dengan yang dipakai GetDashboardStats.
+type notificationRule struct {
+    Type       string
+    DateColumn string
+    Condition  string
+    Title      string
+    Message    string // format: nama customer, id transaksi, tanggal
+    Icon       string
+    Color      string
+}
+
+var notificationRules = []notificationRule{
+    {
+        Type:       "overdue_payment",
+        DateColumn: "payment_date",
+        Condition:  "t.status != 'paid' AND t.payment_date IS NOT NULL AND t.payment_date < CURDATE()",
+        Title:      "Pembayaran Terlambat",
+        Message:    "Pembayaran %s (transaksi #%d) sudah melewati jatuh tempo %s",
+        Icon:       "ph-currency-circle-dollar",
+        Color:      "danger",
+    },
+    {
+        Type:       "overdue_transaction",
+        DateColumn: "transaction_date",
+        Condition:  "t.status = 'pending' AND t.transaction_date IS NOT NULL AND t.transaction_date < CURDATE()",
+        Title:      "Transaksi Terlambat",
+        Message:    "Pesanan %s (transaksi #%d) sudah melewati target tanggal %s",
+        Icon:       "ph-clock",
+        Color:      "warning",
+    },
+    {
+        Type:       "reminder_payment",
+        DateColumn: "payment_date",
+        Condition:  "t.status != 'paid' AND t.payment_date IS NOT NULL AND DATE_SUB(t.payment_date, INTERVAL 2 DAY) <= CURDATE() AND CURDATE() < t.payment_date",
+        Title:      "Reminder Pembayaran",
+        Message:    "Pembayaran %s (transaksi #%d) jatuh tempo pada %s",
+        Icon:       "ph-bell",
+        Color:      "info",
+    },
+    {
+        Type:       "reminder_transaction",
+        DateColumn: "transaction_date",
+        Condition:  "t.status = 'pending' AND t.transaction_date IS NOT NULL AND DATE_SUB(t.transaction_date, INTERVAL 2 DAY) <= CURDATE() AND CURDATE() < t.transaction_date",
+        Title:      "Reminder Pengerjaan",
+        Message:    "Pesanan %s (transaksi #%d) harus selesai pada %s",
+        Icon:       "ph-wrench",
+        Color:      "primary",
+    },
+}
+
+func findNotificationRule(notificationType string) *notificationRule {
+    for i := range notificationRules {
+        if notificationRules[i].Type == notificationType {
+            return &notificationRules[i]
+        }
+    }
+    return nil
+}
+
+// Generate membuat notifikasi untuk setiap transaksi yang memenuhi kondisi,
+// dan menandai resolved notifikasi yang kondisinya sudah tidak berlaku lagi.
+// Aman dipanggil berulang kali (unik per type + transaksi + tanggal event).
+func (r *NotificationRepository) Generate() (int, error) {
+    created := 0
+    for _, rule := range notificationRules {
+        rows, err := r.DB.Query(fmt.Sprintf(`
+            SELECT t.id, t.customer_id, c.name, t.%s
+            FROM transactions t
+            JOIN customers c ON t.customer_id = c.id
+            WHERE %s`, rule.DateColumn, rule.Condition))
+        if err != nil {
+            return created, err
+        }
+
+        type event struct {
+            TransactionID int
+            CustomerID    int
+            CustomerName  string
+            EventDate     string
+        }
+        var events []event
+        for rows.Next() {
+            var e event
+            if err := rows.Scan(&e.TransactionID, &e.CustomerID, &e.CustomerName, &e.EventDate); err != nil {
+                rows.Close()
+                return created, err
+            }
+            events = append(events, e)
+        }
+        rows.Close()
+
+        for _, e := range events {
+            res, err := r.DB.Exec(`
+                INSERT IGNORE INTO notifications
+                (type, transaction_id, customer_id, title, message, event_date)
+                VALUES (?, ?, ?, ?, ?, ?)`,
+                rule.Type, e.TransactionID, e.CustomerID, rule.Title,
+                fmt.Sprintf(rule.Message, e.CustomerName, e.TransactionID, e.EventDate),
+                e.EventDate,
+            )
+            if err != nil {
+                return created, err
+            }
+            if n, err := res.RowsAffected(); err == nil {
+                created += int(n)
+            }
+        }
+
+        _, err = r.DB.Exec(fmt.Sprintf(`
+            UPDATE notifications n SET n.resolved_at = NOW()
+            WHERE n.type = ? AND n.resolved_at IS NULL
+            AND NOT EXISTS (
+                SELECT 1 FROM transactions t
+                WHERE t.id = n.transaction_id AND t.%s = n.event_date AND %s
+            )`, rule.DateColumn, rule.Condition), rule.Type)
+        if err != nil {
+            return created, err
+        }
+    }
+
+    log.Printf("Notifications generated: %d new", created)
+    return created, nil
+}
+
+// GetForUser mengambil notifikasi aktif (belum resolved, tidak di-dismiss,
+// tidak sedang di-snooze) untuk user tertentu
+func (r *NotificationRepository) GetForUser(userID int, unreadOnly bool) ([]models.Notification, error) {
+    query := `
+        SELECT n.id, n.type, n.transaction_id, n.customer_id, COALESCE(c.name, ''),
+               n.title, n.message, n.event_date, n.created_at,
+               s.read_at IS NOT NULL, COALESCE(s.read_at, ''), COALESCE(s.snoozed_until, '')
+        FROM notifications n
+        LEFT JOIN customers c ON n.customer_id = c.id
+        LEFT JOIN notification_user_states s ON s.notification_id = n.id AND s.user_id = ?
+        WHERE n.resolved_at IS NULL
+        AND s.dismissed_at IS NULL
+        AND (s.snoozed_until IS NULL OR s.snoozed_until <= CURDATE())`
+    if unreadOnly {
+        query += " AND s.read_at IS NULL"
+    }
+    query += " ORDER BY n.event_date ASC, n.id DESC"
+
+    rows, err := r.DB.Query(query, userID)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    notifications := []models.Notification{}
+    for rows.Next() {
+        var n models.Notification
+        err := rows.Scan(
+            &n.ID, &n.Type, &n.TransactionID, &n.CustomerID, &n.CustomerName,
+            &n.Title, &n.Message, &n.EventDate, &n.CreatedAt,
+            &n.IsRead, &n.ReadAt, &n.SnoozedUntil,
+        )
+        if err != nil {
+            return nil, err
+        }
+        if rule := findNotificationRule(n.Type); rule != nil {
+            n.Icon = rule.Icon
+            n.Color = rule.Color
+        }
+        n.ActionURL = fmt.Sprintf("/kelolatransaksi?filter=%s&id=%d", n.Type, n.TransactionID)
+        notifications = append(notifications, n)
+    }
+    return notifications, nil
+}
+
+func (r *NotificationRepository) CountUnread(userID int) (int, error) {
+    var count int
+    err := r.DB.QueryRow(`
+        SELECT COUNT(*)
+        FROM notifications n
+        LEFT JOIN notification_user_states s ON s.notification_id = n.id AND s.user_id = ?
+        WHERE n.resolved_at IS NULL
+        AND s.dismissed_at IS NULL
+        AND (s.snoozed_until IS NULL OR s.snoozed_until <= CURDATE())
+        AND s.read_at IS NULL`, userID).Scan(&count)
+    return count, err
+}
+
+func (r *NotificationRepository) exists(id int) error {
+    var count int
+    err := r.DB.QueryRow("SELECT COUNT(*) FROM notifications WHERE id = ?", id).Scan(&count)
+    if err != nil {
+        return err
+    }
+    if count == 0 {
+        return sql.ErrNoRows
+    }
+    return nil
+}
+
+func (r *NotificationRepository) MarkRead(id, userID int) error {
+    if err := r.exists(id); err != nil {
+        return err
+    }
+    _, err := r.DB.Exec(`
+        INSERT INTO notification_user_states (notification_id, user_id, read_at)
+        VALUES (?, ?, NOW())
+        ON DUPLICATE KEY UPDATE read_at = COALESCE(read_at, NOW())`,
+        id, userID,
+    )
+    return err
+}
+
+func (r *NotificationRepository) MarkAllRead(userID int) error {
+    _, err := r.DB.Exec(`
+        INSERT INTO notification_user_states (notification_id, user_id, read_at)
+        SELECT n.id, ?, NOW() FROM notifications n WHERE n.resolved_at IS NULL
+        ON DUPLICATE KEY UPDATE read_at = COALESCE(notification_user_states.read_at, NOW())`,
+        userID,
+    )
+    return err
+}
+
+func (r *NotificationRepository) Dismiss(id, userID int) error {
+    if err := r.exists(id); err != nil {
+        return err
+    }
+    _, err := r.DB.Exec(`
+        INSERT INTO notification_user_states (notification_id, user_id, read_at, dismissed_at)
+        VALUES (?, ?, NOW(), NOW())
+        ON DUPLICATE KEY UPDATE dismissed_at = NOW(), read_at = COALESCE(read_at, NOW())`,
+        id, userID,
+    )
+    return err
+}
+
+// Snooze menyembunyikan notifikasi sampai tanggal tertentu (format YYYY-MM-DD)
+func (r *NotificationRepository) Snooze(id, userID int, until string) error {
+    if err := r.exists(id); err != nil {
+        return err
+    }
+    _, err := r.DB.Exec(`
+        INSERT INTO notification_user_states (notification_id, user_id, snoozed_until)
+        VALUES (?, ?, ?)
+        ON DUPLICATE KEY UPDATE snoozed_until = VALUES(snoozed_until)`,
+        id, userID, until,
+    )
+    return err
+}
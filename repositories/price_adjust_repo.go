@@ -0,0 +1,131 @@
+package repositories
+
+import (
+    "fmt"
+    "strings"
+)
+
+// PriceAdjustment memilih baris daftar harga dan perubahan yang diterapkan
+type PriceAdjustment struct {
+    CustomerType   string  // TK, SD, SMP, ... (kosong = semua)
+    UniformPattern string  // cocok sebagian, * sebagai wildcard (kosong = semua)
+    Size           string  // kosong = semua
+    Mode           string  // fixed (tambah/kurang rupiah) atau percent
+    Amount         float64 // mis. 5000 atau 10 (persen); negatif untuk menurunkan harga
+    RoundTo        float64 // 0, 500 atau 1000
+    ChangedBy      string
+    Reason         string
+}
+
+// PriceAdjustmentRow adalah satu baris yang terkena penyesuaian harga
+type PriceAdjustmentRow struct {
+    CustomerUniformID int     `json:"customer_uniform_id"`
+    CustomerID        int     `json:"customer_id"`
+    CustomerName      string  `json:"customer_name"`
+    CustomerType      string  `json:"customer_type"`
+    UniformName       string  `json:"uniform_name"`
+    Size              string  `json:"size"`
+    OldPrice          float64 `json:"old_price"`
+    NewPrice          float64 `json:"new_price"`
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// uniformLikePattern mengubah pola "kemeja*sd" menjadi LIKE '%kemeja%sd%'
+func uniformLikePattern(pattern string) string {
+    parts := strings.Split(strings.TrimSpace(pattern), "*")
+    for i := range parts {
+        parts[i] = likeEscaper.Replace(parts[i])
+    }
+    return "%" + strings.Join(parts, "%") + "%"
+}
+
+func (a PriceAdjustment) newPrice(old float64) float64 {
+    price := old + a.Amount
+    if a.Mode == "percent" {
+        price = old * (1 + a.Amount/100)
+    }
+    return RoundPrice(price, a.RoundTo)
+}
+
+// AdjustPrices menerapkan penyesuaian harga ke semua baris yang cocok dalam satu transaksi
+// dan mencatat setiap perubahan ke customer_uniform_price_history. apply=false hanya preview.
+func (r *CustomerRepository) AdjustPrices(a PriceAdjustment, apply bool) ([]PriceAdjustmentRow, error) {
+    if a.Mode != "fixed" && a.Mode != "percent" {
+        return nil, fmt.Errorf("mode harus fixed atau percent")
+    }
+    if a.CustomerType == "" && a.UniformPattern == "" && a.Size == "" {
+        return nil, fmt.Errorf("pilih minimal satu filter: tipe customer, nama seragam atau ukuran")
+    }
+
+    where := []string{"1 = 1"}
+    args := []interface{}{}
+    if a.CustomerType != "" {
+        where = append(where, "c.type = ?")
+        args = append(args, a.CustomerType)
+    }
+    if a.UniformPattern != "" {
+        where = append(where, "cu.uniform_name LIKE ?")
+        args = append(args, uniformLikePattern(a.UniformPattern))
+    }
+    if a.Size != "" {
+        where = append(where, "cu.size = ?")
+        args = append(args, a.Size)
+    }
+
+    tx, err := r.DB.Begin()
+    if err != nil {
+        return nil, err
+    }
+    defer tx.Rollback()
+
+    rows, err := tx.Query(`
+        SELECT cu.id, c.id, c.name, c.type, cu.uniform_name, cu.size, cu.price
+        FROM customer_uniforms cu
+        JOIN customers c ON c.id = cu.customer_id
+        WHERE `+strings.Join(where, " AND ")+`
+        ORDER BY c.name, cu.uniform_name, cu.size
+        FOR UPDATE`,
+        args...,
+    )
+    if err != nil {
+        return nil, err
+    }
+    result := []PriceAdjustmentRow{}
+    for rows.Next() {
+        var row PriceAdjustmentRow
+        err := rows.Scan(&row.CustomerUniformID, &row.CustomerID, &row.CustomerName, &row.CustomerType,
+            &row.UniformName, &row.Size, &row.OldPrice)
+        if err != nil {
+            rows.Close()
+            return nil, err
+        }
+        row.NewPrice = a.newPrice(row.OldPrice)
+        if row.NewPrice < 0 {
+            rows.Close()
+            return nil, fmt.Errorf("harga %s %s untuk %s menjadi negatif", row.UniformName, row.Size, row.CustomerName)
+        }
+        result = append(result, row)
+    }
+    rows.Close()
+    if err := rows.Err(); err != nil {
+        return nil, err
+    }
+
+    if !apply {
+        return result, nil
+    }
+
+    for _, row := range result {
+        if row.NewPrice == row.OldPrice {
+            continue
+        }
+        if err := recordPriceChange(tx, row.CustomerUniformID, row.OldPrice, row.NewPrice, a.ChangedBy, a.Reason); err != nil {
+            return nil, err
+        }
+        if _, err := tx.Exec("UPDATE customer_uniforms SET price = ? WHERE id = ?", row.NewPrice, row.CustomerUniformID); err != nil {
+            return nil, err
+        }
+    }
+    return result, tx.Commit()
+}
@@ -0,0 +1,93 @@
+package repositories
+
+import (
+    "strings"
+    "testing"
+
+    "github.com/DATA-DOG/go-sqlmock"
+)
+
+var adjustColumns = []string{"id", "customer_id", "name", "type", "uniform_name", "size", "price"}
+
+func TestPriceAdjustmentNewPrice(t *testing.T) {
+    tests := []struct {
+        a    PriceAdjustment
+        old  float64
+        want float64
+    }{
+        {PriceAdjustment{Mode: "percent", Amount: 7, RoundTo: 500}, 47000, 50500},
+        {PriceAdjustment{Mode: "percent", Amount: 7, RoundTo: 1000}, 32500, 35000},
+        {PriceAdjustment{Mode: "percent", Amount: -10, RoundTo: 0}, 33333, 29999.7},
+        {PriceAdjustment{Mode: "fixed", Amount: 2500, RoundTo: 1000}, 45000, 48000},
+        {PriceAdjustment{Mode: "fixed", Amount: -5000}, 45000, 40000},
+    }
+    for _, tt := range tests {
+        if got := tt.a.newPrice(tt.old); got != tt.want {
+            t.Errorf("newPrice(%v) with %+v = %v, want %v", tt.old, tt.a, got, tt.want)
+        }
+    }
+}
+
+func TestAdjustPricesPercentWithRounding(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer db.Close()
+    repo := &CustomerRepository{DB: db}
+
+    mock.ExpectBegin()
+    mock.ExpectQuery("FROM customer_uniforms cu").WithArgs("SD", "%kemeja%").
+        WillReturnRows(sqlmock.NewRows(adjustColumns).
+            AddRow(11, 2, "SD Ceria", "SD", "Kemeja Putih", "M", 47000.0).
+            AddRow(12, 2, "SD Ceria", "SD", "Kemeja Putih", "S", 32500.0))
+    for _, row := range []struct {
+        id       int
+        old, new float64
+    }{{11, 47000, 50500}, {12, 32500, 35000}} {
+        mock.ExpectExec("INSERT INTO customer_uniform_price_history").
+            WithArgs(row.id, row.old, row.new, "admin", "Kenaikan 2025", sqlmock.AnyArg()).
+            WillReturnResult(sqlmock.NewResult(1, 1))
+        mock.ExpectExec("INSERT INTO customer_uniform_prices").WillReturnResult(sqlmock.NewResult(1, 1))
+        mock.ExpectExec("UPDATE customer_uniforms SET price = \\? WHERE id = \\?").WithArgs(row.new, row.id).
+            WillReturnResult(sqlmock.NewResult(0, 1))
+    }
+    mock.ExpectCommit()
+
+    a := PriceAdjustment{CustomerType: "SD", UniformPattern: "kemeja", Mode: "percent", Amount: 7, RoundTo: 500,
+        ChangedBy: "admin", Reason: "Kenaikan 2025"}
+    rows, err := repo.AdjustPrices(a, true)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if len(rows) != 2 || rows[0].NewPrice != 50500 || rows[1].NewPrice != 35000 {
+        t.Fatalf("rows = %+v, want 50500 and 35000", rows)
+    }
+    if err := mock.ExpectationsWereMet(); err != nil {
+        t.Fatal(err)
+    }
+}
+
+func TestAdjustPricesRejectsNegativeResult(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer db.Close()
+    repo := &CustomerRepository{DB: db}
+
+    mock.ExpectBegin()
+    mock.ExpectQuery("FROM customer_uniforms cu").WithArgs("S").
+        WillReturnRows(sqlmock.NewRows(adjustColumns).
+            AddRow(11, 2, "SD Ceria", "SD", "Kemeja Putih", "S", 80000.0).
+            AddRow(14, 3, "TK Pelita", "TK", "Topi", "S", 15000.0))
+    mock.ExpectRollback()
+
+    _, err = repo.AdjustPrices(PriceAdjustment{Size: "S", Mode: "fixed", Amount: -20000}, true)
+    if err == nil || !strings.Contains(err.Error(), "Topi S untuk TK Pelita menjadi negatif") {
+        t.Fatalf("err = %v, want negative price rejected", err)
+    }
+    if err := mock.ExpectationsWereMet(); err != nil {
+        t.Fatal(err)
+    }
+}
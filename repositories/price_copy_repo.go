@@ -0,0 +1,149 @@
+package repositories
+
+import (
+    "database/sql"
+    "fmt"
+    "math"
+    "strings"
+)
+
+// RoundPrice membulatkan harga ke kelipatan step terdekat (mis. 500 atau 1000). step <= 0 tidak membulatkan.
+func RoundPrice(price, step float64) float64 {
+    if step <= 0 {
+        return math.Round(price*100) / 100
+    }
+    return math.Round(price/step) * step
+}
+
+// PriceCopyOptions mengatur penyalinan daftar harga antar customer
+type PriceCopyOptions struct {
+    AdjustPercent float64 // mis. 10 untuk +10%, -5 untuk -5%
+    RoundTo       float64 // 0, 500 atau 1000
+    Overwrite     bool    // true: timpa harga yang sudah ada, false: lewati
+    ChangedBy     string
+}
+
+// PriceCopyRow adalah hasil (atau rencana) penyalinan satu seragam/ukuran
+type PriceCopyRow struct {
+    SourceID      int      `json:"source_id"`
+    UniformName   string   `json:"uniform_name"`
+    Size          string   `json:"size"`
+    SourcePrice   float64  `json:"source_price"`
+    NewPrice      float64  `json:"new_price"`
+    ExistingID    int      `json:"existing_id,omitempty"`
+    ExistingPrice *float64 `json:"existing_price,omitempty"`
+    Action        string   `json:"action"` // create, overwrite, skip
+}
+
+// CopyUniforms menyalin semua customer_uniforms dari sourceID ke targetID dalam satu transaksi.
+// Jika apply=false transaksi di-rollback sehingga hasilnya hanya preview.
+// Duplikat mengikuti aturan unik uniform_name + size milik customer tujuan.
+func (r *CustomerRepository) CopyUniforms(sourceID, targetID int, opts PriceCopyOptions, apply bool) ([]PriceCopyRow, error) {
+    if sourceID == targetID {
+        return nil, fmt.Errorf("customer sumber dan tujuan tidak boleh sama")
+    }
+
+    tx, err := r.DB.Begin()
+    if err != nil {
+        return nil, err
+    }
+    defer tx.Rollback()
+
+    for _, id := range []int{sourceID, targetID} {
+        var exists int
+        if err := tx.QueryRow("SELECT COUNT(*) FROM customers WHERE id = ?", id).Scan(&exists); err != nil {
+            return nil, err
+        }
+        if exists == 0 {
+            return nil, sql.ErrNoRows
+        }
+    }
+
+    existing := make(map[string]PriceCopyRow)
+    rows, err := tx.Query("SELECT id, uniform_name, size, price FROM customer_uniforms WHERE customer_id = ?", targetID)
+    if err != nil {
+        return nil, err
+    }
+    for rows.Next() {
+        var e PriceCopyRow
+        var price float64
+        if err := rows.Scan(&e.ExistingID, &e.UniformName, &e.Size, &price); err != nil {
+            rows.Close()
+            return nil, err
+        }
+        e.ExistingPrice = &price
+        existing[strings.ToLower(e.UniformName+"|"+e.Size)] = e
+    }
+    rows.Close()
+
+    type sourceRow struct {
+        PriceCopyRow
+        catalogID int
+        notes     string
+    }
+    var sources []sourceRow
+    rows, err = tx.Query(`
+        SELECT id, COALESCE(catalog_id, 0), uniform_name, size, price, COALESCE(notes, '')
+        FROM customer_uniforms WHERE customer_id = ? ORDER BY uniform_name, id`, sourceID)
+    if err != nil {
+        return nil, err
+    }
+    for rows.Next() {
+        var s sourceRow
+        if err := rows.Scan(&s.SourceID, &s.catalogID, &s.UniformName, &s.Size, &s.SourcePrice, &s.notes); err != nil {
+            rows.Close()
+            return nil, err
+        }
+        sources = append(sources, s)
+    }
+    rows.Close()
+
+    result := []PriceCopyRow{}
+    for _, s := range sources {
+        row := s.PriceCopyRow
+        row.NewPrice = RoundPrice(s.SourcePrice*(1+opts.AdjustPercent/100), opts.RoundTo)
+
+        key := strings.ToLower(s.UniformName + "|" + s.Size)
+        e, duplicate := existing[key]
+        targetRowID := e.ExistingID
+        switch {
+        case !duplicate:
+            row.Action = "create"
+            var res sql.Result
+            res, err = tx.Exec(
+                "INSERT INTO customer_uniforms (customer_id, catalog_id, uniform_name, size, price, notes) VALUES (?, ?, ?, ?, ?, ?)",
+                targetID, nullableInt(s.catalogID), s.UniformName, s.Size, row.NewPrice, s.notes,
+            )
+            if err == nil {
+                id, _ := res.LastInsertId()
+                targetRowID = int(id)
+            }
+        case opts.Overwrite:
+            row.Action = "overwrite"
+            row.ExistingID, row.ExistingPrice = e.ExistingID, e.ExistingPrice
+            reason := fmt.Sprintf("salin daftar harga dari customer %d", sourceID)
+            if err := recordPriceChange(tx, e.ExistingID, *e.ExistingPrice, row.NewPrice, opts.ChangedBy, reason); err != nil {
+                return nil, err
+            }
+            _, err = tx.Exec("UPDATE customer_uniforms SET price = ? WHERE id = ?", row.NewPrice, e.ExistingID)
+        default:
+            row.Action = "skip"
+            row.ExistingID, row.ExistingPrice = e.ExistingID, e.ExistingPrice
+        }
+        if err != nil {
+            return nil, err
+        }
+        // Baris sumber yang dobel (beda huruf besar/kecil) hanya disalin sekali
+        newPrice := row.NewPrice
+        if row.Action == "skip" {
+            newPrice = *e.ExistingPrice
+        }
+        existing[key] = PriceCopyRow{ExistingID: targetRowID, ExistingPrice: &newPrice}
+        result = append(result, row)
+    }
+
+    if !apply {
+        return result, nil
+    }
+    return result, tx.Commit()
+}
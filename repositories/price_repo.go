@@ -0,0 +1,104 @@
+package repositories
+
+import (
+    "database/sql"
+    "konveksi-app/sizing"
+    "sort"
+    "strings"
+)
+
+type PriceRepository struct {
+    DB *sql.DB
+}
+
+// PriceRow adalah satu baris dari view_prices
+type PriceRow struct {
+    ID           int     `json:"id"`
+    CustomerID   int     `json:"customer_id"`
+    CustomerName string  `json:"customer_name"`
+    CustomerType string  `json:"customer_type"`
+    CatalogID    int     `json:"catalog_id,omitempty"`
+    UniformName  string  `json:"uniform_name"`
+    Size         string  `json:"size"`
+    Price        float64 `json:"price"`
+    Notes        string  `json:"notes"`
+}
+
+type PriceFilter struct {
+    CustomerID   int
+    CustomerType string
+    Uniform      string // cocok sebagian, tidak membedakan huruf besar/kecil
+}
+
+// GetPrices mengambil daftar harga dari view_prices, diurutkan per customer, seragam lalu ukuran
+func (r *PriceRepository) GetPrices(f PriceFilter) ([]PriceRow, error) {
+    where := []string{"1 = 1"}
+    args := []interface{}{}
+    if f.CustomerID != 0 {
+        where = append(where, "customer_id = ?")
+        args = append(args, f.CustomerID)
+    }
+    if f.CustomerType != "" {
+        where = append(where, "customer_type = ?")
+        args = append(args, f.CustomerType)
+    }
+    if f.Uniform != "" {
+        where = append(where, "uniform_name LIKE ?")
+        args = append(args, uniformLikePattern(f.Uniform))
+    }
+
+    rows, err := r.DB.Query(`
+        SELECT id, customer_id, customer_name, customer_type, COALESCE(catalog_id, 0),
+               uniform_name, size, price, COALESCE(notes, '')
+        FROM view_prices
+        WHERE `+strings.Join(where, " AND ")+`
+        ORDER BY customer_name, customer_id, uniform_name`,
+        args...,
+    )
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    prices := []PriceRow{}
+    for rows.Next() {
+        var p PriceRow
+        err := rows.Scan(&p.ID, &p.CustomerID, &p.CustomerName, &p.CustomerType, &p.CatalogID,
+            &p.UniformName, &p.Size, &p.Price, &p.Notes)
+        if err != nil {
+            return nil, err
+        }
+        prices = append(prices, p)
+    }
+    if err := rows.Err(); err != nil {
+        return nil, err
+    }
+
+    // Ukuran diurutkan S, M, L, XL… atau numerik di dalam setiap customer + seragam
+    group := make([]int, len(prices))
+    for i := 1; i < len(prices); i++ {
+        group[i] = group[i-1]
+        if prices[i].CustomerID != prices[i-1].CustomerID || !strings.EqualFold(prices[i].UniformName, prices[i-1].UniformName) {
+            group[i]++
+        }
+    }
+    sort.Sort(priceSorter{prices, group})
+    return prices, nil
+}
+
+type priceSorter struct {
+    rows  []PriceRow
+    group []int
+}
+
+func (s priceSorter) Len() int { return len(s.rows) }
+func (s priceSorter) Swap(i, j int) {
+    s.rows[i], s.rows[j] = s.rows[j], s.rows[i]
+    s.group[i], s.group[j] = s.group[j], s.group[i]
+}
+func (s priceSorter) Less(i, j int) bool {
+    if s.group[i] != s.group[j] {
+        return s.group[i] < s.group[j]
+    }
+    return sizing.Less(s.rows[i].Size, s.rows[j].Size)
+}
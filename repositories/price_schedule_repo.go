@@ -0,0 +1,352 @@
+package repositories
+
+import (
+    "database/sql"
+    "fmt"
+    "konveksi-app/models"
+    "log"
+    "time"
+)
+
+// recordPriceChange mencatat perubahan harga yang langsung berlaku hari ini ke
+// customer_uniform_price_history dan customer_uniform_prices. Dipanggil di dalam transaksi
+// sebelum customer_uniforms.price diubah.
+func recordPriceChange(tx *sql.Tx, customerUniformID int, oldPrice, newPrice float64, changedBy, reason string) error {
+    if oldPrice == newPrice {
+        return nil
+    }
+    today := time.Now().Format("2006-01-02")
+    _, err := tx.Exec(
+        `INSERT INTO customer_uniform_price_history (customer_uniform_id, old_price, new_price, changed_by, reason, effective_from)
+         VALUES (?, ?, ?, ?, ?, ?)`,
+        customerUniformID, oldPrice, newPrice, nullableString(changedBy), nullableString(reason), today,
+    )
+    if err != nil {
+        return err
+    }
+    _, err = tx.Exec(
+        `INSERT INTO customer_uniform_prices (customer_uniform_id, price, previous_price, effective_from, changed_by, reason, applied_at)
+         VALUES (?, ?, ?, ?, ?, ?, NOW())`,
+        customerUniformID, newPrice, oldPrice, today, nullableString(changedBy), nullableString(reason),
+    )
+    return err
+}
+
+type queryRower interface {
+    QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// priceOnDate mengembalikan harga customer_uniforms yang berlaku pada tanggal date (YYYY-MM-DD):
+// jadwal terakhir dengan effective_from <= date, atau harga sebelum perubahan pertama setelah date,
+// atau harga saat ini jika tidak ada jadwal.
+func priceOnDate(q queryRower, customerUniformID int, date string) (float64, error) {
+    var price float64
+    err := q.QueryRow(
+        `SELECT price FROM customer_uniform_prices
+         WHERE customer_uniform_id = ? AND effective_from <= ?
+         ORDER BY effective_from DESC, id DESC LIMIT 1`,
+        customerUniformID, date,
+    ).Scan(&price)
+    if err != sql.ErrNoRows {
+        return price, err
+    }
+
+    var previous sql.NullFloat64
+    err = q.QueryRow(
+        `SELECT previous_price FROM customer_uniform_prices
+         WHERE customer_uniform_id = ? AND applied_at IS NOT NULL AND effective_from > ?
+         ORDER BY effective_from ASC, id ASC LIMIT 1`,
+        customerUniformID, date,
+    ).Scan(&previous)
+    if err == nil && previous.Valid {
+        return previous.Float64, nil
+    } else if err != nil && err != sql.ErrNoRows {
+        return 0, err
+    }
+
+    err = q.QueryRow("SELECT price FROM customer_uniforms WHERE id = ?", customerUniformID).Scan(&price)
+    return price, err
+}
+
+// lookupPriceOnDate mencari harga seragam/ukuran milik customer pada tanggal tertentu.
+// found=false jika seragam tersebut tidak ada di daftar harga customer. Nama dan ukuran
+// di-TRIM karena kolasi NO PAD membedakan spasi di akhir.
+func lookupPriceOnDate(q queryRower, customerID int, uniformName, size, date string) (price float64, found bool, err error) {
+    var customerUniformID int
+    err = q.QueryRow(
+        "SELECT id FROM customer_uniforms WHERE customer_id = ? AND TRIM(uniform_name) = TRIM(?) AND TRIM(size) = TRIM(?) ORDER BY id LIMIT 1",
+        customerID, uniformName, size,
+    ).Scan(&customerUniformID)
+    if err == sql.ErrNoRows {
+        return 0, false, nil
+    } else if err != nil {
+        return 0, false, err
+    }
+    price, err = priceOnDate(q, customerUniformID, date)
+    return price, err == nil, err
+}
+
+// itemPrice mengembalikan harga daftar harga pada tanggal date, atau clientPrice jika
+// seragam tidak ada di daftar harga (item khusus di luar daftar harga)
+func itemPrice(q queryRower, customerID int, uniformName, size, date string, clientPrice float64) (float64, error) {
+    price, found, err := lookupPriceOnDate(q, customerID, uniformName, size, date)
+    if err != nil {
+        return 0, err
+    }
+    if !found {
+        return clientPrice, nil
+    }
+    return price, nil
+}
+
+// repriceTransaction menghitung ulang harga satuan semua item transaksi sesuai daftar harga
+// pada tanggal date, lalu memperbarui total transaksi
+func repriceTransaction(tx *sql.Tx, transactionID, customerID int, date string) error {
+    type pricedItem struct {
+        id          int
+        uniformName string
+        size        string
+        unitPrice   float64
+    }
+    for _, table := range []string{"order_items", "student_order_items"} {
+        rows, err := tx.Query("SELECT id, uniform_name, size, unit_price FROM "+table+" WHERE transaction_id = ?", transactionID)
+        if err != nil {
+            return err
+        }
+        var items []pricedItem
+        for rows.Next() {
+            var item pricedItem
+            if err := rows.Scan(&item.id, &item.uniformName, &item.size, &item.unitPrice); err != nil {
+                rows.Close()
+                return err
+            }
+            items = append(items, item)
+        }
+        rows.Close()
+        if err := rows.Err(); err != nil {
+            return err
+        }
+
+        for _, item := range items {
+            price, err := itemPrice(tx, customerID, item.uniformName, item.size, date, item.unitPrice)
+            if err != nil {
+                return err
+            }
+            if price == item.unitPrice {
+                continue
+            }
+            if _, err := tx.Exec("UPDATE "+table+" SET unit_price = ? WHERE id = ?", price, item.id); err != nil {
+                return err
+            }
+        }
+    }
+    return updateTransactionTotal(tx, transactionID)
+}
+
+// updateTransactionTotal menyamakan total_price dengan jumlah subtotal semua item
+func updateTransactionTotal(tx *sql.Tx, transactionID int) error {
+    _, err := tx.Exec(`
+        UPDATE transactions SET total_price =
+            (SELECT COALESCE(SUM(quantity * unit_price), 0) FROM order_items WHERE transaction_id = ?) +
+            (SELECT COALESCE(SUM(quantity * unit_price), 0) FROM student_order_items WHERE transaction_id = ?)
+        WHERE id = ?`,
+        transactionID, transactionID, transactionID,
+    )
+    return err
+}
+
+// GetPriceOnDate - harga satu baris daftar harga pada tanggal tertentu
+func (r *CustomerRepository) GetPriceOnDate(customerUniformID int, date string) (float64, error) {
+    return priceOnDate(r.DB, customerUniformID, date)
+}
+
+// SchedulePrice menjadwalkan harga baru. Jika effective_from <= hari ini harga langsung diterapkan.
+func (r *CustomerRepository) SchedulePrice(p *models.ScheduledPrice) error {
+    if _, err := time.Parse("2006-01-02", p.EffectiveFrom); err != nil {
+        return fmt.Errorf("effective_from harus berformat YYYY-MM-DD")
+    }
+    if p.Price < 0 {
+        return fmt.Errorf("harga tidak boleh negatif")
+    }
+
+    tx, err := r.DB.Begin()
+    if err != nil {
+        return err
+    }
+    var exists int
+    if err := tx.QueryRow("SELECT COUNT(*) FROM customer_uniforms WHERE id = ?", p.CustomerUniformID).Scan(&exists); err != nil {
+        tx.Rollback()
+        return err
+    }
+    if exists == 0 {
+        tx.Rollback()
+        return sql.ErrNoRows
+    }
+
+    res, err := tx.Exec(
+        `INSERT INTO customer_uniform_prices (customer_uniform_id, price, effective_from, changed_by, reason)
+         VALUES (?, ?, ?, ?, ?)`,
+        p.CustomerUniformID, p.Price, p.EffectiveFrom, nullableString(p.ChangedBy), nullableString(p.Reason),
+    )
+    if err != nil {
+        tx.Rollback()
+        return err
+    }
+    id, _ := res.LastInsertId()
+    p.ID = int(id)
+
+    if p.EffectiveFrom <= time.Now().Format("2006-01-02") {
+        if err := applyScheduledPrice(tx, p.ID); err != nil {
+            tx.Rollback()
+            return err
+        }
+    }
+    return tx.Commit()
+}
+
+// applyScheduledPrice memindahkan harga terjadwal ke customer_uniforms.price dan mencatat riwayatnya
+func applyScheduledPrice(tx *sql.Tx, scheduledID int) error {
+    var customerUniformID int
+    var price float64
+    var effectiveFrom, changedBy, reason string
+    err := tx.QueryRow(
+        `SELECT customer_uniform_id, price, effective_from, COALESCE(changed_by, ''), COALESCE(reason, '')
+         FROM customer_uniform_prices WHERE id = ? AND applied_at IS NULL FOR UPDATE`,
+        scheduledID,
+    ).Scan(&customerUniformID, &price, &effectiveFrom, &changedBy, &reason)
+    if err != nil {
+        return err
+    }
+
+    var oldPrice float64
+    if err := tx.QueryRow("SELECT price FROM customer_uniforms WHERE id = ? FOR UPDATE", customerUniformID).Scan(&oldPrice); err != nil {
+        return err
+    }
+
+    // Jadwal lama yang baru diproses tidak boleh menimpa jadwal yang lebih baru yang sudah berlaku
+    var newer int
+    err = tx.QueryRow(
+        `SELECT COUNT(*) FROM customer_uniform_prices
+         WHERE customer_uniform_id = ? AND applied_at IS NOT NULL AND effective_from > ?`,
+        customerUniformID, effectiveFrom,
+    ).Scan(&newer)
+    if err != nil {
+        return err
+    }
+
+    if _, err := tx.Exec("UPDATE customer_uniform_prices SET applied_at = NOW(), previous_price = ? WHERE id = ?", oldPrice, scheduledID); err != nil {
+        return err
+    }
+    if newer > 0 || oldPrice == price {
+        return nil
+    }
+
+    _, err = tx.Exec(
+        `INSERT INTO customer_uniform_price_history (customer_uniform_id, old_price, new_price, changed_by, reason, effective_from)
+         VALUES (?, ?, ?, ?, ?, ?)`,
+        customerUniformID, oldPrice, price, nullableString(changedBy), nullableString(reason), effectiveFrom,
+    )
+    if err != nil {
+        return err
+    }
+    _, err = tx.Exec("UPDATE customer_uniforms SET price = ? WHERE id = ?", price, customerUniformID)
+    return err
+}
+
+// ApplyDuePrices menerapkan semua harga terjadwal yang tanggal berlakunya sudah tiba
+func (r *CustomerRepository) ApplyDuePrices() (int, error) {
+    rows, err := r.DB.Query(
+        `SELECT id FROM customer_uniform_prices
+         WHERE applied_at IS NULL AND effective_from <= CURDATE()
+         ORDER BY effective_from ASC, id ASC`,
+    )
+    if err != nil {
+        return 0, err
+    }
+    var ids []int
+    for rows.Next() {
+        var id int
+        if err := rows.Scan(&id); err != nil {
+            rows.Close()
+            return 0, err
+        }
+        ids = append(ids, id)
+    }
+    rows.Close()
+
+    applied := 0
+    for _, id := range ids {
+        tx, err := r.DB.Begin()
+        if err != nil {
+            return applied, err
+        }
+        if err := applyScheduledPrice(tx, id); err != nil {
+            tx.Rollback()
+            if err == sql.ErrNoRows {
+                continue // sudah diterapkan oleh proses lain
+            }
+            log.Printf("Error applying scheduled price %d: %v", id, err)
+            continue
+        }
+        if err := tx.Commit(); err != nil {
+            return applied, err
+        }
+        applied++
+    }
+    return applied, nil
+}
+
+// GetScheduledPrices mengambil semua jadwal harga satu baris daftar harga (terbaru dulu)
+func (r *CustomerRepository) GetScheduledPrices(customerUniformID int) ([]models.ScheduledPrice, error) {
+    rows, err := r.DB.Query(
+        `SELECT id, customer_uniform_id, price, previous_price, effective_from, COALESCE(changed_by, ''),
+                COALESCE(reason, ''), COALESCE(applied_at, ''), created_at
+         FROM customer_uniform_prices WHERE customer_uniform_id = ?
+         ORDER BY effective_from DESC, id DESC`,
+        customerUniformID,
+    )
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    prices := []models.ScheduledPrice{}
+    for rows.Next() {
+        var p models.ScheduledPrice
+        var previous sql.NullFloat64
+        err := rows.Scan(&p.ID, &p.CustomerUniformID, &p.Price, &previous, &p.EffectiveFrom,
+            &p.ChangedBy, &p.Reason, &p.AppliedAt, &p.CreatedAt)
+        if err != nil {
+            return nil, err
+        }
+        if previous.Valid {
+            p.PreviousPrice = &previous.Float64
+        }
+        prices = append(prices, p)
+    }
+    return prices, rows.Err()
+}
+
+// DeleteScheduledPrice membatalkan jadwal harga yang belum berlaku
+func (r *CustomerRepository) DeleteScheduledPrice(id int) error {
+    res, err := r.DB.Exec("DELETE FROM customer_uniform_prices WHERE id = ? AND applied_at IS NULL", id)
+    if err != nil {
+        return err
+    }
+    if n, _ := res.RowsAffected(); n == 0 {
+        return sql.ErrNoRows
+    }
+    return nil
+}
+
+// ApplyPricesOnDate mengganti harga pada daftar seragam dengan harga yang berlaku pada tanggal date
+func (r *CustomerRepository) ApplyPricesOnDate(uniforms []models.CustomerUniform, date string) error {
+    for i := range uniforms {
+        price, err := priceOnDate(r.DB, uniforms[i].ID, date)
+        if err != nil {
+            return err
+        }
+        uniforms[i].Price = price
+    }
+    return nil
+}
@@ -0,0 +1,311 @@
+package repositories
+
+import (
+    "database/sql"
+    "konveksi-app/models"
+    "konveksi-app/sizing"
+    "math"
+    "sort"
+    "strings"
+)
+
+type ProductionRepository struct {
+    DB *sql.DB
+}
+
+// RecapFilter memilih transaksi yang direkap. Filter kosong berarti semua transaksi pending.
+type RecapFilter struct {
+    TransactionIDs []int
+    CustomerIDs    []int
+    From           string // transaction_date >= From (YYYY-MM-DD)
+    To             string // transaction_date <= To
+    Status         string // default pending, "all" untuk semua kecuali cancelled
+}
+
+func placeholders(n int) string {
+    return strings.TrimSuffix(strings.Repeat("?,", n), ",")
+}
+
+// recapWhere membangun kondisi WHERE untuk transaksi t sesuai filter
+func recapWhere(f RecapFilter) (string, []interface{}) {
+    where := []string{"t.status != 'cancelled'"}
+    args := []interface{}{}
+
+    switch f.Status {
+    case "", "pending":
+        if len(f.TransactionIDs) == 0 {
+            where = append(where, "t.status = 'pending'")
+        }
+    case "all":
+    default:
+        where = append(where, "t.status = ?")
+        args = append(args, f.Status)
+    }
+    if len(f.TransactionIDs) > 0 {
+        where = append(where, "t.id IN ("+placeholders(len(f.TransactionIDs))+")")
+        for _, id := range f.TransactionIDs {
+            args = append(args, id)
+        }
+    }
+    if len(f.CustomerIDs) > 0 {
+        where = append(where, "t.customer_id IN ("+placeholders(len(f.CustomerIDs))+")")
+        for _, id := range f.CustomerIDs {
+            args = append(args, id)
+        }
+    }
+    if f.From != "" {
+        where = append(where, "t.transaction_date >= ?")
+        args = append(args, f.From)
+    }
+    if f.To != "" {
+        where = append(where, "t.transaction_date <= ?")
+        args = append(args, f.To)
+    }
+    return strings.Join(where, " AND "), args
+}
+
+// GetSizeRecap menjumlahkan quantity per seragam dan ukuran dari order_items dan student_order_items.
+// Nama seragam dan ukuran di-TRIM sebelum dikelompokkan (kolasi sudah case-insensitive), jadi
+// "Kemeja " dan "kemeja" masuk satu baris dengan jumlah transaksi yang tidak dihitung ganda.
+// Hasil diurutkan per nama seragam, ukuran mengikuti tabel ukuran atau S, M, L, XL… / numerik.
+func (r *ProductionRepository) GetSizeRecap(f RecapFilter) ([]models.SizeRecap, error) {
+    where, args := recapWhere(f)
+    rows, err := r.DB.Query(`
+        SELECT MIN(i.uniform_name), MIN(i.size), SUM(i.quantity), COUNT(DISTINCT i.transaction_id)
+        FROM (
+            SELECT transaction_id, TRIM(uniform_name) AS uniform_name, TRIM(size) AS size, quantity FROM order_items
+            UNION ALL
+            SELECT transaction_id, TRIM(uniform_name), TRIM(size), quantity FROM student_order_items
+        ) i
+        JOIN transactions t ON t.id = i.transaction_id
+        WHERE `+where+`
+        GROUP BY i.uniform_name, i.size`,
+        args...,
+    )
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    recaps := []models.SizeRecap{}
+    index := make(map[string]int)
+    for rows.Next() {
+        var name string
+        var item models.SizeRecapItem
+        if err := rows.Scan(&name, &item.Size, &item.Quantity, &item.TransactionCount); err != nil {
+            return nil, err
+        }
+        key := strings.ToLower(name)
+        i, ok := index[key]
+        if !ok {
+            i = len(recaps)
+            index[key] = i
+            recaps = append(recaps, models.SizeRecap{UniformName: name})
+        }
+        recaps[i].Sizes = append(recaps[i].Sizes, item)
+        recaps[i].Total += item.Quantity
+    }
+    if err := rows.Err(); err != nil {
+        return nil, err
+    }
+
+    sort.Slice(recaps, func(i, j int) bool {
+        return strings.ToLower(recaps[i].UniformName) < strings.ToLower(recaps[j].UniformName)
+    })
+
+    // Urutan ukuran mengikuti tabel ukuran customer yang ada di rekap
+    customerIDs, err := recapCustomers(r.DB, f)
+    if err != nil {
+        return nil, err
+    }
+    charts, err := recapCharts(r.DB, customerIDs)
+    if err != nil {
+        return nil, err
+    }
+    for _, recap := range recaps {
+        sizes := recap.Sizes
+        chart := charts[strings.ToLower(strings.TrimSpace(recap.UniformName))]
+        sort.SliceStable(sizes, func(i, j int) bool { return chart.Less(sizes[i].Size, sizes[j].Size) })
+    }
+    return recaps, nil
+}
+
+// recapCustomers mengembalikan customer yang transaksinya masuk rekap
+func recapCustomers(q rowsQueryer, f RecapFilter) ([]int, error) {
+    where, args := recapWhere(f)
+    rows, err := q.Query("SELECT DISTINCT t.customer_id FROM transactions t WHERE "+where+" ORDER BY t.customer_id", args...)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    var ids []int
+    for rows.Next() {
+        var id int
+        if err := rows.Scan(&id); err != nil {
+            return nil, err
+        }
+        ids = append(ids, id)
+    }
+    return ids, rows.Err()
+}
+
+// MaterialRequirements menghitung total kebutuhan bahan dari BOM untuk transaksi yang dipilih filter.
+// Item yang seragamnya belum terhubung ke katalog atau belum punya BOM dilaporkan di uncovered.
+func (r *ProductionRepository) MaterialRequirements(f RecapFilter) ([]models.MaterialRequirement, []models.UncoveredItem, error) {
+    return materialRequirements(r.DB, f)
+}
+
+type orderedUniform struct {
+    UniformName string
+    Size        string
+    CatalogID   int
+    Quantity    int
+}
+
+// orderedUniforms menjumlahkan item per seragam/ukuran beserta catalog_id dari daftar harga customer.
+// Nama dan ukuran di-TRIM karena collation NO PAD membedakan spasi di belakang.
+func orderedUniforms(q rowsQueryer, f RecapFilter) ([]orderedUniform, error) {
+    where, args := recapWhere(f)
+    rows, err := q.Query(`
+        SELECT i.uniform_name, i.size, COALESCE(cu.catalog_id, 0), SUM(i.quantity)
+        FROM (
+            SELECT transaction_id, TRIM(uniform_name) AS uniform_name, TRIM(size) AS size, quantity FROM order_items
+            UNION ALL
+            SELECT transaction_id, TRIM(uniform_name) AS uniform_name, TRIM(size) AS size, quantity FROM student_order_items
+        ) i
+        JOIN transactions t ON t.id = i.transaction_id
+        LEFT JOIN (
+            SELECT customer_id, TRIM(uniform_name) AS uniform_name, MAX(catalog_id) AS catalog_id
+            FROM customer_uniforms GROUP BY customer_id, TRIM(uniform_name)
+        ) cu ON cu.customer_id = t.customer_id AND cu.uniform_name = i.uniform_name
+        WHERE `+where+`
+        GROUP BY i.uniform_name, i.size, cu.catalog_id`,
+        args...,
+    )
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    var items []orderedUniform
+    for rows.Next() {
+        var item orderedUniform
+        if err := rows.Scan(&item.UniformName, &item.Size, &item.CatalogID, &item.Quantity); err != nil {
+            return nil, err
+        }
+        items = append(items, item)
+    }
+    return items, rows.Err()
+}
+
+// bomByCatalog mengambil BOM beberapa katalog sekaligus
+func bomByCatalog(q rowsQueryer, catalogIDs []int) (map[int][]models.BOMLine, error) {
+    boms := map[int][]models.BOMLine{}
+    if len(catalogIDs) == 0 {
+        return boms, nil
+    }
+    args := make([]interface{}, len(catalogIDs))
+    for i, id := range catalogIDs {
+        args[i] = id
+    }
+    rows, err := q.Query(`
+        SELECT `+bomColumns+`
+        FROM uniform_bom b
+        JOIN materials m ON m.id = b.material_id
+        WHERE b.catalog_id IN (`+placeholders(len(args))+`)`,
+        args...,
+    )
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+    for rows.Next() {
+        var l models.BOMLine
+        err := rows.Scan(&l.ID, &l.CatalogID, &l.Size, &l.MaterialID, &l.MaterialName, &l.Category, &l.Unit, &l.Quantity, &l.Notes)
+        if err != nil {
+            return nil, err
+        }
+        boms[l.CatalogID] = append(boms[l.CatalogID], l)
+    }
+    return boms, rows.Err()
+}
+
+// bomForSize memilih baris BOM untuk satu ukuran: baris ukuran tersebut menggantikan baris umum bahan yang sama
+func bomForSize(lines []models.BOMLine, size string) []models.BOMLine {
+    specific := map[int]bool{}
+    for _, l := range lines {
+        if l.Size != "" && sizing.Equal(l.Size, size) {
+            specific[l.MaterialID] = true
+        }
+    }
+    var result []models.BOMLine
+    for _, l := range lines {
+        switch {
+        case l.Size == "" && !specific[l.MaterialID]:
+            result = append(result, l)
+        case l.Size != "" && sizing.Equal(l.Size, size):
+            result = append(result, l)
+        }
+    }
+    return result
+}
+
+func materialRequirements(q rowsQueryer, f RecapFilter) ([]models.MaterialRequirement, []models.UncoveredItem, error) {
+    items, err := orderedUniforms(q, f)
+    if err != nil {
+        return nil, nil, err
+    }
+    var catalogIDs []int
+    seen := map[int]bool{}
+    for _, item := range items {
+        if item.CatalogID != 0 && !seen[item.CatalogID] {
+            seen[item.CatalogID] = true
+            catalogIDs = append(catalogIDs, item.CatalogID)
+        }
+    }
+    boms, err := bomByCatalog(q, catalogIDs)
+    if err != nil {
+        return nil, nil, err
+    }
+
+    totals := map[int]*models.MaterialRequirement{}
+    uncovered := []models.UncoveredItem{}
+    for _, item := range items {
+        if item.CatalogID == 0 {
+            uncovered = append(uncovered, models.UncoveredItem{
+                UniformName: item.UniformName, Size: item.Size, Quantity: item.Quantity, Reason: "seragam belum terhubung ke katalog",
+            })
+            continue
+        }
+        lines := bomForSize(boms[item.CatalogID], item.Size)
+        if len(lines) == 0 {
+            uncovered = append(uncovered, models.UncoveredItem{
+                UniformName: item.UniformName, Size: item.Size, Quantity: item.Quantity, Reason: "BOM belum diisi",
+            })
+            continue
+        }
+        for _, l := range lines {
+            req, ok := totals[l.MaterialID]
+            if !ok {
+                req = &models.MaterialRequirement{MaterialID: l.MaterialID, Name: l.MaterialName, Category: l.Category, Unit: l.Unit}
+                totals[l.MaterialID] = req
+            }
+            req.Quantity += l.Quantity * float64(item.Quantity)
+        }
+    }
+
+    requirements := make([]models.MaterialRequirement, 0, len(totals))
+    for _, req := range totals {
+        req.Quantity = math.Round(req.Quantity*1000) / 1000
+        requirements = append(requirements, *req)
+    }
+    sort.Slice(requirements, func(i, j int) bool {
+        if requirements[i].Category != requirements[j].Category {
+            return requirements[i].Category < requirements[j].Category
+        }
+        return requirements[i].Name < requirements[j].Name
+    })
+    sort.SliceStable(uncovered, func(i, j int) bool { return uncovered[i].UniformName < uncovered[j].UniformName })
+    return requirements, uncovered, nil
+}
@@ -0,0 +1,126 @@
+package repositories
+
+import (
+    "database/sql/driver"
+    "testing"
+
+    "github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestGetSizeRecapGroupsTrimmedNames(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer db.Close()
+    repo := &ProductionRepository{DB: db}
+
+    // Normalisasi dilakukan di SQL, jadi setiap seragam/ukuran hanya muncul sekali
+    mock.ExpectQuery(`TRIM\(uniform_name\) AS uniform_name, TRIM\(size\) AS size.*GROUP BY i.uniform_name, i.size`).
+        WillReturnRows(sqlmock.NewRows([]string{"uniform_name", "size", "quantity", "transactions"}).
+            AddRow("Kemeja", "M", 5, 2).
+            AddRow("Celana", "M", 2, 1).
+            AddRow("Kemeja", "S", 3, 1))
+    mock.ExpectQuery("SELECT DISTINCT t.customer_id FROM transactions t").
+        WillReturnRows(sqlmock.NewRows([]string{"customer_id"}).AddRow(3))
+    mock.ExpectQuery("FROM customer_uniforms cu").WithArgs(3).
+        WillReturnRows(sqlmock.NewRows([]string{"uniform_name", "priority", "id", "size", "sort_order"}))
+
+    recaps, err := repo.GetSizeRecap(RecapFilter{})
+    if err != nil {
+        t.Fatal(err)
+    }
+    if len(recaps) != 2 {
+        t.Fatalf("recaps = %+v, want 2 uniforms", recaps)
+    }
+    if recaps[0].UniformName != "Celana" || recaps[1].UniformName != "Kemeja" {
+        t.Fatalf("order = %s, %s", recaps[0].UniformName, recaps[1].UniformName)
+    }
+    kemeja := recaps[1]
+    if kemeja.Total != 8 || len(kemeja.Sizes) != 2 || kemeja.Sizes[0].Size != "S" {
+        t.Fatalf("kemeja = %+v, want total 8 with S before M", kemeja)
+    }
+    if err := mock.ExpectationsWereMet(); err != nil {
+        t.Fatal(err)
+    }
+}
+
+func chartRows(rows ...[]driver.Value) *sqlmock.Rows {
+    r := sqlmock.NewRows([]string{"uniform_name", "priority", "id", "size", "sort_order"})
+    for _, row := range rows {
+        r.AddRow(row...)
+    }
+    return r
+}
+
+func TestRecapChartsPerCustomer(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer db.Close()
+
+    // Customer 3 dan 5 sama-sama punya "Kemeja" dengan tabel berbeda, "Celana" hanya di customer 3
+    mock.ExpectQuery("FROM customer_uniforms cu").WithArgs(3).WillReturnRows(chartRows(
+        []driver.Value{"Kemeja", 0, 1, "M", 1}, []driver.Value{"Kemeja", 0, 1, "S", 2},
+        []driver.Value{"Celana", 0, 4, "30", 1}, []driver.Value{"Celana", 0, 4, "28", 2},
+    ))
+    mock.ExpectQuery("FROM customer_uniforms cu").WithArgs(5).WillReturnRows(chartRows(
+        []driver.Value{"kemeja", 1, 2, "S", 1}, []driver.Value{"kemeja", 1, 2, "M", 2},
+    ))
+
+    charts, err := recapCharts(db, []int{3, 5})
+    if err != nil {
+        t.Fatal(err)
+    }
+    if _, ok := charts["kemeja"]; ok {
+        t.Fatalf("kemeja chart = %v, want none because customers disagree", charts["kemeja"])
+    }
+    if celana := charts["celana"]; len(celana) != 2 || celana[0] != "30" {
+        t.Fatalf("celana chart = %v, want [30 28]", celana)
+    }
+    if err := mock.ExpectationsWereMet(); err != nil {
+        t.Fatal(err)
+    }
+}
+
+func TestChartOrdersWithoutCustomer(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer db.Close()
+
+    ids, charts, err := chartOrders(db, 0)
+    if err != nil || len(ids) != 0 || len(charts) != 0 {
+        t.Fatalf("chartOrders(0) = %v, %v, %v; want empty without querying", ids, charts, err)
+    }
+    if err := mock.ExpectationsWereMet(); err != nil {
+        t.Fatal(err)
+    }
+}
+
+func TestOrderedUniformsJoinsCatalogOnTrimmedNames(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer db.Close()
+
+    // "Kemeja " dengan spasi di belakang tetap terhubung ke katalog seragam customer
+    mock.ExpectQuery(`TRIM\(uniform_name\) AS uniform_name, TRIM\(size\) AS size.*` +
+        `SELECT customer_id, TRIM\(uniform_name\) AS uniform_name.*GROUP BY customer_id, TRIM\(uniform_name\)`).
+        WillReturnRows(sqlmock.NewRows([]string{"uniform_name", "size", "catalog_id", "quantity"}).
+            AddRow("Kemeja", "M", 4, 12))
+
+    items, err := orderedUniforms(db, RecapFilter{})
+    if err != nil {
+        t.Fatal(err)
+    }
+    if len(items) != 1 || items[0].CatalogID != 4 || items[0].Quantity != 12 {
+        t.Fatalf("items = %+v, want Kemeja M linked to catalog 4", items)
+    }
+    if err := mock.ExpectationsWereMet(); err != nil {
+        t.Fatal(err)
+    }
+}
@@ -0,0 +1,109 @@
+package repositories
+
+import (
+    "fmt"
+    "konveksi-app/models"
+)
+
+// ProductionStages adalah urutan tahap produksi transaksi
+var ProductionStages = []string{"antri", "potong", "jahit", "finishing", "selesai", "diserahkan"}
+
+func validStage(stage string) bool {
+    return stageIndex(stage) >= 0
+}
+
+// stageIndex mengembalikan urutan tahap di ProductionStages, -1 jika tidak dikenal
+func stageIndex(stage string) int {
+    for i, s := range ProductionStages {
+        if s == stage {
+            return i
+        }
+    }
+    return -1
+}
+
+// passesCutting bernilai true jika perpindahan tahap melewati atau berhenti di "potong",
+// mis. antri → jahit juga berarti bahan sudah dipotong
+func passesCutting(from, to string) bool {
+    cutting := stageIndex("potong")
+    return stageIndex(to) >= cutting && (stageIndex(from) < cutting || to == "potong")
+}
+
+// StageChange adalah hasil perubahan tahap produksi beserta pemakaian bahan yang dicatat
+type StageChange struct {
+    TransactionID int                            `json:"transaction_id"`
+    From          string                         `json:"from"`
+    To            string                         `json:"to"`
+    Movements     []models.MaterialMovement      `json:"material_movements,omitempty"`
+    Uncovered     []models.UncoveredItem         `json:"uncovered,omitempty"`
+    Issued        []models.FinishedGoodsMovement `json:"issued_stock,omitempty"`
+    Delivery      *models.Delivery               `json:"delivery,omitempty"`
+}
+
+// SetStage memindahkan tahap produksi transaksi. Jika deductMaterials dan transaksi melewati tahap "potong"
+// (termasuk lompat langsung ke tahap sesudahnya), pemakaian bahan menurut BOM dicatat sebagai stok keluar
+// dalam satu transaksi DB; pemotongan hanya terjadi sekali per transaksi.
+// Saat diserahkan, stok barang jadi yang disisihkan untuk transaksi ikut dikeluarkan; tahap ini ditolak
+// selama masih ada sisa pengiriman bertahap yang belum dicatat. Sebaliknya, transaksi yang sudah
+// diserahkan tidak bisa mundur tahap selama pengirimannya masih tercatat.
+func (r *ProductionRepository) SetStage(transactionID int, stage string, deductMaterials bool, performedBy string) (*StageChange, error) {
+    if !validStage(stage) {
+        return nil, fmt.Errorf("tahap produksi '%s' tidak dikenal", stage)
+    }
+    tx, err := r.DB.Begin()
+    if err != nil {
+        return nil, err
+    }
+    defer tx.Rollback()
+
+    change := StageChange{TransactionID: transactionID, To: stage}
+    var status string
+    err = tx.QueryRow("SELECT status, production_stage FROM transactions WHERE id = ? FOR UPDATE", transactionID).
+        Scan(&status, &change.From)
+    if err != nil {
+        return nil, err
+    }
+    if status == "cancelled" {
+        return nil, fmt.Errorf("transaksi sudah dibatalkan")
+    }
+
+    if change.From == "diserahkan" && stage != change.From {
+        var deliveries int
+        if err := tx.QueryRow("SELECT COUNT(*) FROM deliveries WHERE transaction_id = ?", transactionID).Scan(&deliveries); err != nil {
+            return nil, err
+        }
+        if deliveries > 0 {
+            return nil, fmt.Errorf("transaksi sudah diserahkan lewat %d pengiriman, hapus pengirimannya dulu untuk mengubah tahap", deliveries)
+        }
+    }
+    if stage == "diserahkan" && change.From != stage {
+        if change.Delivery, err = completeDeliveries(tx, transactionID, performedBy); err != nil {
+            return nil, err
+        }
+    }
+    if change.From != stage {
+        _, err = tx.Exec(
+            "UPDATE transactions SET production_stage = ?, production_stage_at = NOW() WHERE id = ?",
+            stage, transactionID,
+        )
+        if err != nil {
+            return nil, err
+        }
+    }
+    if deductMaterials && passesCutting(change.From, stage) {
+        change.Movements, change.Uncovered, err = deductMaterialsForTransaction(tx, transactionID, performedBy)
+        if err != nil {
+            return nil, err
+        }
+    }
+    if stage == "diserahkan" && change.From != stage {
+        change.Issued, err = issueReservations(tx, transactionID, performedBy)
+        if err != nil {
+            return nil, err
+        }
+    }
+    if err := tx.Commit(); err != nil {
+        return nil, err
+    }
+    return &change, nil
+}
@@ -0,0 +1,58 @@
+package repositories
+
+import (
+    "testing"
+
+    "github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestPassesCutting(t *testing.T) {
+    tests := []struct {
+        from, to string
+        want     bool
+    }{
+        {"antri", "potong", true},
+        {"antri", "jahit", true},
+        {"antri", "selesai", true},
+        {"", "finishing", true},
+        {"potong", "potong", true},
+        {"potong", "jahit", false},
+        {"jahit", "finishing", false},
+        {"jahit", "antri", false},
+        {"antri", "antri", false},
+    }
+    for _, tt := range tests {
+        if got := passesCutting(tt.from, tt.to); got != tt.want {
+            t.Errorf("passesCutting(%q, %q) = %v, want %v", tt.from, tt.to, got, tt.want)
+        }
+    }
+}
+
+func TestSetStageSkippingCuttingDeductsMaterials(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer db.Close()
+    repo := &ProductionRepository{DB: db}
+
+    mock.ExpectBegin()
+    mock.ExpectQuery("SELECT status, production_stage FROM transactions WHERE id = \\? FOR UPDATE").WithArgs(7).
+        WillReturnRows(sqlmock.NewRows([]string{"status", "production_stage"}).AddRow("pending", "antri"))
+    mock.ExpectExec("UPDATE transactions SET production_stage").WithArgs("jahit", 7).WillReturnResult(sqlmock.NewResult(0, 1))
+    // Bahan sudah pernah dipotong: tidak dicatat dua kali
+    mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM material_movements WHERE transaction_id = \\? AND type = 'out'").WithArgs(7).
+        WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+    mock.ExpectCommit()
+
+    change, err := repo.SetStage(7, "jahit", true, "admin")
+    if err != nil {
+        t.Fatal(err)
+    }
+    if change.From != "antri" || change.Movements == nil {
+        t.Fatalf("change = %+v, want deduction checked when skipping potong", change)
+    }
+    if err := mock.ExpectationsWereMet(); err != nil {
+        t.Fatal(err)
+    }
+}
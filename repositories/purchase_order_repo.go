@@ -0,0 +1,456 @@
+package repositories
+
+import (
+    "database/sql"
+    "fmt"
+    "konveksi-app/models"
+    "math"
+    "strings"
+    "time"
+
+    "github.com/go-sql-driver/mysql"
+)
+
+type PurchaseOrderRepository struct {
+    DB *sql.DB
+}
+
+// PurchaseOrderFilter untuk daftar PO; nilai kosong berarti semua
+type PurchaseOrderFilter struct {
+    SupplierID int
+    Status     string
+}
+
+const purchaseOrderColumns = `po.id, po.supplier_id, s.name, po.order_date, COALESCE(po.expected_date, ''), po.status, po.total,
+    COALESCE((SELECT SUM(l.received_quantity * l.unit_price) FROM purchase_order_lines l WHERE l.purchase_order_id = po.id), 0),
+    COALESCE(po.notes, ''), COALESCE(po.created_by, ''), po.created_at, COALESCE(po.updated_at, po.created_at)`
+
+func scanPurchaseOrder(scanner interface{ Scan(...interface{}) error }) (models.PurchaseOrder, error) {
+    var po models.PurchaseOrder
+    err := scanner.Scan(&po.ID, &po.SupplierID, &po.SupplierName, &po.OrderDate, &po.ExpectedDate, &po.Status, &po.Total,
+        &po.ReceivedValue, &po.Notes, &po.CreatedBy, &po.CreatedAt, &po.UpdatedAt)
+    po.ReceivedValue = math.Round(po.ReceivedValue*100) / 100
+    return po, err
+}
+
+func (r *PurchaseOrderRepository) GetAll(f PurchaseOrderFilter) ([]models.PurchaseOrder, error) {
+    where := []string{"1 = 1"}
+    args := []interface{}{}
+    if f.SupplierID != 0 {
+        where = append(where, "po.supplier_id = ?")
+        args = append(args, f.SupplierID)
+    }
+    if f.Status != "" {
+        where = append(where, "po.status = ?")
+        args = append(args, f.Status)
+    }
+    rows, err := r.DB.Query(`
+        SELECT `+purchaseOrderColumns+`
+        FROM purchase_orders po
+        JOIN suppliers s ON s.id = po.supplier_id
+        WHERE `+strings.Join(where, " AND ")+`
+        ORDER BY po.order_date DESC, po.id DESC`,
+        args...,
+    )
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    orders := []models.PurchaseOrder{}
+    for rows.Next() {
+        po, err := scanPurchaseOrder(rows)
+        if err != nil {
+            return nil, err
+        }
+        orders = append(orders, po)
+    }
+    return orders, rows.Err()
+}
+
+// GetByID mengambil PO beserta baris dan riwayat penerimaannya
+func (r *PurchaseOrderRepository) GetByID(id int) (*models.PurchaseOrder, error) {
+    po, err := scanPurchaseOrder(r.DB.QueryRow(`
+        SELECT `+purchaseOrderColumns+`
+        FROM purchase_orders po
+        JOIN suppliers s ON s.id = po.supplier_id
+        WHERE po.id = ?`,
+        id,
+    ))
+    if err != nil {
+        return nil, err
+    }
+    if po.Lines, err = purchaseOrderLines(r.DB, id); err != nil {
+        return nil, err
+    }
+    if po.Receipts, err = r.goodsReceipts(id); err != nil {
+        return nil, err
+    }
+    return &po, nil
+}
+
+func purchaseOrderLines(q rowsQueryer, purchaseOrderID int) ([]models.PurchaseOrderLine, error) {
+    rows, err := q.Query(`
+        SELECT l.id, l.material_id, m.name, m.unit, l.quantity, l.received_quantity, l.unit_price, COALESCE(l.notes, '')
+        FROM purchase_order_lines l
+        JOIN materials m ON m.id = l.material_id
+        WHERE l.purchase_order_id = ?
+        ORDER BY l.id`,
+        purchaseOrderID,
+    )
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    lines := []models.PurchaseOrderLine{}
+    for rows.Next() {
+        var l models.PurchaseOrderLine
+        err := rows.Scan(&l.ID, &l.MaterialID, &l.MaterialName, &l.Unit, &l.Quantity, &l.ReceivedQuantity, &l.UnitPrice, &l.Notes)
+        if err != nil {
+            return nil, err
+        }
+        l.Subtotal = math.Round(l.Quantity*l.UnitPrice*100) / 100
+        lines = append(lines, l)
+    }
+    return lines, rows.Err()
+}
+
+func (r *PurchaseOrderRepository) goodsReceipts(purchaseOrderID int) ([]models.GoodsReceipt, error) {
+    rows, err := r.DB.Query(`
+        SELECT gr.id, gr.received_date, COALESCE(gr.reference, ''), COALESCE(gr.notes, ''), COALESCE(gr.received_by, ''), gr.created_at,
+               grl.purchase_order_line_id, l.material_id, m.name, grl.quantity, COALESCE(grl.material_movement_id, 0)
+        FROM goods_receipts gr
+        JOIN goods_receipt_lines grl ON grl.goods_receipt_id = gr.id
+        JOIN purchase_order_lines l ON l.id = grl.purchase_order_line_id
+        JOIN materials m ON m.id = l.material_id
+        WHERE gr.purchase_order_id = ?
+        ORDER BY gr.received_date, gr.id, grl.id`,
+        purchaseOrderID,
+    )
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    receipts := []models.GoodsReceipt{}
+    for rows.Next() {
+        var gr models.GoodsReceipt
+        var l models.GoodsReceiptLine
+        err := rows.Scan(&gr.ID, &gr.ReceivedDate, &gr.Reference, &gr.Notes, &gr.ReceivedBy, &gr.CreatedAt,
+            &l.PurchaseOrderLineID, &l.MaterialID, &l.MaterialName, &l.Quantity, &l.MovementID)
+        if err != nil {
+            return nil, err
+        }
+        if n := len(receipts); n == 0 || receipts[n-1].ID != gr.ID {
+            gr.PurchaseOrderID = purchaseOrderID
+            receipts = append(receipts, gr)
+        }
+        last := &receipts[len(receipts)-1]
+        last.Lines = append(last.Lines, l)
+    }
+    return receipts, rows.Err()
+}
+
+func validateDate(value, field string) error {
+    if value == "" {
+        return nil
+    }
+    if _, err := time.Parse("2006-01-02", value); err != nil {
+        return fmt.Errorf("%s harus berformat YYYY-MM-DD", field)
+    }
+    return nil
+}
+
+func validatePurchaseOrder(po *models.PurchaseOrder) error {
+    if po.SupplierID == 0 {
+        return fmt.Errorf("pemasok wajib dipilih")
+    }
+    if po.OrderDate == "" {
+        po.OrderDate = time.Now().Format("2006-01-02")
+    }
+    if err := validateDate(po.OrderDate, "tanggal PO"); err != nil {
+        return err
+    }
+    if err := validateDate(po.ExpectedDate, "tanggal perkiraan datang"); err != nil {
+        return err
+    }
+    if len(po.Lines) == 0 {
+        return fmt.Errorf("PO minimal berisi satu bahan")
+    }
+    po.Total = 0
+    for i := range po.Lines {
+        l := &po.Lines[i]
+        if l.MaterialID == 0 {
+            return fmt.Errorf("baris %d: bahan wajib dipilih", i+1)
+        }
+        if l.Quantity <= 0 {
+            return fmt.Errorf("baris %d: jumlah harus lebih dari 0", i+1)
+        }
+        if l.UnitPrice < 0 {
+            return fmt.Errorf("baris %d: harga tidak boleh negatif", i+1)
+        }
+        l.Subtotal = math.Round(l.Quantity*l.UnitPrice*100) / 100
+        po.Total += l.Subtotal
+    }
+    return nil
+}
+
+func savePurchaseOrderLines(tx *sql.Tx, po *models.PurchaseOrder) error {
+    for i, l := range po.Lines {
+        _, err := tx.Exec(
+            "INSERT INTO purchase_order_lines (purchase_order_id, material_id, quantity, unit_price, notes) VALUES (?, ?, ?, ?, ?)",
+            po.ID, l.MaterialID, l.Quantity, l.UnitPrice, nullableString(l.Notes),
+        )
+        if err != nil {
+            if mysqlErr, ok := err.(*mysql.MySQLError); ok && mysqlErr.Number == 1452 {
+                return fmt.Errorf("baris %d: bahan %d tidak ditemukan", i+1, l.MaterialID)
+            }
+            return err
+        }
+    }
+    return nil
+}
+
+// Create membuat PO baru dengan status draft atau ordered
+func (r *PurchaseOrderRepository) Create(po *models.PurchaseOrder) error {
+    if po.Status == "" {
+        po.Status = "draft"
+    }
+    if po.Status != "draft" && po.Status != "ordered" {
+        return fmt.Errorf("PO baru harus berstatus draft atau ordered")
+    }
+    if err := validatePurchaseOrder(po); err != nil {
+        return err
+    }
+
+    tx, err := r.DB.Begin()
+    if err != nil {
+        return err
+    }
+    defer tx.Rollback()
+
+    res, err := tx.Exec(
+        `INSERT INTO purchase_orders (supplier_id, order_date, expected_date, status, total, notes, created_by)
+         VALUES (?, ?, ?, ?, ?, ?, ?)`,
+        po.SupplierID, po.OrderDate, nullableString(po.ExpectedDate), po.Status, po.Total,
+        nullableString(po.Notes), nullableString(po.CreatedBy),
+    )
+    if err != nil {
+        if mysqlErr, ok := err.(*mysql.MySQLError); ok && mysqlErr.Number == 1452 {
+            return fmt.Errorf("pemasok %d tidak ditemukan", po.SupplierID)
+        }
+        return err
+    }
+    id, _ := res.LastInsertId()
+    po.ID = int(id)
+    if err := savePurchaseOrderLines(tx, po); err != nil {
+        return err
+    }
+    return tx.Commit()
+}
+
+// Update mengganti isi PO; hanya PO draft yang boleh diubah
+func (r *PurchaseOrderRepository) Update(po *models.PurchaseOrder) error {
+    if err := validatePurchaseOrder(po); err != nil {
+        return err
+    }
+    tx, err := r.DB.Begin()
+    if err != nil {
+        return err
+    }
+    defer tx.Rollback()
+
+    var status string
+    if err := tx.QueryRow("SELECT status FROM purchase_orders WHERE id = ? FOR UPDATE", po.ID).Scan(&status); err != nil {
+        return err
+    }
+    if status != "draft" {
+        return fmt.Errorf("PO berstatus %s tidak bisa diubah", status)
+    }
+    _, err = tx.Exec(
+        "UPDATE purchase_orders SET supplier_id = ?, order_date = ?, expected_date = ?, total = ?, notes = ? WHERE id = ?",
+        po.SupplierID, po.OrderDate, nullableString(po.ExpectedDate), po.Total, nullableString(po.Notes), po.ID,
+    )
+    if err != nil {
+        if mysqlErr, ok := err.(*mysql.MySQLError); ok && mysqlErr.Number == 1452 {
+            return fmt.Errorf("pemasok %d tidak ditemukan", po.SupplierID)
+        }
+        return err
+    }
+    if _, err := tx.Exec("DELETE FROM purchase_order_lines WHERE purchase_order_id = ?", po.ID); err != nil {
+        return err
+    }
+    if err := savePurchaseOrderLines(tx, po); err != nil {
+        return err
+    }
+    return tx.Commit()
+}
+
+// Delete menghapus PO draft
+func (r *PurchaseOrderRepository) Delete(id int) error {
+    var status string
+    if err := r.DB.QueryRow("SELECT status FROM purchase_orders WHERE id = ?", id).Scan(&status); err != nil {
+        return err
+    }
+    if status != "draft" {
+        return fmt.Errorf("hanya PO draft yang bisa dihapus, batalkan saja")
+    }
+    _, err := r.DB.Exec("DELETE FROM purchase_orders WHERE id = ?", id)
+    return err
+}
+
+// SetStatus memesan (draft -> ordered) atau membatalkan PO yang belum pernah diterima.
+// Status partially_received dan received hanya diatur lewat penerimaan barang.
+func (r *PurchaseOrderRepository) SetStatus(id int, status string) error {
+    tx, err := r.DB.Begin()
+    if err != nil {
+        return err
+    }
+    defer tx.Rollback()
+
+    var current string
+    if err := tx.QueryRow("SELECT status FROM purchase_orders WHERE id = ? FOR UPDATE", id).Scan(&current); err != nil {
+        return err
+    }
+    switch {
+    case status == "ordered" && current == "draft":
+    case status == "cancelled" && (current == "draft" || current == "ordered"):
+    case status == "cancelled" && current == "partially_received":
+        return fmt.Errorf("PO sudah diterima sebagian dan tidak bisa dibatalkan")
+    default:
+        return fmt.Errorf("status PO tidak bisa diubah dari %s ke %s", current, status)
+    }
+    if _, err := tx.Exec("UPDATE purchase_orders SET status = ? WHERE id = ?", status, id); err != nil {
+        return err
+    }
+    return tx.Commit()
+}
+
+// Receive mencatat penerimaan barang: stok bahan bertambah lewat buku mutasi
+// dan status PO menjadi partially_received atau received
+func (r *PurchaseOrderRepository) Receive(purchaseOrderID int, gr *models.GoodsReceipt) error {
+    if gr.ReceivedDate == "" {
+        gr.ReceivedDate = time.Now().Format("2006-01-02")
+    }
+    if err := validateDate(gr.ReceivedDate, "tanggal terima"); err != nil {
+        return err
+    }
+    gr.Reference = strings.TrimSpace(gr.Reference)
+
+    tx, err := r.DB.Begin()
+    if err != nil {
+        return err
+    }
+    defer tx.Rollback()
+
+    var status string
+    if err := tx.QueryRow("SELECT status FROM purchase_orders WHERE id = ? FOR UPDATE", purchaseOrderID).Scan(&status); err != nil {
+        return err
+    }
+    if status != "ordered" && status != "partially_received" {
+        return fmt.Errorf("PO berstatus %s tidak bisa diterima", status)
+    }
+    lines, err := purchaseOrderLines(tx, purchaseOrderID)
+    if err != nil {
+        return err
+    }
+    byID := map[int]*models.PurchaseOrderLine{}
+    for i := range lines {
+        byID[lines[i].ID] = &lines[i]
+    }
+
+    // Satu baris PO hanya boleh muncul sekali per penerimaan, kalau tidak setiap entri lolos cek sisa sendiri-sendiri
+    received := 0
+    seen := map[int]int{}
+    for i, l := range gr.Lines {
+        if l.Quantity < 0 {
+            return fmt.Errorf("baris %d: jumlah tidak boleh negatif", i+1)
+        }
+        line, ok := byID[l.PurchaseOrderLineID]
+        if !ok {
+            return fmt.Errorf("baris %d: baris PO %d tidak ditemukan", i+1, l.PurchaseOrderLineID)
+        }
+        if first, dup := seen[l.PurchaseOrderLineID]; dup {
+            return fmt.Errorf("baris %d: %s sudah diisi di baris %d", i+1, line.MaterialName, first)
+        }
+        seen[l.PurchaseOrderLineID] = i + 1
+        if remaining := line.Quantity - line.ReceivedQuantity; l.Quantity > remaining+0.0005 {
+            return fmt.Errorf("baris %d: %s hanya tersisa %g %s", i+1, line.MaterialName, remaining, line.Unit)
+        }
+        if l.Quantity > 0 {
+            received++
+        }
+    }
+    if received == 0 {
+        return fmt.Errorf("tidak ada barang yang diterima")
+    }
+
+    res, err := tx.Exec(
+        "INSERT INTO goods_receipts (purchase_order_id, received_date, reference, notes, received_by) VALUES (?, ?, ?, ?, ?)",
+        purchaseOrderID, gr.ReceivedDate, nullableString(gr.Reference), nullableString(gr.Notes), nullableString(gr.ReceivedBy),
+    )
+    if err != nil {
+        return err
+    }
+    id, _ := res.LastInsertId()
+    gr.ID = int(id)
+    gr.PurchaseOrderID = purchaseOrderID
+
+    reference := fmt.Sprintf("PO #%d", purchaseOrderID)
+    if gr.Reference != "" {
+        reference += " / " + gr.Reference
+    }
+    saved := []models.GoodsReceiptLine{}
+    for _, l := range gr.Lines {
+        if l.Quantity == 0 {
+            continue
+        }
+        line := byID[l.PurchaseOrderLineID]
+        mv := models.MaterialMovement{
+            MaterialID:  line.MaterialID,
+            Type:        "in",
+            Quantity:    l.Quantity,
+            Reference:   reference,
+            Reason:      "penerimaan pembelian",
+            PerformedBy: gr.ReceivedBy,
+        }
+        if err := applyMovement(tx, &mv, false); err != nil {
+            return err
+        }
+        _, err := tx.Exec(
+            "INSERT INTO goods_receipt_lines (goods_receipt_id, purchase_order_line_id, quantity, material_movement_id) VALUES (?, ?, ?, ?)",
+            gr.ID, line.ID, l.Quantity, mv.ID,
+        )
+        if err != nil {
+            return err
+        }
+        if _, err := tx.Exec(
+            "UPDATE purchase_order_lines SET received_quantity = received_quantity + ? WHERE id = ?",
+            l.Quantity, line.ID,
+        ); err != nil {
+            return err
+        }
+        line.ReceivedQuantity += l.Quantity
+        saved = append(saved, models.GoodsReceiptLine{
+            PurchaseOrderLineID: line.ID,
+            MaterialID:          line.MaterialID,
+            MaterialName:        line.MaterialName,
+            Quantity:            l.Quantity,
+            MovementID:          mv.ID,
+        })
+    }
+    gr.Lines = saved
+
+    status = "received"
+    for _, line := range lines {
+        if line.ReceivedQuantity < line.Quantity-0.0005 {
+            status = "partially_received"
+            break
+        }
+    }
+    if _, err := tx.Exec("UPDATE purchase_orders SET status = ? WHERE id = ?", status, purchaseOrderID); err != nil {
+        return err
+    }
+    return tx.Commit()
+}
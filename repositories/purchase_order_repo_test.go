@@ -0,0 +1,42 @@
+package repositories
+
+import (
+    "konveksi-app/models"
+    "strings"
+    "testing"
+
+    "github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestReceiveRejectsDuplicateLines(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer db.Close()
+    repo := &PurchaseOrderRepository{DB: db}
+
+    mock.ExpectBegin()
+    mock.ExpectQuery("SELECT status FROM purchase_orders WHERE id = \\? FOR UPDATE").WithArgs(4).
+        WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow("ordered"))
+    mock.ExpectQuery("FROM purchase_order_lines l").WithArgs(4).
+        WillReturnRows(sqlmock.NewRows([]string{"id", "material_id", "name", "unit", "quantity", "received_quantity", "unit_price", "notes"}).
+            AddRow(11, 2, "Kain drill putih", "meter", 100.0, 40.0, 25000.0, ""))
+    mock.ExpectRollback()
+
+    // Masing-masing 50 masih di bawah sisa 60, tapi totalnya 100
+    gr := &models.GoodsReceipt{
+        ReceivedDate: "2025-07-01",
+        Lines: []models.GoodsReceiptLine{
+            {PurchaseOrderLineID: 11, Quantity: 50},
+            {PurchaseOrderLineID: 11, Quantity: 50},
+        },
+    }
+    err = repo.Receive(4, gr)
+    if err == nil || !strings.Contains(err.Error(), "baris 2") {
+        t.Fatalf("err = %v, want duplicate line rejected", err)
+    }
+    if err := mock.ExpectationsWereMet(); err != nil {
+        t.Fatal(err)
+    }
+}
@@ -0,0 +1,417 @@
+package repositories
+
+import (
+    "database/sql"
+    "fmt"
+    "konveksi-app/models"
+    "konveksi-app/sizing"
+    "strings"
+
+    "github.com/go-sql-driver/mysql"
+)
+
+type SizeChartRepository struct {
+    DB *sql.DB
+}
+
+type rowsQueryer interface {
+    Query(query string, args ...interface{}) (*sql.Rows, error)
+}
+
+const sizeChartColumns = `sc.id, COALESCE(sc.catalog_id, 0), COALESCE(uc.name, ''), COALESCE(sc.customer_id, 0),
+    COALESCE(c.name, ''), sc.name, COALESCE(sc.notes, ''), sc.created_at, COALESCE(sc.updated_at, sc.created_at)`
+
+const sizeChartFrom = `FROM size_charts sc
+    LEFT JOIN uniform_catalog uc ON uc.id = sc.catalog_id
+    LEFT JOIN customers c ON c.id = sc.customer_id`
+
+func scanSizeChart(scanner interface{ Scan(...interface{}) error }) (models.SizeChart, error) {
+    var sc models.SizeChart
+    err := scanner.Scan(&sc.ID, &sc.CatalogID, &sc.CatalogName, &sc.CustomerID, &sc.CustomerName,
+        &sc.Name, &sc.Notes, &sc.CreatedAt, &sc.UpdatedAt)
+    sc.Entries = []models.SizeChartEntry{}
+    return sc, err
+}
+
+// GetAll mengambil tabel ukuran, bisa difilter per katalog dan/atau customer (0 = semua)
+func (r *SizeChartRepository) GetAll(catalogID, customerID int) ([]models.SizeChart, error) {
+    where := []string{"1 = 1"}
+    args := []interface{}{}
+    if catalogID != 0 {
+        where = append(where, "sc.catalog_id = ?")
+        args = append(args, catalogID)
+    }
+    if customerID != 0 {
+        where = append(where, "sc.customer_id = ?")
+        args = append(args, customerID)
+    }
+
+    rows, err := r.DB.Query(
+        "SELECT "+sizeChartColumns+" "+sizeChartFrom+" WHERE "+strings.Join(where, " AND ")+" ORDER BY sc.name, sc.id",
+        args...,
+    )
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    charts := []models.SizeChart{}
+    index := map[int]int{}
+    for rows.Next() {
+        sc, err := scanSizeChart(rows)
+        if err != nil {
+            return nil, err
+        }
+        index[sc.ID] = len(charts)
+        charts = append(charts, sc)
+    }
+    if err := rows.Err(); err != nil {
+        return nil, err
+    }
+    if len(charts) == 0 {
+        return charts, nil
+    }
+
+    ids := make([]interface{}, 0, len(charts))
+    for _, sc := range charts {
+        ids = append(ids, sc.ID)
+    }
+    entries, err := r.getEntries("WHERE chart_id IN ("+placeholders(len(ids))+")", ids...)
+    if err != nil {
+        return nil, err
+    }
+    for chartID, list := range entries {
+        charts[index[chartID]].Entries = list
+    }
+    return charts, nil
+}
+
+func (r *SizeChartRepository) GetByID(id int) (*models.SizeChart, error) {
+    sc, err := scanSizeChart(r.DB.QueryRow("SELECT "+sizeChartColumns+" "+sizeChartFrom+" WHERE sc.id = ?", id))
+    if err != nil {
+        return nil, err
+    }
+    entries, err := r.getEntries("WHERE chart_id = ?", id)
+    if err != nil {
+        return nil, err
+    }
+    if list, ok := entries[id]; ok {
+        sc.Entries = list
+    }
+    return &sc, nil
+}
+
+// sizeRangeFields berurutan sama dengan kolom: tinggi, dada, pinggang, pinggul
+func sizeRangeFields(e *models.SizeChartEntry) []**models.SizeRange {
+    return []**models.SizeRange{&e.Height, &e.Chest, &e.Waist, &e.Hip}
+}
+
+var sizeRangeNames = []string{"tinggi badan", "lingkar dada", "lingkar pinggang", "lingkar pinggul"}
+
+// getEntries mengambil ukuran tabel dikelompokkan per chart_id, urut sort_order
+func (r *SizeChartRepository) getEntries(where string, args ...interface{}) (map[int][]models.SizeChartEntry, error) {
+    rows, err := r.DB.Query(`
+        SELECT id, chart_id, size, sort_order, height_min, height_max, chest_min, chest_max,
+               waist_min, waist_max, hip_min, hip_max, COALESCE(notes, '')
+        FROM size_chart_entries `+where+`
+        ORDER BY chart_id, sort_order, id`,
+        args...,
+    )
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    entries := map[int][]models.SizeChartEntry{}
+    for rows.Next() {
+        var e models.SizeChartEntry
+        var chartID int
+        var bounds [8]sql.NullFloat64
+        err := rows.Scan(&e.ID, &chartID, &e.Size, &e.SortOrder,
+            &bounds[0], &bounds[1], &bounds[2], &bounds[3], &bounds[4], &bounds[5], &bounds[6], &bounds[7], &e.Notes)
+        if err != nil {
+            return nil, err
+        }
+        for i, field := range sizeRangeFields(&e) {
+            min, max := bounds[i*2], bounds[i*2+1]
+            if min.Valid || max.Valid {
+                *field = &models.SizeRange{Min: min.Float64, Max: max.Float64}
+            }
+        }
+        entries[chartID] = append(entries[chartID], e)
+    }
+    return entries, rows.Err()
+}
+
+// validateSizeChart mengecek pemilik tabel, ukuran kosong/dobel dan rentang; urutan mengikuti urutan input
+func validateSizeChart(sc *models.SizeChart) error {
+    sc.Name = strings.TrimSpace(sc.Name)
+    if sc.Name == "" {
+        return fmt.Errorf("nama tabel ukuran wajib diisi")
+    }
+    if sc.CatalogID == 0 && sc.CustomerID == 0 {
+        return fmt.Errorf("tabel ukuran harus untuk katalog seragam atau customer")
+    }
+    if len(sc.Entries) == 0 {
+        return fmt.Errorf("tabel ukuran minimal berisi satu ukuran")
+    }
+    unique := map[string]bool{}
+    for i := range sc.Entries {
+        e := &sc.Entries[i]
+        e.Size = strings.TrimSpace(e.Size)
+        key := strings.ToLower(e.Size)
+        if key == "" {
+            return fmt.Errorf("ukuran tidak boleh kosong")
+        }
+        if unique[key] {
+            return fmt.Errorf("ukuran '%s' dobel", e.Size)
+        }
+        unique[key] = true
+        for j, field := range sizeRangeFields(e) {
+            rng := *field
+            if rng == nil {
+                continue
+            }
+            if rng.Min < 0 || rng.Max < 0 || (rng.Max > 0 && rng.Min > rng.Max) {
+                return fmt.Errorf("ukuran '%s': rentang %s tidak valid", e.Size, sizeRangeNames[j])
+            }
+        }
+        e.SortOrder = i + 1
+    }
+    return nil
+}
+
+// checkDuplicateChart menolak tabel kedua untuk kombinasi katalog + customer yang sama
+func checkDuplicateChart(tx *sql.Tx, sc *models.SizeChart) error {
+    var existing int
+    err := tx.QueryRow(
+        `SELECT id FROM size_charts
+         WHERE COALESCE(catalog_id, 0) = ? AND COALESCE(customer_id, 0) = ? AND id != ?
+         LIMIT 1`,
+        sc.CatalogID, sc.CustomerID, sc.ID,
+    ).Scan(&existing)
+    if err == sql.ErrNoRows {
+        return nil
+    } else if err != nil {
+        return err
+    }
+    return fmt.Errorf("tabel ukuran untuk katalog/customer ini sudah ada (id %d)", existing)
+}
+
+// duplicateChartError menerjemahkan pelanggaran UNIQUE chart_scope (insert bersamaan) ke pesan yang sama
+func duplicateChartError(err error) error {
+    if mysqlErr, ok := err.(*mysql.MySQLError); ok && mysqlErr.Number == 1062 {
+        return fmt.Errorf("tabel ukuran untuk katalog/customer ini sudah ada")
+    }
+    return err
+}
+
+func saveSizeChartEntries(tx *sql.Tx, chartID int, entries []models.SizeChartEntry) error {
+    if _, err := tx.Exec("DELETE FROM size_chart_entries WHERE chart_id = ?", chartID); err != nil {
+        return err
+    }
+    for _, e := range entries {
+        args := []interface{}{chartID, e.Size, e.SortOrder}
+        for _, field := range sizeRangeFields(&e) {
+            if rng := *field; rng != nil {
+                args = append(args, nullableRangeBound(rng.Min), nullableRangeBound(rng.Max))
+            } else {
+                args = append(args, nil, nil)
+            }
+        }
+        args = append(args, nullableString(e.Notes))
+        _, err := tx.Exec(
+            `INSERT INTO size_chart_entries
+             (chart_id, size, sort_order, height_min, height_max, chest_min, chest_max, waist_min, waist_max, hip_min, hip_max, notes)
+             VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+            args...,
+        )
+        if err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+// nullableRangeBound menyimpan 0 sebagai NULL (batas tidak ditentukan)
+func nullableRangeBound(v float64) interface{} {
+    if v == 0 {
+        return nil
+    }
+    return v
+}
+
+func (r *SizeChartRepository) Create(sc *models.SizeChart) error {
+    if err := validateSizeChart(sc); err != nil {
+        return err
+    }
+    tx, err := r.DB.Begin()
+    if err != nil {
+        return err
+    }
+    defer tx.Rollback()
+
+    if err := checkDuplicateChart(tx, sc); err != nil {
+        return err
+    }
+    res, err := tx.Exec(
+        "INSERT INTO size_charts (catalog_id, customer_id, name, notes) VALUES (?, ?, ?, ?)",
+        nullableInt(sc.CatalogID), nullableInt(sc.CustomerID), sc.Name, nullableString(sc.Notes),
+    )
+    if err != nil {
+        return duplicateChartError(err)
+    }
+    id, _ := res.LastInsertId()
+    sc.ID = int(id)
+    if err := saveSizeChartEntries(tx, sc.ID, sc.Entries); err != nil {
+        return err
+    }
+    return tx.Commit()
+}
+
+func (r *SizeChartRepository) Update(sc *models.SizeChart) error {
+    if err := validateSizeChart(sc); err != nil {
+        return err
+    }
+    tx, err := r.DB.Begin()
+    if err != nil {
+        return err
+    }
+    defer tx.Rollback()
+
+    var exists int
+    if err := tx.QueryRow("SELECT COUNT(*) FROM size_charts WHERE id = ?", sc.ID).Scan(&exists); err != nil {
+        return err
+    }
+    if exists == 0 {
+        return sql.ErrNoRows
+    }
+    if err := checkDuplicateChart(tx, sc); err != nil {
+        return err
+    }
+    _, err = tx.Exec(
+        "UPDATE size_charts SET catalog_id = ?, customer_id = ?, name = ?, notes = ? WHERE id = ?",
+        nullableInt(sc.CatalogID), nullableInt(sc.CustomerID), sc.Name, nullableString(sc.Notes), sc.ID,
+    )
+    if err != nil {
+        return duplicateChartError(err)
+    }
+    if err := saveSizeChartEntries(tx, sc.ID, sc.Entries); err != nil {
+        return err
+    }
+    return tx.Commit()
+}
+
+func (r *SizeChartRepository) Delete(id int) error {
+    res, err := r.DB.Exec("DELETE FROM size_charts WHERE id = ?", id)
+    if err != nil {
+        return err
+    }
+    if n, _ := res.RowsAffected(); n == 0 {
+        return sql.ErrNoRows
+    }
+    return nil
+}
+
+// CustomerChartIDs mengembalikan tabel ukuran yang berlaku untuk seragam customer, per nama seragam
+func (r *SizeChartRepository) CustomerChartIDs(customerID int) (map[string]int, error) {
+    ids, _, err := chartOrders(r.DB, customerID)
+    return ids, err
+}
+
+// chartOrders mencari tabel ukuran yang berlaku per nama seragam (key huruf kecil) beserta urutan ukurannya.
+// Nama seragam hanya unik di dalam satu customer, jadi pencarian selalu per customer; untuk rekap
+// lintas customer pakai recapCharts.
+func chartOrders(q rowsQueryer, customerID int) (map[string]int, map[string]sizing.Chart, error) {
+    if customerID == 0 {
+        return map[string]int{}, map[string]sizing.Chart{}, nil
+    }
+    rows, err := q.Query(`
+        SELECT DISTINCT cu.uniform_name,
+               (sc.customer_id IS NULL) * 2 + (sc.catalog_id IS NULL) AS priority,
+               sc.id, e.size, e.sort_order
+        FROM customer_uniforms cu
+        JOIN size_charts sc
+          ON (sc.customer_id = cu.customer_id AND (sc.catalog_id IS NULL OR sc.catalog_id = cu.catalog_id))
+          OR (sc.customer_id IS NULL AND sc.catalog_id = cu.catalog_id)
+        JOIN size_chart_entries e ON e.chart_id = sc.id
+        WHERE cu.customer_id = ?
+        ORDER BY priority, sc.id, e.sort_order`,
+        customerID,
+    )
+    if err != nil {
+        return nil, nil, err
+    }
+    defer rows.Close()
+
+    ids := map[string]int{}
+    charts := map[string]sizing.Chart{}
+    for rows.Next() {
+        var name, size string
+        var priority, chartID, sortOrder int
+        if err := rows.Scan(&name, &priority, &chartID, &size, &sortOrder); err != nil {
+            return nil, nil, err
+        }
+        key := strings.ToLower(strings.TrimSpace(name))
+        if id, ok := ids[key]; ok && id != chartID {
+            continue // sudah ada tabel dengan prioritas lebih tinggi
+        }
+        ids[key] = chartID
+        if !charts[key].Contains(size) {
+            charts[key] = append(charts[key], size)
+        }
+    }
+    return ids, charts, rows.Err()
+}
+
+// recapCharts mengambil urutan ukuran untuk rekap beberapa customer. Seragam hanya memakai tabel
+// ukuran jika semua customer di rekap yang punya tabel untuk nama itu memakai tabel yang sama;
+// jika berbeda, urutan bawaan yang dipakai.
+func recapCharts(q rowsQueryer, customerIDs []int) (map[string]sizing.Chart, error) {
+    charts := map[string]sizing.Chart{}
+    chartIDs := map[string]int{}
+    for _, customerID := range customerIDs {
+        ids, customerCharts, err := chartOrders(q, customerID)
+        if err != nil {
+            return nil, err
+        }
+        for key, id := range ids {
+            existing, seen := chartIDs[key]
+            switch {
+            case !seen:
+                chartIDs[key] = id
+                charts[key] = customerCharts[key]
+            case existing != id:
+                chartIDs[key] = 0 // tabel berbeda antar customer
+                delete(charts, key)
+            }
+        }
+    }
+    return charts, nil
+}
+
+// uniformSize adalah pasangan seragam dan ukuran pada item pesanan
+type uniformSize struct {
+    Uniform string
+    Size    string
+}
+
+// checkOrderSizes memastikan ukuran tiap item ada di tabel ukuran seragamnya.
+// Seragam yang belum punya tabel ukuran tidak diperiksa.
+func checkOrderSizes(q rowsQueryer, customerID int, items []uniformSize) error {
+    if len(items) == 0 {
+        return nil
+    }
+    _, charts, err := chartOrders(q, customerID)
+    if err != nil {
+        return err
+    }
+    for _, item := range items {
+        chart := charts[strings.ToLower(strings.TrimSpace(item.Uniform))]
+        if chart != nil && !chart.Contains(item.Size) {
+            return fmt.Errorf("ukuran '%s' tidak ada di tabel ukuran %s (%s)",
+                item.Size, item.Uniform, strings.Join(chart, ", "))
+        }
+    }
+    return nil
+}
@@ -0,0 +1,205 @@
+package repositories
+
+import (
+    "fmt"
+    "regexp"
+    "strconv"
+    "strings"
+    "time"
+)
+
+// AcademicYear mengembalikan tahun ajaran (mulai Juli) untuk tanggal YYYY-MM-DD, mis. "2025/2026"
+func AcademicYear(date string) string {
+    t, err := time.Parse("2006-01-02", pricingDate(date))
+    if err != nil {
+        t = time.Now()
+    }
+    year := t.Year()
+    if t.Month() < time.July {
+        year--
+    }
+    return fmt.Sprintf("%d/%d", year, year+1)
+}
+
+var (
+    academicYearPattern = regexp.MustCompile(`^(\d{4})/(\d{4})$`)
+    // "1", "1A", "Kelas 1 B", "VII", "VII-A", "5/Melati", "TK A", "A". Akhiran hanya boleh satu huruf
+    // rombel atau teks setelah - / supaya nama kelas seperti "Anggrek" atau "Venus" tidak dikira kelas A / V
+    classPattern = regexp.MustCompile(`^(?i)(kelas\s*|kls\s*|tk\s*)?([0-9]+|[ivx]+|a|b)(\s*[-/]\s*\S+|\s*[a-z])?$`)
+    romanNumbers = map[string]int{"I": 1, "II": 2, "III": 3, "IV": 4, "V": 5, "VI": 6, "VII": 7, "VIII": 8, "IX": 9}
+)
+
+func toRoman(n int) string {
+    for roman, v := range romanNumbers {
+        if v == n {
+            return roman
+        }
+    }
+    return strconv.Itoa(n)
+}
+
+// NextClass menentukan kelas berikutnya sesuai jenjang sekolah (TK A→B, SD 1→6, SMP 7→9).
+// graduate=true jika siswa lulus; ok=false jika kelas tidak dikenali.
+// Penulisan dipertahankan: "3B" → "4B", "VII-A" → "VIII-A", "TK A" → "TK B".
+func NextClass(schoolType, class string) (next string, graduate bool, ok bool) {
+    m := classPattern.FindStringSubmatch(strings.TrimSpace(class))
+    if m == nil {
+        return "", false, false
+    }
+    prefix, level, suffix := m[1], strings.ToUpper(m[2]), m[3]
+
+    switch schoolType {
+    case "TK":
+        switch level {
+        case "A":
+            return prefix + matchCase(m[2], "B") + suffix, false, true
+        case "B":
+            return "", true, true
+        }
+        return "", false, false
+    case "SD", "SMP":
+        if level == "A" || level == "B" {
+            return "", false, false
+        }
+        n, roman := 0, false
+        if v, err := strconv.Atoi(level); err == nil {
+            n = v
+        } else if v, found := romanNumbers[level]; found {
+            n, roman = v, true
+        } else {
+            return "", false, false
+        }
+
+        first, last := 1, 6
+        if schoolType == "SMP" {
+            first, last = 7, 9
+            if n >= 1 && n <= 3 {
+                first, last = 1, 3 // penomoran lokal kelas 1-3
+            }
+        }
+        if n < first || n > last {
+            return "", false, false
+        }
+        if n == last {
+            return "", true, true
+        }
+        nextLevel := strconv.Itoa(n + 1)
+        if roman {
+            nextLevel = toRoman(n + 1)
+        }
+        return prefix + nextLevel + suffix, false, true
+    }
+    return "", false, false
+}
+
+func matchCase(original, replacement string) string {
+    if strings.ToLower(original) == original {
+        return strings.ToLower(replacement)
+    }
+    return replacement
+}
+
+// PromotionRow adalah rencana kenaikan kelas satu siswa
+type PromotionRow struct {
+    StudentID int    `json:"student_id"`
+    Name      string `json:"name"`
+    NIS       string `json:"nis"`
+    FromClass string `json:"from_class"`
+    ToClass   string `json:"to_class,omitempty"`
+    Action    string `json:"action"` // promote, graduate, skip
+    Reason    string `json:"reason,omitempty"`
+}
+
+// PromoteStudents menaikkan kelas semua siswa aktif satu sekolah untuk tahun ajaran academicYear.
+// Lulusan ditandai tidak aktif. apply=false hanya preview. Satu sekolah hanya bisa dinaikkan
+// sekali per tahun ajaran.
+func (r *StudentRepository) PromoteStudents(customerID int, academicYear, performedBy string, apply bool) ([]PromotionRow, error) {
+    if !academicYearPattern.MatchString(academicYear) {
+        return nil, fmt.Errorf("tahun ajaran harus berformat YYYY/YYYY")
+    }
+
+    tx, err := r.DB.Begin()
+    if err != nil {
+        return nil, err
+    }
+    defer tx.Rollback()
+
+    var schoolType string
+    if err := tx.QueryRow("SELECT type FROM customers WHERE id = ?", customerID).Scan(&schoolType); err != nil {
+        return nil, err
+    }
+    if schoolType != "TK" && schoolType != "SD" && schoolType != "SMP" {
+        return nil, fmt.Errorf("kenaikan kelas hanya untuk sekolah TK, SD atau SMP (tipe customer: %s)", schoolType)
+    }
+
+    var done int
+    if err := tx.QueryRow(
+        "SELECT COUNT(*) FROM student_promotions WHERE customer_id = ? AND academic_year = ?",
+        customerID, academicYear,
+    ).Scan(&done); err != nil {
+        return nil, err
+    }
+    if done > 0 {
+        return nil, fmt.Errorf("kenaikan kelas tahun ajaran %s sudah dilakukan untuk sekolah ini", academicYear)
+    }
+
+    rows, err := tx.Query(
+        `SELECT id, name, COALESCE(nis, ''), COALESCE(class, '') FROM students
+         WHERE customer_id = ? AND active = 1 ORDER BY class, name FOR UPDATE`,
+        customerID,
+    )
+    if err != nil {
+        return nil, err
+    }
+    result := []PromotionRow{}
+    for rows.Next() {
+        var p PromotionRow
+        if err := rows.Scan(&p.StudentID, &p.Name, &p.NIS, &p.FromClass); err != nil {
+            rows.Close()
+            return nil, err
+        }
+        next, graduate, ok := NextClass(schoolType, p.FromClass)
+        switch {
+        case !ok:
+            p.Action = "skip"
+            p.Reason = fmt.Sprintf("kelas '%s' tidak dikenali untuk %s", p.FromClass, schoolType)
+        case graduate:
+            p.Action = "graduate"
+        default:
+            p.Action = "promote"
+            p.ToClass = next
+        }
+        result = append(result, p)
+    }
+    rows.Close()
+    if err := rows.Err(); err != nil {
+        return nil, err
+    }
+
+    if !apply {
+        return result, nil
+    }
+
+    counts := map[string]int{}
+    for _, p := range result {
+        counts[p.Action]++
+        switch p.Action {
+        case "promote":
+            _, err = tx.Exec("UPDATE students SET class = ? WHERE id = ?", p.ToClass, p.StudentID)
+        case "graduate":
+            _, err = tx.Exec("UPDATE students SET active = 0 WHERE id = ?", p.StudentID)
+        }
+        if err != nil {
+            return nil, err
+        }
+    }
+    _, err = tx.Exec(
+        `INSERT INTO student_promotions (customer_id, academic_year, promoted, graduated, skipped, performed_by)
+         VALUES (?, ?, ?, ?, ?, ?)`,
+        customerID, academicYear, counts["promote"], counts["graduate"], counts["skip"], nullableString(performedBy),
+    )
+    if err != nil {
+        return nil, err
+    }
+    return result, tx.Commit()
+}
@@ -0,0 +1,37 @@
+package repositories
+
+import "testing"
+
+func TestNextClass(t *testing.T) {
+    tests := []struct {
+        schoolType, class string
+        next              string
+        graduate, ok      bool
+    }{
+        {"SD", "1", "2", false, true},
+        {"SD", "3B", "4B", false, true},
+        {"SD", "Kelas 2 A", "Kelas 3 A", false, true},
+        {"SD", "5/Melati", "6/Melati", false, true},
+        {"SD", "6", "", true, true},
+        {"SMP", "VII-A", "VIII-A", false, true},
+        {"SMP", "IX", "", true, true},
+        {"SMP", "2", "3", false, true},
+        {"TK", "TK A", "TK B", false, true},
+        {"TK", "a", "b", false, true},
+        {"TK", "B", "", true, true},
+        // Nama kelas yang kebetulan diawali huruf level
+        {"TK", "Anggrek", "", false, false},
+        {"TK", "Bintang", "", false, false},
+        {"SD", "Venus", "", false, false},
+        {"SD", "Kelas Mars", "", false, false},
+        {"SD", "1 Merah Putih", "", false, false},
+        {"SD", "7", "", false, false},
+    }
+    for _, tt := range tests {
+        next, graduate, ok := NextClass(tt.schoolType, tt.class)
+        if next != tt.next || graduate != tt.graduate || ok != tt.ok {
+            t.Errorf("NextClass(%q, %q) = %q, %v, %v; want %q, %v, %v",
+                tt.schoolType, tt.class, next, graduate, ok, tt.next, tt.graduate, tt.ok)
+        }
+    }
+}
@@ -0,0 +1,244 @@
+package repositories
+
+import (
+    "database/sql"
+    "fmt"
+    "konveksi-app/models"
+    "strings"
+)
+
+type StudentRepository struct {
+    DB *sql.DB
+}
+
+const studentColumns = `s.id, s.customer_id, c.name, s.name, COALESCE(s.class, ''), COALESCE(s.nis, ''),
+    COALESCE(s.parent_name, ''), COALESCE(s.parent_contact, ''), s.active, COALESCE(s.notes, ''),
+    s.created_at, COALESCE(s.updated_at, s.created_at)`
+
+func scanStudent(scanner interface{ Scan(...interface{}) error }) (models.Student, error) {
+    var s models.Student
+    err := scanner.Scan(&s.ID, &s.CustomerID, &s.CustomerName, &s.Name, &s.Class, &s.NIS,
+        &s.ParentName, &s.ParentContact, &s.Active, &s.Notes, &s.CreatedAt, &s.UpdatedAt)
+    return s, err
+}
+
+// StudentFilter untuk pencarian siswa. Query mencocokkan nama, NIS atau kontak orang tua.
+type StudentFilter struct {
+    CustomerID      int
+    Class           string
+    Query           string
+    IncludeInactive bool
+    Limit           int
+}
+
+func (r *StudentRepository) Search(f StudentFilter) ([]models.Student, error) {
+    where := []string{"1 = 1"}
+    args := []interface{}{}
+    if f.CustomerID != 0 {
+        where = append(where, "s.customer_id = ?")
+        args = append(args, f.CustomerID)
+    }
+    if f.Class != "" {
+        where = append(where, "s.class = ?")
+        args = append(args, f.Class)
+    }
+    if !f.IncludeInactive {
+        where = append(where, "s.active = 1")
+    }
+    if q := strings.TrimSpace(f.Query); q != "" {
+        like := "%" + likeEscaper.Replace(q) + "%"
+        where = append(where, "(s.name LIKE ? OR s.nis LIKE ? OR s.parent_contact LIKE ?)")
+        args = append(args, like, like, like)
+    }
+    limit := f.Limit
+    if limit <= 0 || limit > 1000 {
+        limit = 200
+    }
+    args = append(args, limit)
+
+    rows, err := r.DB.Query(`
+        SELECT `+studentColumns+`
+        FROM students s
+        JOIN customers c ON c.id = s.customer_id
+        WHERE `+strings.Join(where, " AND ")+`
+        ORDER BY c.name, s.class, s.name
+        LIMIT ?`,
+        args...,
+    )
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    students := []models.Student{}
+    for rows.Next() {
+        s, err := scanStudent(rows)
+        if err != nil {
+            return nil, err
+        }
+        students = append(students, s)
+    }
+    return students, rows.Err()
+}
+
+func (r *StudentRepository) GetByID(id int) (*models.Student, error) {
+    s, err := scanStudent(r.DB.QueryRow(`
+        SELECT `+studentColumns+`
+        FROM students s
+        JOIN customers c ON c.id = s.customer_id
+        WHERE s.id = ?`, id))
+    if err != nil {
+        return nil, err
+    }
+    return &s, nil
+}
+
+func (r *StudentRepository) Create(s *models.Student) error {
+    res, err := r.DB.Exec(
+        `INSERT INTO students (customer_id, name, class, nis, parent_name, parent_contact, active, notes)
+         VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+        s.CustomerID, s.Name, nullableString(s.Class), nullableString(s.NIS),
+        nullableString(s.ParentName), nullableString(s.ParentContact), s.Active, nullableString(s.Notes),
+    )
+    if err != nil {
+        return err
+    }
+    id, _ := res.LastInsertId()
+    s.ID = int(id)
+    return nil
+}
+
+func (r *StudentRepository) Update(s *models.Student) error {
+    res, err := r.DB.Exec(
+        `UPDATE students SET name = ?, class = ?, nis = ?, parent_name = ?, parent_contact = ?, active = ?, notes = ?
+         WHERE id = ?`,
+        s.Name, nullableString(s.Class), nullableString(s.NIS),
+        nullableString(s.ParentName), nullableString(s.ParentContact), s.Active, nullableString(s.Notes), s.ID,
+    )
+    if err != nil {
+        return err
+    }
+    if n, _ := res.RowsAffected(); n == 0 {
+        var exists int
+        if err := r.DB.QueryRow("SELECT COUNT(*) FROM students WHERE id = ?", s.ID).Scan(&exists); err != nil || exists == 0 {
+            return sql.ErrNoRows
+        }
+    }
+    return nil
+}
+
+// Delete menghapus siswa; item pesanan lama tetap ada dengan student_id NULL
+func (r *StudentRepository) Delete(id int) error {
+    res, err := r.DB.Exec("DELETE FROM students WHERE id = ?", id)
+    if err != nil {
+        return err
+    }
+    if n, _ := res.RowsAffected(); n == 0 {
+        return sql.ErrNoRows
+    }
+    return nil
+}
+
+// GetOrderHistory mengambil semua item pesanan siswa, terbaru dulu (kecuali yang dibatalkan)
+func (r *StudentRepository) GetOrderHistory(studentID int) ([]models.StudentOrderHistory, error) {
+    rows, err := r.DB.Query(`
+        SELECT soi.id, t.id, t.transaction_date, COALESCE(t.academic_year, ''), t.status, COALESCE(soi.grade, ''), soi.uniform_name, soi.size,
+               soi.quantity, soi.unit_price, soi.quantity * soi.unit_price, COALESCE(soi.notes, '')
+        FROM student_order_items soi
+        JOIN transactions t ON t.id = soi.transaction_id
+        WHERE soi.student_id = ? AND t.status != 'cancelled'
+        ORDER BY t.transaction_date DESC, soi.id`,
+        studentID,
+    )
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    history := []models.StudentOrderHistory{}
+    for rows.Next() {
+        var h models.StudentOrderHistory
+        err := rows.Scan(&h.ItemID, &h.TransactionID, &h.TransactionDate, &h.AcademicYear, &h.Status, &h.Grade, &h.UniformName,
+            &h.Size, &h.Quantity, &h.UnitPrice, &h.Subtotal, &h.Notes)
+        if err != nil {
+            return nil, err
+        }
+        history = append(history, h)
+    }
+    return history, rows.Err()
+}
+
+// resolveStudent mengisi item.StudentID: memakai data siswa jika StudentID diisi, atau mencari
+// siswa aktif dengan nama yang sama di sekolah tersebut, atau membuat siswa baru.
+// Kelas dipakai untuk memilih di antara siswa bernama sama; siswa satu-satunya dengan nama itu
+// tetap dipakai walau kelasnya berbeda (mis. sudah naik kelas) supaya tidak tercatat ganda.
+func resolveStudent(tx *sql.Tx, item *models.StudentOrderItem) error {
+    if item.StudentID != 0 {
+        var name, class string
+        err := tx.QueryRow(
+            "SELECT name, COALESCE(class, '') FROM students WHERE id = ? AND customer_id = ?",
+            item.StudentID, item.CustomerID,
+        ).Scan(&name, &class)
+        if err != nil {
+            return err
+        }
+        if item.StudentName == "" {
+            item.StudentName = name
+        }
+        if item.Grade == "" {
+            item.Grade = class
+        }
+        return nil
+    }
+
+    name := strings.TrimSpace(item.StudentName)
+    if name == "" {
+        return nil
+    }
+    grade := strings.TrimSpace(item.Grade)
+    rows, err := tx.Query(
+        "SELECT id, COALESCE(class, '') FROM students WHERE customer_id = ? AND name = ? AND active = 1 ORDER BY id",
+        item.CustomerID, name,
+    )
+    if err != nil {
+        return err
+    }
+    var ids []int
+    for rows.Next() {
+        var id int
+        var class string
+        if err := rows.Scan(&id, &class); err != nil {
+            rows.Close()
+            return err
+        }
+        if grade != "" && strings.EqualFold(strings.TrimSpace(class), grade) {
+            item.StudentID = id
+        }
+        ids = append(ids, id)
+    }
+    rows.Close()
+    if err := rows.Err(); err != nil {
+        return err
+    }
+
+    switch {
+    case item.StudentID != 0:
+        return nil
+    case len(ids) == 1:
+        item.StudentID = ids[0]
+        return nil
+    case len(ids) > 1:
+        return fmt.Errorf("ada %d siswa aktif bernama %s, pilih siswa (student_id) atau isi kelas yang sesuai", len(ids), name)
+    }
+
+    res, err := tx.Exec(
+        "INSERT INTO students (customer_id, name, class) VALUES (?, ?, ?)",
+        item.CustomerID, name, nullableString(grade),
+    )
+    if err != nil {
+        return err
+    }
+    id, _ := res.LastInsertId()
+    item.StudentID = int(id)
+    return nil
+}
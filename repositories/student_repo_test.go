@@ -0,0 +1,70 @@
+package repositories
+
+import (
+    "database/sql"
+    "konveksi-app/models"
+    "strings"
+    "testing"
+
+    "github.com/DATA-DOG/go-sqlmock"
+)
+
+func beginMock(t *testing.T) (*sql.DB, sqlmock.Sqlmock, *sql.Tx) {
+    db, mock, err := sqlmock.New()
+    if err != nil {
+        t.Fatal(err)
+    }
+    mock.ExpectBegin()
+    tx, err := db.Begin()
+    if err != nil {
+        t.Fatal(err)
+    }
+    return db, mock, tx
+}
+
+func TestResolveStudent(t *testing.T) {
+    tests := []struct {
+        name     string
+        grade    string
+        existing [][2]interface{} // id, class
+        wantID   int
+        wantErr  string
+        insert   bool
+    }{
+        {name: "kelas sama", grade: "2A", existing: [][2]interface{}{{4, "1A"}, {9, "2A"}}, wantID: 9},
+        {name: "satu siswa beda kelas", grade: "2A", existing: [][2]interface{}{{4, "1A"}}, wantID: 4},
+        {name: "satu siswa tanpa kelas di pesanan", existing: [][2]interface{}{{4, "1A"}}, wantID: 4},
+        {name: "beberapa siswa tanpa kelas cocok", grade: "3C", existing: [][2]interface{}{{4, "1A"}, {9, "2A"}}, wantErr: "2 siswa aktif"},
+        {name: "siswa baru", grade: "1B", wantID: 15, insert: true},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            db, mock, tx := beginMock(t)
+            defer db.Close()
+
+            rows := sqlmock.NewRows([]string{"id", "class"})
+            for _, s := range tt.existing {
+                rows.AddRow(s[0], s[1])
+            }
+            mock.ExpectQuery("FROM students WHERE customer_id = \\? AND name = \\?").WithArgs(3, "Budi").WillReturnRows(rows)
+            if tt.insert {
+                mock.ExpectExec("INSERT INTO students").WithArgs(3, "Budi", tt.grade).WillReturnResult(sqlmock.NewResult(15, 1))
+            }
+
+            item := &models.StudentOrderItem{CustomerID: 3, StudentName: " Budi ", Grade: tt.grade}
+            err := resolveStudent(tx, item)
+            if tt.wantErr != "" {
+                if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+                    t.Fatalf("err = %v, want %q", err, tt.wantErr)
+                }
+            } else if err != nil {
+                t.Fatal(err)
+            } else if item.StudentID != tt.wantID {
+                t.Fatalf("student id = %d, want %d", item.StudentID, tt.wantID)
+            }
+            if err := mock.ExpectationsWereMet(); err != nil {
+                t.Fatal(err)
+            }
+        })
+    }
+}
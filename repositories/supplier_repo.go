@@ -0,0 +1,223 @@
+package repositories
+
+import (
+    "database/sql"
+    "fmt"
+    "konveksi-app/models"
+    "math"
+    "strings"
+    "time"
+)
+
+type SupplierRepository struct {
+    DB *sql.DB
+}
+
+const supplierColumns = `id, name, COALESCE(contact, ''), COALESCE(email, ''), COALESCE(address, ''), COALESCE(notes, ''),
+    active, created_at, COALESCE(updated_at, created_at)`
+
+func scanSupplier(scanner interface{ Scan(...interface{}) error }) (models.Supplier, error) {
+    var s models.Supplier
+    err := scanner.Scan(&s.ID, &s.Name, &s.Contact, &s.Email, &s.Address, &s.Notes, &s.Active, &s.CreatedAt, &s.UpdatedAt)
+    return s, err
+}
+
+func (r *SupplierRepository) GetAll(includeInactive bool) ([]models.Supplier, error) {
+    query := "SELECT " + supplierColumns + " FROM suppliers"
+    if !includeInactive {
+        query += " WHERE active = 1"
+    }
+    rows, err := r.DB.Query(query + " ORDER BY name")
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    suppliers := []models.Supplier{}
+    for rows.Next() {
+        s, err := scanSupplier(rows)
+        if err != nil {
+            return nil, err
+        }
+        suppliers = append(suppliers, s)
+    }
+    return suppliers, rows.Err()
+}
+
+func (r *SupplierRepository) GetByID(id int) (*models.Supplier, error) {
+    s, err := scanSupplier(r.DB.QueryRow("SELECT "+supplierColumns+" FROM suppliers WHERE id = ?", id))
+    if err != nil {
+        return nil, err
+    }
+    return &s, nil
+}
+
+func validateSupplier(s *models.Supplier) error {
+    s.Name = strings.Join(strings.Fields(s.Name), " ")
+    s.Contact = strings.TrimSpace(s.Contact)
+    s.Email = strings.TrimSpace(s.Email)
+    s.Address = strings.TrimSpace(s.Address)
+    if s.Name == "" {
+        return fmt.Errorf("nama pemasok wajib diisi")
+    }
+    return nil
+}
+
+func (r *SupplierRepository) Create(s *models.Supplier) error {
+    if err := validateSupplier(s); err != nil {
+        return err
+    }
+    res, err := r.DB.Exec(
+        "INSERT INTO suppliers (name, contact, email, address, notes, active) VALUES (?, ?, ?, ?, ?, ?)",
+        s.Name, nullableString(s.Contact), nullableString(s.Email), nullableString(s.Address), nullableString(s.Notes), s.Active,
+    )
+    if err != nil {
+        return err
+    }
+    id, _ := res.LastInsertId()
+    s.ID = int(id)
+    return nil
+}
+
+func (r *SupplierRepository) Update(s *models.Supplier) error {
+    if err := validateSupplier(s); err != nil {
+        return err
+    }
+    res, err := r.DB.Exec(
+        "UPDATE suppliers SET name = ?, contact = ?, email = ?, address = ?, notes = ?, active = ? WHERE id = ?",
+        s.Name, nullableString(s.Contact), nullableString(s.Email), nullableString(s.Address), nullableString(s.Notes), s.Active, s.ID,
+    )
+    if err != nil {
+        return err
+    }
+    if n, _ := res.RowsAffected(); n == 0 {
+        var exists int
+        if err := r.DB.QueryRow("SELECT COUNT(*) FROM suppliers WHERE id = ?", s.ID).Scan(&exists); err != nil || exists == 0 {
+            return sql.ErrNoRows
+        }
+    }
+    return nil
+}
+
+// Delete menghapus pemasok yang belum punya PO; selebihnya cukup dinonaktifkan
+func (r *SupplierRepository) Delete(id int) error {
+    var orders int
+    if err := r.DB.QueryRow("SELECT COUNT(*) FROM purchase_orders WHERE supplier_id = ?", id).Scan(&orders); err != nil {
+        return err
+    }
+    if orders > 0 {
+        return fmt.Errorf("pemasok sudah punya %d PO, nonaktifkan saja", orders)
+    }
+    res, err := r.DB.Exec("DELETE FROM suppliers WHERE id = ?", id)
+    if err != nil {
+        return err
+    }
+    if n, _ := res.RowsAffected(); n == 0 {
+        return sql.ErrNoRows
+    }
+    return nil
+}
+
+// Payables menghitung hutang per pemasok: nilai barang yang sudah diterima dikurangi pembayaran.
+// supplierID 0 berarti semua pemasok; outstandingOnly hanya yang masih punya sisa hutang.
+func (r *SupplierRepository) Payables(supplierID int, outstandingOnly bool) ([]models.SupplierPayable, error) {
+    query := `
+        SELECT s.id, s.name,
+               COALESCE((SELECT SUM(l.received_quantity * l.unit_price)
+                         FROM purchase_order_lines l
+                         JOIN purchase_orders po ON po.id = l.purchase_order_id
+                         WHERE po.supplier_id = s.id AND po.status != 'cancelled'), 0),
+               COALESCE((SELECT SUM(p.amount) FROM supplier_payments p WHERE p.supplier_id = s.id), 0),
+               (SELECT COUNT(*) FROM purchase_orders po
+                WHERE po.supplier_id = s.id AND po.status IN ('ordered', 'partially_received'))
+        FROM suppliers s`
+    args := []interface{}{}
+    if supplierID != 0 {
+        query += " WHERE s.id = ?"
+        args = append(args, supplierID)
+    }
+    rows, err := r.DB.Query(query+" ORDER BY s.name", args...)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    payables := []models.SupplierPayable{}
+    for rows.Next() {
+        var p models.SupplierPayable
+        if err := rows.Scan(&p.SupplierID, &p.SupplierName, &p.ReceivedValue, &p.Paid, &p.OpenOrders); err != nil {
+            return nil, err
+        }
+        p.ReceivedValue = math.Round(p.ReceivedValue*100) / 100
+        p.Outstanding = math.Round((p.ReceivedValue-p.Paid)*100) / 100
+        if outstandingOnly && p.Outstanding <= 0 {
+            continue
+        }
+        payables = append(payables, p)
+    }
+    return payables, rows.Err()
+}
+
+func (r *SupplierRepository) GetPayments(supplierID int) ([]models.SupplierPayment, error) {
+    rows, err := r.DB.Query(`
+        SELECT id, supplier_id, COALESCE(purchase_order_id, 0), payment_date, amount, COALESCE(method, ''),
+               COALESCE(reference, ''), COALESCE(notes, ''), COALESCE(created_by, ''), created_at
+        FROM supplier_payments
+        WHERE supplier_id = ?
+        ORDER BY payment_date DESC, id DESC`,
+        supplierID,
+    )
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    payments := []models.SupplierPayment{}
+    for rows.Next() {
+        var p models.SupplierPayment
+        err := rows.Scan(&p.ID, &p.SupplierID, &p.PurchaseOrderID, &p.PaymentDate, &p.Amount, &p.Method,
+            &p.Reference, &p.Notes, &p.CreatedBy, &p.CreatedAt)
+        if err != nil {
+            return nil, err
+        }
+        payments = append(payments, p)
+    }
+    return payments, rows.Err()
+}
+
+// CreatePayment mencatat pembayaran ke pemasok; PO boleh kosong untuk pembayaran gabungan
+func (r *SupplierRepository) CreatePayment(p *models.SupplierPayment) error {
+    if p.Amount <= 0 {
+        return fmt.Errorf("jumlah pembayaran harus lebih dari 0")
+    }
+    if p.PaymentDate == "" {
+        p.PaymentDate = time.Now().Format("2006-01-02")
+    } else if _, err := time.Parse("2006-01-02", p.PaymentDate); err != nil {
+        return fmt.Errorf("tanggal pembayaran harus berformat YYYY-MM-DD")
+    }
+    if _, err := r.GetByID(p.SupplierID); err != nil {
+        return err
+    }
+    if p.PurchaseOrderID != 0 {
+        var supplierID int
+        err := r.DB.QueryRow("SELECT supplier_id FROM purchase_orders WHERE id = ?", p.PurchaseOrderID).Scan(&supplierID)
+        if err == sql.ErrNoRows || (err == nil && supplierID != p.SupplierID) {
+            return fmt.Errorf("PO %d bukan milik pemasok ini", p.PurchaseOrderID)
+        } else if err != nil {
+            return err
+        }
+    }
+
+    res, err := r.DB.Exec(
+        `INSERT INTO supplier_payments (supplier_id, purchase_order_id, payment_date, amount, method, reference, notes, created_by)
+         VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+        p.SupplierID, nullableInt(p.PurchaseOrderID), p.PaymentDate, p.Amount, nullableString(p.Method),
+        nullableString(p.Reference), nullableString(p.Notes), nullableString(p.CreatedBy),
+    )
+    if err != nil {
+        return err
+    }
+    id, _ := res.LastInsertId()
+    p.ID = int(id)
+    return nil
+}
@@ -3,9 +3,11 @@ package repositories
 import (
     "database/sql"
     "konveksi-app/models"
+    "konveksi-app/sizing"
     _ "github.com/go-sql-driver/mysql"
     "fmt"
     "log"
+    "time"
 )
 
 type TransactionRepository struct {
@@ -36,12 +38,36 @@ func (r *TransactionRepository) Create(transaction *models.Transaksi) error {
         }
     }()
 
+    transaction.AcademicYear = AcademicYear(transaction.Transaksidate)
+
+    sizes := make([]uniformSize, len(transaction.Items))
+    for i, item := range transaction.Items {
+        sizes[i] = uniformSize{item.UniformName, item.Size}
+    }
+    if err = checkOrderSizes(tx, transaction.CustomerID, sizes); err != nil {
+        return err
+    }
+
+    // Harga mengikuti daftar harga yang berlaku pada tanggal transaksi; unit_price dari client
+    // hanya dipakai untuk seragam di luar daftar harga
+    transaction.Total = 0
+    for i := range transaction.Items {
+        item := &transaction.Items[i]
+        item.UnitPrice, err = itemPrice(tx, transaction.CustomerID, item.UniformName, item.Size, pricingDate(transaction.Transaksidate), item.UnitPrice)
+        if err != nil {
+            return fmt.Errorf("failed to look up price for item %d: %v", i+1, err)
+        }
+        item.Subtotal = item.UnitPrice * float64(item.Quantity)
+        transaction.Total += item.Subtotal
+    }
+
     result, err := tx.Exec(`
         INSERT INTO transactions 
-        (customer_id, transaction_date, payment_date, status, total_price, notes) 
-        VALUES (?, ?, ?, ?, ?, ?)`,
+        (customer_id, transaction_date, academic_year, payment_date, status, total_price, notes) 
+        VALUES (?, ?, ?, ?, ?, ?, ?)`,
         transaction.CustomerID, 
         transaction.Transaksidate,
+        transaction.AcademicYear,
         transaction.Paymentdate, 
         transaction.Status,
         transaction.Total, 
@@ -84,6 +110,16 @@ func (r *TransactionRepository) Create(transaction *models.Transaksi) error {
 
     log.Printf("All %d items inserted successfully", len(transaction.Items))
 
+    if transaction.ReserveStock {
+        lines := make([]orderLine, len(transaction.Items))
+        for i, item := range transaction.Items {
+            lines[i] = orderLine{uniformSize{item.UniformName, item.Size}, item.Quantity}
+        }
+        if err = reserveOrderLines(tx, transaction.ID, transaction.CustomerID, lines); err != nil {
+            return fmt.Errorf("failed to reserve stock: %v", err)
+        }
+    }
+
     if err = tx.Commit(); err != nil {
         log.Printf("Error committing transaction: %v", err)
         return fmt.Errorf("failed to commit transaction: %v", err)
@@ -93,6 +129,14 @@ func (r *TransactionRepository) Create(transaction *models.Transaksi) error {
     return nil
 }
 
+// pricingDate memakai tanggal transaksi (YYYY-MM-DD) untuk memilih harga, hari ini jika kosong
+func pricingDate(transactionDate string) string {
+    if len(transactionDate) >= 10 {
+        return transactionDate[:10]
+    }
+    return time.Now().Format("2006-01-02")
+}
+
 func (r *TransactionRepository) CreateStudentOrder(transaction *models.Transaksi, studentItems []models.StudentOrderItem) error {
     log.Printf("Starting student order creation for customer: %d", transaction.CustomerID)
     
@@ -108,18 +152,46 @@ func (r *TransactionRepository) CreateStudentOrder(transaction *models.Transaksi
         }
     }()
 
+    transaction.AcademicYear = AcademicYear(transaction.Transaksidate)
+
+    sizes := make([]uniformSize, len(studentItems))
+    for i, item := range studentItems {
+        sizes[i] = uniformSize{item.UniformName, item.Size}
+    }
+    if err = checkOrderSizes(tx, transaction.CustomerID, sizes); err != nil {
+        return err
+    }
+
+    // Harga mengikuti daftar harga yang berlaku pada tanggal transaksi; unit_price dari client
+    // hanya dipakai untuk seragam di luar daftar harga
     var total float64
-    for _, item := range studentItems {
-        total += item.UnitPrice * float64(item.Quantity)
+    for i := range studentItems {
+        item := &studentItems[i]
+        item.CustomerID = transaction.CustomerID
+        if err = resolveStudent(tx, item); err != nil {
+            log.Printf("Error resolving student for item %d: %v", i+1, err)
+            return err
+        }
+        if err = checkMeasurement(tx, item); err != nil {
+            return err
+        }
+        item.UnitPrice, err = itemPrice(tx, transaction.CustomerID, item.UniformName, item.Size, pricingDate(transaction.Transaksidate), item.UnitPrice)
+        if err != nil {
+            log.Printf("Error looking up price for student item %d: %v", i+1, err)
+            return err
+        }
+        item.Subtotal = item.UnitPrice * float64(item.Quantity)
+        total += item.Subtotal
     }
     transaction.Total = total
 
     result, err := tx.Exec(`
         INSERT INTO transactions 
-        (customer_id, transaction_date, payment_date, status, total_price, notes) 
-        VALUES (?, ?, ?, ?, ?, ?)`,
+        (customer_id, transaction_date, academic_year, payment_date, status, total_price, notes) 
+        VALUES (?, ?, ?, ?, ?, ?, ?)`,
         transaction.CustomerID, 
         transaction.Transaksidate,
+        transaction.AcademicYear,
         transaction.Paymentdate, 
         transaction.Status,
         transaction.Total, 
@@ -144,9 +216,11 @@ func (r *TransactionRepository) CreateStudentOrder(transaction *models.Transaksi
         
         _, err := tx.Exec(`
             INSERT INTO student_order_items 
-            (customer_id, student_name, grade, transaction_id, uniform_name, size, quantity, unit_price, notes) 
-            VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+            (customer_id, student_id, measurement_id, student_name, grade, transaction_id, uniform_name, size, quantity, unit_price, notes) 
+            VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
             item.CustomerID,
+            nullableInt(item.StudentID),
+            nullableInt(item.MeasurementID),
             item.StudentName,
             item.Grade,
             item.TransactionID,
@@ -167,6 +241,17 @@ func (r *TransactionRepository) CreateStudentOrder(transaction *models.Transaksi
 
     log.Printf("All %d student items inserted successfully", len(studentItems))
 
+    if transaction.ReserveStock {
+        lines := make([]orderLine, len(studentItems))
+        for i, item := range studentItems {
+            lines[i] = orderLine{uniformSize{item.UniformName, item.Size}, item.Quantity}
+        }
+        if err = reserveOrderLines(tx, transaction.ID, transaction.CustomerID, lines); err != nil {
+            log.Printf("Error reserving stock: %v", err)
+            return err
+        }
+    }
+
     if err = tx.Commit(); err != nil {
         log.Printf("Error committing transaction: %v", err)
         return err
@@ -236,14 +321,14 @@ func (r *TransactionRepository) GetByIDNormal(id int) (*models.Transaksi, error)
     var t models.Transaksi
     query := `
         SELECT t.id, t.customer_id, c.name AS customer_name, 
-            t.transaction_date, t.payment_date, t.status, 
+            t.transaction_date, COALESCE(t.academic_year, ''), t.payment_date, t.status, t.production_stage, 
             t.total_price, t.notes, t.created_at
         FROM transactions t
         JOIN customers c ON t.customer_id = c.id
         WHERE t.id = ?`
     err := r.DB.QueryRow(query, id).Scan(
         &t.ID, &t.CustomerID, &t.Customer_name,
-        &t.Transaksidate, &t.Paymentdate, &t.Status,
+        &t.Transaksidate, &t.AcademicYear, &t.Paymentdate, &t.Status, &t.ProductionStage,
         &t.Total, &t.Notes, &t.CreatedAt,
     )
     if err != nil {
@@ -277,14 +362,14 @@ func (r *TransactionRepository) GetByIDStudentOrder(id int) (*models.Transaksi,
     var t models.Transaksi
     query := `
         SELECT t.id, t.customer_id, c.name AS customer_name, 
-            t.transaction_date, t.payment_date, t.status, 
+            t.transaction_date, COALESCE(t.academic_year, ''), t.payment_date, t.status, t.production_stage, 
             t.total_price, t.notes, t.created_at
         FROM transactions t
         JOIN customers c ON t.customer_id = c.id
         WHERE t.id = ?`
     err := r.DB.QueryRow(query, id).Scan(
         &t.ID, &t.CustomerID, &t.Customer_name,
-        &t.Transaksidate, &t.Paymentdate, &t.Status,
+        &t.Transaksidate, &t.AcademicYear, &t.Paymentdate, &t.Status, &t.ProductionStage,
         &t.Total, &t.Notes, &t.CreatedAt,
     )
     if err != nil {
@@ -292,10 +377,10 @@ func (r *TransactionRepository) GetByIDStudentOrder(id int) (*models.Transaksi,
     }
     
     itemsQuery := `
-        SELECT id, customer_id, student_name, grade, transaction_id, 
-               uniform_name, size, quantity, unit_price, 
-               (quantity * unit_price) as subtotal, notes, created_at
-        FROM student_order_items WHERE transaction_id = ?`
+        SELECT soi.id, soi.customer_id, COALESCE(soi.student_id, 0), COALESCE(soi.measurement_id, 0), soi.student_name, soi.grade, soi.transaction_id, 
+               soi.uniform_name, soi.size, soi.quantity, soi.unit_price, 
+               (soi.quantity * soi.unit_price) as subtotal, soi.notes, soi.created_at
+        FROM student_order_items soi WHERE soi.transaction_id = ?`
     rows, err := r.DB.Query(itemsQuery, id)
     if err != nil {
         return &t, nil, err
@@ -306,7 +391,7 @@ func (r *TransactionRepository) GetByIDStudentOrder(id int) (*models.Transaksi,
     for rows.Next() {
         var item models.StudentOrderItem
         err := rows.Scan(
-            &item.ID, &item.CustomerID, &item.StudentName, &item.Grade, &item.TransactionID,
+            &item.ID, &item.CustomerID, &item.StudentID, &item.MeasurementID, &item.StudentName, &item.Grade, &item.TransactionID,
             &item.UniformName, &item.Size, &item.Quantity, &item.UnitPrice, 
             &item.Subtotal, &item.Notes, &item.CreatedAt,
         )
@@ -315,6 +400,31 @@ func (r *TransactionRepository) GetByIDStudentOrder(id int) (*models.Transaksi,
         }
         items = append(items, item)
     }
+    if err := rows.Err(); err != nil {
+        return &t, nil, err
+    }
+
+    // Lampirkan ukuran badan untuk item jahit ukur
+    mrows, err := r.DB.Query(`
+        SELECT `+measurementColumns+`
+        FROM student_measurements m
+        JOIN student_order_items soi ON soi.measurement_id = m.id
+        WHERE soi.transaction_id = ?`, id)
+    if err != nil {
+        return &t, nil, err
+    }
+    defer mrows.Close()
+    measurements := map[int]*models.StudentMeasurement{}
+    for mrows.Next() {
+        m, err := scanMeasurement(mrows)
+        if err != nil {
+            return &t, nil, err
+        }
+        measurements[m.ID] = &m
+    }
+    for i := range items {
+        items[i].Measurement = measurements[items[i].MeasurementID]
+    }
     return &t, items, nil
 }
 
@@ -354,20 +464,51 @@ func (r *TransactionRepository) GetCustomerUniformsByTransactionID(transactionID
     return uniforms, nil
 }
 
+// UpdateStatus mengubah status transaksi; pembatalan melepas stok yang disisihkan dalam transaksi DB yang sama
 func (r *TransactionRepository) UpdateStatus(id int, status string) error {
-    _, err := r.DB.Exec(
-        "UPDATE transactions SET status = ? WHERE id = ?",
-        status, id,
-    )
-    return err
+    tx, err := r.DB.Begin()
+    if err != nil {
+        return err
+    }
+    defer tx.Rollback()
+
+    if _, err := tx.Exec("UPDATE transactions SET status = ? WHERE id = ?", status, id); err != nil {
+        return err
+    }
+    if status == "cancelled" {
+        if err := releaseReservations(tx, id); err != nil {
+            return err
+        }
+    }
+    return tx.Commit()
 }
 
+// UpdateTransaction mengubah header transaksi. Jika tanggal transaksi berubah, harga item
+// dihitung ulang sesuai daftar harga pada tanggal yang baru.
 func (r *TransactionRepository) UpdateTransaction(id int, transactionDate, paymentDate, notes string) error {
-    _, err := r.DB.Exec(
-        "UPDATE transactions SET transaction_date = ?, payment_date = ?, notes = ? WHERE id = ?",
-        transactionDate, paymentDate, notes, id,
-    )
-    return err
+    tx, err := r.DB.Begin()
+    if err != nil {
+        return err
+    }
+    defer tx.Rollback()
+
+    var customerID int
+    var oldDate sql.NullString
+    if err := tx.QueryRow("SELECT customer_id, transaction_date FROM transactions WHERE id = ? FOR UPDATE", id).Scan(&customerID, &oldDate); err != nil {
+        return err
+    }
+    if _, err := tx.Exec(
+        "UPDATE transactions SET transaction_date = ?, academic_year = ?, payment_date = ?, notes = ? WHERE id = ?",
+        transactionDate, AcademicYear(transactionDate), paymentDate, notes, id,
+    ); err != nil {
+        return err
+    }
+    if date := pricingDate(transactionDate); date != pricingDate(oldDate.String) {
+        if err := repriceTransaction(tx, id, customerID, date); err != nil {
+            return err
+        }
+    }
+    return tx.Commit()
 }
 
 func (r *TransactionRepository) UpdateOrderItemsNormal(transactionID int, items []OrderItemUpdate) error {
@@ -390,16 +531,30 @@ func (r *TransactionRepository) UpdateOrderItems(transactionID int, items []Orde
         }
     }()
     
+    var customerID int
+    var transactionDate sql.NullString
+    if err = tx.QueryRow("SELECT customer_id, transaction_date FROM transactions WHERE id = ?", transactionID).Scan(&customerID, &transactionDate); err != nil {
+        return err
+    }
+    sizes := make([]uniformSize, len(items))
+    for i, item := range items {
+        sizes[i] = uniformSize{item.UniformName, item.Size}
+    }
+    if err = checkOrderSizes(tx, customerID, sizes); err != nil {
+        return err
+    }
+
     _, err = tx.Exec("DELETE FROM order_items WHERE transaction_id = ?", transactionID)
     if err != nil {
         return err
     }
     
-    total := 0.0
     for _, item := range items {
-        subtotal := float64(item.Quantity) * item.UnitPrice
-        total += subtotal
-        _, err := tx.Exec(
+        item.UnitPrice, err = itemPrice(tx, customerID, item.UniformName, item.Size, pricingDate(transactionDate.String), item.UnitPrice)
+        if err != nil {
+            return err
+        }
+        _, err = tx.Exec(
             `INSERT INTO order_items (transaction_id, uniform_name, size, quantity, unit_price, notes) VALUES (?, ?, ?, ?, ?, ?)`,
             transactionID, item.UniformName, item.Size, item.Quantity, item.UnitPrice, item.Notes, 
         )
@@ -408,8 +563,11 @@ func (r *TransactionRepository) UpdateOrderItems(transactionID int, items []Orde
         }
     }
     
-    _, err = tx.Exec("UPDATE transactions SET total_price = ? WHERE id = ?", total, transactionID)
-    if err != nil {
+    // Total mencakup item siswa juga jika transaksi campuran
+    if err = updateTransactionTotal(tx, transactionID); err != nil {
+        return err
+    }
+    if err = syncReservations(tx, transactionID, customerID); err != nil {
         return err
     }
     return tx.Commit()
@@ -426,20 +584,43 @@ func (r *TransactionRepository) UpdateOrderItemsStudent(transactionID int, stude
         }
     }()
     
+    var customerID int
+    var transactionDate sql.NullString
+    if err = tx.QueryRow("SELECT customer_id, transaction_date FROM transactions WHERE id = ?", transactionID).Scan(&customerID, &transactionDate); err != nil {
+        return err
+    }
+    sizes := make([]uniformSize, len(studentItems))
+    for i, item := range studentItems {
+        sizes[i] = uniformSize{item.UniformName, item.Size}
+    }
+    if err = checkOrderSizes(tx, customerID, sizes); err != nil {
+        return err
+    }
+
     _, err = tx.Exec("DELETE FROM student_order_items WHERE transaction_id = ?", transactionID)
     if err != nil {
         return err
     }
     
-    total := 0.0
-    for _, item := range studentItems {
-        subtotal := float64(item.Quantity) * item.UnitPrice
-        total += subtotal
-        _, err := tx.Exec(
+    for i := range studentItems {
+        item := &studentItems[i]
+        // Customer selalu mengikuti transaksi, bukan customer_id dari client
+        item.CustomerID = customerID
+        if err = resolveStudent(tx, item); err != nil {
+            return err
+        }
+        if err = checkMeasurement(tx, item); err != nil {
+            return err
+        }
+        item.UnitPrice, err = itemPrice(tx, customerID, item.UniformName, item.Size, pricingDate(transactionDate.String), item.UnitPrice)
+        if err != nil {
+            return err
+        }
+        _, err = tx.Exec(
             `INSERT INTO student_order_items 
-            (customer_id, student_name, grade, transaction_id, uniform_name, size, quantity, unit_price, notes) 
-            VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
-            item.CustomerID, item.StudentName, item.Grade, transactionID,
+            (customer_id, student_id, measurement_id, student_name, grade, transaction_id, uniform_name, size, quantity, unit_price, notes) 
+            VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+            item.CustomerID, nullableInt(item.StudentID), nullableInt(item.MeasurementID), item.StudentName, item.Grade, transactionID,
             item.UniformName, item.Size, item.Quantity, item.UnitPrice, item.Notes,
         )
         if err != nil {
@@ -447,31 +628,91 @@ func (r *TransactionRepository) UpdateOrderItemsStudent(transactionID int, stude
         }
     }
     
-    _, err = tx.Exec("UPDATE transactions SET total_price = ? WHERE id = ?", total, transactionID)
-    if err != nil {
+    if err = updateTransactionTotal(tx, transactionID); err != nil {
+        return err
+    }
+    if err = syncReservations(tx, transactionID, customerID); err != nil {
         return err
     }
     return tx.Commit()
 }
 
+// UpdateStudentOrderItem mengubah satu item siswa; sisihan stok transaksi ikut disesuaikan
 func (r *TransactionRepository) UpdateStudentOrderItem(itemID int, studentName, grade, uniformName, size string, quantity int, unitPrice float64, notes string) error {
-    _, err := r.DB.Exec(
+    tx, err := r.DB.Begin()
+    if err != nil {
+        return err
+    }
+    defer tx.Rollback()
+
+    var transactionID, customerID int
+    var transactionDate sql.NullString
+    err = tx.QueryRow(
+        "SELECT t.id, t.customer_id, t.transaction_date FROM student_order_items si JOIN transactions t ON t.id = si.transaction_id WHERE si.id = ?",
+        itemID,
+    ).Scan(&transactionID, &customerID, &transactionDate)
+    if err != nil {
+        return err
+    }
+    if err := checkOrderSizes(tx, customerID, []uniformSize{{uniformName, size}}); err != nil {
+        return err
+    }
+    unitPrice, err = itemPrice(tx, customerID, uniformName, size, pricingDate(transactionDate.String), unitPrice)
+    if err != nil {
+        return err
+    }
+    _, err = tx.Exec(
         `UPDATE student_order_items 
          SET student_name = ?, grade = ?, uniform_name = ?, size = ?, quantity = ?, unit_price = ?, notes = ?
          WHERE id = ?`,
         studentName, grade, uniformName, size, quantity, unitPrice, notes, itemID,
     )
-    return err
+    if err != nil {
+        return err
+    }
+    if err := syncReservations(tx, transactionID, customerID); err != nil {
+        return err
+    }
+    return tx.Commit()
 }
 
+// UpdateNormalOrderItem mengubah satu item biasa; sisihan stok transaksi ikut disesuaikan
 func (r *TransactionRepository) UpdateNormalOrderItem(itemID int, uniformName, size string, quantity int, unitPrice float64, notes string) error {
-    _, err := r.DB.Exec(
+    tx, err := r.DB.Begin()
+    if err != nil {
+        return err
+    }
+    defer tx.Rollback()
+
+    var transactionID, customerID int
+    var transactionDate sql.NullString
+    err = tx.QueryRow(
+        "SELECT t.id, t.customer_id, t.transaction_date FROM order_items oi JOIN transactions t ON t.id = oi.transaction_id WHERE oi.id = ?",
+        itemID,
+    ).Scan(&transactionID, &customerID, &transactionDate)
+    if err != nil {
+        return err
+    }
+    if err := checkOrderSizes(tx, customerID, []uniformSize{{uniformName, size}}); err != nil {
+        return err
+    }
+    unitPrice, err = itemPrice(tx, customerID, uniformName, size, pricingDate(transactionDate.String), unitPrice)
+    if err != nil {
+        return err
+    }
+    _, err = tx.Exec(
         `UPDATE order_items 
          SET uniform_name = ?, size = ?, quantity = ?, unit_price = ?, notes = ?
          WHERE id = ?`,
         uniformName, size, quantity, unitPrice, notes, itemID,
     )
-    return err
+    if err != nil {
+        return err
+    }
+    if err := syncReservations(tx, transactionID, customerID); err != nil {
+        return err
+    }
+    return tx.Commit()
 }
 
 func (r *TransactionRepository) RecalculateTransactionTotal(transactionID int) error {
@@ -727,11 +968,25 @@ func (r *TransactionRepository) GetTransactionsByCustomerID(customerID int, stat
 }
 
 func (r *TransactionRepository) UpdateTransactionStatus(transactionID int, status string) error {
-    _, err := r.DB.Exec(
+    tx, err := r.DB.Begin()
+    if err != nil {
+        return err
+    }
+    defer tx.Rollback()
+
+    _, err = tx.Exec(
         "UPDATE transactions SET status = ?, updated_at = NOW() WHERE id = ?",
         status, transactionID,
     )
-    return err
+    if err != nil {
+        return err
+    }
+    if status == "cancelled" {
+        if err := releaseReservations(tx, transactionID); err != nil {
+            return err
+        }
+    }
+    return tx.Commit()
 }
 
 func (r *TransactionRepository) HasStudentInfo(transactionID int) bool {
@@ -782,7 +1037,7 @@ func (r *TransactionRepository) getTransactionItemCountByID(transactionID int) i
 }
 
 func (r *TransactionRepository) GetCustomerByID(customerID int) (*models.Customer, error) {
-    query := `SELECT id, name, address, contact FROM customers WHERE id = ?`
+    query := `SELECT id, name, COALESCE(address, ''), COALESCE(contact, ''), COALESCE(email, '') FROM customers WHERE id = ?`
     
     var customer models.Customer
     err := r.DB.QueryRow(query, customerID).Scan(
@@ -790,6 +1045,7 @@ func (r *TransactionRepository) GetCustomerByID(customerID int) (*models.Custome
         &customer.Name, 
         &customer.Address, 
         &customer.Contact,
+        &customer.Email,
     )
     if err != nil {
         log.Printf("Error getting customer by ID %d: %v", customerID, err)
@@ -797,4 +1053,60 @@ func (r *TransactionRepository) GetCustomerByID(customerID int) (*models.Custome
     }
     
     return &customer, nil
-}
\ No newline at end of file
+}
+type PaymentReminder struct {
+    TransactionID   int     `json:"transaction_id"`
+    CustomerID      int     `json:"customer_id"`
+    CustomerName    string  `json:"customer_name"`
+    CustomerContact string  `json:"customer_contact"`
+    CustomerEmail   string  `json:"customer_email"`
+    TransactionDate string  `json:"transaction_date"`
+    PaymentDate     string  `json:"payment_date"`
+    Total           float64 `json:"total_price"`
+    Overdue         bool    `json:"overdue"`
+}
+
+// GetPaymentReminders mengambil transaksi belum lunas yang sudah lewat jatuh tempo
+// atau jatuh tempo dalam 2 hari ke depan (sama dengan reminder di dashboard)
+func (r *TransactionRepository) GetPaymentReminders() ([]PaymentReminder, error) {
+    rows, err := r.DB.Query(`
+        SELECT t.id, t.customer_id, c.name, COALESCE(c.contact, ''), COALESCE(c.email, ''),
+               t.transaction_date, t.payment_date, COALESCE(t.total_price, 0),
+               t.payment_date < CURDATE() AS overdue
+        FROM transactions t
+        JOIN customers c ON t.customer_id = c.id
+        WHERE t.status = 'pending'
+        AND t.payment_date IS NOT NULL
+        AND DATE_SUB(t.payment_date, INTERVAL 2 DAY) <= CURDATE()
+        ORDER BY t.payment_date ASC
+    `)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    var reminders []PaymentReminder
+    for rows.Next() {
+        var p PaymentReminder
+        err := rows.Scan(
+            &p.TransactionID, &p.CustomerID, &p.CustomerName, &p.CustomerContact, &p.CustomerEmail,
+            &p.TransactionDate, &p.PaymentDate, &p.Total, &p.Overdue,
+        )
+        if err != nil {
+            return nil, err
+        }
+        reminders = append(reminders, p)
+    }
+    return reminders, nil
+}
+
+// SizeCharts mengembalikan urutan ukuran dari tabel ukuran yang berlaku untuk seragam customer
+func (r *TransactionRepository) SizeCharts(customerID int) (map[string]sizing.Chart, error) {
+    _, charts, err := chartOrders(r.DB, customerID)
+    return charts, err
+}
+
+// StockReservations mengambil stok barang jadi yang disisihkan untuk transaksi
+func (r *TransactionRepository) StockReservations(transactionID int) ([]models.StockReservation, error) {
+    return (&FinishedGoodsRepository{DB: r.DB}).GetReservations(transactionID)
+}
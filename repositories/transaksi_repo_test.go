@@ -0,0 +1,149 @@
+package repositories
+
+import (
+    "konveksi-app/models"
+    "testing"
+
+    "github.com/DATA-DOG/go-sqlmock"
+)
+
+// expectListPrice menyiapkan query lookupPriceOnDate untuk harga tanpa jadwal
+func expectListPrice(mock sqlmock.Sqlmock, customerUniformID int, price float64) {
+    mock.ExpectQuery(`FROM customer_uniforms WHERE customer_id = \? AND TRIM\(uniform_name\) = TRIM\(\?\) AND TRIM\(size\) = TRIM\(\?\)`).
+        WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(customerUniformID))
+    mock.ExpectQuery("FROM customer_uniform_prices").WillReturnRows(sqlmock.NewRows([]string{"price"}))
+    mock.ExpectQuery("SELECT previous_price FROM customer_uniform_prices").WillReturnRows(sqlmock.NewRows([]string{"previous_price"}))
+    mock.ExpectQuery("SELECT price FROM customer_uniforms WHERE id").WithArgs(customerUniformID).
+        WillReturnRows(sqlmock.NewRows([]string{"price"}).AddRow(price))
+}
+
+// expectNoReservations menyiapkan syncReservations untuk transaksi tanpa stok yang disisihkan
+func expectNoReservations(mock sqlmock.Sqlmock) {
+    mock.ExpectQuery("FROM finished_goods_reservations WHERE transaction_id").
+        WillReturnRows(sqlmock.NewRows([]string{"catalog_id", "size", "quantity"}))
+}
+
+func expectNotInPriceList(mock sqlmock.Sqlmock) {
+    mock.ExpectQuery("FROM customer_uniforms WHERE customer_id").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+}
+
+func TestUpdateOrderItemsUsesPriceList(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer db.Close()
+    repo := &TransactionRepository{DB: db}
+
+    mock.ExpectBegin()
+    mock.ExpectQuery("SELECT customer_id, transaction_date FROM transactions").WithArgs(7).
+        WillReturnRows(sqlmock.NewRows([]string{"customer_id", "transaction_date"}).AddRow(3, "2025-07-01"))
+    mock.ExpectQuery("FROM customer_uniforms cu").WillReturnRows(sqlmock.NewRows([]string{"uniform_name", "priority", "id", "size", "sort_order"}))
+    mock.ExpectExec("DELETE FROM order_items").WithArgs(7).WillReturnResult(sqlmock.NewResult(0, 2))
+    // Harga dari client (1000) diganti harga daftar harga
+    expectListPrice(mock, 10, 85000)
+    mock.ExpectExec("INSERT INTO order_items").WithArgs(7, "Kemeja ", "M", 2, 85000.0, "").WillReturnResult(sqlmock.NewResult(1, 1))
+    // Item khusus di luar daftar harga memakai harga dari client
+    expectNotInPriceList(mock)
+    mock.ExpectExec("INSERT INTO order_items").WithArgs(7, "Bordir nama", "-", 2, 5000.0, "").WillReturnResult(sqlmock.NewResult(2, 1))
+    mock.ExpectExec("UPDATE transactions SET total_price").WithArgs(7, 7, 7).WillReturnResult(sqlmock.NewResult(0, 1))
+    expectNoReservations(mock)
+    mock.ExpectCommit()
+
+    err = repo.UpdateOrderItems(7, []OrderItemUpdate{
+        {UniformName: "Kemeja ", Size: "M", Quantity: 2, UnitPrice: 1000},
+        {UniformName: "Bordir nama", Size: "-", Quantity: 2, UnitPrice: 5000},
+    })
+    if err != nil {
+        t.Fatal(err)
+    }
+    if err := mock.ExpectationsWereMet(); err != nil {
+        t.Fatal(err)
+    }
+}
+
+func TestUpdateTransactionRepricesWhenDateChanges(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer db.Close()
+    repo := &TransactionRepository{DB: db}
+
+    mock.ExpectBegin()
+    mock.ExpectQuery("SELECT customer_id, transaction_date FROM transactions WHERE id = \\? FOR UPDATE").WithArgs(7).
+        WillReturnRows(sqlmock.NewRows([]string{"customer_id", "transaction_date"}).AddRow(3, "2025-06-01"))
+    mock.ExpectExec("UPDATE transactions SET transaction_date").WillReturnResult(sqlmock.NewResult(0, 1))
+    mock.ExpectQuery("FROM order_items WHERE transaction_id").WithArgs(7).
+        WillReturnRows(sqlmock.NewRows([]string{"id", "uniform_name", "size", "unit_price"}).AddRow(5, "Kemeja", "M", 80000))
+    expectListPrice(mock, 10, 90000)
+    mock.ExpectExec("UPDATE order_items SET unit_price").WithArgs(90000.0, 5).WillReturnResult(sqlmock.NewResult(0, 1))
+    mock.ExpectQuery("FROM student_order_items WHERE transaction_id").WithArgs(7).
+        WillReturnRows(sqlmock.NewRows([]string{"id", "uniform_name", "size", "unit_price"}))
+    mock.ExpectExec("UPDATE transactions SET total_price").WithArgs(7, 7, 7).WillReturnResult(sqlmock.NewResult(0, 1))
+    mock.ExpectCommit()
+
+    if err := repo.UpdateTransaction(7, "2025-07-15", "2025-08-01", ""); err != nil {
+        t.Fatal(err)
+    }
+    if err := mock.ExpectationsWereMet(); err != nil {
+        t.Fatal(err)
+    }
+}
+
+func TestUpdateTransactionSameDateKeepsPrices(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer db.Close()
+    repo := &TransactionRepository{DB: db}
+
+    mock.ExpectBegin()
+    mock.ExpectQuery("SELECT customer_id, transaction_date FROM transactions").WithArgs(7).
+        WillReturnRows(sqlmock.NewRows([]string{"customer_id", "transaction_date"}).AddRow(3, "2025-07-15"))
+    mock.ExpectExec("UPDATE transactions SET transaction_date").WillReturnResult(sqlmock.NewResult(0, 1))
+    mock.ExpectCommit()
+
+    if err := repo.UpdateTransaction(7, "2025-07-15", "2025-08-10", "DP 50%"); err != nil {
+        t.Fatal(err)
+    }
+    if err := mock.ExpectationsWereMet(); err != nil {
+        t.Fatal(err)
+    }
+}
+
+func TestUpdateOrderItemsStudentUsesTransactionCustomer(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer db.Close()
+    repo := &TransactionRepository{DB: db}
+
+    mock.ExpectBegin()
+    mock.ExpectQuery("SELECT customer_id, transaction_date FROM transactions").WithArgs(7).
+        WillReturnRows(sqlmock.NewRows([]string{"customer_id", "transaction_date"}).AddRow(3, "2025-07-01"))
+    mock.ExpectQuery("FROM customer_uniforms cu").WillReturnRows(sqlmock.NewRows([]string{"uniform_name", "priority", "id", "size", "sort_order"}))
+    mock.ExpectExec("DELETE FROM student_order_items").WithArgs(7).WillReturnResult(sqlmock.NewResult(0, 1))
+    // customer_id 99 dari client diabaikan, siswa dicari di customer transaksi
+    mock.ExpectQuery("FROM students WHERE customer_id").WithArgs(3, "Budi").
+        WillReturnRows(sqlmock.NewRows([]string{"id", "class"}).AddRow(4, "1A"))
+    expectNotInPriceList(mock)
+    mock.ExpectExec("INSERT INTO student_order_items").
+        WithArgs(3, 4, nil, "Budi", "1A", 7, "Kemeja", "M", 1, 75000.0, "").
+        WillReturnResult(sqlmock.NewResult(1, 1))
+    mock.ExpectExec("UPDATE transactions SET total_price").WillReturnResult(sqlmock.NewResult(0, 1))
+    expectNoReservations(mock)
+    mock.ExpectCommit()
+
+    err = repo.UpdateOrderItemsStudent(7, []models.StudentOrderItem{
+        {CustomerID: 99, StudentName: "Budi", Grade: "1A", UniformName: "Kemeja", Size: "M", Quantity: 1, UnitPrice: 75000},
+    })
+    if err != nil {
+        t.Fatal(err)
+    }
+    if err := mock.ExpectationsWereMet(); err != nil {
+        t.Fatal(err)
+    }
+}
@@ -42,6 +42,20 @@ func (r *UserRepository) GetByID(id int) (*models.User, error) {
 	return &user, err
 }
 
+func (r *UserRepository) GetByUsername(username string) (*models.User, error) {
+	query := `
+		SELECT id, username, password, contact, address, created_at
+		FROM users WHERE username = ?`
+
+	var user models.User
+	err := r.DB.QueryRow(query, username).Scan(
+		&user.ID, &user.Username, &user.Password,
+		&user.Contact, &user.Address, &user.CreatedAt,
+	)
+
+	return &user, err
+}
+
 func (r *UserRepository) GetAll() ([]models.User, error) {
 	query := `
 		SELECT id, username, password, contact, address, created_at
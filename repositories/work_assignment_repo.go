@@ -0,0 +1,363 @@
+package repositories
+
+import (
+    "database/sql"
+    "fmt"
+    "konveksi-app/models"
+    "math"
+    "strings"
+    "time"
+)
+
+// AssignmentFilter untuk daftar pekerjaan; nilai kosong berarti semua
+type AssignmentFilter struct {
+    WorkerID      int
+    TransactionID int
+    OpenOnly      bool // hanya yang belum selesai semua
+}
+
+const assignmentColumns = `a.id, a.worker_id, w.name, a.transaction_id, a.item_type, COALESCE(a.item_id, 0),
+    a.uniform_name, a.size, COALESCE(a.student_id, 0), a.student_name, a.grade, a.operation, a.quantity,
+    COALESCE((SELECT SUM(c.quantity) FROM work_completions c WHERE c.assignment_id = a.id), 0),
+    a.rate, a.assigned_date, COALESCE(a.notes, ''), COALESCE(a.created_by, ''), a.created_at`
+
+func scanAssignment(scanner interface{ Scan(...interface{}) error }) (models.WorkAssignment, error) {
+    var a models.WorkAssignment
+    err := scanner.Scan(&a.ID, &a.WorkerID, &a.WorkerName, &a.TransactionID, &a.ItemType, &a.ItemID,
+        &a.UniformName, &a.Size, &a.StudentID, &a.StudentName, &a.Grade, &a.Operation, &a.Quantity, &a.CompletedQuantity,
+        &a.Rate, &a.AssignedDate, &a.Notes, &a.CreatedBy, &a.CreatedAt)
+    return a, err
+}
+
+func (r *WorkerRepository) GetAssignments(f AssignmentFilter) ([]models.WorkAssignment, error) {
+    where := []string{"1 = 1"}
+    args := []interface{}{}
+    if f.WorkerID != 0 {
+        where = append(where, "a.worker_id = ?")
+        args = append(args, f.WorkerID)
+    }
+    if f.TransactionID != 0 {
+        where = append(where, "a.transaction_id = ?")
+        args = append(args, f.TransactionID)
+    }
+    rows, err := r.DB.Query(`
+        SELECT `+assignmentColumns+`
+        FROM work_assignments a
+        JOIN workers w ON w.id = a.worker_id
+        WHERE `+strings.Join(where, " AND ")+`
+        ORDER BY a.assigned_date DESC, a.id DESC`,
+        args...,
+    )
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    assignments := []models.WorkAssignment{}
+    for rows.Next() {
+        a, err := scanAssignment(rows)
+        if err != nil {
+            return nil, err
+        }
+        if f.OpenOnly && a.CompletedQuantity >= a.Quantity {
+            continue
+        }
+        assignments = append(assignments, a)
+    }
+    return assignments, rows.Err()
+}
+
+func (r *WorkerRepository) GetAssignment(id int) (*models.WorkAssignment, error) {
+    a, err := scanAssignment(r.DB.QueryRow(`
+        SELECT `+assignmentColumns+`
+        FROM work_assignments a
+        JOIN workers w ON w.id = a.worker_id
+        WHERE a.id = ?`,
+        id,
+    ))
+    if err != nil {
+        return nil, err
+    }
+    return &a, nil
+}
+
+// CreateAssignment memberi pekerjaan untuk satu baris pesanan. Tarif diambil dari tarif borongan
+// seragam katalog saat ini dan disimpan, supaya perubahan tarif tidak mengubah upah yang sudah berjalan.
+// Batas jumlah dihitung per transaksi + seragam + ukuran (+ siswa) + operasi, karena item_id berubah
+// setiap kali item transaksi ditulis ulang.
+func (r *WorkerRepository) CreateAssignment(a *models.WorkAssignment) error {
+    a.Operation = strings.ToLower(strings.TrimSpace(a.Operation))
+    if a.ItemType == "" {
+        a.ItemType = "order"
+    }
+    if !validOperation(a.Operation) {
+        return fmt.Errorf("operasi '%s' tidak dikenal", a.Operation)
+    }
+    if a.Quantity <= 0 {
+        return fmt.Errorf("jumlah harus lebih dari 0")
+    }
+    if a.AssignedDate == "" {
+        a.AssignedDate = time.Now().Format("2006-01-02")
+    }
+    if err := validateDate(a.AssignedDate, "tanggal"); err != nil {
+        return err
+    }
+
+    var itemQuery string
+    switch a.ItemType {
+    case "order":
+        itemQuery = "SELECT transaction_id, TRIM(uniform_name), TRIM(size), 0, '', '' FROM order_items WHERE id = ?"
+    case "student":
+        itemQuery = `SELECT transaction_id, TRIM(uniform_name), TRIM(size), COALESCE(student_id, 0), TRIM(student_name), TRIM(COALESCE(grade, ''))
+            FROM student_order_items WHERE id = ?`
+    default:
+        return fmt.Errorf("jenis item harus order atau student")
+    }
+
+    tx, err := r.DB.Begin()
+    if err != nil {
+        return err
+    }
+    defer tx.Rollback()
+
+    var active bool
+    err = tx.QueryRow("SELECT name, active FROM workers WHERE id = ?", a.WorkerID).Scan(&a.WorkerName, &active)
+    if err == sql.ErrNoRows {
+        return fmt.Errorf("pekerja %d tidak ditemukan", a.WorkerID)
+    } else if err != nil {
+        return err
+    }
+    if !active {
+        return fmt.Errorf("pekerja %s sudah nonaktif", a.WorkerName)
+    }
+
+    var transactionID int
+    err = tx.QueryRow(itemQuery, a.ItemID).Scan(&transactionID, &a.UniformName, &a.Size, &a.StudentID, &a.StudentName, &a.Grade)
+    if err == sql.ErrNoRows {
+        return fmt.Errorf("item pesanan %d tidak ditemukan", a.ItemID)
+    } else if err != nil {
+        return err
+    }
+    if a.TransactionID != 0 && a.TransactionID != transactionID {
+        return fmt.Errorf("item %d bukan bagian dari transaksi %d", a.ItemID, a.TransactionID)
+    }
+    a.TransactionID = transactionID
+
+    // Kunci transaksi supaya pembagian kerja dan edit item untuk transaksi yang sama berjalan bergantian
+    var customerID int
+    var status string
+    err = tx.QueryRow("SELECT customer_id, status FROM transactions WHERE id = ? FOR UPDATE", transactionID).Scan(&customerID, &status)
+    if err != nil {
+        return err
+    }
+    if status == "cancelled" {
+        return fmt.Errorf("transaksi sudah dibatalkan")
+    }
+
+    ordered, err := orderedForAssignment(tx, a)
+    if err != nil {
+        return err
+    }
+    var assigned int
+    student, studentArgs := assignmentStudentFilter(a, "student_name", "grade")
+    args := append([]interface{}{a.TransactionID, a.ItemType, a.UniformName, a.Size}, studentArgs...)
+    err = tx.QueryRow(`
+        SELECT COALESCE(SUM(quantity), 0) FROM work_assignments
+        WHERE transaction_id = ? AND item_type = ? AND uniform_name = ? AND size = ?
+          AND `+student+` AND operation = ?`,
+        append(args, a.Operation)...,
+    ).Scan(&assigned)
+    if err != nil {
+        return err
+    }
+    if assigned+a.Quantity > ordered {
+        remaining := ordered - assigned
+        if remaining < 0 {
+            remaining = 0
+        }
+        return fmt.Errorf("%s %s ukuran %s tinggal %d pcs yang belum dibagi", a.Operation, a.UniformName, a.Size, remaining)
+    }
+
+    catalogID, err := catalogForUniform(tx, customerID, a.UniformName)
+    if err != nil {
+        return err
+    }
+    if catalogID == 0 {
+        return fmt.Errorf("seragam %s belum dihubungkan ke katalog, tarif borongan tidak diketahui", a.UniformName)
+    }
+    err = tx.QueryRow("SELECT rate FROM piece_rates WHERE catalog_id = ? AND operation = ?", catalogID, a.Operation).Scan(&a.Rate)
+    if err == sql.ErrNoRows {
+        return fmt.Errorf("tarif borongan %s untuk %s belum diatur", a.Operation, a.UniformName)
+    } else if err != nil {
+        return err
+    }
+
+    res, err := tx.Exec(
+        `INSERT INTO work_assignments
+         (worker_id, transaction_id, item_type, item_id, uniform_name, size, student_id, student_name, grade, operation, quantity, rate, assigned_date, notes, created_by)
+         VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+        a.WorkerID, a.TransactionID, a.ItemType, a.ItemID, a.UniformName, a.Size, nullableInt(a.StudentID), a.StudentName, a.Grade,
+        a.Operation, a.Quantity, a.Rate,
+        a.AssignedDate, nullableString(a.Notes), nullableString(a.CreatedBy),
+    )
+    if err != nil {
+        return err
+    }
+    id, _ := res.LastInsertId()
+    a.ID = int(id)
+    return tx.Commit()
+}
+
+// assignmentStudentFilter membatasi baris ke siswa yang sama: student_id jika ada, selain itu nama + kelas,
+// supaya dua siswa bernama sama di satu kelas tidak berbagi batas
+func assignmentStudentFilter(a *models.WorkAssignment, nameColumn, gradeColumn string) (string, []interface{}) {
+    if a.StudentID != 0 {
+        return "student_id = ?", []interface{}{a.StudentID}
+    }
+    return "student_id IS NULL AND " + nameColumn + " = ? AND " + gradeColumn + " = ?", []interface{}{a.StudentName, a.Grade}
+}
+
+// orderedForAssignment menjumlahkan semua baris transaksi dengan seragam, ukuran, dan siswa yang sama
+func orderedForAssignment(q queryRower, a *models.WorkAssignment) (int, error) {
+    var ordered int
+    var err error
+    if a.ItemType == "student" {
+        student, studentArgs := assignmentStudentFilter(a, "TRIM(student_name)", "TRIM(COALESCE(grade, ''))")
+        err = q.QueryRow(`
+            SELECT COALESCE(SUM(quantity), 0) FROM student_order_items
+            WHERE transaction_id = ? AND TRIM(uniform_name) = ? AND TRIM(size) = ? AND `+student,
+            append([]interface{}{a.TransactionID, a.UniformName, a.Size}, studentArgs...)...,
+        ).Scan(&ordered)
+    } else {
+        err = q.QueryRow(
+            "SELECT COALESCE(SUM(quantity), 0) FROM order_items WHERE transaction_id = ? AND TRIM(uniform_name) = ? AND TRIM(size) = ?",
+            a.TransactionID, a.UniformName, a.Size,
+        ).Scan(&ordered)
+    }
+    return ordered, err
+}
+
+// DeleteAssignment membatalkan pekerjaan yang belum ada hasil kerjanya
+func (r *WorkerRepository) DeleteAssignment(id int) error {
+    var completed int
+    if err := r.DB.QueryRow("SELECT COUNT(*) FROM work_completions WHERE assignment_id = ?", id).Scan(&completed); err != nil {
+        return err
+    }
+    if completed > 0 {
+        return fmt.Errorf("pekerjaan sudah ada hasil kerjanya dan tidak bisa dihapus")
+    }
+    res, err := r.DB.Exec("DELETE FROM work_assignments WHERE id = ?", id)
+    if err != nil {
+        return err
+    }
+    if n, _ := res.RowsAffected(); n == 0 {
+        return sql.ErrNoRows
+    }
+    return nil
+}
+
+// RecordCompletion mencatat hasil kerja yang disetor pekerja
+func (r *WorkerRepository) RecordCompletion(c *models.WorkCompletion) error {
+    if c.Quantity <= 0 {
+        return fmt.Errorf("jumlah harus lebih dari 0")
+    }
+    if c.CompletedDate == "" {
+        c.CompletedDate = time.Now().Format("2006-01-02")
+    }
+    if err := validateDate(c.CompletedDate, "tanggal selesai"); err != nil {
+        return err
+    }
+
+    tx, err := r.DB.Begin()
+    if err != nil {
+        return err
+    }
+    defer tx.Rollback()
+
+    var quantity, completed int
+    err = tx.QueryRow("SELECT quantity FROM work_assignments WHERE id = ? FOR UPDATE", c.AssignmentID).Scan(&quantity)
+    if err != nil {
+        return err
+    }
+    err = tx.QueryRow("SELECT COALESCE(SUM(quantity), 0) FROM work_completions WHERE assignment_id = ?", c.AssignmentID).Scan(&completed)
+    if err != nil {
+        return err
+    }
+    if completed+c.Quantity > quantity {
+        return fmt.Errorf("pekerjaan tinggal %d pcs", quantity-completed)
+    }
+
+    res, err := tx.Exec(
+        "INSERT INTO work_completions (assignment_id, quantity, completed_date, recorded_by) VALUES (?, ?, ?, ?)",
+        c.AssignmentID, c.Quantity, c.CompletedDate, nullableString(c.RecordedBy),
+    )
+    if err != nil {
+        return err
+    }
+    id, _ := res.LastInsertId()
+    c.ID = int(id)
+    return tx.Commit()
+}
+
+// WeekRange mengembalikan Senin-Minggu dari minggu yang memuat tanggal (YYYY-MM-DD), minggu ini jika kosong
+func WeekRange(date string) (string, string, error) {
+    day := time.Now()
+    if date != "" {
+        var err error
+        if day, err = time.Parse("2006-01-02", date); err != nil {
+            return "", "", fmt.Errorf("tanggal minggu harus berformat YYYY-MM-DD")
+        }
+    }
+    offset := (int(day.Weekday()) + 6) % 7
+    monday := day.AddDate(0, 0, -offset)
+    return monday.Format("2006-01-02"), monday.AddDate(0, 0, 6).Format("2006-01-02"), nil
+}
+
+// WeeklyWages menghitung upah borongan per pekerja dari hasil kerja yang disetor pada minggu tanggal date.
+// workerID 0 berarti semua pekerja.
+func (r *WorkerRepository) WeeklyWages(date string, workerID int) ([]models.WorkerWage, error) {
+    from, to, err := WeekRange(date)
+    if err != nil {
+        return nil, err
+    }
+    query := `
+        SELECT w.id, w.name, a.id, a.transaction_id, a.uniform_name, a.size, a.operation, SUM(c.quantity), a.rate
+        FROM work_completions c
+        JOIN work_assignments a ON a.id = c.assignment_id
+        JOIN workers w ON w.id = a.worker_id
+        WHERE c.completed_date BETWEEN ? AND ?`
+    args := []interface{}{from, to}
+    if workerID != 0 {
+        query += " AND w.id = ?"
+        args = append(args, workerID)
+    }
+    rows, err := r.DB.Query(query+`
+        GROUP BY w.id, w.name, a.id, a.transaction_id, a.uniform_name, a.size, a.operation, a.rate
+        ORDER BY w.name, w.id, a.transaction_id, a.id`,
+        args...,
+    )
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    wages := []models.WorkerWage{}
+    for rows.Next() {
+        var id int
+        var name string
+        var l models.WageLine
+        err := rows.Scan(&id, &name, &l.AssignmentID, &l.TransactionID, &l.UniformName, &l.Size, &l.Operation, &l.Quantity, &l.Rate)
+        if err != nil {
+            return nil, err
+        }
+        l.Amount = math.Round(float64(l.Quantity)*l.Rate*100) / 100
+        if n := len(wages); n == 0 || wages[n-1].WorkerID != id {
+            wages = append(wages, models.WorkerWage{WorkerID: id, WorkerName: name, PeriodFrom: from, PeriodTo: to})
+        }
+        wage := &wages[len(wages)-1]
+        wage.Lines = append(wage.Lines, l)
+        wage.Pieces += l.Quantity
+        wage.Total += l.Amount
+    }
+    return wages, rows.Err()
+}
@@ -0,0 +1,151 @@
+package repositories
+
+import (
+    "database/sql/driver"
+    "konveksi-app/models"
+    "strings"
+    "testing"
+
+    "github.com/DATA-DOG/go-sqlmock"
+)
+
+// expectAssignmentLine menyiapkan pekerja aktif, item pesanan, dan kunci transaksi
+func expectAssignmentLine(mock sqlmock.Sqlmock, itemID int) {
+    mock.ExpectBegin()
+    mock.ExpectQuery("SELECT name, active FROM workers").WithArgs(3).
+        WillReturnRows(sqlmock.NewRows([]string{"name", "active"}).AddRow("Bu Sri", true))
+    mock.ExpectQuery("FROM order_items WHERE id = \\?").WithArgs(itemID).
+        WillReturnRows(sqlmock.NewRows([]string{"transaction_id", "uniform_name", "size", "student_id", "student_name", "grade"}).
+            AddRow(8, "Kemeja SD", "M", 0, "", ""))
+    mock.ExpectQuery("SELECT customer_id, status FROM transactions WHERE id = \\? FOR UPDATE").WithArgs(8).
+        WillReturnRows(sqlmock.NewRows([]string{"customer_id", "status"}).AddRow(2, "pending"))
+    mock.ExpectQuery("FROM order_items WHERE transaction_id = \\?").WithArgs(8, "Kemeja SD", "M").
+        WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow(40))
+}
+
+func TestCreateAssignmentCapSurvivesItemRewrite(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer db.Close()
+    repo := &WorkerRepository{DB: db}
+
+    // Item 91 adalah baris yang ditulis ulang dari item lama; 30 pcs sudah dibagi lewat item lama
+    expectAssignmentLine(mock, 91)
+    mock.ExpectQuery("FROM work_assignments").WithArgs(8, "order", "Kemeja SD", "M", "", "", "jahit").
+        WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow(30))
+    mock.ExpectRollback()
+
+    a := &models.WorkAssignment{WorkerID: 3, ItemID: 91, Operation: "jahit", Quantity: 20, AssignedDate: "2025-07-01"}
+    err = repo.CreateAssignment(a)
+    if err == nil || !strings.Contains(err.Error(), "tinggal 10 pcs") {
+        t.Fatalf("err = %v, want cap counted from earlier assignments", err)
+    }
+    if err := mock.ExpectationsWereMet(); err != nil {
+        t.Fatal(err)
+    }
+}
+
+func TestCreateAssignmentWithinCap(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer db.Close()
+    repo := &WorkerRepository{DB: db}
+
+    expectAssignmentLine(mock, 91)
+    mock.ExpectQuery("FROM work_assignments").WithArgs(8, "order", "Kemeja SD", "M", "", "", "jahit").
+        WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow(30))
+    mock.ExpectQuery("FROM customer_uniforms").WithArgs(2, "Kemeja SD").
+        WillReturnRows(sqlmock.NewRows([]string{"catalog_id"}).AddRow(5))
+    mock.ExpectQuery("SELECT rate FROM piece_rates").WithArgs(5, "jahit").
+        WillReturnRows(sqlmock.NewRows([]string{"rate"}).AddRow(4500.0))
+    mock.ExpectExec("INSERT INTO work_assignments").
+        WithArgs(3, 8, "order", 91, "Kemeja SD", "M", nil, "", "", "jahit", 10, 4500.0, "2025-07-01", nil, nil).
+        WillReturnResult(sqlmock.NewResult(17, 1))
+    mock.ExpectCommit()
+
+    a := &models.WorkAssignment{WorkerID: 3, ItemID: 91, Operation: "jahit", Quantity: 10, AssignedDate: "2025-07-01"}
+    if err := repo.CreateAssignment(a); err != nil {
+        t.Fatal(err)
+    }
+    if a.ID != 17 || a.TransactionID != 8 || a.Rate != 4500 {
+        t.Fatalf("assignment = %+v", a)
+    }
+    if err := mock.ExpectationsWereMet(); err != nil {
+        t.Fatal(err)
+    }
+}
+
+func TestOrderedForAssignmentStudentKey(t *testing.T) {
+    tests := []struct {
+        name    string
+        a       models.WorkAssignment
+        pattern string
+        args    []driver.Value
+    }{
+        {
+            // Dua siswa bernama Budi di kelas 3A punya student_id berbeda, jadi batasnya terpisah
+            name:    "student_id",
+            a:       models.WorkAssignment{StudentID: 12, StudentName: "Budi", Grade: "3A"},
+            pattern: "AND student_id = \\?$",
+            args:    []driver.Value{8, "Kemeja SD", "M", 12},
+        },
+        {
+            name:    "nama dan kelas",
+            a:       models.WorkAssignment{StudentName: "Budi", Grade: "3A"},
+            pattern: "student_id IS NULL AND TRIM\\(student_name\\) = \\? AND TRIM\\(COALESCE\\(grade, ''\\)\\) = \\?$",
+            args:    []driver.Value{8, "Kemeja SD", "M", "Budi", "3A"},
+        },
+    }
+    for _, tt := range tests {
+        db, mock, tx := beginMock(t)
+        mock.ExpectQuery("FROM student_order_items.*" + tt.pattern).WithArgs(tt.args...).
+            WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow(2))
+
+        a := tt.a
+        a.ItemType, a.TransactionID, a.UniformName, a.Size = "student", 8, "Kemeja SD", "M"
+        ordered, err := orderedForAssignment(tx, &a)
+        if err != nil || ordered != 2 {
+            t.Errorf("%s: ordered = %d, err = %v; want 2", tt.name, ordered, err)
+        }
+        if err := mock.ExpectationsWereMet(); err != nil {
+            t.Errorf("%s: %v", tt.name, err)
+        }
+        db.Close()
+    }
+}
+
+func TestCreateAssignmentCapPerStudentID(t *testing.T) {
+    db, mock, err := sqlmock.New()
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer db.Close()
+    repo := &WorkerRepository{DB: db}
+
+    mock.ExpectBegin()
+    mock.ExpectQuery("SELECT name, active FROM workers").WithArgs(3).
+        WillReturnRows(sqlmock.NewRows([]string{"name", "active"}).AddRow("Bu Sri", true))
+    mock.ExpectQuery("FROM student_order_items WHERE id = \\?").WithArgs(301).
+        WillReturnRows(sqlmock.NewRows([]string{"transaction_id", "uniform_name", "size", "student_id", "student_name", "grade"}).
+            AddRow(8, "Kemeja SD", "M", 12, "Budi", "3A"))
+    mock.ExpectQuery("FOR UPDATE").WithArgs(8).
+        WillReturnRows(sqlmock.NewRows([]string{"customer_id", "status"}).AddRow(2, "pending"))
+    mock.ExpectQuery("FROM student_order_items").WithArgs(8, "Kemeja SD", "M", 12).
+        WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow(1))
+    mock.ExpectQuery("FROM work_assignments.*AND student_id = \\? AND operation").WithArgs(8, "student", "Kemeja SD", "M", 12, "jahit").
+        WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow(1))
+    mock.ExpectRollback()
+
+    a := &models.WorkAssignment{WorkerID: 3, ItemType: "student", ItemID: 301, Operation: "jahit", Quantity: 1, AssignedDate: "2025-07-01"}
+    err = repo.CreateAssignment(a)
+    if err == nil || !strings.Contains(err.Error(), "tinggal 0 pcs") {
+        t.Fatalf("err = %v, want cap reached for student 12", err)
+    }
+    if err := mock.ExpectationsWereMet(); err != nil {
+        t.Fatal(err)
+    }
+}
@@ -0,0 +1,201 @@
+package repositories
+
+import (
+    "database/sql"
+    "fmt"
+    "konveksi-app/models"
+    "strings"
+)
+
+type WorkerRepository struct {
+    DB *sql.DB
+}
+
+// WorkOperations adalah operasi yang dibayar borongan, urut sesuai alur produksi
+var WorkOperations = []string{"potong", "jahit", "lubang_kancing", "setrika"}
+
+func validOperation(op string) bool {
+    for _, o := range WorkOperations {
+        if o == op {
+            return true
+        }
+    }
+    return false
+}
+
+const workerColumns = `id, name, COALESCE(phone, ''), COALESCE(address, ''), COALESCE(notes, ''), active,
+    created_at, COALESCE(updated_at, created_at)`
+
+func scanWorker(scanner interface{ Scan(...interface{}) error }) (models.Worker, error) {
+    var w models.Worker
+    err := scanner.Scan(&w.ID, &w.Name, &w.Phone, &w.Address, &w.Notes, &w.Active, &w.CreatedAt, &w.UpdatedAt)
+    return w, err
+}
+
+func (r *WorkerRepository) GetAll(includeInactive bool) ([]models.Worker, error) {
+    query := "SELECT " + workerColumns + " FROM workers"
+    if !includeInactive {
+        query += " WHERE active = 1"
+    }
+    rows, err := r.DB.Query(query + " ORDER BY name")
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    workers := []models.Worker{}
+    for rows.Next() {
+        w, err := scanWorker(rows)
+        if err != nil {
+            return nil, err
+        }
+        workers = append(workers, w)
+    }
+    return workers, rows.Err()
+}
+
+func (r *WorkerRepository) GetByID(id int) (*models.Worker, error) {
+    w, err := scanWorker(r.DB.QueryRow("SELECT "+workerColumns+" FROM workers WHERE id = ?", id))
+    if err != nil {
+        return nil, err
+    }
+    return &w, nil
+}
+
+func validateWorker(w *models.Worker) error {
+    w.Name = strings.Join(strings.Fields(w.Name), " ")
+    w.Phone = strings.TrimSpace(w.Phone)
+    w.Address = strings.TrimSpace(w.Address)
+    if w.Name == "" {
+        return fmt.Errorf("nama pekerja wajib diisi")
+    }
+    return nil
+}
+
+func (r *WorkerRepository) Create(w *models.Worker) error {
+    if err := validateWorker(w); err != nil {
+        return err
+    }
+    res, err := r.DB.Exec(
+        "INSERT INTO workers (name, phone, address, notes, active) VALUES (?, ?, ?, ?, ?)",
+        w.Name, nullableString(w.Phone), nullableString(w.Address), nullableString(w.Notes), w.Active,
+    )
+    if err != nil {
+        return err
+    }
+    id, _ := res.LastInsertId()
+    w.ID = int(id)
+    return nil
+}
+
+func (r *WorkerRepository) Update(w *models.Worker) error {
+    if err := validateWorker(w); err != nil {
+        return err
+    }
+    res, err := r.DB.Exec(
+        "UPDATE workers SET name = ?, phone = ?, address = ?, notes = ?, active = ? WHERE id = ?",
+        w.Name, nullableString(w.Phone), nullableString(w.Address), nullableString(w.Notes), w.Active, w.ID,
+    )
+    if err != nil {
+        return err
+    }
+    if n, _ := res.RowsAffected(); n == 0 {
+        var exists int
+        if err := r.DB.QueryRow("SELECT COUNT(*) FROM workers WHERE id = ?", w.ID).Scan(&exists); err != nil || exists == 0 {
+            return sql.ErrNoRows
+        }
+    }
+    return nil
+}
+
+// Delete menghapus pekerja yang belum pernah diberi pekerjaan; selebihnya cukup dinonaktifkan
+func (r *WorkerRepository) Delete(id int) error {
+    var assigned int
+    if err := r.DB.QueryRow("SELECT COUNT(*) FROM work_assignments WHERE worker_id = ?", id).Scan(&assigned); err != nil {
+        return err
+    }
+    if assigned > 0 {
+        return fmt.Errorf("pekerja sudah punya riwayat pekerjaan, nonaktifkan saja")
+    }
+    res, err := r.DB.Exec("DELETE FROM workers WHERE id = ?", id)
+    if err != nil {
+        return err
+    }
+    if n, _ := res.RowsAffected(); n == 0 {
+        return sql.ErrNoRows
+    }
+    return nil
+}
+
+// GetPieceRates mengambil tarif borongan satu seragam katalog, urut sesuai alur produksi
+func (r *WorkerRepository) GetPieceRates(catalogID int) ([]models.PieceRate, error) {
+    rows, err := r.DB.Query("SELECT catalog_id, operation, rate FROM piece_rates WHERE catalog_id = ?", catalogID)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    byOperation := map[string]models.PieceRate{}
+    for rows.Next() {
+        var p models.PieceRate
+        if err := rows.Scan(&p.CatalogID, &p.Operation, &p.Rate); err != nil {
+            return nil, err
+        }
+        byOperation[p.Operation] = p
+    }
+    if err := rows.Err(); err != nil {
+        return nil, err
+    }
+    rates := []models.PieceRate{}
+    for _, op := range WorkOperations {
+        if p, ok := byOperation[op]; ok {
+            rates = append(rates, p)
+        }
+    }
+    return rates, nil
+}
+
+// SavePieceRates mengganti seluruh tarif borongan satu seragam katalog
+func (r *WorkerRepository) SavePieceRates(catalogID int, rates []models.PieceRate) error {
+    seen := map[string]bool{}
+    for i := range rates {
+        rates[i].Operation = strings.ToLower(strings.TrimSpace(rates[i].Operation))
+        if !validOperation(rates[i].Operation) {
+            return fmt.Errorf("baris %d: operasi '%s' tidak dikenal", i+1, rates[i].Operation)
+        }
+        if rates[i].Rate < 0 {
+            return fmt.Errorf("baris %d: tarif tidak boleh negatif", i+1)
+        }
+        if seen[rates[i].Operation] {
+            return fmt.Errorf("baris %d: operasi %s dobel", i+1, rates[i].Operation)
+        }
+        seen[rates[i].Operation] = true
+    }
+
+    var exists int
+    if err := r.DB.QueryRow("SELECT COUNT(*) FROM uniform_catalog WHERE id = ?", catalogID).Scan(&exists); err != nil {
+        return err
+    }
+    if exists == 0 {
+        return sql.ErrNoRows
+    }
+
+    tx, err := r.DB.Begin()
+    if err != nil {
+        return err
+    }
+    defer tx.Rollback()
+
+    if _, err := tx.Exec("DELETE FROM piece_rates WHERE catalog_id = ?", catalogID); err != nil {
+        return err
+    }
+    for _, p := range rates {
+        if _, err := tx.Exec(
+            "INSERT INTO piece_rates (catalog_id, operation, rate) VALUES (?, ?, ?)",
+            catalogID, p.Operation, p.Rate,
+        ); err != nil {
+            return err
+        }
+    }
+    return tx.Commit()
+}
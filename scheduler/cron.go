@@ -0,0 +1,145 @@
+package scheduler
+
+import (
+    "fmt"
+    "strconv"
+    "strings"
+    "time"
+)
+
+// Schedule adalah ekspresi cron 5 field: menit jam tanggal bulan hari
+// Mendukung "*", angka, daftar "1,15", range "1-5" dan step "*/15" atau "0-30/10"
+type Schedule struct {
+    Spec   string
+    minute [60]bool
+    hour   [24]bool
+    dom    [32]bool
+    month  [13]bool
+    dow    [7]bool
+
+    domAny bool
+    dowAny bool
+}
+
+func ParseSchedule(spec string) (*Schedule, error) {
+    fields := strings.Fields(spec)
+    if len(fields) != 5 {
+        return nil, fmt.Errorf("ekspresi cron '%s' harus terdiri dari 5 field", spec)
+    }
+
+    s := &Schedule{Spec: spec}
+    if err := parseField(fields[0], 0, 59, s.minute[:]); err != nil {
+        return nil, fmt.Errorf("field menit: %v", err)
+    }
+    if err := parseField(fields[1], 0, 23, s.hour[:]); err != nil {
+        return nil, fmt.Errorf("field jam: %v", err)
+    }
+    if err := parseField(fields[2], 1, 31, s.dom[:]); err != nil {
+        return nil, fmt.Errorf("field tanggal: %v", err)
+    }
+    if err := parseField(fields[3], 1, 12, s.month[:]); err != nil {
+        return nil, fmt.Errorf("field bulan: %v", err)
+    }
+    // Hari 7 diperlakukan sama dengan 0 (Minggu)
+    var dow [8]bool
+    if err := parseField(fields[4], 0, 7, dow[:]); err != nil {
+        return nil, fmt.Errorf("field hari: %v", err)
+    }
+    copy(s.dow[:], dow[:7])
+    if dow[7] {
+        s.dow[0] = true
+    }
+
+    s.domAny = fields[2] == "*"
+    s.dowAny = fields[4] == "*"
+    return s, nil
+}
+
+func parseField(field string, min, max int, out []bool) error {
+    for _, part := range strings.Split(field, ",") {
+        step := 1
+        if i := strings.Index(part, "/"); i != -1 {
+            n, err := strconv.Atoi(part[i+1:])
+            if err != nil || n <= 0 {
+                return fmt.Errorf("step tidak valid '%s'", part)
+            }
+            step = n
+            part = part[:i]
+        }
+
+        lo, hi := min, max
+        if part != "*" {
+            if i := strings.Index(part, "-"); i != -1 {
+                a, errA := strconv.Atoi(part[:i])
+                b, errB := strconv.Atoi(part[i+1:])
+                if errA != nil || errB != nil {
+                    return fmt.Errorf("range tidak valid '%s'", part)
+                }
+                lo, hi = a, b
+            } else {
+                n, err := strconv.Atoi(part)
+                if err != nil {
+                    return fmt.Errorf("nilai tidak valid '%s'", part)
+                }
+                lo, hi = n, n
+                if step > 1 {
+                    hi = max
+                }
+            }
+        }
+
+        if lo < min || hi > max || lo > hi {
+            return fmt.Errorf("nilai '%s' di luar batas %d-%d", part, min, max)
+        }
+        for v := lo; v <= hi; v += step {
+            out[v] = true
+        }
+    }
+    return nil
+}
+
+// Matches mengecek apakah waktu t (dibulatkan ke menit) cocok dengan jadwal
+func (s *Schedule) Matches(t time.Time) bool {
+    return s.minute[t.Minute()] && s.hour[t.Hour()] && s.month[int(t.Month())] && s.dayMatches(t)
+}
+
+// dayMatches mengecek field tanggal dan hari
+func (s *Schedule) dayMatches(t time.Time) bool {
+    domMatch := s.dom[t.Day()]
+    dowMatch := s.dow[int(t.Weekday())]
+    // Sama seperti cron standar: jika tanggal dan hari sama-sama dibatasi, cukup salah satu cocok
+    if !s.domAny && !s.dowAny {
+        return domMatch || dowMatch
+    }
+    return domMatch && dowMatch
+}
+
+// Next mengembalikan waktu terdekat setelah t yang cocok dengan jadwal, atau waktu nol jika
+// tidak ada dalam 5 tahun (mis. "0 0 30 2 *"). Waktu dilompati per field: bulan, tanggal, jam,
+// lalu menit, jadwal harian cukup beberapa puluh langkah.
+func (s *Schedule) Next(t time.Time) time.Time {
+    next := t.Truncate(time.Minute).Add(time.Minute)
+    // 5 tahun supaya jadwal 29 Februari tetap ketemu
+    limit := next.AddDate(5, 0, 0)
+    loc := next.Location()
+    for next.Before(limit) {
+        if !s.month[int(next.Month())] {
+            next = time.Date(next.Year(), next.Month()+1, 1, 0, 0, 0, 0, loc)
+            continue
+        }
+        if !s.dayMatches(next) {
+            next = time.Date(next.Year(), next.Month(), next.Day()+1, 0, 0, 0, 0, loc)
+            continue
+        }
+        if !s.hour[next.Hour()] {
+            next = time.Date(next.Year(), next.Month(), next.Day(), next.Hour()+1, 0, 0, 0, loc)
+            continue
+        }
+        if !s.minute[next.Minute()] {
+            next = next.Add(time.Minute)
+            continue
+        }
+        return next
+    }
+    return time.Time{}
+}
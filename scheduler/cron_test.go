@@ -0,0 +1,54 @@
+package scheduler
+
+import (
+    "testing"
+    "time"
+)
+
+// nextByMinute adalah cara lama: maju per menit sampai cocok, dipakai sebagai pembanding
+func nextByMinute(s *Schedule, t time.Time) time.Time {
+    next := t.Truncate(time.Minute).Add(time.Minute)
+    limit := next.AddDate(1, 0, 1)
+    for next.Before(limit) {
+        if s.Matches(next) {
+            return next
+        }
+        next = next.Add(time.Minute)
+    }
+    return time.Time{}
+}
+
+func TestNextMatchesMinuteScan(t *testing.T) {
+    loc := time.FixedZone("WIB", 7*3600)
+    specs := []string{"0 6 * * *", "*/15 * * * *", "30 7 * * 1-5", "5 0 1,15 * *", "0 9 13 * 5", "0 0 31 * *", "45 23 * 12 0"}
+    starts := []time.Time{
+        time.Date(2025, 7, 1, 5, 59, 30, 0, loc),
+        time.Date(2025, 12, 31, 23, 50, 0, 0, loc),
+        time.Date(2026, 2, 28, 6, 0, 0, 0, loc),
+    }
+    for _, spec := range specs {
+        s, err := ParseSchedule(spec)
+        if err != nil {
+            t.Fatal(err)
+        }
+        for _, start := range starts {
+            if got, want := s.Next(start), nextByMinute(s, start); !got.Equal(want) {
+                t.Errorf("%q Next(%v) = %v, want %v", spec, start, got, want)
+            }
+        }
+    }
+}
+
+func TestNextLeapDayAndImpossibleSpec(t *testing.T) {
+    start := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+
+    s, _ := ParseSchedule("0 0 29 2 *")
+    if got, want := s.Next(start), time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC); !got.Equal(want) {
+        t.Errorf("Next = %v, want %v", got, want)
+    }
+
+    s, _ = ParseSchedule("0 0 30 2 *")
+    if got := s.Next(start); !got.IsZero() {
+        t.Errorf("Next = %v, want zero time for impossible spec", got)
+    }
+}
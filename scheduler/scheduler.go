@@ -0,0 +1,282 @@
+package scheduler
+
+import (
+    "context"
+    "database/sql"
+    "fmt"
+    "konveksi-app/models"
+    "konveksi-app/repositories"
+    "log"
+    "sort"
+    "sync"
+    "time"
+)
+
+// JobFunc menjalankan satu job dan mengembalikan ringkasan hasil untuk riwayat
+type JobFunc func(ctx context.Context) (string, error)
+
+type Job struct {
+    Name     string
+    Schedule *Schedule
+    Run      JobFunc
+    // Local berarti job mengurus state milik proses ini (mis. session di memori),
+    // jadi dijalankan di setiap instance tanpa kunci database
+    Local bool
+
+    running bool
+}
+
+type JobStatus struct {
+    Name     string         `json:"name"`
+    Schedule string         `json:"schedule"`
+    Running  bool           `json:"running"`
+    NextRun  string         `json:"next_run"`
+    LastRun  *models.JobRun `json:"last_run"`
+}
+
+// Scheduler menjalankan job secara in-process. Setiap eksekusi dikunci dengan
+// GET_LOCK MySQL supaya beberapa instance aplikasi tidak menjalankan job yang sama bersamaan,
+// dan setiap slot jadwal diklaim di job_runs supaya instance yang terlambat tidak mengulang slot itu.
+type Scheduler struct {
+    DB   *sql.DB
+    Runs *repositories.JobRunRepository
+
+    mu   sync.Mutex
+    jobs map[string]*Job
+    stop chan struct{}
+}
+
+func New(db *sql.DB, runs *repositories.JobRunRepository) *Scheduler {
+    return &Scheduler{
+        DB:   db,
+        Runs: runs,
+        jobs: make(map[string]*Job),
+    }
+}
+
+func (s *Scheduler) Register(name, spec string, fn JobFunc) error {
+    return s.register(name, spec, fn, false)
+}
+
+// RegisterLocal mendaftarkan job yang dijalankan di setiap instance, tanpa kunci dan klaim slot
+func (s *Scheduler) RegisterLocal(name, spec string, fn JobFunc) error {
+    return s.register(name, spec, fn, true)
+}
+
+func (s *Scheduler) register(name, spec string, fn JobFunc, local bool) error {
+    schedule, err := ParseSchedule(spec)
+    if err != nil {
+        return err
+    }
+
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    if _, exists := s.jobs[name]; exists {
+        return fmt.Errorf("job '%s' sudah terdaftar", name)
+    }
+    s.jobs[name] = &Job{Name: name, Schedule: schedule, Run: fn, Local: local}
+    log.Printf("Job registered: %s (%s)", name, spec)
+    return nil
+}
+
+// Start mengecek jadwal setiap awal menit sampai Stop dipanggil
+func (s *Scheduler) Start() {
+    s.mu.Lock()
+    if s.stop != nil {
+        s.mu.Unlock()
+        return
+    }
+    s.stop = make(chan struct{})
+    stop := s.stop
+    s.mu.Unlock()
+
+    log.Println("Scheduler started")
+    for {
+        now := time.Now()
+        wait := now.Truncate(time.Minute).Add(time.Minute).Sub(now)
+        select {
+        case <-stop:
+            log.Println("Scheduler stopped")
+            return
+        case tick := <-time.After(wait):
+            tick = tick.Truncate(time.Minute)
+            for _, job := range s.dueJobs(tick) {
+                go s.execute(job, "schedule", tick)
+            }
+        }
+    }
+}
+
+func (s *Scheduler) Stop() {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    if s.stop != nil {
+        close(s.stop)
+        s.stop = nil
+    }
+}
+
+func (s *Scheduler) dueJobs(t time.Time) []*Job {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    var due []*Job
+    for _, job := range s.jobs {
+        if job.Schedule.Matches(t) {
+            due = append(due, job)
+        }
+    }
+    return due
+}
+
+// RunNow menjalankan job secara manual di background
+func (s *Scheduler) RunNow(name string) error {
+    s.mu.Lock()
+    job, exists := s.jobs[name]
+    running := exists && job.running
+    s.mu.Unlock()
+
+    if !exists {
+        return fmt.Errorf("job '%s' tidak ditemukan", name)
+    }
+    if running {
+        return fmt.Errorf("job '%s' sedang berjalan", name)
+    }
+
+    go s.execute(job, "manual", time.Time{})
+    return nil
+}
+
+func (s *Scheduler) HasJob(name string) bool {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    _, exists := s.jobs[name]
+    return exists
+}
+
+// execute menjalankan job; scheduledAt adalah menit jadwal untuk trigger "schedule"
+func (s *Scheduler) execute(job *Job, triggerType string, scheduledAt time.Time) {
+    s.mu.Lock()
+    if job.running {
+        s.mu.Unlock()
+        log.Printf("Job %s still running, skipped", job.Name)
+        return
+    }
+    job.running = true
+    s.mu.Unlock()
+
+    defer func() {
+        s.mu.Lock()
+        job.running = false
+        s.mu.Unlock()
+    }()
+
+    ctx := context.Background()
+
+    var runID int
+    var err error
+    if job.Local {
+        runID, err = s.Runs.Start(job.Name, triggerType)
+    } else {
+        // Lock database: GET_LOCK berlaku per koneksi, jadi pakai satu koneksi khusus
+        conn, connErr := s.DB.Conn(ctx)
+        if connErr != nil {
+            log.Printf("Job %s: failed to get DB connection: %v", job.Name, connErr)
+            return
+        }
+        defer conn.Close()
+
+        lockName := "konveksi_job_" + job.Name
+        var acquired sql.NullInt64
+        if err := conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, 0)", lockName).Scan(&acquired); err != nil {
+            log.Printf("Job %s: failed to acquire lock: %v", job.Name, err)
+            return
+        }
+        if !acquired.Valid || acquired.Int64 != 1 {
+            log.Printf("Job %s: lock held by another instance, skipped", job.Name)
+            return
+        }
+        defer conn.ExecContext(ctx, "SELECT RELEASE_LOCK(?)", lockName)
+
+        if triggerType == "schedule" {
+            // Kunci hanya berlaku selama job berjalan; klaim slot mencegah instance yang tick-nya
+            // sedikit terlambat menjalankan slot yang sama setelah kunci dilepas
+            claimed := false
+            runID, claimed, err = s.Runs.Claim(job.Name, scheduledAt)
+            if err != nil {
+                log.Printf("Job %s: failed to claim slot %s: %v", job.Name, scheduledAt.Format("2006-01-02 15:04"), err)
+                return
+            }
+            if !claimed {
+                log.Printf("Job %s: slot %s already run by another instance, skipped", job.Name, scheduledAt.Format("2006-01-02 15:04"))
+                return
+            }
+        } else {
+            runID, err = s.Runs.Start(job.Name, triggerType)
+        }
+    }
+    if err != nil {
+        log.Printf("Job %s: failed to record run: %v", job.Name, err)
+    }
+
+    log.Printf("Job %s started (%s)", job.Name, triggerType)
+    message, err := s.safeRun(ctx, job)
+    status := "success"
+    if err != nil {
+        status = "failed"
+        message = err.Error()
+        log.Printf("Job %s failed: %v", job.Name, err)
+    } else {
+        log.Printf("Job %s finished: %s", job.Name, message)
+    }
+
+    if runID > 0 {
+        if err := s.Runs.Finish(runID, status, message); err != nil {
+            log.Printf("Job %s: failed to update run %d: %v", job.Name, runID, err)
+        }
+    }
+}
+
+// safeRun mencegah panic di dalam job menghentikan scheduler
+func (s *Scheduler) safeRun(ctx context.Context, job *Job) (message string, err error) {
+    defer func() {
+        if rec := recover(); rec != nil {
+            err = fmt.Errorf("panic: %v", rec)
+        }
+    }()
+    return job.Run(ctx)
+}
+
+func (s *Scheduler) Status() []JobStatus {
+    // Salin data job selama lock dipegang; Next dan query riwayat dihitung setelah lock dilepas
+    type snapshot struct {
+        status   JobStatus
+        schedule *Schedule
+    }
+    s.mu.Lock()
+    snapshots := make([]snapshot, 0, len(s.jobs))
+    for _, job := range s.jobs {
+        snapshots = append(snapshots, snapshot{
+            status:   JobStatus{Name: job.Name, Schedule: job.Schedule.Spec, Running: job.running},
+            schedule: job.Schedule,
+        })
+    }
+    s.mu.Unlock()
+
+    now := time.Now()
+    statuses := make([]JobStatus, len(snapshots))
+    for i, snap := range snapshots {
+        statuses[i] = snap.status
+        statuses[i].NextRun = snap.schedule.Next(now).Format("2006-01-02 15:04:05")
+    }
+    sort.Slice(statuses, func(i, j int) bool { return statuses[i].Name < statuses[j].Name })
+
+    for i := range statuses {
+        lastRun, err := s.Runs.GetLastRun(statuses[i].Name)
+        if err == nil {
+            statuses[i].LastRun = lastRun
+        } else if err != sql.ErrNoRows {
+            log.Printf("Error getting last run for %s: %v", statuses[i].Name, err)
+        }
+    }
+    return statuses
+}
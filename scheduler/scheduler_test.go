@@ -0,0 +1,115 @@
+package scheduler
+
+import (
+    "context"
+    "database/sql"
+    "konveksi-app/repositories"
+    "testing"
+    "time"
+
+    "github.com/DATA-DOG/go-sqlmock"
+    "github.com/go-sql-driver/mysql"
+)
+
+func newTestScheduler(t *testing.T) (*Scheduler, sqlmock.Sqlmock) {
+    t.Helper()
+    db, mock, err := sqlmock.New()
+    if err != nil {
+        t.Fatal(err)
+    }
+    t.Cleanup(func() { db.Close() })
+    return New(db, &repositories.JobRunRepository{DB: db}), mock
+}
+
+func countingJob(calls *int) JobFunc {
+    return func(ctx context.Context) (string, error) {
+        *calls++
+        return "ok", nil
+    }
+}
+
+func TestExecuteSkipsSlotClaimedByAnotherInstance(t *testing.T) {
+    s, mock := newTestScheduler(t)
+    calls := 0
+    if err := s.Register("backup", "0 2 * * *", countingJob(&calls)); err != nil {
+        t.Fatal(err)
+    }
+    slot := time.Date(2025, 7, 1, 2, 0, 0, 0, time.Local)
+
+    mock.ExpectQuery("SELECT GET_LOCK").WithArgs("konveksi_job_backup").
+        WillReturnRows(sqlmock.NewRows([]string{"lock"}).AddRow(1))
+    mock.ExpectExec("INSERT INTO job_runs").WithArgs("backup", "2025-07-01 02:00:00").
+        WillReturnError(&mysql.MySQLError{Number: 1062, Message: "Duplicate entry"})
+    mock.ExpectExec("SELECT RELEASE_LOCK").WillReturnResult(sqlmock.NewResult(0, 0))
+
+    s.execute(s.jobs["backup"], "schedule", slot)
+
+    if calls != 0 {
+        t.Fatalf("job dijalankan %d kali untuk slot yang sudah diklaim", calls)
+    }
+    if err := mock.ExpectationsWereMet(); err != nil {
+        t.Fatal(err)
+    }
+}
+
+func TestExecuteClaimsSlotThenRuns(t *testing.T) {
+    s, mock := newTestScheduler(t)
+    calls := 0
+    s.Register("backup", "0 2 * * *", countingJob(&calls))
+    slot := time.Date(2025, 7, 1, 2, 0, 0, 0, time.Local)
+
+    mock.ExpectQuery("SELECT GET_LOCK").WillReturnRows(sqlmock.NewRows([]string{"lock"}).AddRow(1))
+    mock.ExpectExec("INSERT INTO job_runs").WithArgs("backup", "2025-07-01 02:00:00").
+        WillReturnResult(sqlmock.NewResult(7, 1))
+    mock.ExpectExec("UPDATE job_runs").WithArgs("success", "ok", 7).WillReturnResult(sqlmock.NewResult(0, 1))
+    mock.ExpectExec("SELECT RELEASE_LOCK").WillReturnResult(sqlmock.NewResult(0, 0))
+
+    s.execute(s.jobs["backup"], "schedule", slot)
+
+    if calls != 1 {
+        t.Fatalf("job dijalankan %d kali, seharusnya 1", calls)
+    }
+    if err := mock.ExpectationsWereMet(); err != nil {
+        t.Fatal(err)
+    }
+}
+
+func TestExecuteLocalJobRunsWithoutLock(t *testing.T) {
+    s, mock := newTestScheduler(t)
+    calls := 0
+    s.RegisterLocal("expire_sessions", "*/15 * * * *", countingJob(&calls))
+
+    // Tidak ada GET_LOCK maupun klaim slot; sqlmock gagal jika query lain dijalankan
+    mock.ExpectExec("INSERT INTO job_runs").WithArgs("expire_sessions", "schedule").
+        WillReturnResult(sqlmock.NewResult(3, 1))
+    mock.ExpectExec("UPDATE job_runs").WithArgs("success", "ok", 3).WillReturnResult(sqlmock.NewResult(0, 1))
+
+    s.execute(s.jobs["expire_sessions"], "schedule", time.Now().Truncate(time.Minute))
+
+    if calls != 1 {
+        t.Fatalf("job lokal dijalankan %d kali, seharusnya 1", calls)
+    }
+    if err := mock.ExpectationsWereMet(); err != nil {
+        t.Fatal(err)
+    }
+}
+
+func TestStatusComputesNextRun(t *testing.T) {
+    s, mock := newTestScheduler(t)
+    s.Register("b_job", "0 2 * * *", countingJob(new(int)))
+    s.Register("a_job", "30 7 * * *", countingJob(new(int)))
+    mock.ExpectQuery("FROM job_runs").WithArgs("a_job").WillReturnError(sql.ErrNoRows)
+    mock.ExpectQuery("FROM job_runs").WithArgs("b_job").WillReturnError(sql.ErrNoRows)
+
+    statuses := s.Status()
+    if len(statuses) != 2 || statuses[0].Name != "a_job" || statuses[1].Name != "b_job" {
+        t.Fatalf("urutan status salah: %+v", statuses)
+    }
+    next, err := time.ParseInLocation("2006-01-02 15:04:05", statuses[0].NextRun, time.Local)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if next.Hour() != 7 || next.Minute() != 30 || !next.After(time.Now()) {
+        t.Fatalf("next run a_job = %s", statuses[0].NextRun)
+    }
+}
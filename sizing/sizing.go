@@ -0,0 +1,151 @@
+package sizing
+
+import (
+    "regexp"
+    "sort"
+    "strconv"
+    "strings"
+)
+
+// Urutan ukuran: angka (6, 8, 10, ...) lebih dulu, lalu huruf (XS, S, M, L, XL, XXL/2XL, ...),
+// lalu ukuran lain (mis. "all size") secara alfabetis.
+const (
+    groupNumeric = iota
+    groupLetter
+    groupOther
+)
+
+var letterSize = regexp.MustCompile(`^(\d*)(X*)(S|M|L)$`)
+
+func rank(size string) (int, float64) {
+    s := strings.ToUpper(strings.Join(strings.Fields(size), ""))
+    s = strings.TrimPrefix(strings.TrimPrefix(s, "NO."), "NO")
+
+    if n, err := strconv.ParseFloat(strings.Replace(s, ",", ".", 1), 64); err == nil {
+        return groupNumeric, n
+    }
+
+    if m := letterSize.FindStringSubmatch(s); m != nil {
+        extra := len(m[2])
+        if m[1] != "" {
+            if m[2] != "X" {
+                return groupOther, 0 // mis. "2XXL" bukan penulisan yang dikenal
+            }
+            extra, _ = strconv.Atoi(m[1])
+        }
+        switch m[3] {
+        case "S":
+            return groupLetter, float64(-1 - extra)
+        case "L":
+            return groupLetter, float64(1 + extra)
+        default:
+            if extra > 0 {
+                return groupOther, 0
+            }
+            return groupLetter, 0
+        }
+    }
+    return groupOther, 0
+}
+
+// Compare membandingkan dua ukuran: -1 jika a sebelum b, 1 jika sesudah, 0 jika setara
+func Compare(a, b string) int {
+    ga, va := rank(a)
+    gb, vb := rank(b)
+    switch {
+    case ga != gb:
+        if ga < gb {
+            return -1
+        }
+        return 1
+    case va != vb:
+        if va < vb {
+            return -1
+        }
+        return 1
+    }
+    return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+}
+
+// Less bisa dipakai langsung di sort.Slice
+func Less(a, b string) bool {
+    return Compare(a, b) < 0
+}
+
+// Sort mengurutkan daftar ukuran di tempat
+func Sort(sizes []string) {
+    sort.SliceStable(sizes, func(i, j int) bool { return Less(sizes[i], sizes[j]) })
+}
+
+// SortedKeys mengembalikan ukuran-ukuran (key map) dalam urutan ukuran
+func SortedKeys(m map[string]int) []string {
+    sizes := make([]string, 0, len(m))
+    for size := range m {
+        sizes = append(sizes, size)
+    }
+    Sort(sizes)
+    return sizes
+}
+
+// Chart adalah urutan ukuran dari tabel ukuran (size chart). Ukuran yang tidak ada di tabel
+// diletakkan sesudahnya dengan urutan bawaan; Chart nil sama dengan urutan bawaan.
+type Chart []string
+
+func normalize(size string) string {
+    return strings.ToUpper(strings.Join(strings.Fields(size), ""))
+}
+
+// Equal membandingkan dua kode ukuran tanpa membedakan huruf besar/kecil dan spasi
+func Equal(a, b string) bool {
+    return normalize(a) == normalize(b)
+}
+
+// Index mengembalikan posisi ukuran di tabel, -1 jika tidak ada
+func (c Chart) Index(size string) int {
+    for i, s := range c {
+        if Equal(s, size) {
+            return i
+        }
+    }
+    return -1
+}
+
+func (c Chart) Contains(size string) bool {
+    return c.Index(size) >= 0
+}
+
+func (c Chart) Compare(a, b string) int {
+    ia, ib := c.Index(a), c.Index(b)
+    switch {
+    case ia >= 0 && ib >= 0:
+        if ia != ib {
+            if ia < ib {
+                return -1
+            }
+            return 1
+        }
+        return 0
+    case ia >= 0:
+        return -1
+    case ib >= 0:
+        return 1
+    }
+    return Compare(a, b)
+}
+
+func (c Chart) Less(a, b string) bool {
+    return c.Compare(a, b) < 0
+}
+
+func (c Chart) Sort(sizes []string) {
+    sort.SliceStable(sizes, func(i, j int) bool { return c.Less(sizes[i], sizes[j]) })
+}
+
+func (c Chart) SortedKeys(m map[string]int) []string {
+    sizes := make([]string, 0, len(m))
+    for size := range m {
+        sizes = append(sizes, size)
+    }
+    c.Sort(sizes)
+    return sizes
+}
@@ -0,0 +1,237 @@
+package spreadsheet
+
+import (
+    "bytes"
+    "encoding/csv"
+    "fmt"
+    "io"
+    "path/filepath"
+    "strings"
+
+    "github.com/xuri/excelize/v2"
+)
+
+// Table adalah isi sheet: baris pertama sebagai header, sisanya data
+type Table struct {
+    Header []string
+    Rows   [][]string
+    // Lines berisi nomor baris asli di file (mulai dari 1) untuk setiap Rows,
+    // karena baris kosong tidak ikut dihitung di Rows
+    Lines []int
+}
+
+// Line mengembalikan nomor baris asli di file untuk Rows[i]
+func (t *Table) Line(i int) int {
+    if i < len(t.Lines) {
+        return t.Lines[i]
+    }
+    return i + 2
+}
+
+// Read membaca file CSV atau XLSX (berdasarkan ekstensi) menjadi Table.
+// Baris kosong di-skip (nomor baris asli tetap tercatat di Lines); untuk XLSX yang dibaca adalah sheet pertama.
+func Read(filename string, r io.Reader) (*Table, error) {
+    var records [][]string
+    var lines []int
+    var err error
+
+    switch strings.ToLower(filepath.Ext(filename)) {
+    case ".csv", ".txt":
+        records, lines, err = readCSV(r)
+    case ".xlsx":
+        records, lines, err = readXLSX(r)
+    default:
+        return nil, fmt.Errorf("format file '%s' tidak didukung, gunakan CSV atau XLSX", filepath.Ext(filename))
+    }
+    if err != nil {
+        return nil, err
+    }
+
+    table := &Table{}
+    for n, record := range records {
+        empty := true
+        for i := range record {
+            record[i] = strings.TrimSpace(record[i])
+            if record[i] != "" {
+                empty = false
+            }
+        }
+        if empty {
+            continue
+        }
+        if table.Header == nil {
+            table.Header = record
+            continue
+        }
+        table.Rows = append(table.Rows, record)
+        table.Lines = append(table.Lines, lines[n])
+    }
+    if table.Header == nil {
+        return nil, fmt.Errorf("file kosong")
+    }
+    return table, nil
+}
+
+// readCSV mendukung pemisah koma maupun titik koma (default Excel berbahasa Indonesia).
+// Baris kosong dilewati oleh encoding/csv, jadi nomor baris asli diambil dari FieldPos.
+func readCSV(r io.Reader) ([][]string, []int, error) {
+    data, err := io.ReadAll(r)
+    if err != nil {
+        return nil, nil, err
+    }
+    data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")) // BOM dari Excel
+
+    // Deteksi pemisah dari baris pertama yang berisi
+    firstLine := bytes.TrimLeft(data, "\r\n")
+    if i := bytes.IndexByte(firstLine, '\n'); i != -1 {
+        firstLine = firstLine[:i]
+    }
+
+    reader := csv.NewReader(bytes.NewReader(data))
+    reader.FieldsPerRecord = -1
+    reader.TrimLeadingSpace = true
+    if bytes.Count(firstLine, []byte(";")) > bytes.Count(firstLine, []byte(",")) {
+        reader.Comma = ';'
+    }
+
+    var records [][]string
+    var lines []int
+    for {
+        record, err := reader.Read()
+        if err == io.EOF {
+            break
+        }
+        if err != nil {
+            return nil, nil, err
+        }
+        line, _ := reader.FieldPos(0)
+        records = append(records, record)
+        lines = append(lines, line)
+    }
+    return records, lines, nil
+}
+
+// readXLSX membaca sheet pertama; GetRows menyertakan baris kosong di tengah, jadi index = nomor baris - 1
+func readXLSX(r io.Reader) ([][]string, []int, error) {
+    f, err := excelize.OpenReader(r)
+    if err != nil {
+        return nil, nil, fmt.Errorf("file XLSX tidak valid: %v", err)
+    }
+    defer f.Close()
+
+    sheets := f.GetSheetList()
+    if len(sheets) == 0 {
+        return nil, nil, fmt.Errorf("file XLSX tidak memiliki sheet")
+    }
+    records, err := f.GetRows(sheets[0])
+    if err != nil {
+        return nil, nil, err
+    }
+    lines := make([]int, len(records))
+    for i := range records {
+        lines[i] = i + 1
+    }
+    return records, lines, nil
+}
+
+func normalizeHeader(s string) string {
+    s = strings.ToLower(strings.TrimSpace(s))
+    s = strings.NewReplacer("_", " ", "-", " ", ".", "").Replace(s)
+    return strings.Join(strings.Fields(s), " ")
+}
+
+// MapColumns mencari index kolom untuk setiap field berdasarkan daftar alias header.
+// override (field -> nama header) dipakai jika user memilih kolom secara manual.
+// Mengembalikan field wajib yang tidak ditemukan.
+func (t *Table) MapColumns(aliases map[string][]string, required []string, override map[string]string) (map[string]int, []string) {
+    index := make(map[string]int)
+    for i, h := range t.Header {
+        index[normalizeHeader(h)] = i
+    }
+
+    columns := make(map[string]int)
+    for field, names := range aliases {
+        if header, ok := override[field]; ok && header != "" {
+            if i, found := index[normalizeHeader(header)]; found {
+                columns[field] = i
+            }
+            continue
+        }
+        for _, name := range append([]string{field}, names...) {
+            if i, found := index[normalizeHeader(name)]; found {
+                columns[field] = i
+                break
+            }
+        }
+    }
+
+    var missing []string
+    for _, field := range required {
+        if _, ok := columns[field]; !ok {
+            missing = append(missing, field)
+        }
+    }
+    return columns, missing
+}
+
+// Value mengambil nilai kolom field pada baris (string kosong jika kolom tidak ada)
+func Value(row []string, columns map[string]int, field string) string {
+    i, ok := columns[field]
+    if !ok || i >= len(row) {
+        return ""
+    }
+    return row[i]
+}
+
+// WriteCSV menulis header dan baris sebagai CSV (dengan BOM supaya terbaca benar di Excel)
+func WriteCSV(w io.Writer, header []string, rows [][]string) error {
+    if _, err := w.Write([]byte("\xef\xbb\xbf")); err != nil {
+        return err
+    }
+    writer := csv.NewWriter(w)
+    if err := writer.Write(header); err != nil {
+        return err
+    }
+    if err := writer.WriteAll(rows); err != nil {
+        return err
+    }
+    writer.Flush()
+    return writer.Error()
+}
+
+// WriteXLSX menulis header dan baris ke satu sheet XLSX
+func WriteXLSX(w io.Writer, sheetName string, header []string, rows [][]string) error {
+    f := excelize.NewFile()
+    defer f.Close()
+
+    if err := f.SetSheetName("Sheet1", sheetName); err != nil {
+        return err
+    }
+    writeRow := func(rowIndex int, values []string) error {
+        cells := make([]interface{}, len(values))
+        for i, v := range values {
+            cells[i] = v
+        }
+        cell, err := excelize.CoordinatesToCellName(1, rowIndex)
+        if err != nil {
+            return err
+        }
+        return f.SetSheetRow(sheetName, cell, &cells)
+    }
+
+    if err := writeRow(1, header); err != nil {
+        return err
+    }
+    for i, row := range rows {
+        if err := writeRow(i+2, row); err != nil {
+            return err
+        }
+    }
+
+    bold, err := f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}})
+    if err == nil {
+        lastCell, _ := excelize.CoordinatesToCellName(len(header), 1)
+        f.SetCellStyle(sheetName, "A1", lastCell, bold)
+    }
+    return f.Write(w)
+}
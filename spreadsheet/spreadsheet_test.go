@@ -0,0 +1,56 @@
+package spreadsheet
+
+import (
+    "bytes"
+    "strings"
+    "testing"
+
+    "github.com/xuri/excelize/v2"
+)
+
+func TestReadKeepsOriginalLineNumbers(t *testing.T) {
+    data := "Nama,Kelas\n\nBudi,1A\n,\nSiti,2B\n\n\nAni,3C\n"
+    table, err := Read("roster.csv", strings.NewReader(data))
+    if err != nil {
+        t.Fatal(err)
+    }
+    if len(table.Rows) != 3 {
+        t.Fatalf("rows = %d, want 3", len(table.Rows))
+    }
+    want := []int{3, 5, 8}
+    for i, line := range want {
+        if got := table.Line(i); got != line {
+            t.Errorf("row %d (%s): line = %d, want %d", i, table.Rows[i][0], got, line)
+        }
+    }
+}
+
+func TestReadBlankLinesBeforeHeader(t *testing.T) {
+    table, err := Read("roster.csv", strings.NewReader("\n\nNama;Kelas\nBudi;1A\n"))
+    if err != nil {
+        t.Fatal(err)
+    }
+    if table.Header[0] != "Nama" || table.Line(0) != 4 {
+        t.Fatalf("header = %v, line = %d, want Nama and 4", table.Header, table.Line(0))
+    }
+}
+
+func TestReadXLSXKeepsOriginalLineNumbers(t *testing.T) {
+    f := excelize.NewFile()
+    sheet := f.GetSheetName(0)
+    f.SetSheetRow(sheet, "A1", &[]string{"Nama", "Kelas"})
+    f.SetSheetRow(sheet, "A4", &[]string{"Budi", "1A"})
+    f.SetSheetRow(sheet, "A6", &[]string{"Siti", "2B"})
+    var buf bytes.Buffer
+    if err := f.Write(&buf); err != nil {
+        t.Fatal(err)
+    }
+
+    table, err := Read("roster.xlsx", &buf)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if len(table.Rows) != 2 || table.Line(0) != 4 || table.Line(1) != 6 {
+        t.Fatalf("rows = %v, lines = %v, want lines [4 6]", table.Rows, table.Lines)
+    }
+}